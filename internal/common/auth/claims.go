@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	commonerrors "github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/errors"
+)
+
+// ClaimMapping defines which claims of the token are copied to User.
+//
+// Nested claims can be addressed with dots, for example "app_metadata.role".
+type ClaimMapping struct {
+	UUID  string
+	Email string
+	Role  string
+
+	// DisplayName and SessionID are optional, empty values are used when they are missing in the token.
+	DisplayName string
+	SessionID   string
+}
+
+// DefaultClaimMapping uses standard OIDC claims and the custom "role" claim, which is also set by Firebase.
+func DefaultClaimMapping() ClaimMapping {
+	return ClaimMapping{
+		UUID:        "sub",
+		Email:       "email",
+		Role:        "role",
+		DisplayName: "name",
+		SessionID:   "auth_time",
+	}
+}
+
+// userFromClaims returns authorization error, when one of required claims is missing.
+func userFromClaims(claims map[string]interface{}, mapping ClaimMapping) (User, error) {
+	uuid, err := requiredClaim(claims, mapping.UUID)
+	if err != nil {
+		return User{}, err
+	}
+	email, err := requiredClaim(claims, mapping.Email)
+	if err != nil {
+		return User{}, err
+	}
+	role, err := requiredClaim(claims, mapping.Role)
+	if err != nil {
+		return User{}, err
+	}
+
+	displayName, _ := claimValue(claims, mapping.DisplayName)
+	sessionID, _ := claimValue(claims, mapping.SessionID)
+
+	return User{
+		UUID:        uuid,
+		Email:       email,
+		Role:        role,
+		DisplayName: displayName,
+		SessionID:   sessionID,
+	}, nil
+}
+
+func requiredClaim(claims map[string]interface{}, name string) (string, error) {
+	value, ok := claimValue(claims, name)
+	if !ok || value == "" {
+		return "", commonerrors.NewAuthorizationError(
+			fmt.Sprintf("missing '%s' claim in the token", name),
+			"missing-jwt-claim",
+		)
+	}
+
+	return value, nil
+}
+
+// claimValue returns the claim as string. Numeric claims, like auth_time, are formatted as integers.
+func claimValue(claims map[string]interface{}, name string) (string, bool) {
+	if name == "" {
+		return "", false
+	}
+
+	var value interface{} = claims
+	for _, part := range strings.Split(name, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return "", false
+		}
+
+		value, ok = object[part]
+		if !ok {
+			return "", false
+		}
+	}
+
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case float64:
+		return strconv.FormatInt(int64(v), 10), true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,194 @@
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"strings"
+
+	commonerrors "github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/errors"
+	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// ServiceAuthenticator returns the identity of the service, which called the gRPC method.
+type ServiceAuthenticator interface {
+	AuthenticateService(ctx context.Context) (string, error)
+}
+
+// IDTokenServiceAuthenticator verifies identity tokens sent in the authorization metadata,
+// like tokens issued by the Cloud Run metadata server. The identity is the email claim of the token.
+type IDTokenServiceAuthenticator struct {
+	keySet   KeySet
+	issuer   string
+	audience string
+}
+
+func NewIDTokenServiceAuthenticator(keySet KeySet, issuer string, audience string) IDTokenServiceAuthenticator {
+	if keySet == nil {
+		panic("nil keySet")
+	}
+	if issuer == "" {
+		panic("empty issuer")
+	}
+	if audience == "" {
+		panic("empty audience")
+	}
+
+	return IDTokenServiceAuthenticator{keySet: keySet, issuer: issuer, audience: audience}
+}
+
+func (a IDTokenServiceAuthenticator) AuthenticateService(ctx context.Context) (string, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	values := md.Get("authorization")
+	if len(values) == 0 || !strings.HasPrefix(values[0], "Bearer ") {
+		return "", commonerrors.NewAuthorizationError("missing bearer token in metadata", "empty-bearer-token")
+	}
+
+	claims, err := verifyJWT(ctx, a.keySet, strings.TrimPrefix(values[0], "Bearer "), a.issuer, a.audience)
+	if err != nil {
+		return "", err
+	}
+
+	return requiredClaim(claims, "email")
+}
+
+// MTLSServiceAuthenticator uses the common name of the client certificate.
+// The server must require and verify client certificates, so only certificates signed by the trusted CA are accepted.
+type MTLSServiceAuthenticator struct{}
+
+func (MTLSServiceAuthenticator) AuthenticateService(ctx context.Context) (string, error) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return "", commonerrors.NewAuthorizationError("no peer in context", "missing-client-certificate")
+	}
+
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return "", commonerrors.NewAuthorizationError("no verified client certificate", "missing-client-certificate")
+	}
+
+	commonName := tlsInfo.State.VerifiedChains[0][0].Subject.CommonName
+	if commonName == "" {
+		return "", commonerrors.NewAuthorizationError("empty common name of client certificate", "missing-client-certificate")
+	}
+
+	return commonName, nil
+}
+
+// LoadMTLSConfig loads the certificate of the service and the CA, which signs certificates of all services.
+// The same certificate is used by the service as a server and as a client.
+func LoadMTLSConfig(certFile string, keyFile string, caFile string) (*tls.Config, error) {
+	if certFile == "" || keyFile == "" || caFile == "" {
+		return nil, errors.New("certificate, key and CA files are required")
+	}
+
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to load certificate")
+	}
+
+	caPEM, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read CA file")
+	}
+	caPool := x509.NewCertPool()
+	if !caPool.AppendCertsFromPEM(caPEM) {
+		return nil, errors.New("no certificates found in CA file")
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      caPool,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// GrpcServiceAuth authenticates calling services and allows them to call only methods from the allowlist.
+type GrpcServiceAuth struct {
+	authenticator ServiceAuthenticator
+	// allowedCallers contains identities of services by the full method name, like "/users.UsersService/GetUser".
+	allowedCallers map[string][]string
+	// publicServices are called without authentication, by names like "grpc.health.v1.Health".
+	publicServices []string
+}
+
+// NewGrpcServiceAuth denies calls to all methods, which are missing in allowedCallers.
+func NewGrpcServiceAuth(authenticator ServiceAuthenticator, allowedCallers map[string][]string) GrpcServiceAuth {
+	if authenticator == nil {
+		panic("nil authenticator")
+	}
+
+	return GrpcServiceAuth{authenticator: authenticator, allowedCallers: allowedCallers}
+}
+
+// AllowUnauthenticated allows anyone to call all methods of services, like the health checking service.
+func (a GrpcServiceAuth) AllowUnauthenticated(services ...string) GrpcServiceAuth {
+	a.publicServices = append(append([]string{}, a.publicServices...), services...)
+	return a
+}
+
+func (a GrpcServiceAuth) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := a.authorize(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func (a GrpcServiceAuth) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := a.authorize(stream.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+
+		wrapped := grpc_middleware.WrapServerStream(stream)
+		wrapped.WrappedContext = ctx
+
+		return handler(srv, wrapped)
+	}
+}
+
+func (a GrpcServiceAuth) authorize(ctx context.Context, fullMethod string) (context.Context, error) {
+	for _, service := range a.publicServices {
+		if strings.HasPrefix(fullMethod, "/"+service+"/") {
+			return ctx, nil
+		}
+	}
+
+	identity, err := a.authenticator.AuthenticateService(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	for _, allowed := range a.allowedCallers[fullMethod] {
+		if allowed == identity {
+			return ContextWithService(ctx, identity), nil
+		}
+	}
+
+	return nil, status.Errorf(codes.PermissionDenied, "service '%s' is not allowed to call %s", identity, fullMethod)
+}
+
+// ContextWithService is used by interceptors after the calling service is authenticated.
+func ContextWithService(ctx context.Context, identity string) context.Context {
+	return context.WithValue(ctx, serviceContextKey, identity)
+}
+
+// ServiceFromCtx returns the identity of the service, which called the gRPC method.
+func ServiceFromCtx(ctx context.Context) (string, bool) {
+	identity, ok := ctx.Value(serviceContextKey).(string)
+	return identity, ok
+}
@@ -0,0 +1,126 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/auth"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	testUpdateBalanceMethod = "/users.UsersService/UpdateTrainingBalance"
+	testGetUserMethod       = "/users.UsersService/GetUser"
+	testHealthCheckMethod   = "/grpc.health.v1.Health/Check"
+	testTrainingsIdentity   = "trainings@project.iam.gserviceaccount.com"
+)
+
+func TestGrpcServiceAuth_UnaryServerInterceptor(t *testing.T) {
+	t.Parallel()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	serviceAuth := auth.NewGrpcServiceAuth(
+		auth.NewIDTokenServiceAuthenticator(newTestRemoteKeySet(t, rsaKey, ecKey), testIssuer, testAudience),
+		map[string][]string{
+			testUpdateBalanceMethod: {testTrainingsIdentity},
+		},
+	).AllowUnauthenticated("grpc.health.v1.Health")
+	interceptor := serviceAuth.UnaryServerInterceptor()
+
+	serviceToken := func(t *testing.T, email string, audience string) string {
+		claims := validClaims()
+		claims["email"] = email
+		claims["aud"] = audience
+		return "Bearer " + signToken(t, jwt.SigningMethodRS256, "rsa-key", rsaKey, claims)
+	}
+
+	testCases := []struct {
+		Name           string
+		Authorization  string
+		Method         string
+		ExpectedCode   codes.Code
+		ExpectedCaller string
+	}{
+		{
+			Name:           "allowed_service",
+			Authorization:  serviceToken(t, testTrainingsIdentity, testAudience),
+			Method:         testUpdateBalanceMethod,
+			ExpectedCode:   codes.OK,
+			ExpectedCaller: testTrainingsIdentity,
+		},
+		{
+			Name:          "public_service_without_token",
+			Authorization: "",
+			Method:        testHealthCheckMethod,
+			ExpectedCode:  codes.OK,
+		},
+		{
+			Name:          "not_allowed_service",
+			Authorization: serviceToken(t, "trainer@project.iam.gserviceaccount.com", testAudience),
+			Method:        testUpdateBalanceMethod,
+			ExpectedCode:  codes.PermissionDenied,
+		},
+		{
+			Name:          "method_not_in_allowlist",
+			Authorization: serviceToken(t, testTrainingsIdentity, testAudience),
+			Method:        testGetUserMethod,
+			ExpectedCode:  codes.PermissionDenied,
+		},
+		{
+			Name:          "token_for_other_service",
+			Authorization: serviceToken(t, testTrainingsIdentity, "https://trainer-grpc.example.com"),
+			Method:        testUpdateBalanceMethod,
+			ExpectedCode:  codes.Unauthenticated,
+		},
+		{
+			Name:          "missing_token",
+			Authorization: "",
+			Method:        testUpdateBalanceMethod,
+			ExpectedCode:  codes.Unauthenticated,
+		},
+	}
+
+	for _, c := range testCases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			if c.Authorization != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", c.Authorization))
+			}
+
+			var callerInHandler string
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				callerInHandler, _ = auth.ServiceFromCtx(ctx)
+				return "response", nil
+			}
+
+			resp, err := interceptor(ctx, "request", &grpc.UnaryServerInfo{FullMethod: c.Method}, handler)
+
+			if c.ExpectedCode != codes.OK {
+				assert.Equal(t, c.ExpectedCode, status.Code(err), "error: %v", err)
+				assert.Empty(t, callerInHandler, "handler should not be called")
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "response", resp)
+			assert.Equal(t, c.ExpectedCaller, callerInHandler)
+		})
+	}
+}
@@ -30,14 +30,21 @@ func (a FirebaseHttpMiddleware) Middleware(next http.Handler) http.Handler {
 			return
 		}
 
-		// it's always a good idea to use custom type as context value (in this case ctxKey)
-		// because nobody from the outside of the package will be able to override/read this value
-		ctx = context.WithValue(ctx, userContextKey, User{
-			UUID:        token.UID,
-			Email:       token.Claims["email"].(string),
-			Role:        token.Claims["role"].(string),
-			DisplayName: token.Claims["name"].(string),
-		})
+		claims := map[string]interface{}{}
+		for name, value := range token.Claims {
+			claims[name] = value
+		}
+		// sub is removed from Claims by the Firebase SDK
+		claims["sub"] = token.UID
+
+		// tokens refreshed from the same sign-in have the same auth_time, so it's used as the session ID
+		user, err := userFromClaims(claims, DefaultClaimMapping())
+		if err != nil {
+			httperr.RespondWithSlugError(err, w, r)
+			return
+		}
+
+		ctx = ContextWithUser(ctx, user)
 		r = r.WithContext(ctx)
 
 		next.ServeHTTP(w, r)
@@ -60,12 +67,16 @@ type User struct {
 	Role  string
 
 	DisplayName string
+
+	// SessionID identifies the sign-in, which the token comes from. It's empty when it's not known.
+	SessionID string
 }
 
 type ctxKey int
 
 const (
 	userContextKey ctxKey = iota
+	serviceContextKey
 )
 
 var (
@@ -74,6 +85,13 @@ var (
 	NoUserInContextError = commonerrors.NewAuthorizationError("no user in context", "no-user-found")
 )
 
+// ContextWithUser is used by middlewares after the user is authenticated.
+func ContextWithUser(ctx context.Context, user User) context.Context {
+	// it's always a good idea to use custom type as context value (in this case ctxKey)
+	// because nobody from the outside of the package will be able to override/read this value
+	return context.WithValue(ctx, userContextKey, user)
+}
+
 func UserFromCtx(ctx context.Context) (User, error) {
 	u, ok := ctx.Value(userContextKey).(User)
 	if ok {
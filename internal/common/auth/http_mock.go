@@ -1,7 +1,6 @@
 package auth
 
 import (
-	"context"
 	"net/http"
 
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/server/httperr"
@@ -31,14 +30,23 @@ func HttpMockMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
-		ctx := context.WithValue(r.Context(), userContextKey, User{
-			UUID:        claims["user_uuid"].(string),
-			Email:       claims["email"].(string),
-			Role:        claims["role"].(string),
-			DisplayName: claims["name"].(string),
-		})
+		user, err := userFromClaims(claims, mockClaimMapping())
+		if err != nil {
+			httperr.RespondWithSlugError(err, w, r)
+			return
+		}
+
+		ctx := ContextWithUser(r.Context(), user)
 		r = r.WithContext(ctx)
 
 		next.ServeHTTP(w, r)
 	})
 }
+
+// mockClaimMapping matches tokens generated by the frontend and component tests.
+func mockClaimMapping() ClaimMapping {
+	mapping := DefaultClaimMapping()
+	mapping.UUID = "user_uuid"
+
+	return mapping
+}
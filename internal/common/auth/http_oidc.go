@@ -0,0 +1,144 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"net/http"
+	"time"
+
+	commonerrors "github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/errors"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/server/httperr"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/dgrijalva/jwt-go/request"
+	"github.com/pkg/errors"
+)
+
+type OIDCConfig struct {
+	// Issuer must be equal to the iss claim of the token.
+	Issuer string
+	// Audience must be one of values of the aud claim of the token.
+	Audience string
+
+	Claims ClaimMapping
+}
+
+// OIDCHttpMiddleware verifies RS256 and ES256 tokens issued by any OpenID Connect provider.
+type OIDCHttpMiddleware struct {
+	keySet KeySet
+	config OIDCConfig
+}
+
+func NewOIDCHttpMiddleware(keySet KeySet, config OIDCConfig) OIDCHttpMiddleware {
+	if keySet == nil {
+		panic("nil keySet")
+	}
+	if config.Issuer == "" {
+		panic("empty issuer")
+	}
+	if config.Audience == "" {
+		panic("empty audience")
+	}
+
+	return OIDCHttpMiddleware{keySet: keySet, config: config}
+}
+
+func (a OIDCHttpMiddleware) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		bearerToken, err := request.AuthorizationHeaderExtractor.ExtractToken(r)
+		if err != nil {
+			httperr.Unauthorised("empty-bearer-token", err, w, r)
+			return
+		}
+
+		user, err := a.VerifyToken(ctx, bearerToken)
+		if err != nil {
+			httperr.RespondWithSlugError(err, w, r)
+			return
+		}
+
+		ctx = ContextWithUser(ctx, user)
+		r = r.WithContext(ctx)
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// VerifyToken checks the signature and registered claims of the token and maps its claims to User.
+func (a OIDCHttpMiddleware) VerifyToken(ctx context.Context, bearerToken string) (User, error) {
+	claims, err := verifyJWT(ctx, a.keySet, bearerToken, a.config.Issuer, a.config.Audience)
+	if err != nil {
+		return User{}, err
+	}
+
+	return userFromClaims(claims, a.config.Claims)
+}
+
+// verifyJWT returns claims of the token, when it's signed with one of keys from the keySet
+// and it was issued by the issuer for the audience.
+func verifyJWT(ctx context.Context, keySet KeySet, bearerToken string, issuer string, audience string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+
+	_, err := jwt.ParseWithClaims(bearerToken, claims, func(token *jwt.Token) (interface{}, error) {
+		// The algorithm is checked explicitly, otherwise the public key could be used as a HMAC secret.
+		switch token.Method {
+		case jwt.SigningMethodRS256, jwt.SigningMethodES256:
+		default:
+			return nil, errors.Errorf("unsupported signing method '%s'", token.Method.Alg())
+		}
+
+		kid, _ := token.Header["kid"].(string)
+		key, err := keySet.Key(ctx, kid)
+		if err != nil {
+			return nil, err
+		}
+
+		switch key.(type) {
+		case *rsa.PublicKey:
+			if token.Method != jwt.SigningMethodRS256 {
+				return nil, errors.New("signing method doesn't match the key type")
+			}
+		case *ecdsa.PublicKey:
+			if token.Method != jwt.SigningMethodES256 {
+				return nil, errors.New("signing method doesn't match the key type")
+			}
+		}
+
+		return key, nil
+	})
+	if err != nil {
+		// details of the verification are only logged, they are not returned to the client
+		return nil, errors.Wrap(commonerrors.NewAuthorizationError("invalid token", "unable-to-verify-jwt"), err.Error())
+	}
+
+	// exp, iss and aud are not verified (or are optional) in jwt.MapClaims.Valid
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return nil, commonerrors.NewAuthorizationError("token is expired or has no exp claim", "invalid-jwt")
+	}
+	if !claims.VerifyIssuer(issuer, true) {
+		return nil, commonerrors.NewAuthorizationError("invalid token issuer", "invalid-jwt")
+	}
+	if !hasAudience(claims, audience) {
+		return nil, commonerrors.NewAuthorizationError("invalid token audience", "invalid-jwt")
+	}
+
+	return claims, nil
+}
+
+// hasAudience supports both forms of the aud claim, a single string and an array of strings.
+func hasAudience(claims jwt.MapClaims, audience string) bool {
+	switch aud := claims["aud"].(type) {
+	case string:
+		return aud == audience
+	case []interface{}:
+		for _, a := range aud {
+			if a == audience {
+				return true
+			}
+		}
+	}
+
+	return false
+}
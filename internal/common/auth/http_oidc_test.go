@@ -0,0 +1,259 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/auth"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/errors"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testIssuer   = "https://auth.example.com/"
+	testAudience = "wild-workouts"
+)
+
+func TestOIDCHttpMiddleware_VerifyToken(t *testing.T) {
+	t.Parallel()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	keySet := newTestRemoteKeySet(t, rsaKey, ecKey)
+
+	testCases := []struct {
+		Name         string
+		Token        func(t *testing.T) string
+		Claims       auth.ClaimMapping
+		ExpectedSlug string
+	}{
+		{
+			Name: "rs256",
+			Token: func(t *testing.T) string {
+				return signToken(t, jwt.SigningMethodRS256, "rsa-key", rsaKey, validClaims())
+			},
+		},
+		{
+			Name: "es256",
+			Token: func(t *testing.T) string {
+				return signToken(t, jwt.SigningMethodES256, "ec-key", ecKey, validClaims())
+			},
+		},
+		{
+			Name: "audience_array",
+			Token: func(t *testing.T) string {
+				claims := validClaims()
+				claims["aud"] = []string{"other-service", testAudience}
+				return signToken(t, jwt.SigningMethodRS256, "rsa-key", rsaKey, claims)
+			},
+		},
+		{
+			Name: "custom_claim_mapping",
+			Token: func(t *testing.T) string {
+				claims := validClaims()
+				claims["app_metadata"] = map[string]interface{}{"role": "trainer"}
+				delete(claims, "role")
+				return signToken(t, jwt.SigningMethodRS256, "rsa-key", rsaKey, claims)
+			},
+			Claims: func() auth.ClaimMapping {
+				mapping := auth.DefaultClaimMapping()
+				mapping.Role = "app_metadata.role"
+				return mapping
+			}(),
+		},
+		{
+			Name: "invalid_issuer",
+			Token: func(t *testing.T) string {
+				claims := validClaims()
+				claims["iss"] = "https://evil.example.com/"
+				return signToken(t, jwt.SigningMethodRS256, "rsa-key", rsaKey, claims)
+			},
+			ExpectedSlug: "invalid-jwt",
+		},
+		{
+			Name: "invalid_audience",
+			Token: func(t *testing.T) string {
+				claims := validClaims()
+				claims["aud"] = "other-service"
+				return signToken(t, jwt.SigningMethodRS256, "rsa-key", rsaKey, claims)
+			},
+			ExpectedSlug: "invalid-jwt",
+		},
+		{
+			Name: "expired",
+			Token: func(t *testing.T) string {
+				claims := validClaims()
+				claims["exp"] = time.Now().Add(-time.Minute).Unix()
+				return signToken(t, jwt.SigningMethodRS256, "rsa-key", rsaKey, claims)
+			},
+			ExpectedSlug: "unable-to-verify-jwt",
+		},
+		{
+			Name: "without_exp",
+			Token: func(t *testing.T) string {
+				claims := validClaims()
+				delete(claims, "exp")
+				return signToken(t, jwt.SigningMethodRS256, "rsa-key", rsaKey, claims)
+			},
+			ExpectedSlug: "invalid-jwt",
+		},
+		{
+			Name: "unknown_key",
+			Token: func(t *testing.T) string {
+				otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+				require.NoError(t, err)
+				return signToken(t, jwt.SigningMethodRS256, "other-key", otherKey, validClaims())
+			},
+			ExpectedSlug: "unable-to-verify-jwt",
+		},
+		{
+			Name: "hmac_signed_with_public_key",
+			Token: func(t *testing.T) string {
+				publicKey, err := json.Marshal(rsaKey.PublicKey)
+				require.NoError(t, err)
+				return signToken(t, jwt.SigningMethodHS256, "rsa-key", publicKey, validClaims())
+			},
+			ExpectedSlug: "unable-to-verify-jwt",
+		},
+		{
+			Name: "key_type_not_matching_algorithm",
+			Token: func(t *testing.T) string {
+				return signToken(t, jwt.SigningMethodES256, "rsa-key", ecKey, validClaims())
+			},
+			ExpectedSlug: "unable-to-verify-jwt",
+		},
+		{
+			Name: "missing_email_claim",
+			Token: func(t *testing.T) string {
+				claims := validClaims()
+				delete(claims, "email")
+				return signToken(t, jwt.SigningMethodRS256, "rsa-key", rsaKey, claims)
+			},
+			ExpectedSlug: "missing-jwt-claim",
+		},
+	}
+
+	for _, c := range testCases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			t.Parallel()
+
+			claims := c.Claims
+			if claims == (auth.ClaimMapping{}) {
+				claims = auth.DefaultClaimMapping()
+			}
+
+			middleware := auth.NewOIDCHttpMiddleware(keySet, auth.OIDCConfig{
+				Issuer:   testIssuer,
+				Audience: testAudience,
+				Claims:   claims,
+			})
+
+			user, err := middleware.VerifyToken(context.Background(), c.Token(t))
+
+			if c.ExpectedSlug != "" {
+				slugErr, ok := errors.AsSlugError(err)
+				require.True(t, ok, "expected SlugError, got %T: %v", err, err)
+				assert.Equal(t, c.ExpectedSlug, slugErr.Slug())
+				assert.Equal(t, errors.ErrorTypeAuthorization, slugErr.ErrorType())
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "user-1", user.UUID)
+			assert.Equal(t, "attendee@threedots.tech", user.Email)
+			assert.NotEmpty(t, user.Role)
+			assert.Equal(t, "Mariusz Pudzianowski", user.DisplayName)
+			assert.Equal(t, "1600000000", user.SessionID)
+		})
+	}
+}
+
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":       testIssuer,
+		"aud":       testAudience,
+		"sub":       "user-1",
+		"exp":       time.Now().Add(time.Hour).Unix(),
+		"email":     "attendee@threedots.tech",
+		"role":      "attendee",
+		"name":      "Mariusz Pudzianowski",
+		"auth_time": 1600000000,
+	}
+}
+
+func signToken(t *testing.T, method jwt.SigningMethod, kid string, key interface{}, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+
+	return signed
+}
+
+func newTestRemoteKeySet(t *testing.T, rsaKey *rsa.PrivateKey, ecKey *ecdsa.PrivateKey) auth.KeySet {
+	t.Helper()
+
+	encode := func(i *big.Int) string {
+		return base64.RawURLEncoding.EncodeToString(i.Bytes())
+	}
+
+	jwks, err := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{
+			{
+				"kty": "RSA",
+				"kid": "rsa-key",
+				"use": "sig",
+				"n":   encode(rsaKey.N),
+				"e":   encode(big.NewInt(int64(rsaKey.E))),
+			},
+			{
+				"kty": "EC",
+				"kid": "ec-key",
+				"crv": "P-256",
+				"x":   encode(ecKey.X),
+				"y":   encode(ecKey.Y),
+			},
+			// keys not supported by the service are skipped
+			{
+				"kty": "EC",
+				"kid": "p384-key",
+				"crv": "P-384",
+				"x":   "unsupported",
+				"y":   "unsupported",
+			},
+			{
+				"kty": "OKP",
+				"kid": "ed25519-key",
+				"crv": "Ed25519",
+				"x":   "unsupported",
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(jwks)
+	}))
+	t.Cleanup(server.Close)
+
+	return auth.NewRemoteKeySet(server.URL, server.Client(), time.Minute)
+}
@@ -0,0 +1,113 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/server/httperr"
+)
+
+// SessionRevocationChecker checks if the session was revoked by the user, sessions are tracked by the users service.
+type SessionRevocationChecker interface {
+	IsSessionRevoked(ctx context.Context, userUUID string, sessionID string) (bool, error)
+}
+
+// SessionRevocationMiddleware rejects requests done with revoked sessions.
+//
+// Results are cached for cacheTTL, so the session may be still used for this time after it's revoked,
+// but the users service is not called for each request. It must be run after the auth middleware.
+type SessionRevocationMiddleware struct {
+	checker  SessionRevocationChecker
+	cacheTTL time.Duration
+
+	mu          sync.Mutex
+	cache       map[sessionKey]cachedRevocation
+	nextCleanup time.Time
+}
+
+type sessionKey struct {
+	userUUID  string
+	sessionID string
+}
+
+type cachedRevocation struct {
+	revoked   bool
+	expiresAt time.Time
+}
+
+func NewSessionRevocationMiddleware(checker SessionRevocationChecker, cacheTTL time.Duration) *SessionRevocationMiddleware {
+	if checker == nil {
+		panic("nil checker")
+	}
+
+	return &SessionRevocationMiddleware{
+		checker:  checker,
+		cacheTTL: cacheTTL,
+		cache:    map[sessionKey]cachedRevocation{},
+	}
+}
+
+func (m *SessionRevocationMiddleware) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, err := UserFromCtx(r.Context())
+		if err != nil {
+			httperr.RespondWithSlugError(err, w, r)
+			return
+		}
+
+		if user.SessionID == "" {
+			// tokens without the session can't be revoked, so there is nothing to check
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		revoked, err := m.isRevoked(r.Context(), sessionKey{userUUID: user.UUID, sessionID: user.SessionID})
+		if err != nil {
+			// requests are rejected, because it's not known if the session is still valid
+			httperr.InternalError("unable-to-check-session", err, w, r)
+			return
+		}
+		if revoked {
+			httperr.Unauthorised("session-revoked", nil, w, r)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (m *SessionRevocationMiddleware) isRevoked(ctx context.Context, key sessionKey) (bool, error) {
+	now := time.Now()
+
+	m.mu.Lock()
+	cached, ok := m.cache[key]
+	m.mu.Unlock()
+
+	if ok && now.Before(cached.expiresAt) {
+		return cached.revoked, nil
+	}
+
+	// the users service is called without holding the lock, so other sessions are not blocked by it
+	revoked, err := m.checker.IsSessionRevoked(ctx, key.userUUID, key.sessionID)
+	if err != nil {
+		return false, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if now.After(m.nextCleanup) {
+		for k, c := range m.cache {
+			if now.After(c.expiresAt) {
+				delete(m.cache, k)
+			}
+		}
+		m.nextCleanup = now.Add(m.cacheTTL)
+	}
+
+	m.cache[key] = cachedRevocation{revoked: revoked, expiresAt: now.Add(m.cacheTTL)}
+
+	return revoked, nil
+}
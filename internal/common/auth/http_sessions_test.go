@@ -0,0 +1,59 @@
+package auth_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/auth"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/logs"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionRevocationMiddleware(t *testing.T) {
+	checker := &testSessionRevocationChecker{revoked: map[string]bool{"revoked-session": true}}
+	middleware := auth.NewSessionRevocationMiddleware(checker, time.Minute)
+
+	handler := logs.NewStructuredLogger(logrus.New())(middleware.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})))
+
+	request := func(sessionID string) int {
+		ctx := auth.ContextWithUser(context.Background(), auth.User{UUID: "user-uuid", SessionID: sessionID})
+		req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusNoContent, request("active-session"))
+	assert.Equal(t, http.StatusUnauthorized, request("revoked-session"))
+	assert.Equal(t, http.StatusNoContent, request(""), "tokens without the session should not be checked")
+
+	assert.Equal(t, http.StatusNoContent, request("active-session"))
+	assert.Equal(t, 2, checker.calls, "results should be cached")
+
+	checker.err = errors.New("users service is not available")
+	assert.Equal(t, http.StatusInternalServerError, request("not-cached-session"))
+}
+
+type testSessionRevocationChecker struct {
+	revoked map[string]bool
+	err     error
+	calls   int
+}
+
+func (c *testSessionRevocationChecker) IsSessionRevoked(ctx context.Context, userUUID string, sessionID string) (bool, error) {
+	c.calls++
+	if c.err != nil {
+		return false, c.err
+	}
+
+	return c.revoked[sessionID], nil
+}
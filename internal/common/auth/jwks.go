@@ -0,0 +1,266 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// KeySet provides public keys used to verify signatures of tokens.
+type KeySet interface {
+	// Key returns *rsa.PublicKey or *ecdsa.PublicKey with the kid from the token header.
+	Key(ctx context.Context, kid string) (interface{}, error)
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+
+	// RSA keys
+	N string `json:"n"`
+	E string `json:"e"`
+
+	// EC keys
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type jwks struct {
+	Keys []jwk `json:"keys"`
+}
+
+// parseJWKS returns public keys by their kid. Keys which are not used for signatures are skipped.
+// Keys of unsupported types and curves are skipped as well, because identity providers may publish them
+// next to the supported ones.
+func parseJWKS(data []byte) (map[string]interface{}, error) {
+	set := jwks{}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, errors.Wrap(err, "unable to unmarshal JWKS")
+	}
+
+	keys := map[string]interface{}{}
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		if !k.isSupported() {
+			continue
+		}
+
+		key, err := k.publicKey()
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid key '%s'", k.Kid)
+		}
+		keys[k.Kid] = key
+	}
+
+	return keys, nil
+}
+
+func (k jwk) isSupported() bool {
+	return k.Kty == "RSA" || (k.Kty == "EC" && k.Crv == "P-256")
+}
+
+func (k jwk) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid modulus")
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid exponent")
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("exponent is too large")
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, errors.Errorf("unsupported curve '%s'", k.Crv)
+		}
+
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid x coordinate")
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid y coordinate")
+		}
+
+		curve := elliptic.P256()
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("point is not on the curve")
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, errors.Errorf("unsupported key type '%s'", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, errors.New("empty value")
+	}
+
+	return new(big.Int).SetBytes(b), nil
+}
+
+type staticKeySet struct {
+	keys map[string]interface{}
+}
+
+// NewFileKeySet loads JWKS from the local file. The file is read only once.
+func NewFileKeySet(path string) (KeySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read JWKS file")
+	}
+
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return staticKeySet{keys: keys}, nil
+}
+
+func (s staticKeySet) Key(ctx context.Context, kid string) (interface{}, error) {
+	key, ok := s.keys[kid]
+	if !ok {
+		return nil, errors.Errorf("unknown key '%s'", kid)
+	}
+
+	return key, nil
+}
+
+// RemoteKeySet fetches JWKS over HTTP.
+//
+// Keys are fetched again when a token is signed with an unknown key, because the identity provider may rotate keys.
+// To not overload the identity provider with tokens signed with random kids, keys are refreshed at most once per minRefreshInterval.
+type RemoteKeySet struct {
+	url        string
+	httpClient *http.Client
+
+	minRefreshInterval time.Duration
+
+	lock        sync.Mutex
+	keys        map[string]interface{}
+	lastRefresh time.Time
+	// refreshing is closed when the running refresh is finished, it's nil when keys are not refreshed
+	refreshing chan struct{}
+}
+
+func NewRemoteKeySet(url string, httpClient *http.Client, minRefreshInterval time.Duration) *RemoteKeySet {
+	if url == "" {
+		panic("empty JWKS url")
+	}
+	if httpClient == nil {
+		panic("nil httpClient")
+	}
+
+	return &RemoteKeySet{
+		url:                url,
+		httpClient:         httpClient,
+		minRefreshInterval: minRefreshInterval,
+	}
+}
+
+func (s *RemoteKeySet) Key(ctx context.Context, kid string) (interface{}, error) {
+	for {
+		s.lock.Lock()
+
+		if key, ok := s.keys[kid]; ok {
+			s.lock.Unlock()
+			return key, nil
+		}
+
+		// keys are fetched once for all concurrent requests, so they wait for the running refresh
+		if refreshing := s.refreshing; refreshing != nil {
+			s.lock.Unlock()
+
+			select {
+			case <-refreshing:
+				continue
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+
+		if !s.lastRefresh.IsZero() && time.Since(s.lastRefresh) < s.minRefreshInterval {
+			s.lock.Unlock()
+			return nil, errors.Errorf("unknown key '%s'", kid)
+		}
+
+		// the attempt is recorded also when it fails, so the identity provider is not called for every request when it's down
+		s.lastRefresh = time.Now()
+		refreshing := make(chan struct{})
+		s.refreshing = refreshing
+
+		s.lock.Unlock()
+
+		// keys are fetched without holding the lock, so requests with known keys are not blocked by it
+		keys, err := s.fetchKeys(ctx)
+
+		s.lock.Lock()
+		if err == nil {
+			s.keys = keys
+		}
+		s.refreshing = nil
+		close(refreshing)
+		s.lock.Unlock()
+
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (s *RemoteKeySet) fetchKeys(ctx context.Context) (map[string]interface{}, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to fetch JWKS")
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to fetch JWKS, status code: %d", resp.StatusCode)
+	}
+
+	// JWKS is small, the limit protects from misconfigured url
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read JWKS")
+	}
+
+	return parseJWKS(data)
+}
@@ -0,0 +1,33 @@
+package auth_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoteKeySet_failed_refresh(t *testing.T) {
+	t.Parallel()
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(server.Close)
+
+	keySet := auth.NewRemoteKeySet(server.URL, server.Client(), time.Minute)
+
+	for i := 0; i < 3; i++ {
+		_, err := keySet.Key(context.Background(), "rsa-key")
+		assert.Error(t, err)
+	}
+
+	assert.EqualValues(t, 1, atomic.LoadInt32(&requests), "failed refreshes should be limited as well")
+}
@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	commonerrors "github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/errors"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/server/httperr"
+)
+
+// Permission allows to do an action. Handlers should check permissions instead of roles,
+// so a new role can be added just by listing its permissions in rolePermissions.
+type Permission string
+
+const (
+	// PermissionTrainingsSchedule allows to schedule and reschedule own trainings.
+	PermissionTrainingsSchedule Permission = "trainings:schedule"
+	// PermissionTrainingsReadAll allows to see trainings of all users.
+	PermissionTrainingsReadAll Permission = "trainings:read:all"
+	// PermissionTrainingsManageAll allows to cancel and reschedule trainings of all users on behalf of the trainer.
+	PermissionTrainingsManageAll Permission = "trainings:manage:all"
+
+	// PermissionCalendarEdit allows to change the availability of the trainer's hours.
+	PermissionCalendarEdit Permission = "calendar:edit"
+	// PermissionCalendarConfigEdit allows to change which hours can be made available.
+	PermissionCalendarConfigEdit  Permission = "calendar:config:edit"
+	PermissionCalendarReadHistory Permission = "calendar:read:history"
+
+	PermissionCreditsPurchase Permission = "credits:purchase"
+
+	// PermissionUsersManage allows to see and change accounts of other users, including their credits and roles.
+	PermissionUsersManage Permission = "users:manage"
+)
+
+var rolePermissions = map[string][]Permission{
+	"attendee": {
+		PermissionTrainingsSchedule,
+		PermissionCreditsPurchase,
+	},
+	"trainer": {
+		PermissionTrainingsReadAll,
+		PermissionTrainingsManageAll,
+		PermissionCalendarEdit,
+		PermissionCalendarConfigEdit,
+		PermissionCalendarReadHistory,
+	},
+	"admin": {
+		PermissionTrainingsReadAll,
+		PermissionTrainingsManageAll,
+		PermissionCalendarConfigEdit,
+		PermissionCalendarReadHistory,
+		PermissionUsersManage,
+	},
+}
+
+// HasPermission returns false for unknown roles.
+func (u User) HasPermission(permission Permission) bool {
+	for _, p := range rolePermissions[u.Role] {
+		if p == permission {
+			return true
+		}
+	}
+
+	return false
+}
+
+// UserWithPermission returns the user from the context, when the user has the permission.
+func UserWithPermission(ctx context.Context, permission Permission) (User, error) {
+	user, err := UserFromCtx(ctx)
+	if err != nil {
+		return User{}, err
+	}
+
+	if !user.HasPermission(permission) {
+		return User{}, commonerrors.NewForbiddenError(
+			fmt.Sprintf("user with role '%s' has no '%s' permission", user.Role, permission),
+			"missing-permission",
+		)
+	}
+
+	return user, nil
+}
+
+// HttpUserWithPermission responds with an error, when the request was not done by the user with the permission.
+func HttpUserWithPermission(w http.ResponseWriter, r *http.Request, permission Permission) (User, bool) {
+	user, err := UserWithPermission(r.Context(), permission)
+	if err != nil {
+		httperr.RespondWithSlugError(err, w, r)
+		return User{}, false
+	}
+
+	return user, true
+}
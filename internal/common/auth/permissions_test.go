@@ -0,0 +1,51 @@
+package auth_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/auth"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUser_HasPermission(t *testing.T) {
+	t.Parallel()
+
+	attendee := auth.User{UUID: "1", Role: "attendee"}
+	assert.True(t, attendee.HasPermission(auth.PermissionTrainingsSchedule))
+	assert.False(t, attendee.HasPermission(auth.PermissionTrainingsReadAll))
+	assert.False(t, attendee.HasPermission(auth.PermissionUsersManage))
+
+	trainer := auth.User{UUID: "2", Role: "trainer"}
+	assert.True(t, trainer.HasPermission(auth.PermissionCalendarEdit))
+	assert.True(t, trainer.HasPermission(auth.PermissionTrainingsReadAll))
+	assert.False(t, trainer.HasPermission(auth.PermissionTrainingsSchedule))
+
+	admin := auth.User{UUID: "3", Role: "admin"}
+	assert.True(t, admin.HasPermission(auth.PermissionUsersManage))
+	assert.False(t, admin.HasPermission(auth.PermissionCalendarEdit))
+
+	unknown := auth.User{UUID: "4", Role: "receptionist"}
+	assert.False(t, unknown.HasPermission(auth.PermissionTrainingsSchedule))
+}
+
+func TestUserWithPermission(t *testing.T) {
+	t.Parallel()
+
+	ctx := auth.ContextWithUser(context.Background(), auth.User{UUID: "1", Role: "attendee"})
+
+	user, err := auth.UserWithPermission(ctx, auth.PermissionTrainingsSchedule)
+	require.NoError(t, err)
+	assert.Equal(t, "1", user.UUID)
+
+	_, err = auth.UserWithPermission(ctx, auth.PermissionUsersManage)
+	slugErr, ok := err.(errors.SlugError)
+	require.True(t, ok, "expected SlugError, got %T", err)
+	assert.Equal(t, "missing-permission", slugErr.Slug())
+	assert.Equal(t, errors.ErrorTypeForbidden, slugErr.ErrorType())
+
+	_, err = auth.UserWithPermission(context.Background(), auth.PermissionTrainingsSchedule)
+	assert.Equal(t, auth.NoUserInContextError, err)
+}
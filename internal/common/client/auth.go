@@ -25,7 +25,8 @@ func newMetadataServerToken(grpcAddr string) credentials.PerRPCCredentials {
 // GetRequestMetadata is called on every request, so we are sure that token is always not expired
 func (t metadataServerToken) GetRequestMetadata(ctx context.Context, in ...string) (map[string]string, error) {
 	// based on https://cloud.google.com/run/docs/authenticating/service-to-service#go
-	tokenURL := fmt.Sprintf("/instance/service-accounts/default/identity?audience=%s", t.serviceURL)
+	// the full format contains the email claim, which identifies the service account of the caller
+	tokenURL := fmt.Sprintf("/instance/service-accounts/default/identity?audience=%s&format=full", t.serviceURL)
 	idToken, err := metadata.Get(tokenURL)
 	if err != nil {
 		return nil, errors.Wrap(err, "cannot query id token for gRPC")
@@ -7,48 +7,62 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/auth"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/genproto/trainer"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/genproto/trainings"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/genproto/users"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/tracing"
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health/grpc_health_v1"
 )
 
 func NewTrainerClient() (client trainer.TrainerServiceClient, close func() error, err error) {
-	grpcAddr := os.Getenv("TRAINER_GRPC_ADDR")
-	if grpcAddr == "" {
-		return nil, func() error { return nil }, errors.New("empty env TRAINER_GRPC_ADDR")
-	}
-
-	opts, err := grpcDialOpts(grpcAddr)
+	conn, err := dial("TRAINER_GRPC_ADDR")
 	if err != nil {
 		return nil, func() error { return nil }, err
 	}
 
-	conn, err := grpc.Dial(grpcAddr, opts...)
+	return trainer.NewTrainerServiceClient(conn), conn.Close, nil
+}
+
+// NewTrainerHealthClient returns the client of the gRPC health checking protocol, served by the trainer service.
+func NewTrainerHealthClient() (client grpc_health_v1.HealthClient, close func() error, err error) {
+	conn, err := dial("TRAINER_GRPC_ADDR")
 	if err != nil {
 		return nil, func() error { return nil }, err
 	}
 
-	return trainer.NewTrainerServiceClient(conn), conn.Close, nil
+	return grpc_health_v1.NewHealthClient(conn), conn.Close, nil
 }
 
+// WaitForTrainerService waits until the trainer service reports that it's ready to serve calls.
 func WaitForTrainerService(timeout time.Duration) bool {
-	return waitForPort(os.Getenv("TRAINER_GRPC_ADDR"), timeout)
+	return waitForService("TRAINER_GRPC_ADDR", trainer.TrainerService_ServiceDesc.ServiceName, timeout)
 }
 
-func NewUsersClient() (client users.UsersServiceClient, close func() error, err error) {
-	grpcAddr := os.Getenv("USERS_GRPC_ADDR")
-	if grpcAddr == "" {
-		return nil, func() error { return nil }, errors.New("empty env USERS_GRPC_ADDR")
+func NewTrainingsClient() (client trainings.TrainingsServiceClient, close func() error, err error) {
+	conn, err := dial("TRAININGS_GRPC_ADDR")
+	if err != nil {
+		return nil, func() error { return nil }, err
 	}
 
-	opts, err := grpcDialOpts(grpcAddr)
+	return trainings.NewTrainingsServiceClient(conn), conn.Close, nil
+}
+
+// NewTrainingsHealthClient returns the client of the gRPC health checking protocol, served by the trainings service.
+func NewTrainingsHealthClient() (client grpc_health_v1.HealthClient, close func() error, err error) {
+	conn, err := dial("TRAININGS_GRPC_ADDR")
 	if err != nil {
 		return nil, func() error { return nil }, err
 	}
 
-	conn, err := grpc.Dial(grpcAddr, opts...)
+	return grpc_health_v1.NewHealthClient(conn), conn.Close, nil
+}
+
+func NewUsersClient() (client users.UsersServiceClient, close func() error, err error) {
+	conn, err := dial("USERS_GRPC_ADDR")
 	if err != nil {
 		return nil, func() error { return nil }, err
 	}
@@ -56,8 +70,39 @@ func NewUsersClient() (client users.UsersServiceClient, close func() error, err
 	return users.NewUsersServiceClient(conn), conn.Close, nil
 }
 
+// NewUsersHealthClient returns the client of the gRPC health checking protocol, served by the users service.
+func NewUsersHealthClient() (client grpc_health_v1.HealthClient, close func() error, err error) {
+	conn, err := dial("USERS_GRPC_ADDR")
+	if err != nil {
+		return nil, func() error { return nil }, err
+	}
+
+	return grpc_health_v1.NewHealthClient(conn), conn.Close, nil
+}
+
+// WaitForUsersService waits until the users service reports that it's ready to serve calls.
 func WaitForUsersService(timeout time.Duration) bool {
-	return waitForPort(os.Getenv("USERS_GRPC_ADDR"), timeout)
+	return waitForService("USERS_GRPC_ADDR", users.UsersService_ServiceDesc.ServiceName, timeout)
+}
+
+func dial(addrEnv string) (*grpc.ClientConn, error) {
+	grpcAddr := os.Getenv(addrEnv)
+	if grpcAddr == "" {
+		return nil, errors.Errorf("empty env %s", addrEnv)
+	}
+
+	opts, err := grpcDialOpts(grpcAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(
+		opts,
+		grpc.WithChainUnaryInterceptor(tracing.UnaryClientInterceptor()),
+		grpc.WithChainStreamInterceptor(tracing.StreamClientInterceptor()),
+	)
+
+	return grpc.Dial(grpcAddr, opts...)
 }
 
 func grpcDialOpts(grpcAddr string) ([]grpc.DialOption, error) {
@@ -65,6 +110,16 @@ func grpcDialOpts(grpcAddr string) ([]grpc.DialOption, error) {
 		return []grpc.DialOption{grpc.WithInsecure()}, nil
 	}
 
+	// with mTLS, the service is authenticated with its certificate instead of the identity token
+	if certFile := os.Getenv("GRPC_TLS_CERT_FILE"); certFile != "" {
+		tlsConfig, err := auth.LoadMTLSConfig(certFile, os.Getenv("GRPC_TLS_KEY_FILE"), os.Getenv("GRPC_TLS_CA_FILE"))
+		if err != nil {
+			return nil, err
+		}
+
+		return []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))}, nil
+	}
+
 	systemRoots, err := x509.SystemCertPool()
 	if err != nil {
 		return nil, errors.Wrap(err, "cannot load root CA cert")
@@ -0,0 +1,40 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// waitForService polls the gRPC health checking protocol until the service is serving.
+// It's waiting for readiness of the service, not only for the open port.
+func waitForService(addrEnv string, service string, timeout time.Duration) bool {
+	conn, err := dial(addrEnv)
+	if err != nil {
+		logrus.WithError(err).Error("Unable to connect to the service")
+		return false
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	healthClient := grpc_health_v1.NewHealthClient(conn)
+
+	for {
+		resp, err := healthClient.Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: service})
+		if err == nil && resp.Status == grpc_health_v1.HealthCheckResponse_SERVING {
+			return true
+		}
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(time.Millisecond * 200):
+		}
+	}
+}
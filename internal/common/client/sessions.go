@@ -0,0 +1,35 @@
+package client
+
+import (
+	"context"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/auth"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/genproto/users"
+	"github.com/pkg/errors"
+)
+
+// NewSessionRevocationChecker returns the checker of sessions revoked by users, which calls the users service.
+func NewSessionRevocationChecker() (checker auth.SessionRevocationChecker, close func() error, err error) {
+	usersClient, closeUsersClient, err := NewUsersClient()
+	if err != nil {
+		return nil, closeUsersClient, err
+	}
+
+	return sessionRevocationChecker{usersClient: usersClient}, closeUsersClient, nil
+}
+
+type sessionRevocationChecker struct {
+	usersClient users.UsersServiceClient
+}
+
+func (c sessionRevocationChecker) IsSessionRevoked(ctx context.Context, userUUID string, sessionID string) (bool, error) {
+	resp, err := c.usersClient.IsSessionRevoked(ctx, &users.IsSessionRevokedRequest{
+		UserId:    userUUID,
+		SessionId: sessionID,
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "unable to check if the session was revoked")
+	}
+
+	return resp.Revoked, nil
+}
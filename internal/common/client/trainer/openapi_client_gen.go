@@ -93,6 +93,20 @@ type ClientInterface interface {
 	// GetTrainerAvailableHours request
 	GetTrainerAvailableHours(ctx context.Context, params *GetTrainerAvailableHoursParams, reqEditors ...RequestEditorFn) (*http.Response, error)
 
+	// GetCalendarConfig request
+	GetCalendarConfig(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// UpdateCalendarConfig request with any body
+	UpdateCalendarConfigWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	UpdateCalendarConfig(ctx context.Context, body UpdateCalendarConfigJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetCalendarEvents request
+	GetCalendarEvents(ctx context.Context, params *GetCalendarEventsParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetCalendarHistory request
+	GetCalendarHistory(ctx context.Context, params *GetCalendarHistoryParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
 	// MakeHourAvailable request with any body
 	MakeHourAvailableWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
 
@@ -116,6 +130,66 @@ func (c *Client) GetTrainerAvailableHours(ctx context.Context, params *GetTraine
 	return c.Client.Do(req)
 }
 
+func (c *Client) GetCalendarConfig(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetCalendarConfigRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) UpdateCalendarConfigWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewUpdateCalendarConfigRequestWithBody(c.Server, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) UpdateCalendarConfig(ctx context.Context, body UpdateCalendarConfigJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewUpdateCalendarConfigRequest(c.Server, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetCalendarEvents(ctx context.Context, params *GetCalendarEventsParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetCalendarEventsRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetCalendarHistory(ctx context.Context, params *GetCalendarHistoryParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetCalendarHistoryRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
 func (c *Client) MakeHourAvailableWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
 	req, err := NewMakeHourAvailableRequestWithBody(c.Server, contentType, body)
 	if err != nil {
@@ -219,6 +293,183 @@ func NewGetTrainerAvailableHoursRequest(server string, params *GetTrainerAvailab
 	return req, nil
 }
 
+// NewGetCalendarConfigRequest generates requests for GetCalendarConfig
+func NewGetCalendarConfigRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/trainer/calendar/config")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewUpdateCalendarConfigRequest calls the generic UpdateCalendarConfig builder with application/json body
+func NewUpdateCalendarConfigRequest(server string, body UpdateCalendarConfigJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewUpdateCalendarConfigRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewUpdateCalendarConfigRequestWithBody generates requests for UpdateCalendarConfig with any type of body
+func NewUpdateCalendarConfigRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/trainer/calendar/config")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("PUT", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewGetCalendarEventsRequest generates requests for GetCalendarEvents
+func NewGetCalendarEventsRequest(server string, params *GetCalendarEventsParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/trainer/calendar/events")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	queryValues := queryURL.Query()
+
+	if queryFrag, err := runtime.StyleParamWithLocation("form", true, "dateFrom", runtime.ParamLocationQuery, params.DateFrom); err != nil {
+		return nil, err
+	} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+		return nil, err
+	} else {
+		for k, v := range parsed {
+			for _, v2 := range v {
+				queryValues.Add(k, v2)
+			}
+		}
+	}
+
+	if queryFrag, err := runtime.StyleParamWithLocation("form", true, "dateTo", runtime.ParamLocationQuery, params.DateTo); err != nil {
+		return nil, err
+	} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+		return nil, err
+	} else {
+		for k, v := range parsed {
+			for _, v2 := range v {
+				queryValues.Add(k, v2)
+			}
+		}
+	}
+
+	queryURL.RawQuery = queryValues.Encode()
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetCalendarHistoryRequest generates requests for GetCalendarHistory
+func NewGetCalendarHistoryRequest(server string, params *GetCalendarHistoryParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/trainer/calendar/history")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	queryValues := queryURL.Query()
+
+	if queryFrag, err := runtime.StyleParamWithLocation("form", true, "dateFrom", runtime.ParamLocationQuery, params.DateFrom); err != nil {
+		return nil, err
+	} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+		return nil, err
+	} else {
+		for k, v := range parsed {
+			for _, v2 := range v {
+				queryValues.Add(k, v2)
+			}
+		}
+	}
+
+	if queryFrag, err := runtime.StyleParamWithLocation("form", true, "dateTo", runtime.ParamLocationQuery, params.DateTo); err != nil {
+		return nil, err
+	} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+		return nil, err
+	} else {
+		for k, v := range parsed {
+			for _, v2 := range v {
+				queryValues.Add(k, v2)
+			}
+		}
+	}
+
+	queryURL.RawQuery = queryValues.Encode()
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
 // NewMakeHourAvailableRequest calls the generic MakeHourAvailable builder with application/json body
 func NewMakeHourAvailableRequest(server string, body MakeHourAvailableJSONRequestBody) (*http.Request, error) {
 	var bodyReader io.Reader
@@ -345,6 +596,20 @@ type ClientWithResponsesInterface interface {
 	// GetTrainerAvailableHours request
 	GetTrainerAvailableHoursWithResponse(ctx context.Context, params *GetTrainerAvailableHoursParams, reqEditors ...RequestEditorFn) (*GetTrainerAvailableHoursResponse, error)
 
+	// GetCalendarConfig request
+	GetCalendarConfigWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetCalendarConfigResponse, error)
+
+	// UpdateCalendarConfig request with any body
+	UpdateCalendarConfigWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*UpdateCalendarConfigResponse, error)
+
+	UpdateCalendarConfigWithResponse(ctx context.Context, body UpdateCalendarConfigJSONRequestBody, reqEditors ...RequestEditorFn) (*UpdateCalendarConfigResponse, error)
+
+	// GetCalendarEvents request
+	GetCalendarEventsWithResponse(ctx context.Context, params *GetCalendarEventsParams, reqEditors ...RequestEditorFn) (*GetCalendarEventsResponse, error)
+
+	// GetCalendarHistory request
+	GetCalendarHistoryWithResponse(ctx context.Context, params *GetCalendarHistoryParams, reqEditors ...RequestEditorFn) (*GetCalendarHistoryResponse, error)
+
 	// MakeHourAvailable request with any body
 	MakeHourAvailableWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*MakeHourAvailableResponse, error)
 
@@ -379,6 +644,96 @@ func (r GetTrainerAvailableHoursResponse) StatusCode() int {
 	return 0
 }
 
+type GetCalendarConfigResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *CalendarConfig
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetCalendarConfigResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetCalendarConfigResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type UpdateCalendarConfigResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r UpdateCalendarConfigResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r UpdateCalendarConfigResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetCalendarEventsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetCalendarEventsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetCalendarEventsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetCalendarHistoryResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *[]AvailabilityChange
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetCalendarHistoryResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetCalendarHistoryResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
 type MakeHourAvailableResponse struct {
 	Body         []byte
 	HTTPResponse *http.Response
@@ -434,6 +789,50 @@ func (c *ClientWithResponses) GetTrainerAvailableHoursWithResponse(ctx context.C
 	return ParseGetTrainerAvailableHoursResponse(rsp)
 }
 
+// GetCalendarConfigWithResponse request returning *GetCalendarConfigResponse
+func (c *ClientWithResponses) GetCalendarConfigWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetCalendarConfigResponse, error) {
+	rsp, err := c.GetCalendarConfig(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetCalendarConfigResponse(rsp)
+}
+
+// UpdateCalendarConfigWithBodyWithResponse request with arbitrary body returning *UpdateCalendarConfigResponse
+func (c *ClientWithResponses) UpdateCalendarConfigWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*UpdateCalendarConfigResponse, error) {
+	rsp, err := c.UpdateCalendarConfigWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseUpdateCalendarConfigResponse(rsp)
+}
+
+func (c *ClientWithResponses) UpdateCalendarConfigWithResponse(ctx context.Context, body UpdateCalendarConfigJSONRequestBody, reqEditors ...RequestEditorFn) (*UpdateCalendarConfigResponse, error) {
+	rsp, err := c.UpdateCalendarConfig(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseUpdateCalendarConfigResponse(rsp)
+}
+
+// GetCalendarEventsWithResponse request returning *GetCalendarEventsResponse
+func (c *ClientWithResponses) GetCalendarEventsWithResponse(ctx context.Context, params *GetCalendarEventsParams, reqEditors ...RequestEditorFn) (*GetCalendarEventsResponse, error) {
+	rsp, err := c.GetCalendarEvents(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetCalendarEventsResponse(rsp)
+}
+
+// GetCalendarHistoryWithResponse request returning *GetCalendarHistoryResponse
+func (c *ClientWithResponses) GetCalendarHistoryWithResponse(ctx context.Context, params *GetCalendarHistoryParams, reqEditors ...RequestEditorFn) (*GetCalendarHistoryResponse, error) {
+	rsp, err := c.GetCalendarHistory(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetCalendarHistoryResponse(rsp)
+}
+
 // MakeHourAvailableWithBodyWithResponse request with arbitrary body returning *MakeHourAvailableResponse
 func (c *ClientWithResponses) MakeHourAvailableWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*MakeHourAvailableResponse, error) {
 	rsp, err := c.MakeHourAvailableWithBody(ctx, contentType, body, reqEditors...)
@@ -501,6 +900,124 @@ func ParseGetTrainerAvailableHoursResponse(rsp *http.Response) (*GetTrainerAvail
 	return response, nil
 }
 
+// ParseGetCalendarConfigResponse parses an HTTP response from a GetCalendarConfigWithResponse call
+func ParseGetCalendarConfigResponse(rsp *http.Response) (*GetCalendarConfigResponse, error) {
+	bodyBytes, err := ioutil.ReadAll(rsp.Body)
+	defer rsp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetCalendarConfigResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest CalendarConfig
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseUpdateCalendarConfigResponse parses an HTTP response from a UpdateCalendarConfigWithResponse call
+func ParseUpdateCalendarConfigResponse(rsp *http.Response) (*UpdateCalendarConfigResponse, error) {
+	bodyBytes, err := ioutil.ReadAll(rsp.Body)
+	defer rsp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &UpdateCalendarConfigResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetCalendarEventsResponse parses an HTTP response from a GetCalendarEventsWithResponse call
+func ParseGetCalendarEventsResponse(rsp *http.Response) (*GetCalendarEventsResponse, error) {
+	bodyBytes, err := ioutil.ReadAll(rsp.Body)
+	defer rsp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetCalendarEventsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetCalendarHistoryResponse parses an HTTP response from a GetCalendarHistoryWithResponse call
+func ParseGetCalendarHistoryResponse(rsp *http.Response) (*GetCalendarHistoryResponse, error) {
+	bodyBytes, err := ioutil.ReadAll(rsp.Body)
+	defer rsp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetCalendarHistoryResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest []AvailabilityChange
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
 // ParseMakeHourAvailableResponse parses an HTTP response from a MakeHourAvailableWithResponse call
 func ParseMakeHourAvailableResponse(rsp *http.Response) (*MakeHourAvailableResponse, error) {
 	bodyBytes, err := ioutil.ReadAll(rsp.Body)
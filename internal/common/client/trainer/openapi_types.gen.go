@@ -13,6 +13,31 @@ const (
 	BearerAuthScopes = "bearerAuth.Scopes"
 )
 
+// AvailabilityChange defines model for AvailabilityChange.
+type AvailabilityChange struct {
+	Actor     string    `json:"actor"`
+	ChangedAt time.Time `json:"changedAt"`
+	Command   string    `json:"command"`
+	From      string    `json:"from"`
+	Hour      time.Time `json:"hour"`
+	To        string    `json:"to"`
+}
+
+// CalendarConfig defines model for CalendarConfig.
+type CalendarConfig struct {
+	MaxTimeToSet             time.Time `json:"maxTimeToSet"`
+	MaxUtcHour               int       `json:"maxUtcHour"`
+	MaxWeeksInTheFutureToSet int       `json:"maxWeeksInTheFutureToSet"`
+	MinUtcHour               int       `json:"minUtcHour"`
+}
+
+// CalendarConfigUpdate defines model for CalendarConfigUpdate.
+type CalendarConfigUpdate struct {
+	MaxUtcHour               int `json:"maxUtcHour"`
+	MaxWeeksInTheFutureToSet int `json:"maxWeeksInTheFutureToSet"`
+	MinUtcHour               int `json:"minUtcHour"`
+}
+
 // Date defines model for Date.
 type Date struct {
 	Date         openapi_types.Date `json:"date"`
@@ -22,6 +47,15 @@ type Date struct {
 
 // Error defines model for Error.
 type Error struct {
+	// Invalid fields of the request, returned only for some of the incorrect input errors.
+	Details *[]FieldError `json:"details,omitempty"`
+	Message string        `json:"message"`
+	Slug    string        `json:"slug"`
+}
+
+// FieldError defines model for FieldError.
+type FieldError struct {
+	Field   string `json:"field"`
 	Message string `json:"message"`
 	Slug    string `json:"slug"`
 }
@@ -44,12 +78,30 @@ type GetTrainerAvailableHoursParams struct {
 	DateTo   time.Time `json:"dateTo"`
 }
 
+// UpdateCalendarConfigJSONBody defines parameters for UpdateCalendarConfig.
+type UpdateCalendarConfigJSONBody CalendarConfigUpdate
+
+// GetCalendarEventsParams defines parameters for GetCalendarEvents.
+type GetCalendarEventsParams struct {
+	DateFrom time.Time `json:"dateFrom"`
+	DateTo   time.Time `json:"dateTo"`
+}
+
+// GetCalendarHistoryParams defines parameters for GetCalendarHistory.
+type GetCalendarHistoryParams struct {
+	DateFrom time.Time `json:"dateFrom"`
+	DateTo   time.Time `json:"dateTo"`
+}
+
 // MakeHourAvailableJSONBody defines parameters for MakeHourAvailable.
 type MakeHourAvailableJSONBody HourUpdate
 
 // MakeHourUnavailableJSONBody defines parameters for MakeHourUnavailable.
 type MakeHourUnavailableJSONBody HourUpdate
 
+// UpdateCalendarConfigJSONRequestBody defines body for UpdateCalendarConfig for application/json ContentType.
+type UpdateCalendarConfigJSONRequestBody UpdateCalendarConfigJSONBody
+
 // MakeHourAvailableJSONRequestBody defines body for MakeHourAvailable for application/json ContentType.
 type MakeHourAvailableJSONRequestBody MakeHourAvailableJSONBody
 
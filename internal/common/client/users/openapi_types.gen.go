@@ -3,13 +3,422 @@
 // Code generated by github.com/deepmap/oapi-codegen version v1.8.2 DO NOT EDIT.
 package users
 
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
 const (
 	BearerAuthScopes = "bearerAuth.Scopes"
 )
 
+// Defines values for LedgerEntryReason.
+const (
+	LedgerEntryReasonBooking LedgerEntryReason = "booking"
+
+	LedgerEntryReasonCancelRefund LedgerEntryReason = "cancel_refund"
+
+	LedgerEntryReasonExpiry LedgerEntryReason = "expiry"
+
+	LedgerEntryReasonManualAdjustment LedgerEntryReason = "manual_adjustment"
+
+	LedgerEntryReasonPurchase LedgerEntryReason = "purchase"
+
+	LedgerEntryReasonTrainerPenalty LedgerEntryReason = "trainer_penalty"
+)
+
+// Defines values for PurchaseStatus.
+const (
+	PurchaseStatusFailed PurchaseStatus = "failed"
+
+	PurchaseStatusPaid PurchaseStatus = "paid"
+
+	PurchaseStatusPending PurchaseStatus = "pending"
+)
+
+// Defines values for RoleChangeRole.
+const (
+	RoleChangeRoleAdmin RoleChangeRole = "admin"
+
+	RoleChangeRoleAttendee RoleChangeRole = "attendee"
+
+	RoleChangeRoleTrainer RoleChangeRole = "trainer"
+)
+
+// Defines values for UserPreferencesNotificationChannel.
+const (
+	UserPreferencesNotificationChannelEmail UserPreferencesNotificationChannel = "email"
+
+	UserPreferencesNotificationChannelPush UserPreferencesNotificationChannel = "push"
+)
+
+// Defines values for UserProfileUpdateNotificationChannel.
+const (
+	UserProfileUpdateNotificationChannelEmail UserProfileUpdateNotificationChannel = "email"
+
+	UserProfileUpdateNotificationChannelPush UserProfileUpdateNotificationChannel = "push"
+)
+
+// CreditPackage defines model for CreditPackage.
+type CreditPackage struct {
+	Credits  int    `json:"credits"`
+	Currency string `json:"currency"`
+	Id       string `json:"id"`
+	Name     string `json:"name"`
+
+	// price in the smallest currency unit, for example in cents
+	Price int `json:"price"`
+}
+
+// CreditsAdjustment defines model for CreditsAdjustment.
+type CreditsAdjustment struct {
+	// positive value grants credits, negative value takes them
+	AmountChange int `json:"amountChange"`
+
+	// explanation of the adjustment, visible in the user's ledger
+	Reason string `json:"reason"`
+}
+
+// Error defines model for Error.
+type Error struct {
+	// Invalid fields of the request, returned only for some of the incorrect input errors.
+	Details *[]FieldError `json:"details,omitempty"`
+	Message string        `json:"message"`
+	Slug    string        `json:"slug"`
+}
+
+// ExpiringCredits defines model for ExpiringCredits.
+type ExpiringCredits struct {
+	Amount    int       `json:"amount"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// FieldError defines model for FieldError.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+	Slug    string `json:"slug"`
+}
+
+// LedgerEntry defines model for LedgerEntry.
+type LedgerEntry struct {
+	Actor        string    `json:"actor"`
+	AmountChange int       `json:"amountChange"`
+	CreatedAt    time.Time `json:"createdAt"`
+
+	// explanation of the manual adjustment
+	Note         *string           `json:"note,omitempty"`
+	OperationId  string            `json:"operationId"`
+	Reason       LedgerEntryReason `json:"reason"`
+	TrainingUuid *string           `json:"trainingUuid,omitempty"`
+}
+
+// LedgerEntryReason defines model for LedgerEntry.Reason.
+type LedgerEntryReason string
+
+// PostPurchase defines model for PostPurchase.
+type PostPurchase struct {
+	PackageId string `json:"packageId"`
+}
+
+// Purchase defines model for Purchase.
+type Purchase struct {
+	CheckoutUrl string         `json:"checkoutUrl"`
+	Credits     int            `json:"credits"`
+	Currency    string         `json:"currency"`
+	PackageId   string         `json:"packageId"`
+	Price       int            `json:"price"`
+	Status      PurchaseStatus `json:"status"`
+	Uuid        string         `json:"uuid"`
+}
+
+// PurchaseStatus defines model for Purchase.Status.
+type PurchaseStatus string
+
+// RoleChange defines model for RoleChange.
+type RoleChange struct {
+	Role RoleChangeRole `json:"role"`
+}
+
+// RoleChangeRole defines model for RoleChange.Role.
+type RoleChangeRole string
+
+// Session defines model for Session.
+type Session struct {
+	// true for the session used to make the request
+	Current    bool      `json:"current"`
+	Id         string    `json:"id"`
+	Ip         string    `json:"ip"`
+	LastSeenAt time.Time `json:"lastSeenAt"`
+	Revoked    bool      `json:"revoked"`
+	StartedAt  time.Time `json:"startedAt"`
+	UserAgent  string    `json:"userAgent"`
+}
+
 // User defines model for User.
 type User struct {
 	Balance     int    `json:"balance"`
 	DisplayName string `json:"displayName"`
+
+	// credits included in the balance, which will expire, ordered by the expiration time
+	ExpiringCredits []ExpiringCredits `json:"expiringCredits"`
+	Preferences     UserPreferences   `json:"preferences"`
+	Role            string            `json:"role"`
+}
+
+// data exported by each service, format of the data is defined by the service
+type UserDataArchive struct {
+	Trainer   UserDataArchive_Trainer   `json:"trainer"`
+	Trainings UserDataArchive_Trainings `json:"trainings"`
+	Users     UserDataArchive_Users     `json:"users"`
+}
+
+// UserDataArchive_Trainer defines model for UserDataArchive.Trainer.
+type UserDataArchive_Trainer struct {
+	AdditionalProperties map[string]interface{} `json:"-"`
+}
+
+// UserDataArchive_Trainings defines model for UserDataArchive.Trainings.
+type UserDataArchive_Trainings struct {
+	AdditionalProperties map[string]interface{} `json:"-"`
+}
+
+// UserDataArchive_Users defines model for UserDataArchive.Users.
+type UserDataArchive_Users struct {
+	AdditionalProperties map[string]interface{} `json:"-"`
+}
+
+// UserDetails defines model for UserDetails.
+type UserDetails struct {
+	Balance     int    `json:"balance"`
+	DisplayName string `json:"displayName"`
+	Email       string `json:"email"`
 	Role        string `json:"role"`
+	Uuid        string `json:"uuid"`
+}
+
+// UserPreferences defines model for UserPreferences.
+type UserPreferences struct {
+	// BCP 47 language tag
+	Locale              string                             `json:"locale"`
+	NotificationChannel UserPreferencesNotificationChannel `json:"notificationChannel"`
+
+	// how long before the training the user is reminded about it, 0 disables reminders
+	ReminderLeadTimeMinutes int `json:"reminderLeadTimeMinutes"`
+
+	// IANA time zone name
+	TimeZone string `json:"timeZone"`
+}
+
+// UserPreferencesNotificationChannel defines model for UserPreferences.NotificationChannel.
+type UserPreferencesNotificationChannel string
+
+// fields which are not provided are not changed
+type UserProfileUpdate struct {
+	// when changed, the display name from the auth provider is not used anymore
+	DisplayName *string `json:"displayName,omitempty"`
+
+	// BCP 47 language tag
+	Locale                  *string                               `json:"locale,omitempty"`
+	NotificationChannel     *UserProfileUpdateNotificationChannel `json:"notificationChannel,omitempty"`
+	ReminderLeadTimeMinutes *int                                  `json:"reminderLeadTimeMinutes,omitempty"`
+
+	// IANA time zone name
+	TimeZone *string `json:"timeZone,omitempty"`
+}
+
+// UserProfileUpdateNotificationChannel defines model for UserProfileUpdate.NotificationChannel.
+type UserProfileUpdateNotificationChannel string
+
+// GetUsersParams defines parameters for GetUsers.
+type GetUsersParams struct {
+	// part of the display name or the email
+	Search *string `json:"search,omitempty"`
+	Limit  *int    `json:"limit,omitempty"`
+}
+
+// AdjustUserCreditsJSONBody defines parameters for AdjustUserCredits.
+type AdjustUserCreditsJSONBody CreditsAdjustment
+
+// ChangeUserRoleJSONBody defines parameters for ChangeUserRole.
+type ChangeUserRoleJSONBody RoleChange
+
+// CreatePurchaseJSONBody defines parameters for CreatePurchase.
+type CreatePurchaseJSONBody PostPurchase
+
+// UpdateCurrentUserJSONBody defines parameters for UpdateCurrentUser.
+type UpdateCurrentUserJSONBody UserProfileUpdate
+
+// AdjustUserCreditsJSONRequestBody defines body for AdjustUserCredits for application/json ContentType.
+type AdjustUserCreditsJSONRequestBody AdjustUserCreditsJSONBody
+
+// ChangeUserRoleJSONRequestBody defines body for ChangeUserRole for application/json ContentType.
+type ChangeUserRoleJSONRequestBody ChangeUserRoleJSONBody
+
+// CreatePurchaseJSONRequestBody defines body for CreatePurchase for application/json ContentType.
+type CreatePurchaseJSONRequestBody CreatePurchaseJSONBody
+
+// UpdateCurrentUserJSONRequestBody defines body for UpdateCurrentUser for application/json ContentType.
+type UpdateCurrentUserJSONRequestBody UpdateCurrentUserJSONBody
+
+// Getter for additional properties for UserDataArchive_Trainer. Returns the specified
+// element and whether it was found
+func (a UserDataArchive_Trainer) Get(fieldName string) (value interface{}, found bool) {
+	if a.AdditionalProperties != nil {
+		value, found = a.AdditionalProperties[fieldName]
+	}
+	return
+}
+
+// Setter for additional properties for UserDataArchive_Trainer
+func (a *UserDataArchive_Trainer) Set(fieldName string, value interface{}) {
+	if a.AdditionalProperties == nil {
+		a.AdditionalProperties = make(map[string]interface{})
+	}
+	a.AdditionalProperties[fieldName] = value
+}
+
+// Override default JSON handling for UserDataArchive_Trainer to handle AdditionalProperties
+func (a *UserDataArchive_Trainer) UnmarshalJSON(b []byte) error {
+	object := make(map[string]json.RawMessage)
+	err := json.Unmarshal(b, &object)
+	if err != nil {
+		return err
+	}
+
+	if len(object) != 0 {
+		a.AdditionalProperties = make(map[string]interface{})
+		for fieldName, fieldBuf := range object {
+			var fieldVal interface{}
+			err := json.Unmarshal(fieldBuf, &fieldVal)
+			if err != nil {
+				return errors.Wrap(err, fmt.Sprintf("error unmarshaling field %s", fieldName))
+			}
+			a.AdditionalProperties[fieldName] = fieldVal
+		}
+	}
+	return nil
+}
+
+// Override default JSON handling for UserDataArchive_Trainer to handle AdditionalProperties
+func (a UserDataArchive_Trainer) MarshalJSON() ([]byte, error) {
+	var err error
+	object := make(map[string]json.RawMessage)
+
+	for fieldName, field := range a.AdditionalProperties {
+		object[fieldName], err = json.Marshal(field)
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("error marshaling '%s'", fieldName))
+		}
+	}
+	return json.Marshal(object)
+}
+
+// Getter for additional properties for UserDataArchive_Trainings. Returns the specified
+// element and whether it was found
+func (a UserDataArchive_Trainings) Get(fieldName string) (value interface{}, found bool) {
+	if a.AdditionalProperties != nil {
+		value, found = a.AdditionalProperties[fieldName]
+	}
+	return
+}
+
+// Setter for additional properties for UserDataArchive_Trainings
+func (a *UserDataArchive_Trainings) Set(fieldName string, value interface{}) {
+	if a.AdditionalProperties == nil {
+		a.AdditionalProperties = make(map[string]interface{})
+	}
+	a.AdditionalProperties[fieldName] = value
+}
+
+// Override default JSON handling for UserDataArchive_Trainings to handle AdditionalProperties
+func (a *UserDataArchive_Trainings) UnmarshalJSON(b []byte) error {
+	object := make(map[string]json.RawMessage)
+	err := json.Unmarshal(b, &object)
+	if err != nil {
+		return err
+	}
+
+	if len(object) != 0 {
+		a.AdditionalProperties = make(map[string]interface{})
+		for fieldName, fieldBuf := range object {
+			var fieldVal interface{}
+			err := json.Unmarshal(fieldBuf, &fieldVal)
+			if err != nil {
+				return errors.Wrap(err, fmt.Sprintf("error unmarshaling field %s", fieldName))
+			}
+			a.AdditionalProperties[fieldName] = fieldVal
+		}
+	}
+	return nil
+}
+
+// Override default JSON handling for UserDataArchive_Trainings to handle AdditionalProperties
+func (a UserDataArchive_Trainings) MarshalJSON() ([]byte, error) {
+	var err error
+	object := make(map[string]json.RawMessage)
+
+	for fieldName, field := range a.AdditionalProperties {
+		object[fieldName], err = json.Marshal(field)
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("error marshaling '%s'", fieldName))
+		}
+	}
+	return json.Marshal(object)
+}
+
+// Getter for additional properties for UserDataArchive_Users. Returns the specified
+// element and whether it was found
+func (a UserDataArchive_Users) Get(fieldName string) (value interface{}, found bool) {
+	if a.AdditionalProperties != nil {
+		value, found = a.AdditionalProperties[fieldName]
+	}
+	return
+}
+
+// Setter for additional properties for UserDataArchive_Users
+func (a *UserDataArchive_Users) Set(fieldName string, value interface{}) {
+	if a.AdditionalProperties == nil {
+		a.AdditionalProperties = make(map[string]interface{})
+	}
+	a.AdditionalProperties[fieldName] = value
+}
+
+// Override default JSON handling for UserDataArchive_Users to handle AdditionalProperties
+func (a *UserDataArchive_Users) UnmarshalJSON(b []byte) error {
+	object := make(map[string]json.RawMessage)
+	err := json.Unmarshal(b, &object)
+	if err != nil {
+		return err
+	}
+
+	if len(object) != 0 {
+		a.AdditionalProperties = make(map[string]interface{})
+		for fieldName, fieldBuf := range object {
+			var fieldVal interface{}
+			err := json.Unmarshal(fieldBuf, &fieldVal)
+			if err != nil {
+				return errors.Wrap(err, fmt.Sprintf("error unmarshaling field %s", fieldName))
+			}
+			a.AdditionalProperties[fieldName] = fieldVal
+		}
+	}
+	return nil
+}
+
+// Override default JSON handling for UserDataArchive_Users to handle AdditionalProperties
+func (a UserDataArchive_Users) MarshalJSON() ([]byte, error) {
+	var err error
+	object := make(map[string]json.RawMessage)
+
+	for fieldName, field := range a.AdditionalProperties {
+		object[fieldName], err = json.Marshal(field)
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("error marshaling '%s'", fieldName))
+		}
+	}
+	return json.Marshal(object)
 }
@@ -9,12 +9,14 @@ import (
 )
 
 func ApplyCommandDecorators[H any](handler CommandHandler[H], logger *logrus.Entry, metricsClient MetricsClient) CommandHandler[H] {
-	return commandLoggingDecorator[H]{
-		base: commandMetricsDecorator[H]{
-			base:   handler,
-			client: metricsClient,
+	return commandTracingDecorator[H]{
+		base: commandLoggingDecorator[H]{
+			base: commandMetricsDecorator[H]{
+				base:   handler,
+				client: metricsClient,
+			},
+			logger: logger,
 		},
-		logger: logger,
 	}
 }
 
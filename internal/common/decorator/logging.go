@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/tracing"
 	"github.com/sirupsen/logrus"
 )
 
@@ -19,6 +20,9 @@ func (d commandLoggingDecorator[C]) Handle(ctx context.Context, cmd C) (err erro
 		"command":      handlerType,
 		"command_body": fmt.Sprintf("%#v", cmd),
 	})
+	if traceID := tracing.TraceID(ctx); traceID != "" {
+		logger = logger.WithField("trace_id", traceID)
+	}
 
 	logger.Debug("Executing command")
 	defer func() {
@@ -42,6 +46,9 @@ func (d queryLoggingDecorator[C, R]) Handle(ctx context.Context, cmd C) (result
 		"query":      generateActionName(cmd),
 		"query_body": fmt.Sprintf("%#v", cmd),
 	})
+	if traceID := tracing.TraceID(ctx); traceID != "" {
+		logger = logger.WithField("trace_id", traceID)
+	}
 
 	logger.Debug("Executing query")
 	defer func() {
@@ -7,12 +7,14 @@ import (
 )
 
 func ApplyQueryDecorators[H any, R any](handler QueryHandler[H, R], logger *logrus.Entry, metricsClient MetricsClient) QueryHandler[H, R] {
-	return queryLoggingDecorator[H, R]{
-		base: queryMetricsDecorator[H, R]{
-			base:   handler,
-			client: metricsClient,
+	return queryTracingDecorator[H, R]{
+		base: queryLoggingDecorator[H, R]{
+			base: queryMetricsDecorator[H, R]{
+				base:   handler,
+				client: metricsClient,
+			},
+			logger: logger,
 		},
-		logger: logger,
 	}
 }
 
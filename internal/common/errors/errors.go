@@ -1,5 +1,9 @@
 package errors
 
+import (
+	stderrors "errors"
+)
+
 type ErrorType struct {
 	t string
 }
@@ -8,12 +12,34 @@ var (
 	ErrorTypeUnknown        = ErrorType{"unknown"}
 	ErrorTypeAuthorization  = ErrorType{"authorization"}
 	ErrorTypeIncorrectInput = ErrorType{"incorrect-input"}
+	ErrorTypeNotFound       = ErrorType{"not-found"}
+	// ErrorTypeForbidden is used when the user is authenticated, but is not allowed to do the action.
+	ErrorTypeForbidden = ErrorType{"forbidden"}
+	// ErrorTypeConflict is used when the action conflicts with an already existing state, like a duplicated operation.
+	ErrorTypeConflict = ErrorType{"conflict"}
+	// ErrorTypePreconditionFailed is used when the request is correct, but it can't be done in the current state,
+	// for example when the hour is not available anymore.
+	ErrorTypePreconditionFailed = ErrorType{"precondition-failed"}
 )
 
+func (t ErrorType) String() string {
+	return t.t
+}
+
+// FieldError describes which field of the request is invalid.
+type FieldError struct {
+	Field   string
+	Slug    string
+	Message string
+}
+
 type SlugError struct {
 	error     string
 	slug      string
 	errorType ErrorType
+
+	// fieldErrors is a pointer, so SlugError stays comparable and can be still used as a sentinel error
+	fieldErrors *[]FieldError
 }
 
 func (s SlugError) Error() string {
@@ -28,6 +54,46 @@ func (s SlugError) ErrorType() ErrorType {
 	return s.errorType
 }
 
+func (s SlugError) FieldErrors() []FieldError {
+	if s.fieldErrors == nil {
+		return nil
+	}
+
+	return *s.fieldErrors
+}
+
+// WithFieldErrors returns a copy of the error with details about invalid fields of the request.
+func (s SlugError) WithFieldErrors(fieldErrors ...FieldError) SlugError {
+	s.fieldErrors = &fieldErrors
+	return s
+}
+
+// TypedError is implemented by domain errors, which can't be created with constructors of SlugError,
+// because they carry additional data or are checked by their type. They are handled in the same way as SlugError.
+type TypedError interface {
+	error
+	Slug() string
+	ErrorType() ErrorType
+}
+
+// AsSlugError returns the first SlugError or TypedError from the chain of wrapped errors.
+func AsSlugError(err error) (SlugError, bool) {
+	var typedErr TypedError
+	if !stderrors.As(err, &typedErr) {
+		return SlugError{}, false
+	}
+
+	if slugErr, ok := typedErr.(SlugError); ok {
+		return slugErr, true
+	}
+
+	return SlugError{
+		error:     typedErr.Error(),
+		slug:      typedErr.Slug(),
+		errorType: typedErr.ErrorType(),
+	}, true
+}
+
 func NewSlugError(error string, slug string) SlugError {
 	return SlugError{
 		error:     error,
@@ -51,3 +117,35 @@ func NewIncorrectInputError(error string, slug string) SlugError {
 		errorType: ErrorTypeIncorrectInput,
 	}
 }
+
+func NewNotFoundError(error string, slug string) SlugError {
+	return SlugError{
+		error:     error,
+		slug:      slug,
+		errorType: ErrorTypeNotFound,
+	}
+}
+
+func NewForbiddenError(error string, slug string) SlugError {
+	return SlugError{
+		error:     error,
+		slug:      slug,
+		errorType: ErrorTypeForbidden,
+	}
+}
+
+func NewConflictError(error string, slug string) SlugError {
+	return SlugError{
+		error:     error,
+		slug:      slug,
+		errorType: ErrorTypeConflict,
+	}
+}
+
+func NewPreconditionFailedError(error string, slug string) SlugError {
+	return SlugError{
+		error:     error,
+		slug:      slug,
+		errorType: ErrorTypePreconditionFailed,
+	}
+}
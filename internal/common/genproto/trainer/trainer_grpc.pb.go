@@ -24,6 +24,9 @@ type TrainerServiceClient interface {
 	ScheduleTraining(ctx context.Context, in *UpdateHourRequest, opts ...grpc.CallOption) (*empty.Empty, error)
 	CancelTraining(ctx context.Context, in *UpdateHourRequest, opts ...grpc.CallOption) (*empty.Empty, error)
 	MakeHourAvailable(ctx context.Context, in *UpdateHourRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	WatchHourAvailability(ctx context.Context, in *WatchHourAvailabilityRequest, opts ...grpc.CallOption) (TrainerService_WatchHourAvailabilityClient, error)
+	ExportUserData(ctx context.Context, in *ExportUserDataRequest, opts ...grpc.CallOption) (*ExportUserDataResponse, error)
+	EraseUserData(ctx context.Context, in *EraseUserDataRequest, opts ...grpc.CallOption) (*empty.Empty, error)
 }
 
 type trainerServiceClient struct {
@@ -70,6 +73,56 @@ func (c *trainerServiceClient) MakeHourAvailable(ctx context.Context, in *Update
 	return out, nil
 }
 
+func (c *trainerServiceClient) WatchHourAvailability(ctx context.Context, in *WatchHourAvailabilityRequest, opts ...grpc.CallOption) (TrainerService_WatchHourAvailabilityClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TrainerService_ServiceDesc.Streams[0], "/trainer.TrainerService/WatchHourAvailability", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &trainerServiceWatchHourAvailabilityClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TrainerService_WatchHourAvailabilityClient interface {
+	Recv() (*HourAvailabilityChange, error)
+	grpc.ClientStream
+}
+
+type trainerServiceWatchHourAvailabilityClient struct {
+	grpc.ClientStream
+}
+
+func (x *trainerServiceWatchHourAvailabilityClient) Recv() (*HourAvailabilityChange, error) {
+	m := new(HourAvailabilityChange)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *trainerServiceClient) ExportUserData(ctx context.Context, in *ExportUserDataRequest, opts ...grpc.CallOption) (*ExportUserDataResponse, error) {
+	out := new(ExportUserDataResponse)
+	err := c.cc.Invoke(ctx, "/trainer.TrainerService/ExportUserData", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trainerServiceClient) EraseUserData(ctx context.Context, in *EraseUserDataRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/trainer.TrainerService/EraseUserData", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TrainerServiceServer is the server API for TrainerService service.
 // All implementations should embed UnimplementedTrainerServiceServer
 // for forward compatibility
@@ -78,6 +131,9 @@ type TrainerServiceServer interface {
 	ScheduleTraining(context.Context, *UpdateHourRequest) (*empty.Empty, error)
 	CancelTraining(context.Context, *UpdateHourRequest) (*empty.Empty, error)
 	MakeHourAvailable(context.Context, *UpdateHourRequest) (*empty.Empty, error)
+	WatchHourAvailability(*WatchHourAvailabilityRequest, TrainerService_WatchHourAvailabilityServer) error
+	ExportUserData(context.Context, *ExportUserDataRequest) (*ExportUserDataResponse, error)
+	EraseUserData(context.Context, *EraseUserDataRequest) (*empty.Empty, error)
 }
 
 // UnimplementedTrainerServiceServer should be embedded to have forward compatible implementations.
@@ -96,6 +152,15 @@ func (UnimplementedTrainerServiceServer) CancelTraining(context.Context, *Update
 func (UnimplementedTrainerServiceServer) MakeHourAvailable(context.Context, *UpdateHourRequest) (*empty.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method MakeHourAvailable not implemented")
 }
+func (UnimplementedTrainerServiceServer) WatchHourAvailability(*WatchHourAvailabilityRequest, TrainerService_WatchHourAvailabilityServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchHourAvailability not implemented")
+}
+func (UnimplementedTrainerServiceServer) ExportUserData(context.Context, *ExportUserDataRequest) (*ExportUserDataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportUserData not implemented")
+}
+func (UnimplementedTrainerServiceServer) EraseUserData(context.Context, *EraseUserDataRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EraseUserData not implemented")
+}
 
 // UnsafeTrainerServiceServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to TrainerServiceServer will
@@ -180,6 +245,63 @@ func _TrainerService_MakeHourAvailable_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TrainerService_WatchHourAvailability_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchHourAvailabilityRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TrainerServiceServer).WatchHourAvailability(m, &trainerServiceWatchHourAvailabilityServer{stream})
+}
+
+type TrainerService_WatchHourAvailabilityServer interface {
+	Send(*HourAvailabilityChange) error
+	grpc.ServerStream
+}
+
+type trainerServiceWatchHourAvailabilityServer struct {
+	grpc.ServerStream
+}
+
+func (x *trainerServiceWatchHourAvailabilityServer) Send(m *HourAvailabilityChange) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TrainerService_ExportUserData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportUserDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrainerServiceServer).ExportUserData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trainer.TrainerService/ExportUserData",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrainerServiceServer).ExportUserData(ctx, req.(*ExportUserDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrainerService_EraseUserData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EraseUserDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrainerServiceServer).EraseUserData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trainer.TrainerService/EraseUserData",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrainerServiceServer).EraseUserData(ctx, req.(*EraseUserDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // TrainerService_ServiceDesc is the grpc.ServiceDesc for TrainerService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -203,7 +325,21 @@ var TrainerService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "MakeHourAvailable",
 			Handler:    _TrainerService_MakeHourAvailable_Handler,
 		},
+		{
+			MethodName: "ExportUserData",
+			Handler:    _TrainerService_ExportUserData_Handler,
+		},
+		{
+			MethodName: "EraseUserData",
+			Handler:    _TrainerService_EraseUserData_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchHourAvailability",
+			Handler:       _TrainerService_WatchHourAvailability_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "trainer.proto",
 }
@@ -0,0 +1,96 @@
+// Package grpcerr passes SlugError between services over gRPC.
+//
+// The slug and the error type are sent in ErrorInfo details of the status,
+// so the client can return the same error to its caller (for example, in the HTTP response).
+package grpcerr
+
+import (
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	errorInfoDomain = "wild-workouts"
+	errorTypeKey    = "error-type"
+)
+
+var errorTypeCodes = map[errors.ErrorType]codes.Code{
+	errors.ErrorTypeAuthorization:      codes.Unauthenticated,
+	errors.ErrorTypeIncorrectInput:     codes.InvalidArgument,
+	errors.ErrorTypeNotFound:           codes.NotFound,
+	errors.ErrorTypeForbidden:          codes.PermissionDenied,
+	errors.ErrorTypeConflict:           codes.Aborted,
+	errors.ErrorTypePreconditionFailed: codes.FailedPrecondition,
+}
+
+var errorConstructors = map[string]func(error string, slug string) errors.SlugError{
+	errors.ErrorTypeAuthorization.String():      errors.NewAuthorizationError,
+	errors.ErrorTypeIncorrectInput.String():     errors.NewIncorrectInputError,
+	errors.ErrorTypeNotFound.String():           errors.NewNotFoundError,
+	errors.ErrorTypeForbidden.String():          errors.NewForbiddenError,
+	errors.ErrorTypeConflict.String():           errors.NewConflictError,
+	errors.ErrorTypePreconditionFailed.String(): errors.NewPreconditionFailedError,
+}
+
+// Status converts the error to the status error, with the code chosen by the type of SlugError.
+// Errors, which are not SlugError, are returned with the Internal code.
+func Status(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	slugErr, ok := errors.AsSlugError(err)
+	if !ok {
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	code, ok := errorTypeCodes[slugErr.ErrorType()]
+	if !ok {
+		code = codes.Internal
+	}
+
+	return slugErrorStatus(code, slugErr)
+}
+
+func slugErrorStatus(code codes.Code, slugErr errors.SlugError) error {
+	st, err := status.New(code, slugErr.Error()).WithDetails(&errdetails.ErrorInfo{
+		Reason:   slugErr.Slug(),
+		Domain:   errorInfoDomain,
+		Metadata: map[string]string{errorTypeKey: slugErr.ErrorType().String()},
+	})
+	if err != nil {
+		return status.Error(code, slugErr.Error())
+	}
+
+	return st.Err()
+}
+
+// SlugError converts the status error returned by Status back to SlugError.
+// Other errors are returned unchanged.
+func SlugError(err error) error {
+	st, ok := status.FromError(err)
+	if !ok || st.Code() == codes.OK {
+		return err
+	}
+
+	for _, detail := range st.Details() {
+		info, ok := detail.(*errdetails.ErrorInfo)
+		if !ok || info.Domain != errorInfoDomain {
+			continue
+		}
+
+		newError, ok := errorConstructors[info.Metadata[errorTypeKey]]
+		if !ok {
+			newError = errors.NewSlugError
+		}
+
+		return newError(st.Message(), info.Reason)
+	}
+
+	return err
+}
@@ -0,0 +1,128 @@
+package grpcerr_test
+
+import (
+	stderrors "errors"
+	"testing"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/errors"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/grpcerr"
+	pkgerrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestStatus(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		Name         string
+		Err          error
+		ExpectedCode codes.Code
+	}{
+		{
+			Name:         "incorrect_input",
+			Err:          errors.NewIncorrectInputError("hour is not available", "hour-not-available"),
+			ExpectedCode: codes.InvalidArgument,
+		},
+		{
+			Name:         "authorization",
+			Err:          errors.NewAuthorizationError("no user in context", "no-user-found"),
+			ExpectedCode: codes.Unauthenticated,
+		},
+		{
+			Name:         "forbidden",
+			Err:          errors.NewForbiddenError("missing permission", "missing-permission"),
+			ExpectedCode: codes.PermissionDenied,
+		},
+		{
+			Name:         "not_found",
+			Err:          errors.NewNotFoundError("user not found", "user-not-found"),
+			ExpectedCode: codes.NotFound,
+		},
+		{
+			Name:         "conflict",
+			Err:          errors.NewConflictError("purchase already completed", "purchase-already-completed"),
+			ExpectedCode: codes.Aborted,
+		},
+		{
+			Name:         "precondition_failed",
+			Err:          errors.NewPreconditionFailedError("hour is not available", "hour-not-available"),
+			ExpectedCode: codes.FailedPrecondition,
+		},
+		{
+			Name:         "typed_error",
+			Err:          pkgerrors.Wrap(testNotFoundError{UUID: "1"}, "unable to get"),
+			ExpectedCode: codes.NotFound,
+		},
+		{
+			Name:         "unknown_slug_error",
+			Err:          errors.NewSlugError("db is down", "unable-to-update-availability"),
+			ExpectedCode: codes.Internal,
+		},
+		{
+			Name:         "wrapped_slug_error",
+			Err:          pkgerrors.Wrap(errors.NewIncorrectInputError("invalid", "invalid-hour"), "unable to schedule"),
+			ExpectedCode: codes.InvalidArgument,
+		},
+		{
+			Name:         "not_slug_error",
+			Err:          stderrors.New("unexpected"),
+			ExpectedCode: codes.Internal,
+		},
+		{
+			Name:         "status_error",
+			Err:          status.Error(codes.Unavailable, "trainer is down"),
+			ExpectedCode: codes.Unavailable,
+		},
+	}
+
+	for _, c := range testCases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, c.ExpectedCode, status.Code(grpcerr.Status(c.Err)))
+		})
+	}
+}
+
+func TestSlugError(t *testing.T) {
+	t.Parallel()
+
+	original := errors.NewPreconditionFailedError("hour is not available", "hour-not-available")
+
+	err := grpcerr.SlugError(grpcerr.Status(original))
+
+	slugErr, ok := err.(errors.SlugError)
+	require.True(t, ok, "expected SlugError, got %T: %v", err, err)
+	assert.Equal(t, original.Slug(), slugErr.Slug())
+	assert.Equal(t, original.ErrorType(), slugErr.ErrorType())
+	assert.Equal(t, original.Error(), slugErr.Error())
+}
+
+func TestSlugError_without_details(t *testing.T) {
+	t.Parallel()
+
+	statusErr := status.Error(codes.Unavailable, "trainer is down")
+	assert.Equal(t, statusErr, grpcerr.SlugError(statusErr))
+
+	assert.NoError(t, grpcerr.SlugError(nil))
+}
+
+type testNotFoundError struct {
+	UUID string
+}
+
+func (e testNotFoundError) Error() string {
+	return "not found: " + e.UUID
+}
+
+func (e testNotFoundError) Slug() string {
+	return "test-not-found"
+}
+
+func (e testNotFoundError) ErrorType() errors.ErrorType {
+	return errors.ErrorTypeNotFound
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/tracing"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/sirupsen/logrus"
 )
@@ -25,6 +26,9 @@ func (l *StructuredLogger) NewLogEntry(r *http.Request) middleware.LogEntry {
 	if reqID := middleware.GetReqID(r.Context()); reqID != "" {
 		logFields["req_id"] = reqID
 	}
+	if traceID := tracing.TraceID(r.Context()); traceID != "" {
+		logFields["trace_id"] = traceID
+	}
 
 	logFields["http_method"] = r.Method
 
@@ -1,7 +1,7 @@
 package metrics
 
+import "time"
+
 type NoOp struct{}
 
-func (d NoOp) Inc(_ string, _ int) {
-	// todo - add some implementation!
-}
+func (d NoOp) ObserveHandler(_ string, _ string, _ time.Duration, _ error) {}
@@ -0,0 +1,140 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// registry contains all metrics of the service. Metrics are registered once, so they can be shared
+// by multiple servers and applications running in the same process (like in component tests).
+var registry = prometheus.NewRegistry()
+
+var (
+	handlerDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "app_handler_duration_seconds",
+		Help:    "Duration of command and query handlers.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"type", "name"})
+	handlerResults = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "app_handler_results_total",
+		Help: "Number of executed command and query handlers by the result (success or failure).",
+	}, []string{"type", "name", "result"})
+
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_server_request_duration_seconds",
+		Help:    "Duration of HTTP requests handled by the server.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "code"})
+
+	grpcHandlingDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "grpc_server_handling_seconds",
+		Help:    "Duration of gRPC calls handled by the server.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"grpc_service", "grpc_method", "grpc_code"})
+	grpcHandled = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_server_handled_total",
+		Help: "Number of gRPC calls handled by the server by the status code.",
+	}, []string{"grpc_service", "grpc_method", "grpc_code"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		handlerDuration,
+		handlerResults,
+		httpRequestDuration,
+		grpcHandlingDuration,
+		grpcHandled,
+	)
+}
+
+// Handler exposes metrics in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// Prometheus records metrics of command and query handlers, which are exposed by Handler.
+type Prometheus struct{}
+
+func (p Prometheus) ObserveHandler(handlerType string, handlerName string, duration time.Duration, err error) {
+	handlerDuration.WithLabelValues(handlerType, handlerName).Observe(duration.Seconds())
+
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	handlerResults.WithLabelValues(handlerType, handlerName, result).Inc()
+}
+
+// HTTPMiddleware records durations of requests by the route pattern, so paths with parameters
+// (like UUIDs) don't create a new time series for each request.
+// It should be used on the root router, as the pattern is complete when the request is handled.
+func HTTPMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		next.ServeHTTP(ww, r)
+
+		route := "unmatched"
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			route = rctx.RoutePattern()
+		}
+
+		code := ww.Status()
+		if code == 0 {
+			code = http.StatusOK
+		}
+
+		httpRequestDuration.
+			WithLabelValues(r.Method, route, strconv.Itoa(code)).
+			Observe(time.Since(start).Seconds())
+	})
+}
+
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+
+		resp, err := handler(ctx, req)
+		observeGRPCCall(info.FullMethod, start, err)
+
+		return resp, err
+	}
+}
+
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+
+		err := handler(srv, stream)
+		observeGRPCCall(info.FullMethod, start, err)
+
+		return err
+	}
+}
+
+func observeGRPCCall(fullMethod string, start time.Time, err error) {
+	// full method is in the "/package.Service/Method" format
+	service, method := "unknown", "unknown"
+	if parts := strings.SplitN(strings.TrimPrefix(fullMethod, "/"), "/", 2); len(parts) == 2 {
+		service, method = parts[0], parts[1]
+	}
+
+	code := status.Code(err).String()
+
+	grpcHandlingDuration.WithLabelValues(service, method, code).Observe(time.Since(start).Seconds())
+	grpcHandled.WithLabelValues(service, method, code).Inc()
+}
@@ -0,0 +1,95 @@
+package metrics_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/metrics"
+	"github.com/go-chi/chi/v5"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPrometheus_ObserveHandler(t *testing.T) {
+	client := metrics.Prometheus{}
+
+	client.ObserveHandler("command", "testobservehandler", time.Millisecond*150, nil)
+	client.ObserveHandler("command", "testobservehandler", time.Millisecond*20, errors.New("failed"))
+
+	exposed := scrapeMetrics(t)
+
+	assert.Contains(t, exposed, `app_handler_results_total{name="testobservehandler",result="success",type="command"} 1`)
+	assert.Contains(t, exposed, `app_handler_results_total{name="testobservehandler",result="failure",type="command"} 1`)
+	assert.Contains(t, exposed, `app_handler_duration_seconds_count{name="testobservehandler",type="command"} 2`)
+	assert.Contains(
+		t,
+		exposed,
+		`app_handler_duration_seconds_bucket{name="testobservehandler",type="command",le="0.1"} 1`,
+		"duration should be recorded with sub-second precision",
+	)
+}
+
+func TestHTTPMiddleware(t *testing.T) {
+	router := chi.NewRouter()
+	router.Use(metrics.HTTPMiddleware)
+	router.Route("/test-http-middleware", func(r chi.Router) {
+		r.Get("/{uuid}", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTeapot)
+		})
+	})
+
+	for _, uuid := range []string{"1", "2"} {
+		router.ServeHTTP(
+			httptest.NewRecorder(),
+			httptest.NewRequest(http.MethodGet, "/test-http-middleware/"+uuid, nil),
+		)
+	}
+
+	assert.Contains(
+		t,
+		scrapeMetrics(t),
+		`http_server_request_duration_seconds_count{code="418",method="GET",route="/test-http-middleware/{uuid}"} 2`,
+		"requests should be grouped by the route pattern",
+	)
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	interceptor := metrics.UnaryServerInterceptor()
+
+	_, err := interceptor(
+		context.Background(),
+		"request",
+		&grpc.UnaryServerInfo{FullMethod: "/test.TestService/TestUnaryServerInterceptor"},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, status.Error(codes.NotFound, "not found")
+		},
+	)
+	require.Error(t, err)
+
+	assert.Contains(
+		t,
+		scrapeMetrics(t),
+		`grpc_server_handled_total{grpc_code="NotFound",grpc_method="TestUnaryServerInterceptor",grpc_service="test.TestService"} 1`,
+	)
+}
+
+func scrapeMetrics(t *testing.T) string {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+
+	return string(body)
+}
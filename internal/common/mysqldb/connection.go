@@ -0,0 +1,28 @@
+package mysqldb
+
+import (
+	"os"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// NewConnection connects to the MySQL database configured with MYSQL_* envs.
+func NewConnection() (*sqlx.DB, error) {
+	config := mysql.NewConfig()
+
+	config.Net = "tcp"
+	config.Addr = os.Getenv("MYSQL_ADDR")
+	config.User = os.Getenv("MYSQL_USER")
+	config.Passwd = os.Getenv("MYSQL_PASSWORD")
+	config.DBName = os.Getenv("MYSQL_DATABASE")
+	config.ParseTime = true // with that parameter, we can scan DATETIME and TIMESTAMP columns to time.Time
+
+	db, err := sqlx.Connect("mysql", config.FormatDSN())
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot connect to MySQL")
+	}
+
+	return db, nil
+}
@@ -0,0 +1,134 @@
+package mysqldb
+
+import (
+	"context"
+	"database/sql"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/multierr"
+)
+
+// Migrate applies all not yet applied migrations from the `migrations` directory of migrationsFS.
+//
+// Migrations are named `<version>_<description>.sql`.
+// Already applied migrations should never be modified - add a new one instead.
+//
+// Applied versions are stored in versionTable, each service sharing the database should use its own table.
+// It's safe to run it from multiple instances at the same time,
+// migrations are applied only by the instance that holds the lock.
+func Migrate(ctx context.Context, db *sqlx.DB, migrationsFS fs.FS, versionTable string) (err error) {
+	migrations, err := loadMigrations(migrationsFS)
+	if err != nil {
+		return err
+	}
+
+	// GET_LOCK is bound to the connection, so we need to be sure that all queries are using the same one
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to get db connection")
+	}
+	defer func() {
+		err = multierr.Combine(err, conn.Close())
+	}()
+
+	var locked int
+	if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, 60)", versionTable).Scan(&locked); err != nil {
+		return errors.Wrap(err, "unable to acquire migrations lock")
+	}
+	if locked != 1 {
+		return errors.New("timed out waiting for migrations lock")
+	}
+	defer func() {
+		_, releaseErr := conn.ExecContext(ctx, "SELECT RELEASE_LOCK(?)", versionTable)
+		err = multierr.Combine(err, releaseErr)
+	}()
+
+	createTable := "CREATE TABLE IF NOT EXISTS `" + versionTable + "` (version INT NOT NULL, PRIMARY KEY (version))"
+	if _, err := conn.ExecContext(ctx, createTable); err != nil {
+		return errors.Wrapf(err, "unable to create %s table", versionTable)
+	}
+
+	var currentVersion int
+	if err := conn.QueryRowContext(ctx, "SELECT COALESCE(MAX(version), 0) FROM `"+versionTable+"`").Scan(&currentVersion); err != nil {
+		return errors.Wrap(err, "unable to get current schema version")
+	}
+
+	for _, m := range migrations {
+		if m.Version <= currentVersion {
+			continue
+		}
+
+		if err := applyMigration(ctx, conn, m, versionTable); err != nil {
+			return errors.Wrapf(err, "unable to apply migration %s", m.Name)
+		}
+
+		logrus.WithField("migration", m.Name).Info("MySQL migration applied")
+	}
+
+	return nil
+}
+
+type migration struct {
+	Version    int
+	Name       string
+	Statements []string
+}
+
+func loadMigrations(migrationsFS fs.FS) ([]migration, error) {
+	files, err := fs.Glob(migrationsFS, "migrations/*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	var migrations []migration
+	for _, file := range files {
+		name := path.Base(file)
+
+		version, err := strconv.Atoi(strings.SplitN(name, "_", 2)[0])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid migration name %s", name)
+		}
+
+		content, err := fs.ReadFile(migrationsFS, file)
+		if err != nil {
+			return nil, err
+		}
+
+		// MySQL driver doesn't support multiple statements in one query
+		var statements []string
+		for _, statement := range strings.Split(string(content), ";") {
+			if strings.TrimSpace(statement) != "" {
+				statements = append(statements, statement)
+			}
+		}
+
+		migrations = append(migrations, migration{
+			Version:    version,
+			Name:       name,
+			Statements: statements,
+		})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+// applyMigration is not using transaction, because in MySQL DDL statements are committing transactions implicitly.
+func applyMigration(ctx context.Context, conn *sql.Conn, m migration, versionTable string) error {
+	for _, statement := range m.Statements {
+		if _, err := conn.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+
+	_, err := conn.ExecContext(ctx, "INSERT INTO `"+versionTable+"` (version) VALUES (?)", m.Version)
+	return err
+}
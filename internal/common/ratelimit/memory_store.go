@@ -0,0 +1,56 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// memoryStoreCleanupInterval is how often expired counters are removed.
+const memoryStoreCleanupInterval = time.Minute
+
+// MemoryStore counts requests only in the current instance of the service.
+type MemoryStore struct {
+	lock        *sync.Mutex
+	counters    map[string]memoryCounter
+	lastCleanup time.Time
+}
+
+type memoryCounter struct {
+	count     int
+	expiresAt time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		lock:        &sync.Mutex{},
+		counters:    map[string]memoryCounter{},
+		lastCleanup: time.Now(),
+	}
+}
+
+func (m *MemoryStore) Increment(_ context.Context, key string, expiresAt time.Time) (int, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	now := time.Now()
+	if now.Sub(m.lastCleanup) > memoryStoreCleanupInterval {
+		m.removeExpired(now)
+	}
+
+	counter := m.counters[key]
+	counter.count++
+	counter.expiresAt = expiresAt
+	m.counters[key] = counter
+
+	return counter.count, nil
+}
+
+func (m *MemoryStore) removeExpired(now time.Time) {
+	for key, counter := range m.counters {
+		if counter.expiresAt.Before(now) {
+			delete(m.counters, key)
+		}
+	}
+	m.lastCleanup = now
+}
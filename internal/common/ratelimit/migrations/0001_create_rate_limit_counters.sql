@@ -0,0 +1,8 @@
+CREATE TABLE `rate_limit_counters`
+(
+    counter_key VARCHAR(255) NOT NULL,
+    count       INT          NOT NULL,
+    expires_at  DATETIME     NOT NULL,
+    PRIMARY KEY (counter_key),
+    INDEX expires_at_idx (expires_at)
+);
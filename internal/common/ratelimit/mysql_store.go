@@ -0,0 +1,89 @@
+package ratelimit
+
+import (
+	"context"
+	"embed"
+	"sync"
+	"time"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/mysqldb"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Migrations are named `<version>_<description>.sql`.
+// Already applied migrations should never be modified - add a new one instead.
+//
+//go:embed migrations/*.sql
+var mysqlMigrations embed.FS
+
+const mysqlMigrationsTable = "ratelimit_schema_migrations"
+
+// mysqlStoreCleanupInterval is how often expired counters are removed.
+const mysqlStoreCleanupInterval = time.Minute
+
+// MigrateMySQL creates the table of counters used by MySQLStore.
+func MigrateMySQL(ctx context.Context, db *sqlx.DB) error {
+	return mysqldb.Migrate(ctx, db, mysqlMigrations, mysqlMigrationsTable)
+}
+
+// MySQLStore counts requests in MySQL, so limits are shared by all instances of the service.
+type MySQLStore struct {
+	db *sqlx.DB
+
+	cleanupLock *sync.Mutex
+	lastCleanup time.Time
+}
+
+func NewMySQLStore(db *sqlx.DB) *MySQLStore {
+	if db == nil {
+		panic("missing db")
+	}
+
+	return &MySQLStore{db: db, cleanupLock: &sync.Mutex{}, lastCleanup: time.Now()}
+}
+
+func (m *MySQLStore) Increment(ctx context.Context, key string, expiresAt time.Time) (int, error) {
+	m.removeExpiredPeriodically(ctx)
+
+	// LAST_INSERT_ID(expr) returns the incremented value in the result of the same query,
+	// so concurrent requests don't need a transaction
+	result, err := m.db.ExecContext(
+		ctx,
+		`INSERT INTO rate_limit_counters (counter_key, count, expires_at) VALUES (?, 1, ?)
+		ON DUPLICATE KEY UPDATE count = LAST_INSERT_ID(count + 1)`,
+		key,
+		expiresAt.UTC(),
+	)
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to increment counter")
+	}
+
+	count, err := result.LastInsertId()
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to get counter value")
+	}
+	// the counter was inserted, LAST_INSERT_ID was not set
+	if count == 0 {
+		count = 1
+	}
+
+	return int(count), nil
+}
+
+func (m *MySQLStore) removeExpiredPeriodically(ctx context.Context) {
+	m.cleanupLock.Lock()
+	if time.Since(m.lastCleanup) < mysqlStoreCleanupInterval {
+		m.cleanupLock.Unlock()
+		return
+	}
+	m.lastCleanup = time.Now()
+	m.cleanupLock.Unlock()
+
+	_, err := m.db.ExecContext(ctx, "DELETE FROM rate_limit_counters WHERE expires_at < ?", time.Now().UTC())
+	if err != nil {
+		// counters are not removed, but it doesn't affect limiting
+		logrus.WithError(err).Warn("Unable to remove expired rate limit counters")
+	}
+}
@@ -0,0 +1,70 @@
+package ratelimit
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Limit allows Requests in each Period.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+func (l Limit) Validate() error {
+	if l.Requests <= 0 {
+		return errors.Errorf("requests of the limit must be positive, got %d", l.Requests)
+	}
+	if l.Period < time.Second {
+		return errors.Errorf("period of the limit must be at least 1s, got %s", l.Period)
+	}
+
+	return nil
+}
+
+// Store counts requests. Shared stores (like MySQLStore) limit requests across all instances of the service.
+type Store interface {
+	// Increment increments the counter of key and returns its new value.
+	// The counter can be removed after expiresAt.
+	Increment(ctx context.Context, key string, expiresAt time.Time) (int, error)
+}
+
+// Limiter counts requests in fixed windows of the limit's period.
+type Limiter struct {
+	store Store
+	now   func() time.Time
+}
+
+func NewLimiter(store Store) Limiter {
+	if store == nil {
+		panic("nil store")
+	}
+
+	return Limiter{store: store, now: time.Now}
+}
+
+// Allow counts the request of key and returns false when it's over the limit,
+// with the time after which the client can retry.
+func (l Limiter) Allow(ctx context.Context, key string, limit Limit) (allowed bool, retryAfter time.Duration, err error) {
+	if err := limit.Validate(); err != nil {
+		return false, 0, err
+	}
+
+	now := l.now()
+	windowStart := now.Truncate(limit.Period)
+	windowEnd := windowStart.Add(limit.Period)
+
+	count, err := l.store.Increment(ctx, key+":"+strconv.FormatInt(windowStart.Unix(), 10), windowEnd)
+	if err != nil {
+		return false, 0, errors.Wrap(err, "unable to count request")
+	}
+
+	if count > limit.Requests {
+		return false, windowEnd.Sub(now), nil
+	}
+
+	return true, 0, nil
+}
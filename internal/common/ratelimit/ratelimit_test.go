@@ -0,0 +1,39 @@
+package ratelimit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter_Allow(t *testing.T) {
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore())
+	limit := ratelimit.Limit{Requests: 3, Period: time.Hour}
+	ctx := context.Background()
+
+	for i := 0; i < limit.Requests; i++ {
+		allowed, _, err := limiter.Allow(ctx, "user:1", limit)
+		require.NoError(t, err)
+		assert.True(t, allowed, "request %d should be allowed", i+1)
+	}
+
+	allowed, retryAfter, err := limiter.Allow(ctx, "user:1", limit)
+	require.NoError(t, err)
+	assert.False(t, allowed)
+	assert.True(t, retryAfter > 0 && retryAfter <= limit.Period, "unexpected retryAfter: %s", retryAfter)
+
+	allowed, _, err = limiter.Allow(ctx, "user:2", limit)
+	require.NoError(t, err)
+	assert.True(t, allowed, "keys should be limited separately")
+}
+
+func TestLimiter_Allow_invalid_limit(t *testing.T) {
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore())
+
+	_, _, err := limiter.Allow(context.Background(), "user:1", ratelimit.Limit{Requests: 0, Period: time.Minute})
+	assert.Error(t, err)
+}
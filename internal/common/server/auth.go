@@ -0,0 +1,176 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	firebase "firebase.google.com/go/v4"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/auth"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc/credentials"
+)
+
+func newFirebaseHttpMiddleware() auth.FirebaseHttpMiddleware {
+	var opts []option.ClientOption
+	if file := os.Getenv("SERVICE_ACCOUNT_FILE"); file != "" {
+		opts = append(opts, option.WithCredentialsFile(file))
+	}
+
+	config := &firebase.Config{ProjectID: os.Getenv("GCP_PROJECT")}
+	firebaseApp, err := firebase.NewApp(context.Background(), config, opts...)
+	if err != nil {
+		logrus.Fatalf("error initializing app: %v\n", err)
+	}
+
+	authClient, err := firebaseApp.Auth(context.Background())
+	if err != nil {
+		logrus.WithError(err).Fatal("Unable to create firebase Auth client")
+	}
+
+	return auth.FirebaseHttpMiddleware{AuthClient: authClient}
+}
+
+// newOIDCHttpMiddleware is configured with OIDC_* envs.
+// Keys are loaded from OIDC_JWKS_FILE if it's set, otherwise they are fetched from OIDC_JWKS_URL.
+func newOIDCHttpMiddleware() auth.OIDCHttpMiddleware {
+	var keySet auth.KeySet
+	if file := os.Getenv("OIDC_JWKS_FILE"); file != "" {
+		var err error
+		keySet, err = auth.NewFileKeySet(file)
+		if err != nil {
+			logrus.WithError(err).Fatal("Unable to load JWKS")
+		}
+	} else if url := os.Getenv("OIDC_JWKS_URL"); url != "" {
+		keySet = auth.NewRemoteKeySet(url, &http.Client{Timeout: time.Second * 10}, time.Minute)
+	} else {
+		logrus.Fatal("OIDC_JWKS_FILE or OIDC_JWKS_URL is required")
+	}
+
+	issuer := os.Getenv("OIDC_ISSUER")
+	audience := os.Getenv("OIDC_AUDIENCE")
+	if issuer == "" || audience == "" {
+		logrus.Fatal("OIDC_ISSUER and OIDC_AUDIENCE are required")
+	}
+
+	return auth.NewOIDCHttpMiddleware(keySet, auth.OIDCConfig{
+		Issuer:   issuer,
+		Audience: audience,
+		Claims:   oidcClaimMappingFromEnv(),
+	})
+}
+
+// oidcClaimMappingFromEnv overrides the default claim mapping with OIDC_CLAIM_* envs, which are set.
+func oidcClaimMappingFromEnv() auth.ClaimMapping {
+	mapping := auth.DefaultClaimMapping()
+
+	overrides := map[string]*string{
+		"OIDC_CLAIM_USER_ID":      &mapping.UUID,
+		"OIDC_CLAIM_EMAIL":        &mapping.Email,
+		"OIDC_CLAIM_ROLE":         &mapping.Role,
+		"OIDC_CLAIM_DISPLAY_NAME": &mapping.DisplayName,
+		"OIDC_CLAIM_SESSION_ID":   &mapping.SessionID,
+	}
+	for env, claim := range overrides {
+		if value := os.Getenv(env); value != "" {
+			*claim = value
+		}
+	}
+
+	return mapping
+}
+
+const (
+	googleIssuer  = "https://accounts.google.com"
+	googleJWKSURL = "https://www.googleapis.com/oauth2/v3/certs"
+)
+
+// newGrpcServiceAuth is configured with GRPC_AUTH env:
+//   - "id-token" (default) verifies identity tokens, by default issued by Google for Cloud Run service accounts,
+//   - "mtls" requires client certificates signed by the CA from GRPC_TLS_CA_FILE,
+//   - "insecure" disables authentication, it's intended only for the local environment.
+//
+// It returns nil auth for the insecure mode and nil credentials, when the server doesn't terminate TLS on its own.
+func newGrpcServiceAuth(allowedCallers map[string][]string) (*auth.GrpcServiceAuth, credentials.TransportCredentials) {
+	mode := strings.ToLower(os.Getenv("GRPC_AUTH"))
+
+	var authenticator auth.ServiceAuthenticator
+	var creds credentials.TransportCredentials
+
+	switch mode {
+	case "", "id-token":
+		audience := os.Getenv("GRPC_AUTH_AUDIENCE")
+		if audience == "" {
+			logrus.Fatal("GRPC_AUTH_AUDIENCE is required")
+		}
+
+		issuer := os.Getenv("GRPC_AUTH_ISSUER")
+		if issuer == "" {
+			issuer = googleIssuer
+		}
+		jwksURL := os.Getenv("GRPC_AUTH_JWKS_URL")
+		if jwksURL == "" {
+			jwksURL = googleJWKSURL
+		}
+
+		keySet := auth.NewRemoteKeySet(jwksURL, &http.Client{Timeout: time.Second * 10}, time.Minute)
+		authenticator = auth.NewIDTokenServiceAuthenticator(keySet, issuer, audience)
+	case "mtls":
+		tlsConfig, err := auth.LoadMTLSConfig(
+			os.Getenv("GRPC_TLS_CERT_FILE"),
+			os.Getenv("GRPC_TLS_KEY_FILE"),
+			os.Getenv("GRPC_TLS_CA_FILE"),
+		)
+		if err != nil {
+			logrus.WithError(err).Fatal("Unable to configure mTLS")
+		}
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		tlsConfig.ClientCAs = tlsConfig.RootCAs
+
+		authenticator = auth.MTLSServiceAuthenticator{}
+		creds = credentials.NewTLS(tlsConfig)
+	case "insecure":
+		logrus.Warn("gRPC calls are not authenticated, don't use GRPC_AUTH=insecure outside of the local environment")
+		return nil, nil
+	default:
+		logrus.Fatalf("gRPC auth '%s' is not supported", mode)
+	}
+
+	serviceAuth := auth.NewGrpcServiceAuth(authenticator, allowedCallerIdentities(allowedCallers))
+
+	return &serviceAuth, creds
+}
+
+// allowedCallerIdentities replaces names of services with their identities from GRPC_SERVICE_IDENTITIES,
+// for example "trainings=trainings@project.iam.gserviceaccount.com;users=users@project.iam.gserviceaccount.com".
+func allowedCallerIdentities(allowedCallers map[string][]string) map[string][]string {
+	identities := map[string]string{}
+	for _, pair := range strings.Split(os.Getenv("GRPC_SERVICE_IDENTITIES"), ";") {
+		if pair == "" {
+			continue
+		}
+
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			logrus.Fatalf("invalid service identity '%s', expected name=identity", pair)
+		}
+		identities[parts[0]] = parts[1]
+	}
+
+	allowed := map[string][]string{}
+	for method, services := range allowedCallers {
+		for _, service := range services {
+			identity, ok := identities[service]
+			if !ok {
+				identity = service
+			}
+			allowed[method] = append(allowed[method], identity)
+		}
+	}
+
+	return allowed
+}
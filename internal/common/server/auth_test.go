@@ -0,0 +1,150 @@
+package server_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/genproto/trainer"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/server"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestRunGRPCServerOnAddr_id_token_auth(t *testing.T) {
+	const (
+		issuer            = "https://issuer.example.com"
+		audience          = "https://trainer-grpc.example.com"
+		trainingsIdentity = "trainings-service@project.iam.gserviceaccount.com"
+		usersIdentity     = "users-service@project.iam.gserviceaccount.com"
+	)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	t.Setenv("GRPC_AUTH", "id-token")
+	t.Setenv("GRPC_AUTH_ISSUER", issuer)
+	t.Setenv("GRPC_AUTH_AUDIENCE", audience)
+	t.Setenv("GRPC_AUTH_JWKS_URL", newTestJWKSServer(t, key))
+	t.Setenv("GRPC_SERVICE_IDENTITIES", "trainings="+trainingsIdentity+";users="+usersIdentity)
+
+	addr := freeAddr(t)
+
+	go server.RunGRPCServerOnAddr(
+		addr,
+		func(server *grpc.Server) {
+			trainer.RegisterTrainerServiceServer(server, trainer.UnimplementedTrainerServiceServer{})
+		},
+		server.WithAllowedCallers(map[string][]string{
+			"/trainer.TrainerService/IsHourAvailable": {"trainings"},
+		}),
+	)
+
+	conn, err := grpc.Dial(addr, grpc.WithInsecure())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.Eventually(t, func() bool {
+		_, err := grpc_health_v1.NewHealthClient(conn).Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+		return err == nil
+	}, time.Second*5, time.Millisecond*10)
+
+	token := func(email string, audience string) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+			"iss":   issuer,
+			"aud":   audience,
+			"email": email,
+			"iat":   time.Now().Unix(),
+			"exp":   time.Now().Add(time.Hour).Unix(),
+		})
+		token.Header["kid"] = "test-key"
+
+		signed, err := token.SignedString(key)
+		require.NoError(t, err)
+
+		return signed
+	}
+
+	testCases := []struct {
+		Name          string
+		Authorization string
+		ExpectedCode  codes.Code
+	}{
+		{
+			Name:          "allowed_service",
+			Authorization: "Bearer " + token(trainingsIdentity, audience),
+			// the call is authorized, so it reaches the not implemented handler
+			ExpectedCode: codes.Unimplemented,
+		},
+		{
+			Name:          "not_allowed_service",
+			Authorization: "Bearer " + token(usersIdentity, audience),
+			ExpectedCode:  codes.PermissionDenied,
+		},
+		{
+			Name:          "token_for_another_service",
+			Authorization: "Bearer " + token(trainingsIdentity, "https://users-grpc.example.com"),
+			ExpectedCode:  codes.Unauthenticated,
+		},
+		{
+			Name:         "missing_token",
+			ExpectedCode: codes.Unauthenticated,
+		},
+	}
+
+	client := trainer.NewTrainerServiceClient(conn)
+
+	for _, c := range testCases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			ctx := context.Background()
+			if c.Authorization != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, "authorization", c.Authorization)
+			}
+
+			_, err := client.IsHourAvailable(ctx, &trainer.IsHourAvailableRequest{})
+			assert.Equal(t, c.ExpectedCode, status.Code(err), "unexpected error: %v", err)
+		})
+	}
+}
+
+func newTestJWKSServer(t *testing.T, key *rsa.PrivateKey) string {
+	t.Helper()
+
+	encode := func(i *big.Int) string {
+		return base64.RawURLEncoding.EncodeToString(i.Bytes())
+	}
+
+	jwks, err := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{
+			{
+				"kty": "RSA",
+				"kid": "test-key",
+				"use": "sig",
+				"n":   encode(key.N),
+				"e":   encode(big.NewInt(int64(key.E))),
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(jwks)
+	}))
+	t.Cleanup(server.Close)
+
+	return server.URL
+}
@@ -1,16 +1,22 @@
 package server
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"os"
+	"time"
 
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/logs"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/metrics"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/tracing"
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	grpc_logrus "github.com/grpc-ecosystem/go-grpc-middleware/logging/logrus"
 	grpc_ctxtags "github.com/grpc-ecosystem/go-grpc-middleware/tags"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
 )
 
 func init() {
@@ -21,34 +27,165 @@ func init() {
 	grpc_logrus.ReplaceGrpcLogger(logrus.NewEntry(logger))
 }
 
-func RunGRPCServer(registerServer func(server *grpc.Server)) {
+type GRPCServerOption func(config *grpcServerConfig)
+
+type grpcServerConfig struct {
+	allowedCallers map[string][]string
+	shutdownHooks  []func()
+	onShutdown     []func()
+	healthChecks   []HealthCheck
+}
+
+// WithAllowedCallers allows services to call methods. Keys are full method names, like "/users.UsersService/GetUser",
+// values are names of services ("trainer", "trainings" or "users"). Calls to methods, which are not listed, are denied.
+//
+// Identities of services are configured with GRPC_SERVICE_IDENTITIES, a service without configured identity
+// is identified by its name (which is expected to be the common name of its certificate, when mTLS is used).
+func WithAllowedCallers(allowedCallers map[string][]string) GRPCServerOption {
+	return func(config *grpcServerConfig) {
+		config.allowedCallers = allowedCallers
+	}
+}
+
+// WithGRPCShutdownHooks adds functions (like closing clients of other services), which are run
+// when the server is stopped and in-flight calls are finished.
+func WithGRPCShutdownHooks(hooks ...func()) GRPCServerOption {
+	return func(config *grpcServerConfig) {
+		config.shutdownHooks = append(config.shutdownHooks, hooks...)
+	}
+}
+
+// WithGRPCOnShutdown adds functions, which are called when the shutdown starts, before waiting for in-flight calls.
+// They should finish long-lived calls (like streams), which otherwise would be waited for until the shutdown timeout.
+func WithGRPCOnShutdown(hooks ...func()) GRPCServerOption {
+	return func(config *grpcServerConfig) {
+		config.onShutdown = append(config.onShutdown, hooks...)
+	}
+}
+
+// WithGRPCHealthChecks adds checks of dependencies, which decide if services registered on the server
+// are reported as serving by the grpc.health.v1.Health service.
+func WithGRPCHealthChecks(checks ...HealthCheck) GRPCServerOption {
+	return func(config *grpcServerConfig) {
+		config.healthChecks = append(config.healthChecks, checks...)
+	}
+}
+
+// RunGRPCServer runs the server until SIGTERM or SIGINT is received, then it gracefully stops it.
+func RunGRPCServer(registerServer func(server *grpc.Server), opts ...GRPCServerOption) {
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
 	}
 	addr := fmt.Sprintf(":%s", port)
-	RunGRPCServerOnAddr(addr, registerServer)
+	RunGRPCServerOnAddr(addr, registerServer, opts...)
 }
 
-func RunGRPCServerOnAddr(addr string, registerServer func(server *grpc.Server)) {
+func RunGRPCServerOnAddr(addr string, registerServer func(server *grpc.Server), opts ...GRPCServerOption) {
+	config := &grpcServerConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+
 	logrusEntry := logrus.NewEntry(logrus.StandardLogger())
 
-	grpcServer := grpc.NewServer(
-		grpc_middleware.WithUnaryServerChain(
-			grpc_ctxtags.UnaryServerInterceptor(grpc_ctxtags.WithFieldExtractor(grpc_ctxtags.CodeGenRequestFieldExtractor)),
-			grpc_logrus.UnaryServerInterceptor(logrusEntry),
-		),
-		grpc_middleware.WithStreamServerChain(
-			grpc_ctxtags.StreamServerInterceptor(grpc_ctxtags.WithFieldExtractor(grpc_ctxtags.CodeGenRequestFieldExtractor)),
-			grpc_logrus.StreamServerInterceptor(logrusEntry),
-		),
+	unaryInterceptors := []grpc.UnaryServerInterceptor{
+		tracing.UnaryServerInterceptor(),
+		metrics.UnaryServerInterceptor(),
+		grpc_ctxtags.UnaryServerInterceptor(grpc_ctxtags.WithFieldExtractor(grpc_ctxtags.CodeGenRequestFieldExtractor)),
+		grpc_logrus.UnaryServerInterceptor(logrusEntry),
+	}
+	streamInterceptors := []grpc.StreamServerInterceptor{
+		tracing.StreamServerInterceptor(),
+		metrics.StreamServerInterceptor(),
+		grpc_ctxtags.StreamServerInterceptor(grpc_ctxtags.WithFieldExtractor(grpc_ctxtags.CodeGenRequestFieldExtractor)),
+		grpc_logrus.StreamServerInterceptor(logrusEntry),
+	}
+
+	var serverOpts []grpc.ServerOption
+
+	serviceAuth, creds := newGrpcServiceAuth(config.allowedCallers)
+	if serviceAuth != nil {
+		// probes of the platform are not authenticated as any of our services
+		*serviceAuth = serviceAuth.AllowUnauthenticated(grpc_health_v1.Health_ServiceDesc.ServiceName)
+
+		// added after the logging interceptors, so denied calls are logged too
+		unaryInterceptors = append(unaryInterceptors, serviceAuth.UnaryServerInterceptor())
+		streamInterceptors = append(streamInterceptors, serviceAuth.StreamServerInterceptor())
+	}
+	if creds != nil {
+		serverOpts = append(serverOpts, grpc.Creds(creds))
+	}
+
+	serverOpts = append(
+		serverOpts,
+		grpc_middleware.WithUnaryServerChain(unaryInterceptors...),
+		grpc_middleware.WithStreamServerChain(streamInterceptors...),
 	)
+
+	grpcServer := grpc.NewServer(serverOpts...)
 	registerServer(grpcServer)
 
+	var services []string
+	for service := range grpcServer.GetServiceInfo() {
+		services = append(services, service)
+	}
+
+	healthServer := health.NewServer()
+	grpc_health_v1.RegisterHealthServer(grpcServer, healthServer)
+
+	healthCtx, stopHealthChecks := context.WithCancel(context.Background())
+	defer stopHealthChecks()
+	go watchGRPCHealth(healthCtx, healthServer, services, config.healthChecks)
+
 	listen, err := net.Listen("tcp", addr)
 	if err != nil {
 		logrus.Fatal(err)
 	}
-	logrus.WithField("grpcEndpoint", addr).Info("Starting: gRPC Listener")
-	logrus.Fatal(grpcServer.Serve(listen))
+	serverErr := make(chan error, 1)
+	go func() {
+		logrus.WithField("grpcEndpoint", addr).Info("Starting: gRPC Listener")
+		serverErr <- grpcServer.Serve(listen)
+	}()
+
+	metricsServer := runMetricsServer()
+
+	if err := waitForShutdown(serverErr); err != nil {
+		logrus.Fatal(err)
+	}
+
+	logrus.Info("Shutting down gRPC server")
+
+	// clients watching the health are notified that the server is going away
+	stopHealthChecks()
+	healthServer.Shutdown()
+	for _, hook := range config.onShutdown {
+		hook()
+	}
+	gracefulStopGRPCServer(grpcServer, shutdownTimeout())
+
+	if metricsServer != nil {
+		// metrics are not important enough to wait for scrapes in progress
+		_ = metricsServer.Close()
+	}
+
+	runShutdownHooks(config.shutdownHooks)
+
+	logrus.Info("gRPC server stopped")
+}
+
+// gracefulStopGRPCServer waits for in-flight calls, and cancels them when they are not finished before the timeout.
+func gracefulStopGRPCServer(grpcServer *grpc.Server, timeout time.Duration) {
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(timeout):
+		logrus.Warn("gRPC server was not stopped gracefully, canceling in-flight calls")
+		grpcServer.Stop()
+	}
 }
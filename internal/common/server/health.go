@@ -0,0 +1,193 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/go-chi/chi/v5"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	healthCheckTimeout  = 3 * time.Second
+	healthCheckInterval = 10 * time.Second
+)
+
+// HealthCheck checks if a dependency, which is required to serve requests, is available.
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+func FirestoreHealthCheck(client *firestore.Client) HealthCheck {
+	return HealthCheck{
+		Name: "firestore",
+		Check: func(ctx context.Context) error {
+			_, err := client.Collections(ctx).Next()
+			if err != nil && err != iterator.Done {
+				return errors.Wrap(err, "firestore is not reachable")
+			}
+
+			return nil
+		},
+	}
+}
+
+func MySQLHealthCheck(db *sqlx.DB) HealthCheck {
+	return HealthCheck{
+		Name: "mysql",
+		Check: func(ctx context.Context) error {
+			return errors.Wrap(db.PingContext(ctx), "mysql is not reachable")
+		},
+	}
+}
+
+// GRPCServiceHealthCheck checks if the server of another service is serving.
+//
+// Only the overall health of the server is checked, not the readiness of its services.
+// Services are calling each other, so when one would wait for readiness of the other, none of them would be ready.
+func GRPCServiceHealthCheck(name string, client grpc_health_v1.HealthClient) HealthCheck {
+	return HealthCheck{
+		Name: name,
+		Check: func(ctx context.Context) error {
+			resp, err := client.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+			if err != nil {
+				return errors.Wrapf(err, "%s is not reachable", name)
+			}
+			if resp.Status != grpc_health_v1.HealthCheckResponse_SERVING {
+				return errors.Errorf("%s is %s", name, resp.Status)
+			}
+
+			return nil
+		},
+	}
+}
+
+// runHealthChecks runs all checks concurrently and returns errors of the failed ones by their names.
+func runHealthChecks(ctx context.Context, checks []HealthCheck) map[string]error {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	type result struct {
+		name string
+		err  error
+	}
+
+	results := make(chan result, len(checks))
+	for _, check := range checks {
+		check := check
+		go func() {
+			results <- result{name: check.Name, err: check.Check(ctx)}
+		}()
+	}
+
+	failed := map[string]error{}
+	for range checks {
+		r := <-results
+		if r.err != nil {
+			failed[r.name] = r.err
+		}
+	}
+
+	return failed
+}
+
+type healthResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// mountHealthRoutes mounts the liveness and readiness endpoints. They are not authenticated,
+// so they can be used by probes of the platform running the service.
+//
+// Liveness reports only that the server is running. Readiness runs the checks and responds with 503,
+// when any of them fails.
+func mountHealthRoutes(router chi.Router, checks []HealthCheck) {
+	router.Get("/health/live", func(w http.ResponseWriter, r *http.Request) {
+		writeHealthResponse(w, http.StatusOK, healthResponse{Status: "ok"})
+	})
+
+	router.Get("/health/ready", func(w http.ResponseWriter, r *http.Request) {
+		failed := runHealthChecks(r.Context(), checks)
+
+		resp := healthResponse{Status: "ok", Checks: map[string]string{}}
+		for _, check := range checks {
+			resp.Checks[check.Name] = "ok"
+		}
+		for name, err := range failed {
+			resp.Checks[name] = err.Error()
+		}
+
+		if len(failed) > 0 {
+			logrus.WithField("failed_checks", resp.Checks).Warn("Service is not ready")
+
+			resp.Status = "unavailable"
+			writeHealthResponse(w, http.StatusServiceUnavailable, resp)
+			return
+		}
+
+		writeHealthResponse(w, http.StatusOK, resp)
+	})
+}
+
+func writeHealthResponse(w http.ResponseWriter, code int, resp healthResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// watchGRPCHealth updates statuses of services in the gRPC health server, until ctx is done.
+//
+// The overall status of the server (empty service name) is always serving, so it can be used as liveness.
+// Statuses of registered services depend on checks, so they can be used as readiness.
+func watchGRPCHealth(ctx context.Context, healthServer *health.Server, services []string, checks []HealthCheck) {
+	healthServer.SetServingStatus("", grpc_health_v1.HealthCheckResponse_SERVING)
+
+	sort.Strings(services)
+	lastStatus := grpc_health_v1.HealthCheckResponse_UNKNOWN
+
+	ticker := time.NewTicker(healthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		status := grpc_health_v1.HealthCheckResponse_SERVING
+
+		failed := runHealthChecks(ctx, checks)
+		if len(failed) > 0 {
+			status = grpc_health_v1.HealthCheckResponse_NOT_SERVING
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		if status != lastStatus {
+			logrus.WithFields(logrus.Fields{
+				"services":      services,
+				"status":        status,
+				"failed_checks": failed,
+			}).Info("gRPC services health changed")
+		}
+		lastStatus = status
+
+		for _, service := range services {
+			healthServer.SetServingStatus(service, status)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
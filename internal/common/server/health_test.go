@@ -0,0 +1,111 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/server"
+	"github.com/go-chi/chi/v5"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestRunHTTPServerOnAddr_health(t *testing.T) {
+	t.Setenv("MOCK_AUTH", "true")
+
+	addr := freeAddr(t)
+
+	go server.RunHTTPServerOnAddr(
+		addr,
+		func(router chi.Router) http.Handler {
+			return router
+		},
+		server.WithHTTPHealthChecks(
+			server.HealthCheck{Name: "working", Check: func(ctx context.Context) error {
+				return nil
+			}},
+			server.HealthCheck{Name: "broken", Check: func(ctx context.Context) error {
+				return errors.New("connection refused")
+			}},
+		),
+	)
+
+	var liveResp *http.Response
+	require.Eventually(t, func() bool {
+		var err error
+		liveResp, err = http.Get("http://" + addr + "/health/live")
+		return err == nil
+	}, time.Second*5, time.Millisecond*10)
+	_ = liveResp.Body.Close()
+	assert.Equal(t, http.StatusOK, liveResp.StatusCode, "liveness should not depend on checks")
+
+	readyResp, err := http.Get("http://" + addr + "/health/ready")
+	require.NoError(t, err)
+	defer readyResp.Body.Close()
+
+	assert.Equal(t, http.StatusServiceUnavailable, readyResp.StatusCode)
+
+	var body struct {
+		Status string            `json:"status"`
+		Checks map[string]string `json:"checks"`
+	}
+	require.NoError(t, json.NewDecoder(readyResp.Body).Decode(&body))
+
+	assert.Equal(t, "unavailable", body.Status)
+	assert.Equal(t, map[string]string{
+		"working": "ok",
+		"broken":  "connection refused",
+	}, body.Checks)
+}
+
+func TestRunGRPCServerOnAddr_health(t *testing.T) {
+	t.Setenv("GRPC_AUTH", "insecure")
+
+	addr := freeAddr(t)
+
+	const testService = "test.TestService"
+
+	go server.RunGRPCServerOnAddr(
+		addr,
+		func(server *grpc.Server) {
+			server.RegisterService(&grpc.ServiceDesc{
+				ServiceName: testService,
+				HandlerType: (*interface{})(nil),
+			}, struct{}{})
+		},
+		server.WithGRPCHealthChecks(server.HealthCheck{Name: "broken", Check: func(ctx context.Context) error {
+			return errors.New("connection refused")
+		}}),
+	)
+
+	conn, err := grpc.Dial(addr, grpc.WithInsecure())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	healthClient := grpc_health_v1.NewHealthClient(conn)
+
+	checkStatus := func(service string) grpc_health_v1.HealthCheckResponse_ServingStatus {
+		resp, err := healthClient.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: service})
+		if err != nil {
+			return grpc_health_v1.HealthCheckResponse_UNKNOWN
+		}
+		return resp.Status
+	}
+
+	require.Eventually(t, func() bool {
+		return checkStatus(testService) == grpc_health_v1.HealthCheckResponse_NOT_SERVING
+	}, time.Second*5, time.Millisecond*10, "service should not be serving when a check fails")
+
+	assert.Equal(
+		t,
+		grpc_health_v1.HealthCheckResponse_SERVING,
+		checkStatus(""),
+		"the overall status of the server should not depend on checks",
+	)
+}
@@ -6,42 +6,165 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
-	firebase "firebase.google.com/go/v4"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/auth"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/logs"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/metrics"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/ratelimit"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/tracing"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
 	"github.com/sirupsen/logrus"
-	"google.golang.org/api/option"
 )
 
-func RunHTTPServer(createHandler func(router chi.Router) http.Handler) {
-	RunHTTPServerOnAddr(":"+os.Getenv("PORT"), createHandler)
+type HTTPServerOption func(config *httpServerConfig)
+
+// sessionRevocationCacheTTL is how long a revoked session may be still used by services other than users.
+const sessionRevocationCacheTTL = time.Second * 10
+
+type httpServerConfig struct {
+	publicRoutes             []func(rootRouter chi.Router, limitByIP func(http.Handler) http.Handler)
+	sessionRevocationChecker auth.SessionRevocationChecker
+	authenticatedMiddlewares []func(http.Handler) http.Handler
+	shutdownHooks            []func()
+	onShutdown               []func()
+	healthChecks             []HealthCheck
+	rateLimits               map[string]ratelimit.Limit
+}
+
+// WithPublicRoutes mounts routes, which are not authenticated with the user's token, under the pattern path.
+// It's intended for endpoints called by external systems (like webhooks), which need to verify requests on their own.
+func WithPublicRoutes(pattern string, createHandler func(router chi.Router) http.Handler) HTTPServerOption {
+	return func(config *httpServerConfig) {
+		config.publicRoutes = append(config.publicRoutes, func(rootRouter chi.Router, limitByIP func(http.Handler) http.Handler) {
+			publicRouter := chi.NewRouter()
+			setBaseMiddlewares(publicRouter, limitByIP)
+
+			rootRouter.Mount(pattern, createHandler(publicRouter))
+		})
+	}
+}
+
+// WithAuthenticatedMiddlewares adds middlewares to the API router, which are run after the user is authenticated,
+// so they can use the user from the context.
+func WithAuthenticatedMiddlewares(middlewares ...func(http.Handler) http.Handler) HTTPServerOption {
+	return func(config *httpServerConfig) {
+		config.authenticatedMiddlewares = append(config.authenticatedMiddlewares, middlewares...)
+	}
+}
+
+// WithSessionRevocationCheck rejects requests done with sessions revoked by the user.
+// It's used by services, which don't track sessions on their own.
+func WithSessionRevocationCheck(checker auth.SessionRevocationChecker) HTTPServerOption {
+	return func(config *httpServerConfig) {
+		config.sessionRevocationChecker = checker
+	}
+}
+
+// WithHTTPShutdownHooks adds functions (like closing clients of other services), which are run
+// when the server is stopped and in-flight requests are finished.
+func WithHTTPShutdownHooks(hooks ...func()) HTTPServerOption {
+	return func(config *httpServerConfig) {
+		config.shutdownHooks = append(config.shutdownHooks, hooks...)
+	}
+}
+
+// WithHTTPOnShutdown adds functions, which are called when the shutdown starts, before waiting for in-flight requests.
+// They should finish long-lived requests (like streams), which otherwise would be waited for until the shutdown timeout.
+func WithHTTPOnShutdown(hooks ...func()) HTTPServerOption {
+	return func(config *httpServerConfig) {
+		config.onShutdown = append(config.onShutdown, hooks...)
+	}
+}
+
+// WithHTTPHealthChecks adds checks of dependencies, which are run by the /health/ready endpoint.
+func WithHTTPHealthChecks(checks ...HealthCheck) HTTPServerOption {
+	return func(config *httpServerConfig) {
+		config.healthChecks = append(config.healthChecks, checks...)
+	}
+}
+
+// RunHTTPServer runs the server until SIGTERM or SIGINT is received, then it gracefully shuts it down.
+func RunHTTPServer(createHandler func(router chi.Router) http.Handler, opts ...HTTPServerOption) {
+	RunHTTPServerOnAddr(":"+os.Getenv("PORT"), createHandler, opts...)
 }
 
-func RunHTTPServerOnAddr(addr string, createHandler func(router chi.Router) http.Handler) {
+func RunHTTPServerOnAddr(addr string, createHandler func(router chi.Router) http.Handler, opts ...HTTPServerOption) {
+	config := &httpServerConfig{rateLimits: map[string]ratelimit.Limit{}}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	rateLimits, err := newRateLimitMiddleware(config.rateLimits)
+	if err != nil {
+		logrus.WithError(err).Fatal("Unable to configure rate limits")
+	}
+
 	apiRouter := chi.NewRouter()
-	setMiddlewares(apiRouter)
+	setMiddlewares(apiRouter, rateLimits.IPMiddleware)
+
+	// added before other authenticated middlewares, so limited requests don't do any work
+	apiRouter.Use(rateLimits.UserMiddleware)
+
+	if config.sessionRevocationChecker != nil {
+		sessionRevocation := auth.NewSessionRevocationMiddleware(config.sessionRevocationChecker, sessionRevocationCacheTTL)
+		apiRouter.Use(sessionRevocation.Middleware)
+	}
+
+	apiRouter.Use(config.authenticatedMiddlewares...)
 
 	rootRouter := chi.NewRouter()
+	rootRouter.Use(tracing.HTTPMiddleware, metrics.HTTPMiddleware)
+
 	// we are mounting all APIs under /api path
 	rootRouter.Mount("/api", createHandler(apiRouter))
 
-	logrus.Info("Starting HTTP server")
+	mountHealthRoutes(rootRouter, config.healthChecks)
 
-	err := http.ListenAndServe(addr, rootRouter)
-	if err != nil {
+	for _, mountPublicRoutes := range config.publicRoutes {
+		mountPublicRoutes(rootRouter, rateLimits.IPMiddleware)
+	}
+
+	httpServer := &http.Server{Addr: addr, Handler: rootRouter}
+	for _, hook := range config.onShutdown {
+		httpServer.RegisterOnShutdown(hook)
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		logrus.Info("Starting HTTP server")
+		serverErr <- httpServer.ListenAndServe()
+	}()
+
+	metricsServer := runMetricsServer()
+
+	if err := waitForShutdown(serverErr); err != nil {
 		logrus.WithError(err).Panic("Unable to start HTTP server")
 	}
+
+	logrus.Info("Shutting down HTTP server")
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout())
+	defer cancel()
+
+	if err := httpServer.Shutdown(ctx); err != nil {
+		logrus.WithError(err).Warn("HTTP server was not shut down gracefully")
+	}
+
+	if metricsServer != nil {
+		// metrics are not important enough to wait for scrapes in progress
+		_ = metricsServer.Close()
+	}
+
+	runShutdownHooks(config.shutdownHooks)
+
+	logrus.Info("HTTP server stopped")
 }
 
-func setMiddlewares(router *chi.Mux) {
-	router.Use(middleware.RequestID)
-	router.Use(middleware.RealIP)
-	router.Use(logs.NewStructuredLogger(logrus.StandardLogger()))
-	router.Use(middleware.Recoverer)
+func setMiddlewares(router *chi.Mux, limitByIP func(http.Handler) http.Handler) {
+	setBaseMiddlewares(router, limitByIP)
 
 	addCorsMiddleware(router)
 	addAuthMiddleware(router)
@@ -53,29 +176,31 @@ func setMiddlewares(router *chi.Mux) {
 	router.Use(middleware.NoCache)
 }
 
+// setBaseMiddlewares limits requests by IP after RealIP, but before the auth, so unauthenticated requests are limited too.
+func setBaseMiddlewares(router *chi.Mux, limitByIP func(http.Handler) http.Handler) {
+	router.Use(middleware.RequestID)
+	router.Use(middleware.RealIP)
+	router.Use(logs.NewStructuredLogger(logrus.StandardLogger()))
+	router.Use(middleware.Recoverer)
+	router.Use(limitByIP)
+}
+
 func addAuthMiddleware(router *chi.Mux) {
 	if mockAuth, _ := strconv.ParseBool(os.Getenv("MOCK_AUTH")); mockAuth {
 		router.Use(auth.HttpMockMiddleware)
 		return
 	}
 
-	var opts []option.ClientOption
-	if file := os.Getenv("SERVICE_ACCOUNT_FILE"); file != "" {
-		opts = append(opts, option.WithCredentialsFile(file))
-	}
-
-	config := &firebase.Config{ProjectID: os.Getenv("GCP_PROJECT")}
-	firebaseApp, err := firebase.NewApp(context.Background(), config, opts...)
-	if err != nil {
-		logrus.Fatalf("error initializing app: %v\n", err)
-	}
+	provider := strings.ToLower(os.Getenv("AUTH_PROVIDER"))
 
-	authClient, err := firebaseApp.Auth(context.Background())
-	if err != nil {
-		logrus.WithError(err).Fatal("Unable to create firebase Auth client")
+	switch provider {
+	case "", "firebase":
+		router.Use(newFirebaseHttpMiddleware().Middleware)
+	case "oidc":
+		router.Use(newOIDCHttpMiddleware().Middleware)
+	default:
+		logrus.Fatalf("auth provider '%s' is not supported", provider)
 	}
-
-	router.Use(auth.FirebaseHttpMiddleware{authClient}.Middleware)
 }
 
 func addCorsMiddleware(router *chi.Mux) {
@@ -86,7 +211,7 @@ func addCorsMiddleware(router *chi.Mux) {
 
 	corsMiddleware := cors.New(cors.Options{
 		AllowedOrigins:   allowedOrigins,
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
 		ExposedHeaders:   []string{"Link"},
 		AllowCredentials: true,
@@ -1,7 +1,10 @@
 package httperr
 
 import (
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/errors"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/logs"
@@ -20,8 +23,36 @@ func BadRequest(slug string, err error, w http.ResponseWriter, r *http.Request)
 	httpRespondWithError(err, slug, w, r, "Bad request", http.StatusBadRequest)
 }
 
+func NotFound(slug string, err error, w http.ResponseWriter, r *http.Request) {
+	httpRespondWithError(err, slug, w, r, "Not found", http.StatusNotFound)
+}
+
+func Forbidden(slug string, err error, w http.ResponseWriter, r *http.Request) {
+	httpRespondWithError(err, slug, w, r, "Forbidden", http.StatusForbidden)
+}
+
+func Conflict(slug string, err error, w http.ResponseWriter, r *http.Request) {
+	httpRespondWithError(err, slug, w, r, "Conflict", http.StatusConflict)
+}
+
+// PreconditionFailed responds with 422, because 412 is reserved for conditional requests (like If-Match header).
+func PreconditionFailed(slug string, err error, w http.ResponseWriter, r *http.Request) {
+	httpRespondWithError(err, slug, w, r, "Precondition failed", http.StatusUnprocessableEntity)
+}
+
+// TooManyRequests responds with 429 and Retry-After header, rounded up to whole seconds.
+func TooManyRequests(slug string, err error, retryAfter time.Duration, w http.ResponseWriter, r *http.Request) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+
+	httpRespondWithError(err, slug, w, r, "Too many requests", http.StatusTooManyRequests)
+}
+
 func RespondWithSlugError(err error, w http.ResponseWriter, r *http.Request) {
-	slugError, ok := err.(errors.SlugError)
+	slugError, ok := errors.AsSlugError(err)
 	if !ok {
 		InternalError("internal-server-error", err, w, r)
 		return
@@ -29,17 +60,40 @@ func RespondWithSlugError(err error, w http.ResponseWriter, r *http.Request) {
 
 	switch slugError.ErrorType() {
 	case errors.ErrorTypeAuthorization:
-		Unauthorised(slugError.Slug(), slugError, w, r)
+		Unauthorised(slugError.Slug(), err, w, r)
 	case errors.ErrorTypeIncorrectInput:
-		BadRequest(slugError.Slug(), slugError, w, r)
+		BadRequest(slugError.Slug(), err, w, r)
+	case errors.ErrorTypeNotFound:
+		NotFound(slugError.Slug(), err, w, r)
+	case errors.ErrorTypeForbidden:
+		Forbidden(slugError.Slug(), err, w, r)
+	case errors.ErrorTypeConflict:
+		Conflict(slugError.Slug(), err, w, r)
+	case errors.ErrorTypePreconditionFailed:
+		PreconditionFailed(slugError.Slug(), err, w, r)
 	default:
-		InternalError(slugError.Slug(), slugError, w, r)
+		InternalError(slugError.Slug(), err, w, r)
 	}
 }
 
 func httpRespondWithError(err error, slug string, w http.ResponseWriter, r *http.Request, logMSg string, status int) {
 	logs.GetLogEntry(r).WithError(err).WithField("error-slug", slug).Warn(logMSg)
-	resp := ErrorResponse{slug, status}
+
+	resp := ErrorResponse{Slug: slug, Message: logMSg, httpStatus: status}
+	if slugError, ok := errors.AsSlugError(err); ok {
+		// Only messages of slug errors are written for the client. Other errors (and messages of internal errors)
+		// may contain details of the infrastructure or of the token verification, so they are only logged.
+		if status != http.StatusInternalServerError {
+			resp.Message = slugError.Error()
+		}
+		for _, fieldErr := range slugError.FieldErrors() {
+			resp.Details = append(resp.Details, FieldErrorResponse{
+				Field:   fieldErr.Field,
+				Slug:    fieldErr.Slug,
+				Message: fieldErr.Message,
+			})
+		}
+	}
 
 	if err := render.Render(w, r, resp); err != nil {
 		panic(err)
@@ -47,10 +101,19 @@ func httpRespondWithError(err error, slug string, w http.ResponseWriter, r *http
 }
 
 type ErrorResponse struct {
-	Slug       string `json:"slug"`
+	Slug    string               `json:"slug"`
+	Message string               `json:"message"`
+	Details []FieldErrorResponse `json:"details,omitempty"`
+
 	httpStatus int
 }
 
+type FieldErrorResponse struct {
+	Field   string `json:"field"`
+	Slug    string `json:"slug"`
+	Message string `json:"message"`
+}
+
 func (e ErrorResponse) Render(w http.ResponseWriter, r *http.Request) error {
 	w.WriteHeader(e.httpStatus)
 	return nil
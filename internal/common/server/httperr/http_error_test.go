@@ -0,0 +1,174 @@
+package httperr_test
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/errors"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/logs"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/server/httperr"
+	pkgerrors "github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRespondWithSlugError(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		Name             string
+		Err              error
+		ExpectedStatus   int
+		ExpectedResponse httperr.ErrorResponse
+	}{
+		{
+			Name:             "incorrect_input",
+			Err:              errors.NewIncorrectInputError("invalid time zone 'Mars'", "invalid-time-zone"),
+			ExpectedStatus:   http.StatusBadRequest,
+			ExpectedResponse: httperr.ErrorResponse{Slug: "invalid-time-zone", Message: "invalid time zone 'Mars'"},
+		},
+		{
+			Name:             "authorization",
+			Err:              errors.NewAuthorizationError("no user in context", "no-user-found"),
+			ExpectedStatus:   http.StatusUnauthorized,
+			ExpectedResponse: httperr.ErrorResponse{Slug: "no-user-found", Message: "no user in context"},
+		},
+		{
+			Name:             "forbidden",
+			Err:              errors.NewForbiddenError("missing permission", "missing-permission"),
+			ExpectedStatus:   http.StatusForbidden,
+			ExpectedResponse: httperr.ErrorResponse{Slug: "missing-permission", Message: "missing permission"},
+		},
+		{
+			Name:             "not_found",
+			Err:              pkgerrors.Wrap(testNotFoundError{}, "unable to get"),
+			ExpectedStatus:   http.StatusNotFound,
+			ExpectedResponse: httperr.ErrorResponse{Slug: "test-not-found", Message: "test not found"},
+		},
+		{
+			Name:             "conflict",
+			Err:              errors.NewConflictError("purchase is already paid", "purchase-already-paid"),
+			ExpectedStatus:   http.StatusConflict,
+			ExpectedResponse: httperr.ErrorResponse{Slug: "purchase-already-paid", Message: "purchase is already paid"},
+		},
+		{
+			Name:             "precondition_failed",
+			Err:              errors.NewPreconditionFailedError("hour is not available", "hour-not-available"),
+			ExpectedStatus:   http.StatusUnprocessableEntity,
+			ExpectedResponse: httperr.ErrorResponse{Slug: "hour-not-available", Message: "hour is not available"},
+		},
+		{
+			Name: "field_errors",
+			Err: errors.NewIncorrectInputError("invalid time zone 'Mars'", "invalid-time-zone").WithFieldErrors(
+				errors.FieldError{Field: "timeZone", Slug: "invalid-time-zone", Message: "invalid time zone 'Mars'"},
+			),
+			ExpectedStatus: http.StatusBadRequest,
+			ExpectedResponse: httperr.ErrorResponse{
+				Slug:    "invalid-time-zone",
+				Message: "invalid time zone 'Mars'",
+				Details: []httperr.FieldErrorResponse{
+					{Field: "timeZone", Slug: "invalid-time-zone", Message: "invalid time zone 'Mars'"},
+				},
+			},
+		},
+		{
+			Name:             "wrapped_slug_error",
+			Err:              pkgerrors.Wrap(errors.NewAuthorizationError("invalid token", "unable-to-verify-jwt"), "crypto/rsa: verification error"),
+			ExpectedStatus:   http.StatusUnauthorized,
+			ExpectedResponse: httperr.ErrorResponse{Slug: "unable-to-verify-jwt", Message: "invalid token"},
+		},
+		{
+			Name:             "unknown_slug_error",
+			Err:              errors.NewSlugError("connection to db refused", "unable-to-update-availability"),
+			ExpectedStatus:   http.StatusInternalServerError,
+			ExpectedResponse: httperr.ErrorResponse{Slug: "unable-to-update-availability", Message: "Internal server error"},
+		},
+		{
+			Name:             "not_slug_error",
+			Err:              stderrors.New("connection to db refused"),
+			ExpectedStatus:   http.StatusInternalServerError,
+			ExpectedResponse: httperr.ErrorResponse{Slug: "internal-server-error", Message: "Internal server error"},
+		},
+	}
+
+	for _, c := range testCases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := logs.NewStructuredLogger(newTestLogger())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				httperr.RespondWithSlugError(c.Err, w, r)
+			}))
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assert.Equal(t, c.ExpectedStatus, rec.Code)
+
+			var resp httperr.ErrorResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			assert.Equal(t, c.ExpectedResponse, resp)
+		})
+	}
+}
+
+func TestTooManyRequests(t *testing.T) {
+	t.Parallel()
+
+	handler := logs.NewStructuredLogger(newTestLogger())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		httperr.TooManyRequests("rate-limit-exceeded", stderrors.New("too many requests"), time.Millisecond*1500, w, r)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("Retry-After"), "retry after should be rounded up to whole seconds")
+
+	var resp httperr.ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, httperr.ErrorResponse{Slug: "rate-limit-exceeded", Message: "Too many requests"}, resp)
+}
+
+func TestUnauthorised_hides_message_of_not_slug_error(t *testing.T) {
+	t.Parallel()
+
+	handler := logs.NewStructuredLogger(newTestLogger())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		httperr.Unauthorised("unable-to-verify-jwt", stderrors.New("unable to fetch JWKS from https://issuer"), w, r)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	var resp httperr.ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, httperr.ErrorResponse{Slug: "unable-to-verify-jwt", Message: "Unauthorised"}, resp)
+}
+
+func newTestLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return logger
+}
+
+type testNotFoundError struct{}
+
+func (e testNotFoundError) Error() string {
+	return "test not found"
+}
+
+func (e testNotFoundError) Slug() string {
+	return "test-not-found"
+}
+
+func (e testNotFoundError) ErrorType() errors.ErrorType {
+	return errors.ErrorTypeNotFound
+}
@@ -0,0 +1,33 @@
+package server
+
+import (
+	"net/http"
+	"os"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/metrics"
+	"github.com/go-chi/chi/v5"
+	"github.com/sirupsen/logrus"
+)
+
+// runMetricsServer serves metrics on METRICS_ADDR (like ":9090"). Metrics are not served on the port of the server,
+// as it's public for HTTP servers (on Cloud Run only the port of the server is exposed) and can't serve HTTP
+// requests for gRPC servers. When METRICS_ADDR is not set, metrics are not exposed and nil is returned.
+func runMetricsServer() *http.Server {
+	addr := os.Getenv("METRICS_ADDR")
+	if addr == "" {
+		return nil
+	}
+
+	router := chi.NewRouter()
+	router.Handle("/metrics", metrics.Handler())
+
+	metricsServer := &http.Server{Addr: addr, Handler: router}
+	go func() {
+		logrus.WithField("metricsEndpoint", addr).Info("Starting: metrics HTTP server")
+		if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logrus.WithError(err).Error("Metrics HTTP server failed")
+		}
+	}()
+
+	return metricsServer
+}
@@ -0,0 +1,251 @@
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/auth"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/mysqldb"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/ratelimit"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/server/httperr"
+	"github.com/go-chi/chi/v5"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// defaultIPRateLimit limits all requests from one IP, before they are authenticated.
+// It's high enough for users behind a shared IP, it should only stop floods of requests.
+var defaultIPRateLimit = ratelimit.Limit{Requests: 600, Period: time.Minute}
+
+// WithRateLimits limits requests to API routes. Keys are methods with route patterns relative to /api,
+// like "POST /trainings" or "PUT /trainer/calendar/make-hour-available".
+//
+// Requests to these routes are counted for each authenticated user.
+// Limits can be changed or added with RATE_LIMITS env, like "POST /trainings=10/1m;PUT /trainer/calendar=5/1h".
+//
+// Independently, all requests to API and public routes are counted for each IP before they are authenticated.
+// This limit can be changed with RATE_LIMIT_PER_IP env, like "100/1m".
+func WithRateLimits(limits map[string]ratelimit.Limit) HTTPServerOption {
+	return func(config *httpServerConfig) {
+		for route, limit := range limits {
+			config.rateLimits[route] = limit
+		}
+	}
+}
+
+type rateLimitMiddleware struct {
+	limiter ratelimit.Limiter
+	ipLimit ratelimit.Limit
+	limits  map[string]ratelimit.Limit
+
+	// routes contains only routes with limits, it's used to match requests to route patterns
+	routes *chi.Mux
+}
+
+func newRateLimitMiddleware(limits map[string]ratelimit.Limit) (*rateLimitMiddleware, error) {
+	limits, err := rateLimitsFromEnv(limits)
+	if err != nil {
+		return nil, err
+	}
+
+	ipLimit, err := ipRateLimitFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	m := &rateLimitMiddleware{
+		ipLimit: ipLimit,
+		limits:  map[string]ratelimit.Limit{},
+		routes:  chi.NewRouter(),
+	}
+
+	for route, limit := range limits {
+		parts := strings.Fields(route)
+		if len(parts) != 2 {
+			return nil, errors.Errorf("invalid rate limited route '%s', expected method and pattern", route)
+		}
+		method, pattern := strings.ToUpper(parts[0]), parts[1]
+
+		if err := limit.Validate(); err != nil {
+			return nil, errors.Wrapf(err, "invalid rate limit of '%s'", route)
+		}
+
+		m.routes.MethodFunc(method, pattern, func(w http.ResponseWriter, r *http.Request) {})
+		m.limits[method+" "+pattern] = limit
+	}
+
+	m.limiter = ratelimit.NewLimiter(newRateLimitStore())
+
+	return m, nil
+}
+
+// IPMiddleware limits all requests from the IP set by the RealIP middleware.
+// It's added before the auth middleware, so requests with invalid tokens are counted too.
+func (m rateLimitMiddleware) IPMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !m.allow(w, r, "ip:"+clientIP(r), m.ipLimit) {
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// UserMiddleware limits requests of the authenticated user to routes with limits.
+func (m rateLimitMiddleware) UserMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, err := auth.UserFromCtx(r.Context())
+		if err != nil {
+			// the middleware is added after the auth middleware, so it should not happen
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// the API router is mounted, so the path of the route is shorter than the URL path
+		routePath := r.URL.Path
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePath != "" {
+			routePath = rctx.RoutePath
+		}
+
+		matched := chi.NewRouteContext()
+		if !m.routes.Match(matched, r.Method, routePath) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		route := r.Method + " " + matched.RoutePattern()
+		if !m.allow(w, r, route+":user:"+user.UUID, m.limits[route]) {
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// allow responds with 429, when the limit of the key is exceeded.
+func (m rateLimitMiddleware) allow(w http.ResponseWriter, r *http.Request, key string, limit ratelimit.Limit) bool {
+	allowed, retryAfter, err := m.limiter.Allow(r.Context(), key, limit)
+	if err != nil {
+		// it's better to allow too many requests, than to stop serving them when the store is not available
+		logrus.WithError(err).Warn("Unable to check rate limit")
+		return true
+	}
+	if !allowed {
+		httperr.TooManyRequests(
+			"rate-limit-exceeded",
+			errors.Errorf("limit of %d requests per %s exceeded", limit.Requests, limit.Period),
+			retryAfter,
+			w,
+			r,
+		)
+		return false
+	}
+
+	return true
+}
+
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		// RealIP middleware sets RemoteAddr without the port
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// newRateLimitStore is configured with RATE_LIMIT_STORE env:
+//   - "memory" (default) counts requests in each instance separately,
+//   - "mysql" counts requests in the database configured with MYSQL_* envs, so limits are shared by all instances.
+func newRateLimitStore() ratelimit.Store {
+	store := strings.ToLower(os.Getenv("RATE_LIMIT_STORE"))
+
+	switch store {
+	case "", "memory":
+		return ratelimit.NewMemoryStore()
+	case "mysql":
+		db, err := mysqldb.NewConnection()
+		if err != nil {
+			logrus.WithError(err).Fatal("Unable to connect to rate limit store")
+		}
+
+		if err := ratelimit.MigrateMySQL(context.Background(), db); err != nil {
+			logrus.WithError(err).Fatal("Unable to migrate rate limit store")
+		}
+
+		return ratelimit.NewMySQLStore(db)
+	default:
+		logrus.Fatalf("rate limit store '%s' is not supported", store)
+		return nil
+	}
+}
+
+// rateLimitsFromEnv overrides limits with RATE_LIMITS env.
+func rateLimitsFromEnv(limits map[string]ratelimit.Limit) (map[string]ratelimit.Limit, error) {
+	merged := map[string]ratelimit.Limit{}
+	for route, limit := range limits {
+		merged[route] = limit
+	}
+
+	for _, entry := range strings.Split(os.Getenv("RATE_LIMITS"), ";") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 {
+			return nil, errors.Errorf("invalid rate limit '%s', expected route=requests/period", entry)
+		}
+
+		limit, err := parseRateLimit(parts[1])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid rate limit of '%s'", parts[0])
+		}
+		merged[strings.TrimSpace(parts[0])] = limit
+	}
+
+	return merged, nil
+}
+
+// ipRateLimitFromEnv returns defaultIPRateLimit, when RATE_LIMIT_PER_IP env is not set.
+func ipRateLimitFromEnv() (ratelimit.Limit, error) {
+	env := os.Getenv("RATE_LIMIT_PER_IP")
+	if env == "" {
+		return defaultIPRateLimit, nil
+	}
+
+	limit, err := parseRateLimit(env)
+	if err != nil {
+		return ratelimit.Limit{}, errors.Wrap(err, "invalid RATE_LIMIT_PER_IP")
+	}
+	if err := limit.Validate(); err != nil {
+		return ratelimit.Limit{}, errors.Wrap(err, "invalid RATE_LIMIT_PER_IP")
+	}
+
+	return limit, nil
+}
+
+// parseRateLimit parses limits like "10/1m".
+func parseRateLimit(s string) (ratelimit.Limit, error) {
+	parts := strings.SplitN(strings.TrimSpace(s), "/", 2)
+	if len(parts) != 2 {
+		return ratelimit.Limit{}, errors.Errorf("expected requests/period, got '%s'", s)
+	}
+
+	requests, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return ratelimit.Limit{}, errors.Wrap(err, "invalid number of requests")
+	}
+
+	period, err := time.ParseDuration(parts[1])
+	if err != nil {
+		return ratelimit.Limit{}, errors.Wrap(err, "invalid period")
+	}
+
+	return ratelimit.Limit{Requests: requests, Period: period}, nil
+}
@@ -0,0 +1,128 @@
+package server_test
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/ratelimit"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/server"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/tests"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunHTTPServerOnAddr_rate_limits(t *testing.T) {
+	t.Setenv("MOCK_AUTH", "true")
+
+	addr := freeAddr(t)
+
+	go server.RunHTTPServerOnAddr(
+		addr,
+		func(router chi.Router) http.Handler {
+			router.Get("/trainings", func(w http.ResponseWriter, r *http.Request) {})
+			router.Post("/trainings", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			})
+			return router
+		},
+		server.WithRateLimits(map[string]ratelimit.Limit{
+			"POST /trainings": {Requests: 2, Period: time.Hour},
+		}),
+	)
+
+	request := func(method string, token string) *http.Response {
+		req, err := http.NewRequest(method, "http://"+addr+"/api/trainings", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+
+		return resp
+	}
+
+	require.Eventually(t, func() bool {
+		_, err := http.Get("http://" + addr + "/health/live")
+		return err == nil
+	}, time.Second*5, time.Millisecond*10)
+
+	attendee := tests.FakeAttendeeJWT(t, "attendee-uuid")
+	otherAttendee := tests.FakeAttendeeJWT(t, "other-attendee-uuid")
+
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, http.StatusNoContent, request(http.MethodPost, attendee).StatusCode)
+	}
+
+	limited := request(http.MethodPost, attendee)
+	assert.Equal(t, http.StatusTooManyRequests, limited.StatusCode)
+
+	retryAfter, err := strconv.Atoi(limited.Header.Get("Retry-After"))
+	require.NoError(t, err)
+	assert.True(t, retryAfter > 0 && retryAfter <= 3600, "unexpected Retry-After: %d", retryAfter)
+
+	assert.Equal(t, http.StatusOK, request(http.MethodGet, attendee).StatusCode, "other routes should not be limited")
+	assert.Equal(t, http.StatusNoContent, request(http.MethodPost, otherAttendee).StatusCode, "users should be limited separately")
+}
+
+func TestRunHTTPServerOnAddr_rate_limits_by_ip_before_auth(t *testing.T) {
+	t.Setenv("MOCK_AUTH", "true")
+	t.Setenv("RATE_LIMIT_PER_IP", "3/1h")
+
+	addr := freeAddr(t)
+
+	go server.RunHTTPServerOnAddr(
+		addr,
+		func(router chi.Router) http.Handler {
+			router.Get("/trainings", func(w http.ResponseWriter, r *http.Request) {})
+			return router
+		},
+		server.WithPublicRoutes("/webhooks", func(router chi.Router) http.Handler {
+			router.Post("/payments", func(w http.ResponseWriter, r *http.Request) {})
+			return router
+		}),
+	)
+
+	require.Eventually(t, func() bool {
+		_, err := http.Get("http://" + addr + "/health/live")
+		return err == nil
+	}, time.Second*5, time.Millisecond*10)
+
+	request := func(method string, path string, ip string) int {
+		req, err := http.NewRequest(method, "http://"+addr+path, nil)
+		require.NoError(t, err)
+		req.Header.Set("X-Real-IP", ip)
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+
+		return resp.StatusCode
+	}
+
+	for i := 0; i < 3; i++ {
+		assert.NotEqual(t, http.StatusTooManyRequests, request(http.MethodGet, "/api/trainings", "10.0.0.1"))
+	}
+	assert.Equal(
+		t,
+		http.StatusTooManyRequests,
+		request(http.MethodGet, "/api/trainings", "10.0.0.1"),
+		"requests without a token should be limited by IP",
+	)
+	assert.NotEqual(t, http.StatusTooManyRequests, request(http.MethodGet, "/api/trainings", "10.0.0.2"), "IPs should be limited separately")
+
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, http.StatusOK, request(http.MethodPost, "/webhooks/payments", "10.0.0.3"))
+	}
+	assert.Equal(
+		t,
+		http.StatusTooManyRequests,
+		request(http.MethodPost, "/webhooks/payments", "10.0.0.3"),
+		"public routes should be limited by IP",
+	)
+
+	assert.Equal(t, http.StatusOK, request(http.MethodGet, "/health/live", "10.0.0.3"), "health checks should not be limited")
+}
@@ -0,0 +1,51 @@
+package server
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// defaultShutdownTimeout is shorter than 10 seconds, after which Cloud Run kills the container,
+// so there is still time to run shutdown hooks.
+const defaultShutdownTimeout = 8 * time.Second
+
+// shutdownTimeout is how long the server waits for in-flight requests, before they are canceled.
+func shutdownTimeout() time.Duration {
+	timeout := os.Getenv("SHUTDOWN_TIMEOUT")
+	if timeout == "" {
+		return defaultShutdownTimeout
+	}
+
+	d, err := time.ParseDuration(timeout)
+	if err != nil {
+		logrus.WithError(err).Panicf("invalid SHUTDOWN_TIMEOUT '%s'", timeout)
+	}
+
+	return d
+}
+
+// waitForShutdown blocks until the process receives SIGTERM or SIGINT.
+// If the server fails before that, the error is returned.
+func waitForShutdown(serverErr <-chan error) error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+
+	select {
+	case err := <-serverErr:
+		return err
+	case <-ctx.Done():
+		return nil
+	}
+}
+
+func runShutdownHooks(hooks []func()) {
+	// hooks are run in the reverse order, like defers, so resources created later are released first
+	for i := len(hooks) - 1; i >= 0; i-- {
+		hooks[i]()
+	}
+}
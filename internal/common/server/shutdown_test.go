@@ -0,0 +1,89 @@
+package server_test
+
+import (
+	"net"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/server"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunHTTPServerOnAddr_graceful_shutdown(t *testing.T) {
+	t.Setenv("MOCK_AUTH", "true")
+
+	addr := freeAddr(t)
+
+	requestStarted := make(chan struct{})
+	hookCalled := false
+
+	serverStopped := make(chan struct{})
+	go func() {
+		defer close(serverStopped)
+
+		server.RunHTTPServerOnAddr(
+			addr,
+			func(router chi.Router) http.Handler {
+				return router
+			},
+			server.WithPublicRoutes("/slow", func(router chi.Router) http.Handler {
+				router.Get("/", func(w http.ResponseWriter, r *http.Request) {
+					close(requestStarted)
+					time.Sleep(time.Millisecond * 200)
+					w.WriteHeader(http.StatusNoContent)
+				})
+				return router
+			}),
+			server.WithHTTPShutdownHooks(func() {
+				hookCalled = true
+			}),
+		)
+	}()
+
+	require.Eventually(t, func() bool {
+		conn, err := net.Dial("tcp", addr)
+		if err != nil {
+			return false
+		}
+		_ = conn.Close()
+		return true
+	}, time.Second*5, time.Millisecond*10)
+
+	responseCode := make(chan int, 1)
+	go func() {
+		resp, err := http.Get("http://" + addr + "/slow/")
+		if err != nil {
+			responseCode <- 0
+			return
+		}
+		_ = resp.Body.Close()
+		responseCode <- resp.StatusCode
+	}()
+
+	<-requestStarted
+	require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGTERM))
+
+	select {
+	case <-serverStopped:
+	case <-time.After(time.Second * 5):
+		t.Fatal("server was not stopped")
+	}
+
+	assert.Equal(t, http.StatusNoContent, <-responseCode, "in-flight request should be finished")
+	assert.True(t, hookCalled, "shutdown hook should be called")
+
+	_, err := net.Dial("tcp", addr)
+	assert.Error(t, err, "server should not accept new connections")
+}
+
+func freeAddr(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+
+	return l.Addr().String()
+}
@@ -75,6 +75,33 @@ func (c TrainerHTTPClient) GetTrainerAvailableHours(t *testing.T, from time.Time
 	return *response.JSON200
 }
 
+func (c TrainerHTTPClient) GetCalendarHistory(t *testing.T, from time.Time, to time.Time) []trainer.AvailabilityChange {
+	response, err := c.client.GetCalendarHistoryWithResponse(context.Background(), &trainer.GetCalendarHistoryParams{
+		DateFrom: from,
+		DateTo:   to,
+	})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, response.StatusCode())
+
+	return *response.JSON200
+}
+
+func (c TrainerHTTPClient) GetCalendarConfig(t *testing.T) trainer.CalendarConfig {
+	response, err := c.client.GetCalendarConfigWithResponse(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, response.StatusCode())
+
+	return *response.JSON200
+}
+
+func (c TrainerHTTPClient) UpdateCalendarConfig(t *testing.T, config trainer.CalendarConfigUpdate) int {
+	response, err := c.client.UpdateCalendarConfig(context.Background(), trainer.UpdateCalendarConfigJSONRequestBody(config))
+	require.NoError(t, err)
+	require.NoError(t, response.Body.Close())
+
+	return response.StatusCode
+}
+
 type TrainingsHTTPClient struct {
 	client *trainings.ClientWithResponses
 }
@@ -168,6 +195,121 @@ func (c UsersHTTPClient) GetCurrentUser(t *testing.T) users.User {
 	return *response.JSON200
 }
 
+func (c UsersHTTPClient) UpdateCurrentUser(t *testing.T, update users.UserProfileUpdate, expectedStatusCode int) users.User {
+	response, err := c.client.UpdateCurrentUserWithResponse(context.Background(), users.UpdateCurrentUserJSONRequestBody(update))
+	require.NoError(t, err)
+	require.Equal(t, expectedStatusCode, response.StatusCode())
+
+	if response.JSON200 == nil {
+		return users.User{}
+	}
+
+	return *response.JSON200
+}
+
+func (c UsersHTTPClient) GetCurrentUserLedger(t *testing.T) []users.LedgerEntry {
+	response, err := c.client.GetCurrentUserLedgerWithResponse(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, response.StatusCode())
+
+	return *response.JSON200
+}
+
+func (c UsersHTTPClient) GetCurrentUserSessions(t *testing.T) []users.Session {
+	response, err := c.client.GetCurrentUserSessionsWithResponse(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, response.StatusCode())
+
+	return *response.JSON200
+}
+
+func (c UsersHTTPClient) RevokeCurrentUserSession(t *testing.T, sessionID string, expectedStatusCode int) {
+	response, err := c.client.RevokeCurrentUserSessionWithResponse(context.Background(), sessionID)
+	require.NoError(t, err)
+	require.Equal(t, expectedStatusCode, response.StatusCode())
+}
+
+func (c UsersHTTPClient) GetUserSessions(t *testing.T, userUUID string) []users.Session {
+	response, err := c.client.GetUserSessionsWithResponse(context.Background(), userUUID)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, response.StatusCode())
+
+	return *response.JSON200
+}
+
+func (c UsersHTTPClient) GetCreditPackages(t *testing.T) []users.CreditPackage {
+	response, err := c.client.GetCreditPackagesWithResponse(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, response.StatusCode())
+
+	return *response.JSON200
+}
+
+func (c UsersHTTPClient) CreatePurchase(t *testing.T, packageID string) users.Purchase {
+	response, err := c.client.CreatePurchaseWithResponse(context.Background(), users.CreatePurchaseJSONRequestBody{
+		PackageId: packageID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusCreated, response.StatusCode())
+
+	return *response.JSON201
+}
+
+func (c UsersHTTPClient) GetPurchase(t *testing.T, purchaseUUID string) users.Purchase {
+	response, err := c.client.GetPurchaseWithResponse(context.Background(), purchaseUUID)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, response.StatusCode())
+
+	return *response.JSON200
+}
+
+func (c UsersHTTPClient) GetUsers(t *testing.T, search string) []users.UserDetails {
+	response, err := c.client.GetUsersWithResponse(context.Background(), &users.GetUsersParams{Search: &search})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, response.StatusCode())
+
+	return *response.JSON200
+}
+
+func (c UsersHTTPClient) GetUser(t *testing.T, userUUID string) users.UserDetails {
+	response, err := c.client.GetUserWithResponse(context.Background(), userUUID)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, response.StatusCode())
+
+	return *response.JSON200
+}
+
+func (c UsersHTTPClient) AdjustUserCredits(t *testing.T, userUUID string, amountChange int, reason string, expectedStatusCode int) {
+	response, err := c.client.AdjustUserCreditsWithResponse(context.Background(), userUUID, users.AdjustUserCreditsJSONRequestBody{
+		AmountChange: amountChange,
+		Reason:       reason,
+	})
+	require.NoError(t, err)
+	require.Equal(t, expectedStatusCode, response.StatusCode())
+}
+
+func (c UsersHTTPClient) ChangeUserRole(t *testing.T, userUUID string, role string, expectedStatusCode int) {
+	response, err := c.client.ChangeUserRoleWithResponse(context.Background(), userUUID, users.ChangeUserRoleJSONRequestBody{
+		Role: users.RoleChangeRole(role),
+	})
+	require.NoError(t, err)
+	require.Equal(t, expectedStatusCode, response.StatusCode())
+}
+
+func (c UsersHTTPClient) ExportUserData(t *testing.T, userUUID string) users.UserDataArchive {
+	response, err := c.client.ExportUserDataWithResponse(context.Background(), userUUID)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, response.StatusCode())
+
+	return *response.JSON200
+}
+
+func (c UsersHTTPClient) EraseUserData(t *testing.T, userUUID string, expectedStatusCode int) {
+	response, err := c.client.EraseUserDataWithResponse(context.Background(), userUUID)
+	require.NoError(t, err)
+	require.Equal(t, expectedStatusCode, response.StatusCode())
+}
+
 func lastPathElement(path string) string {
 	parts := strings.Split(path, "/")
 	return parts[len(parts)-1]
@@ -2,17 +2,25 @@ package tests
 
 import (
 	"testing"
+	"time"
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/stretchr/testify/require"
 )
 
 func FakeAttendeeJWT(t *testing.T, userID string) string {
+	return FakeAttendeeJWTWithAuthTime(t, userID, time.Now())
+}
+
+// FakeAttendeeJWTWithAuthTime returns a token of the attendee, who signed in at authTime.
+// Tokens with the same authTime belong to the same session.
+func FakeAttendeeJWTWithAuthTime(t *testing.T, userID string, authTime time.Time) string {
 	return fakeJWT(t, jwt.MapClaims{
 		"user_uuid": userID,
 		"email":     "attendee@threedots.tech",
 		"role":      "attendee",
 		"name":      "Attendee",
+		"auth_time": authTime.Unix(),
 	})
 }
 
@@ -22,6 +30,17 @@ func FakeTrainerJWT(t *testing.T, userID string) string {
 		"email":     "trainer@threedots.tech",
 		"role":      "trainer",
 		"name":      "Trainer",
+		"auth_time": time.Now().Unix(),
+	})
+}
+
+func FakeAdminJWT(t *testing.T, userID string) string {
+	return fakeJWT(t, jwt.MapClaims{
+		"user_uuid": userID,
+		"email":     "admin@threedots.tech",
+		"role":      "admin",
+		"name":      "Admin",
+		"auth_time": time.Now().Unix(),
 	})
 }
 
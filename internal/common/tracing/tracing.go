@@ -0,0 +1,152 @@
+package tracing
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/tracing"
+
+// flushTimeout is shorter than the time left after the servers are shut down, before Cloud Run kills the container.
+const flushTimeout = time.Second
+
+// Init configures tracing of the service with TRACING_* envs. It returns the function flushing not exported spans,
+// which should be called before the process exits.
+//
+// TRACING_EXPORTER selects where spans are exported:
+//   - "none" (default) doesn't record spans, but the trace context is still passed to other services,
+//   - "stdout" writes spans to the standard output,
+//   - "file" writes spans to TRACING_FILE,
+//   - "otlp" sends spans to the OpenTelemetry collector, configured with standard OTEL_EXPORTER_OTLP_* envs.
+//
+// TRACING_SAMPLE_RATIO is the ratio of traces started by the service, which are recorded (1 by default).
+// Traces started by other services are recorded when they were sampled by them.
+func Init(serviceName string) (flush func()) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	exporter, closeExporter, err := newExporter()
+	if err != nil {
+		logrus.WithError(err).Fatal("Unable to configure tracing")
+	}
+	if exporter == nil {
+		return func() {}
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio()))),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceNameKey.String(serviceName),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+		defer cancel()
+
+		if err := provider.Shutdown(ctx); err != nil {
+			logrus.WithError(err).Warn("Unable to flush spans")
+		}
+		if err := closeExporter(); err != nil {
+			logrus.WithError(err).Warn("Unable to close spans exporter")
+		}
+	}
+}
+
+func newExporter() (sdktrace.SpanExporter, func() error, error) {
+	noClose := func() error { return nil }
+
+	exporter := strings.ToLower(os.Getenv("TRACING_EXPORTER"))
+
+	switch exporter {
+	case "", "none":
+		return nil, noClose, nil
+	case "stdout":
+		e, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		return e, noClose, err
+	case "file":
+		path := os.Getenv("TRACING_FILE")
+		if path == "" {
+			return nil, noClose, errors.New("TRACING_FILE is required by the file exporter")
+		}
+
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, noClose, errors.Wrap(err, "unable to open the traces file")
+		}
+
+		e, err := stdouttrace.New(stdouttrace.WithWriter(file))
+		return e, file.Close, err
+	case "otlp":
+		e, err := otlptracehttp.New(context.Background())
+		return e, noClose, err
+	default:
+		return nil, noClose, errors.Errorf("tracing exporter '%s' is not supported", exporter)
+	}
+}
+
+func sampleRatio() float64 {
+	ratio := os.Getenv("TRACING_SAMPLE_RATIO")
+	if ratio == "" {
+		return 1
+	}
+
+	r, err := strconv.ParseFloat(ratio, 64)
+	if err != nil {
+		logrus.WithError(err).Fatalf("invalid TRACING_SAMPLE_RATIO '%s'", ratio)
+	}
+
+	return r
+}
+
+func tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// StartSpan starts the span of an internal operation, like a repository call.
+// The span must be ended by the caller.
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// EndSpan marks the span as failed when err is not nil, and ends it.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+// TraceID returns ID of the trace from ctx, or empty string when ctx is not traced.
+// It's intended for correlating logs with traces.
+func TraceID(ctx context.Context) string {
+	spanCtx := trace.SpanContextFromContext(ctx)
+	if !spanCtx.HasTraceID() {
+		return ""
+	}
+
+	return spanCtx.TraceID().String()
+}
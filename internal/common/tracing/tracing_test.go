@@ -0,0 +1,104 @@
+package tracing_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/tracing"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestHTTPMiddleware(t *testing.T) {
+	recorder := setupTracing(t)
+
+	router := chi.NewRouter()
+	router.Use(tracing.HTTPMiddleware)
+
+	var traceIDInHandler string
+	router.Get("/trainings/{uuid}", func(w http.ResponseWriter, r *http.Request) {
+		traceIDInHandler = tracing.TraceID(r.Context())
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+
+	req := httptest.NewRequest(http.MethodGet, "/trainings/1", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, traceID, traceIDInHandler, "trace should be continued from the request headers")
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "GET /trainings/{uuid}", spans[0].Name())
+	assert.Equal(t, trace.SpanKindServer, spans[0].SpanKind())
+}
+
+func TestGRPCInterceptors_propagate_trace(t *testing.T) {
+	recorder := setupTracing(t)
+
+	const method = "/trainer.TrainerService/ScheduleTraining"
+
+	ctx, parent := tracing.StartSpan(context.Background(), "command ScheduleTraining")
+
+	var traceIDOnServer string
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		// metadata sent by the client is received by the server
+		md, _ := metadata.FromOutgoingContext(ctx)
+		serverCtx := metadata.NewIncomingContext(context.Background(), md)
+
+		_, err := tracing.UnaryServerInterceptor()(
+			serverCtx,
+			req,
+			&grpc.UnaryServerInfo{FullMethod: method},
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				traceIDOnServer = tracing.TraceID(ctx)
+				return nil, nil
+			},
+		)
+		return err
+	}
+
+	err := tracing.UnaryClientInterceptor()(ctx, method, "request", "reply", nil, invoker)
+	require.NoError(t, err)
+	parent.End()
+
+	assert.Equal(t, tracing.TraceID(ctx), traceIDOnServer)
+
+	spanKinds := map[trace.SpanKind]string{}
+	for _, span := range recorder.Ended() {
+		assert.Equal(t, tracing.TraceID(ctx), span.SpanContext().TraceID().String())
+		spanKinds[span.SpanKind()] = span.Name()
+	}
+	assert.Equal(t, map[trace.SpanKind]string{
+		trace.SpanKindInternal: "command ScheduleTraining",
+		trace.SpanKindClient:   "trainer.TrainerService/ScheduleTraining",
+		trace.SpanKindServer:   "trainer.TrainerService/ScheduleTraining",
+	}, spanKinds)
+}
+
+func setupTracing(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	t.Cleanup(func() {
+		_ = provider.Shutdown(context.Background())
+		otel.SetTracerProvider(trace.NewNoopTracerProvider())
+	})
+
+	return recorder
+}
@@ -0,0 +1,67 @@
+package adapters
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainer/app/query"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainer/domain/hour"
+)
+
+// fillMissingHours adds dates and hours which are not persisted, and sorts them.
+//
+// Not persisted hours are not available, but the calendar should still show them.
+func fillMissingHours(dates []query.Date, from time.Time, to time.Time, factoryConfig hour.FactoryConfig) []query.Date {
+	dates = addMissingDates(dates, from, to)
+	for i, date := range dates {
+		date = setDefaultAvailability(date, factoryConfig)
+		sort.Slice(date.Hours, func(i, j int) bool { return date.Hours[i].Hour.Before(date.Hours[j].Hour) })
+		dates[i] = date
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Date.Before(dates[j].Date) })
+
+	return dates
+}
+
+// setDefaultAvailability adds missing hours to Date model if they were not set
+func setDefaultAvailability(date query.Date, factoryConfig hour.FactoryConfig) query.Date {
+HoursLoop:
+	for h := factoryConfig.MinUtcHour; h <= factoryConfig.MaxUtcHour; h++ {
+		hour := time.Date(date.Date.Year(), date.Date.Month(), date.Date.Day(), h, 0, 0, 0, time.UTC)
+
+		for i := range date.Hours {
+			if date.Hours[i].Hour.Equal(hour) {
+				continue HoursLoop
+			}
+		}
+		newHour := query.Hour{
+			Available: false,
+			Hour:      hour,
+		}
+
+		date.Hours = append(date.Hours, newHour)
+	}
+
+	return date
+}
+
+func addMissingDates(dates []query.Date, from time.Time, to time.Time) []query.Date {
+	for day := from.UTC(); day.Before(to) || day.Equal(to); day = day.AddDate(0, 0, 1) {
+		found := false
+		for _, date := range dates {
+			if date.Date.Equal(day) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			date := query.Date{
+				Date: day,
+			}
+			dates = append(dates, date)
+		}
+	}
+
+	return dates
+}
@@ -2,9 +2,9 @@ package adapters
 
 import (
 	"context"
-	"sort"
 	"time"
 
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/tracing"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainer/app/query"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainer/domain/hour"
 	"github.com/pkg/errors"
@@ -27,15 +27,18 @@ type HourModel struct {
 
 type DatesFirestoreRepository struct {
 	firestoreClient *firestore.Client
-	factoryConfig   hour.FactoryConfig
+	hourFactory     hour.Factory
 }
 
-func NewDatesFirestoreRepository(firestoreClient *firestore.Client, factoryConfig hour.FactoryConfig) DatesFirestoreRepository {
+func NewDatesFirestoreRepository(firestoreClient *firestore.Client, hourFactory hour.Factory) DatesFirestoreRepository {
 	if firestoreClient == nil {
 		panic("missing firestoreClient")
 	}
+	if hourFactory.IsZero() {
+		panic("missing hourFactory")
+	}
 
-	return DatesFirestoreRepository{firestoreClient, factoryConfig}
+	return DatesFirestoreRepository{firestoreClient, hourFactory}
 }
 
 func (d DatesFirestoreRepository) trainerHoursCollection() *firestore.CollectionRef {
@@ -46,15 +49,18 @@ func (d DatesFirestoreRepository) DocumentRef(dateTimeToUpdate time.Time) *fires
 	return d.trainerHoursCollection().Doc(dateTimeToUpdate.Format("2006-01-02"))
 }
 
-func (d DatesFirestoreRepository) AvailableHours(ctx context.Context, from time.Time, to time.Time) ([]query.Date, error) {
+func (d DatesFirestoreRepository) AvailableHours(ctx context.Context, from time.Time, to time.Time) (dates []query.Date, err error) {
+	ctx, span := tracing.StartSpan(ctx, "DatesFirestoreRepository.AvailableHours")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
 	iter := d.
 		trainerHoursCollection().
 		Where("Date", ">=", from).
 		Where("Date", "<=", to).
 		Documents(ctx)
 
-	var dates []query.Date
-
 	for {
 		doc, err := iter.Next()
 		if errors.Is(err, iterator.Done) {
@@ -71,58 +77,7 @@ func (d DatesFirestoreRepository) AvailableHours(ctx context.Context, from time.
 		dates = append(dates, dateModelToApp(date))
 	}
 
-	dates = addMissingDates(dates, from, to)
-	for i, date := range dates {
-		date = d.setDefaultAvailability(date)
-		sort.Slice(date.Hours, func(i, j int) bool { return date.Hours[i].Hour.Before(date.Hours[j].Hour) })
-		dates[i] = date
-	}
-	sort.Slice(dates, func(i, j int) bool { return dates[i].Date.Before(dates[j].Date) })
-
-	return dates, nil
-}
-
-// setDefaultAvailability adds missing hours to Date model if they were not set
-func (d DatesFirestoreRepository) setDefaultAvailability(date query.Date) query.Date {
-HoursLoop:
-	for h := d.factoryConfig.MinUtcHour; h <= d.factoryConfig.MaxUtcHour; h++ {
-		hour := time.Date(date.Date.Year(), date.Date.Month(), date.Date.Day(), h, 0, 0, 0, time.UTC)
-
-		for i := range date.Hours {
-			if date.Hours[i].Hour.Equal(hour) {
-				continue HoursLoop
-			}
-		}
-		newHour := query.Hour{
-			Available: false,
-			Hour:      hour,
-		}
-
-		date.Hours = append(date.Hours, newHour)
-	}
-
-	return date
-}
-
-func addMissingDates(dates []query.Date, from time.Time, to time.Time) []query.Date {
-	for day := from.UTC(); day.Before(to) || day.Equal(to); day = day.AddDate(0, 0, 1) {
-		found := false
-		for _, date := range dates {
-			if date.Date.Equal(day) {
-				found = true
-				break
-			}
-		}
-
-		if !found {
-			date := query.Date{
-				Date: day,
-			}
-			dates = append(dates, date)
-		}
-	}
-
-	return dates
+	return fillMissingHours(dates, from, to, d.hourFactory.Config()), nil
 }
 
 func dateModelToApp(dm DateModel) query.Date {
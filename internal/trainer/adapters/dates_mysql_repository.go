@@ -0,0 +1,87 @@
+package adapters
+
+import (
+	"context"
+	"time"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/tracing"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainer/app/query"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainer/domain/hour"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+type DatesMySQLRepository struct {
+	db          *sqlx.DB
+	hourFactory hour.Factory
+}
+
+func NewDatesMySQLRepository(db *sqlx.DB, hourFactory hour.Factory) DatesMySQLRepository {
+	if db == nil {
+		panic("missing db")
+	}
+	if hourFactory.IsZero() {
+		panic("missing hourFactory")
+	}
+
+	return DatesMySQLRepository{db: db, hourFactory: hourFactory}
+}
+
+func (d DatesMySQLRepository) AvailableHours(ctx context.Context, from time.Time, to time.Time) (dates []query.Date, err error) {
+	ctx, span := tracing.StartSpan(ctx, "DatesMySQLRepository.AvailableHours")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	var dbHours []mysqlHour
+
+	// dates are stored as midnight in UTC, so `to` date includes all hours of this day
+	err = d.db.SelectContext(
+		ctx,
+		&dbHours,
+		"SELECT * FROM `hours` WHERE `hour` >= ? AND `hour` < ? ORDER BY `hour`",
+		from.UTC(),
+		to.UTC().AddDate(0, 0, 1),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get hours from db")
+	}
+
+	datesIndexes := map[time.Time]int{}
+
+	for _, dbHour := range dbHours {
+		queryHour, err := mysqlHourToApp(dbHour)
+		if err != nil {
+			return nil, err
+		}
+
+		date := queryHour.Hour.Truncate(time.Hour * 24)
+
+		i, ok := datesIndexes[date]
+		if !ok {
+			dates = append(dates, query.Date{Date: date})
+			i = len(dates) - 1
+			datesIndexes[date] = i
+		}
+
+		dates[i].Hours = append(dates[i].Hours, queryHour)
+		if queryHour.Available {
+			dates[i].HasFreeHours = true
+		}
+	}
+
+	return fillMissingHours(dates, from, to, d.hourFactory.Config()), nil
+}
+
+func mysqlHourToApp(dbHour mysqlHour) (query.Hour, error) {
+	availability, err := hour.NewAvailabilityFromString(dbHour.Availability)
+	if err != nil {
+		return query.Hour{}, err
+	}
+
+	return query.Hour{
+		Available:            availability == hour.Available,
+		HasTrainingScheduled: availability == hour.TrainingScheduled,
+		Hour:                 dbHour.Hour.UTC(),
+	}, nil
+}
@@ -0,0 +1,129 @@
+package adapters_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainer/adapters"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainer/app/query"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainer/domain/hour"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDatesRepository(t *testing.T) {
+	t.Parallel()
+
+	for _, r := range createDatesRepositories(t) {
+		r := r
+
+		t.Run(r.Name, func(t *testing.T) {
+			t.Parallel()
+
+			t.Run("testAvailableHours", func(t *testing.T) {
+				t.Parallel()
+				testAvailableHours(t, r)
+			})
+			t.Run("testAvailableHours_default_availability", func(t *testing.T) {
+				t.Parallel()
+				testAvailableHours_default_availability(t, r)
+			})
+		})
+	}
+}
+
+type DatesRepository struct {
+	Name           string
+	HourRepository hour.Repository
+	ReadModel      query.AvailableHoursReadModel
+}
+
+func createDatesRepositories(t *testing.T) []DatesRepository {
+	firestoreClient, err := firestore.NewClient(context.Background(), os.Getenv("GCP_PROJECT"))
+	require.NoError(t, err)
+
+	db := newMySQLConnection(t)
+
+	return []DatesRepository{
+		{
+			Name:           "Firebase",
+			HourRepository: adapters.NewFirestoreHourRepository(firestoreClient, testHourFactory),
+			ReadModel:      adapters.NewDatesFirestoreRepository(firestoreClient, testHourFactory),
+		},
+		{
+			Name:           "MySQL",
+			HourRepository: adapters.NewMySQLHourRepository(db, testHourFactory),
+			ReadModel:      adapters.NewDatesMySQLRepository(db, testHourFactory),
+		},
+	}
+}
+
+func testAvailableHours(t *testing.T, r DatesRepository) {
+	t.Helper()
+	ctx := context.Background()
+
+	hourTime := newValidHourTime()
+
+	err := r.HourRepository.UpdateHour(ctx, hourTime, testChangeSource, func(h *hour.Hour) (*hour.Hour, error) {
+		if err := h.MakeAvailable(); err != nil {
+			return nil, err
+		}
+		return h, nil
+	})
+	require.NoError(t, err)
+
+	date := getDateFromReadModel(t, r.ReadModel, hourTime)
+
+	assert.True(t, date.HasFreeHours)
+	assert.Contains(t, date.Hours, query.Hour{
+		Available:            true,
+		HasTrainingScheduled: false,
+		Hour:                 hourTime.UTC(),
+	})
+}
+
+func testAvailableHours_default_availability(t *testing.T, r DatesRepository) {
+	t.Helper()
+	ctx := context.Background()
+
+	hourTime := newValidHourTime()
+
+	err := r.HourRepository.UpdateHour(ctx, hourTime, testChangeSource, func(h *hour.Hour) (*hour.Hour, error) {
+		if err := h.MakeAvailable(); err != nil {
+			return nil, err
+		}
+		if err := h.ScheduleTraining(); err != nil {
+			return nil, err
+		}
+		return h, nil
+	})
+	require.NoError(t, err)
+
+	date := getDateFromReadModel(t, r.ReadModel, hourTime)
+
+	config := testHourFactory.Config()
+	assert.Len(t, date.Hours, config.MaxUtcHour-config.MinUtcHour+1, "all hours of the day should be returned")
+
+	for _, h := range date.Hours {
+		if h.Hour.Equal(hourTime) {
+			assert.True(t, h.HasTrainingScheduled)
+		}
+	}
+}
+
+func getDateFromReadModel(t *testing.T, readModel query.AvailableHoursReadModel, hourTime time.Time) query.Date {
+	t.Helper()
+
+	day := hourTime.UTC().Truncate(time.Hour * 24)
+
+	dates, err := readModel.AvailableHours(context.Background(), day, day)
+	require.NoError(t, err)
+	require.Len(t, dates, 1)
+
+	assert.True(t, dates[0].Date.Equal(day), "%s != %s", dates[0].Date, day)
+
+	return dates[0]
+}
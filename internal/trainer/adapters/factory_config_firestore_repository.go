@@ -0,0 +1,80 @@
+package adapters
+
+import (
+	"context"
+
+	"cloud.google.com/go/firestore"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/tracing"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainer/domain/hour"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type FactoryConfigModel struct {
+	MaxWeeksInTheFutureToSet int `firestore:"MaxWeeksInTheFutureToSet"`
+	MinUtcHour               int `firestore:"MinUtcHour"`
+	MaxUtcHour               int `firestore:"MaxUtcHour"`
+}
+
+type FactoryConfigFirestoreRepository struct {
+	firestoreClient *firestore.Client
+	defaultConfig   hour.FactoryConfig
+}
+
+// NewFactoryConfigFirestoreRepository creates repository, which returns defaultConfig until any config is stored.
+func NewFactoryConfigFirestoreRepository(
+	firestoreClient *firestore.Client,
+	defaultConfig hour.FactoryConfig,
+) FactoryConfigFirestoreRepository {
+	if firestoreClient == nil {
+		panic("missing firestoreClient")
+	}
+
+	return FactoryConfigFirestoreRepository{firestoreClient: firestoreClient, defaultConfig: defaultConfig}
+}
+
+func (f FactoryConfigFirestoreRepository) documentRef() *firestore.DocumentRef {
+	return f.firestoreClient.Collection("trainer-config").Doc("factory")
+}
+
+func (f FactoryConfigFirestoreRepository) GetFactoryConfig(ctx context.Context) (fc hour.FactoryConfig, err error) {
+	ctx, span := tracing.StartSpan(ctx, "FactoryConfigFirestoreRepository.GetFactoryConfig")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	doc, err := f.documentRef().Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return f.defaultConfig, nil
+	}
+	if err != nil {
+		return hour.FactoryConfig{}, errors.Wrap(err, "unable to get factory config")
+	}
+
+	model := FactoryConfigModel{}
+	if err := doc.DataTo(&model); err != nil {
+		return hour.FactoryConfig{}, errors.Wrap(err, "unable to unmarshal FactoryConfigModel from Firestore")
+	}
+
+	return hour.FactoryConfig{
+		MaxWeeksInTheFutureToSet: model.MaxWeeksInTheFutureToSet,
+		MinUtcHour:               model.MinUtcHour,
+		MaxUtcHour:               model.MaxUtcHour,
+	}, nil
+}
+
+func (f FactoryConfigFirestoreRepository) UpdateFactoryConfig(ctx context.Context, fc hour.FactoryConfig) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "FactoryConfigFirestoreRepository.UpdateFactoryConfig")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	_, err = f.documentRef().Set(ctx, FactoryConfigModel{
+		MaxWeeksInTheFutureToSet: fc.MaxWeeksInTheFutureToSet,
+		MinUtcHour:               fc.MinUtcHour,
+		MaxUtcHour:               fc.MaxUtcHour,
+	})
+
+	return errors.Wrap(err, "unable to update factory config")
+}
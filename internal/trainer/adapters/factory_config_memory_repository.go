@@ -0,0 +1,36 @@
+package adapters
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainer/domain/hour"
+)
+
+type FactoryConfigMemoryRepository struct {
+	config hour.FactoryConfig
+	lock   *sync.RWMutex
+}
+
+func NewFactoryConfigMemoryRepository(defaultConfig hour.FactoryConfig) *FactoryConfigMemoryRepository {
+	return &FactoryConfigMemoryRepository{
+		config: defaultConfig,
+		lock:   &sync.RWMutex{},
+	}
+}
+
+func (m *FactoryConfigMemoryRepository) GetFactoryConfig(_ context.Context) (hour.FactoryConfig, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return m.config, nil
+}
+
+func (m *FactoryConfigMemoryRepository) UpdateFactoryConfig(_ context.Context, fc hour.FactoryConfig) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.config = fc
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/tracing"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainer/domain/hour"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// there is only one config, so it's always stored with the same id
+const mysqlFactoryConfigID = 1
+
+type mysqlFactoryConfig struct {
+	ID                       int `db:"id"`
+	MaxWeeksInTheFutureToSet int `db:"max_weeks_in_the_future_to_set"`
+	MinUtcHour               int `db:"min_utc_hour"`
+	MaxUtcHour               int `db:"max_utc_hour"`
+}
+
+type FactoryConfigMySQLRepository struct {
+	db            *sqlx.DB
+	defaultConfig hour.FactoryConfig
+}
+
+// NewFactoryConfigMySQLRepository creates repository, which returns defaultConfig until any config is stored.
+func NewFactoryConfigMySQLRepository(db *sqlx.DB, defaultConfig hour.FactoryConfig) FactoryConfigMySQLRepository {
+	if db == nil {
+		panic("missing db")
+	}
+
+	return FactoryConfigMySQLRepository{db: db, defaultConfig: defaultConfig}
+}
+
+func (m FactoryConfigMySQLRepository) GetFactoryConfig(ctx context.Context) (fc hour.FactoryConfig, err error) {
+	ctx, span := tracing.StartSpan(ctx, "FactoryConfigMySQLRepository.GetFactoryConfig")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	dbConfig := mysqlFactoryConfig{}
+
+	err = m.db.GetContext(ctx, &dbConfig, "SELECT * FROM `factory_config` WHERE `id` = ?", mysqlFactoryConfigID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return m.defaultConfig, nil
+	} else if err != nil {
+		return hour.FactoryConfig{}, errors.Wrap(err, "unable to get factory config from db")
+	}
+
+	return hour.FactoryConfig{
+		MaxWeeksInTheFutureToSet: dbConfig.MaxWeeksInTheFutureToSet,
+		MinUtcHour:               dbConfig.MinUtcHour,
+		MaxUtcHour:               dbConfig.MaxUtcHour,
+	}, nil
+}
+
+func (m FactoryConfigMySQLRepository) UpdateFactoryConfig(ctx context.Context, fc hour.FactoryConfig) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "FactoryConfigMySQLRepository.UpdateFactoryConfig")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	_, err = m.db.NamedExecContext(
+		ctx,
+		`INSERT INTO 
+			factory_config (id, max_weeks_in_the_future_to_set, min_utc_hour, max_utc_hour) 
+		VALUES 
+			(:id, :max_weeks_in_the_future_to_set, :min_utc_hour, :max_utc_hour)
+		ON DUPLICATE KEY UPDATE 
+			max_weeks_in_the_future_to_set = :max_weeks_in_the_future_to_set,
+			min_utc_hour = :min_utc_hour,
+			max_utc_hour = :max_utc_hour`,
+		mysqlFactoryConfig{
+			ID:                       mysqlFactoryConfigID,
+			MaxWeeksInTheFutureToSet: fc.MaxWeeksInTheFutureToSet,
+			MinUtcHour:               fc.MinUtcHour,
+			MaxUtcHour:               fc.MaxUtcHour,
+		},
+	)
+	if err != nil {
+		return errors.Wrap(err, "unable to upsert factory config")
+	}
+
+	return nil
+}
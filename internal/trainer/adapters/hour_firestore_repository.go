@@ -2,15 +2,28 @@ package adapters
 
 import (
 	"context"
+	"sort"
 	"time"
 
 	"cloud.google.com/go/firestore"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/tracing"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainer/app/query"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainer/domain/hour"
 	"github.com/pkg/errors"
+	"google.golang.org/api/iterator"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
+type AvailabilityChangeModel struct {
+	Hour      time.Time `firestore:"Hour"`
+	From      string    `firestore:"From"`
+	To        string    `firestore:"To"`
+	Actor     string    `firestore:"Actor"`
+	Command   string    `firestore:"Command"`
+	ChangedAt time.Time `firestore:"ChangedAt"`
+}
+
 type FirestoreHourRepository struct {
 	firestoreClient *firestore.Client
 	hourFactory     hour.Factory
@@ -27,7 +40,12 @@ func NewFirestoreHourRepository(firestoreClient *firestore.Client, hourFactory h
 	return &FirestoreHourRepository{firestoreClient, hourFactory}
 }
 
-func (f FirestoreHourRepository) GetHour(ctx context.Context, time time.Time) (*hour.Hour, error) {
+func (f FirestoreHourRepository) GetHour(ctx context.Context, time time.Time) (h *hour.Hour, err error) {
+	ctx, span := tracing.StartSpan(ctx, "FirestoreHourRepository.GetHour")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
 	date, err := f.getDateDTO(
 		// getDateDTO should be used both for transactional and non transactional query,
 		// the best way for that is to use closure
@@ -51,9 +69,63 @@ func (f FirestoreHourRepository) GetHour(ctx context.Context, time time.Time) (*
 func (f FirestoreHourRepository) UpdateHour(
 	ctx context.Context,
 	hourTime time.Time,
+	source hour.ChangeSource,
 	updateFn func(h *hour.Hour) (*hour.Hour, error),
+) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "FirestoreHourRepository.UpdateHour")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	return f.updateHour(ctx, hourTime, source, nil, func(h *hour.Hour, _ []time.Time) (*hour.Hour, error) {
+		return updateFn(h)
+	})
+}
+
+func (f FirestoreHourRepository) UpdateHourWithScheduledTrainings(
+	ctx context.Context,
+	hourTime time.Time,
+	from time.Time,
+	to time.Time,
+	source hour.ChangeSource,
+	updateFn func(h *hour.Hour, scheduledTrainings []time.Time) (*hour.Hour, error),
+) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "FirestoreHourRepository.UpdateHourWithScheduledTrainings")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	return f.updateHour(ctx, hourTime, source, &scheduledTrainingsPeriod{from: from, to: to}, updateFn)
+}
+
+// updateHour reads scheduled trainings in the period and passes them to updateFn, when the period is not nil.
+func (f FirestoreHourRepository) updateHour(
+	ctx context.Context,
+	hourTime time.Time,
+	source hour.ChangeSource,
+	period *scheduledTrainingsPeriod,
+	updateFn func(h *hour.Hour, scheduledTrainings []time.Time) (*hour.Hour, error),
 ) error {
 	err := f.firestoreClient.RunTransaction(ctx, func(ctx context.Context, transaction *firestore.Transaction) error {
+		var scheduledTrainings []time.Time
+		if period != nil {
+			// The lock document is read and written by all transactions reading scheduled trainings.
+			// Concurrent transactions conflict on it, so they are retried with trainings scheduled in the meantime.
+			if _, err := transaction.Get(f.scheduleLockRef()); err != nil && status.Code(err) != codes.NotFound {
+				return errors.Wrap(err, "unable to lock schedule")
+			}
+
+			var err error
+			scheduledTrainings, err = f.scheduledTrainings(
+				transaction.Documents(f.scheduledTrainingsQuery(period.from, period.to)),
+				period.from,
+				period.to,
+			)
+			if err != nil {
+				return err
+			}
+		}
+
 		dateDocRef := f.documentRef(hourTime)
 
 		firebaseDate, err := f.getDateDTO(
@@ -72,23 +144,215 @@ func (f FirestoreHourRepository) UpdateHour(
 		if err != nil {
 			return err
 		}
+		availabilityBefore := hourFromDB.Availability()
 
-		updatedHour, err := updateFn(hourFromDB)
+		updatedHour, err := updateFn(hourFromDB, scheduledTrainings)
 		if err != nil {
 			return errors.Wrap(err, "unable to update hour")
 		}
 		updateHourInDataDTO(updatedHour, &firebaseDate)
 
-		return transaction.Set(dateDocRef, firebaseDate)
+		if err := transaction.Set(dateDocRef, firebaseDate); err != nil {
+			return err
+		}
+
+		if period != nil {
+			if err := transaction.Set(f.scheduleLockRef(), map[string]interface{}{"UpdatedAt": time.Now().UTC()}); err != nil {
+				return err
+			}
+		}
+
+		change, changed := hour.NewAvailabilityChange(availabilityBefore, updatedHour, source, time.Now())
+		if !changed {
+			return nil
+		}
+
+		return transaction.Create(f.historyCollection().NewDoc(), AvailabilityChangeModel{
+			Hour:      change.Hour.UTC(),
+			From:      change.From.String(),
+			To:        change.To.String(),
+			Actor:     change.Actor,
+			Command:   change.Command,
+			ChangedAt: change.ChangedAt.UTC(),
+		})
 	})
 
 	return errors.Wrap(err, "firestore transaction failed")
 }
 
+func (f FirestoreHourRepository) ScheduledTrainings(ctx context.Context, from time.Time, to time.Time) (scheduledTrainings []time.Time, err error) {
+	ctx, span := tracing.StartSpan(ctx, "FirestoreHourRepository.ScheduledTrainings")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	return f.scheduledTrainings(f.scheduledTrainingsQuery(from, to).Documents(ctx), from, to)
+}
+
+func (f FirestoreHourRepository) scheduledTrainingsQuery(from time.Time, to time.Time) firestore.Query {
+	return f.
+		trainerHoursCollection().
+		Where("Date", ">=", from.UTC().Truncate(time.Hour*24)).
+		Where("Date", "<", to)
+}
+
+func (f FirestoreHourRepository) scheduledTrainings(
+	iter *firestore.DocumentIterator,
+	from time.Time,
+	to time.Time,
+) ([]time.Time, error) {
+	var trainings []time.Time
+
+	for {
+		doc, err := iter.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to get dates")
+		}
+
+		date := DateModel{}
+		if err := doc.DataTo(&date); err != nil {
+			return nil, errors.Wrap(err, "unable to unmarshal DateModel from Firestore")
+		}
+
+		for _, h := range date.Hours {
+			if h.HasTrainingScheduled && !h.Hour.Before(from) && h.Hour.Before(to) {
+				trainings = append(trainings, h.Hour.Local())
+			}
+		}
+	}
+
+	return trainings, nil
+}
+
+func (f FirestoreHourRepository) AvailabilityHistory(ctx context.Context, from time.Time, to time.Time) (changes []query.AvailabilityChange, err error) {
+	ctx, span := tracing.StartSpan(ctx, "FirestoreHourRepository.AvailabilityHistory")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	// the same as in the calendar, `to` date includes all hours of this day
+	changes, err = f.getAvailabilityChanges(
+		f.historyCollection().
+			Where("Hour", ">=", from.UTC()).
+			Where("Hour", "<", to.UTC().AddDate(0, 0, 1)).
+			Documents(ctx),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Firestore requires ordering by the field used in the range filter, so changes are sorted here
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].ChangedAt.Before(changes[j].ChangedAt) })
+
+	return changes, nil
+}
+
+func (f FirestoreHourRepository) AvailabilityChangesSince(ctx context.Context, since time.Time) (changes []query.AvailabilityChange, err error) {
+	ctx, span := tracing.StartSpan(ctx, "FirestoreHourRepository.AvailabilityChangesSince")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	return f.getAvailabilityChanges(
+		f.historyCollection().
+			Where("ChangedAt", ">=", since.UTC()).
+			OrderBy("ChangedAt", firestore.Asc).
+			Documents(ctx),
+	)
+}
+
+func (f FirestoreHourRepository) AvailabilityChangesByActor(ctx context.Context, actor string) (changes []query.AvailabilityChange, err error) {
+	ctx, span := tracing.StartSpan(ctx, "FirestoreHourRepository.AvailabilityChangesByActor")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	changes, err = f.getAvailabilityChanges(f.historyCollection().Where("Actor", "==", actor).Documents(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].ChangedAt.Before(changes[j].ChangedAt) })
+
+	return changes, nil
+}
+
+// firestoreMaxBatchWrites is the limit of writes in a single Firestore batch.
+const firestoreMaxBatchWrites = 500
+
+func (f FirestoreHourRepository) EraseActor(ctx context.Context, actor string) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "FirestoreHourRepository.EraseActor")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	docs, err := f.historyCollection().Where("Actor", "==", actor).Documents(ctx).GetAll()
+	if err != nil {
+		return errors.Wrap(err, "unable to get availability changes")
+	}
+
+	for start := 0; start < len(docs); start += firestoreMaxBatchWrites {
+		end := start + firestoreMaxBatchWrites
+		if end > len(docs) {
+			end = len(docs)
+		}
+
+		batch := f.firestoreClient.Batch()
+		for _, doc := range docs[start:end] {
+			batch.Update(doc.Ref, []firestore.Update{{Path: "Actor", Value: hour.ErasedActor}})
+		}
+
+		if _, err := batch.Commit(ctx); err != nil {
+			return errors.Wrap(err, "unable to erase actor of availability changes")
+		}
+	}
+
+	return nil
+}
+
+func (f FirestoreHourRepository) getAvailabilityChanges(iter *firestore.DocumentIterator) ([]query.AvailabilityChange, error) {
+	docs, err := iter.GetAll()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get availability changes")
+	}
+
+	var changes []query.AvailabilityChange
+	for _, doc := range docs {
+		model := AvailabilityChangeModel{}
+		if err := doc.DataTo(&model); err != nil {
+			return nil, errors.Wrap(err, "unable to unmarshal AvailabilityChangeModel from Firestore")
+		}
+
+		changes = append(changes, query.AvailabilityChange{
+			ID:        doc.Ref.ID,
+			Hour:      model.Hour.UTC(),
+			From:      model.From,
+			To:        model.To,
+			Actor:     model.Actor,
+			Command:   model.Command,
+			ChangedAt: model.ChangedAt.UTC(),
+		})
+	}
+
+	return changes, nil
+}
+
+func (f FirestoreHourRepository) historyCollection() *firestore.CollectionRef {
+	return f.firestoreClient.Collection("trainer-hour-history")
+}
+
 func (f FirestoreHourRepository) trainerHoursCollection() *firestore.CollectionRef {
 	return f.firestoreClient.Collection("trainer-hours")
 }
 
+// scheduleLockRef is the document serializing transactions, which read scheduled trainings of the trainer.
+func (f FirestoreHourRepository) scheduleLockRef() *firestore.DocumentRef {
+	return f.firestoreClient.Collection("trainer-schedule-lock").Doc("lock")
+}
+
 func (f FirestoreHourRepository) documentRef(hourTime time.Time) *firestore.DocumentRef {
 	return f.trainerHoursCollection().Doc(hourTime.Format("2006-01-02"))
 }
@@ -2,15 +2,18 @@ package adapters
 
 import (
 	"context"
+	"strconv"
 	"sync"
 	"time"
 
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainer/app/query"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainer/domain/hour"
 )
 
 type MemoryHourRepository struct {
-	hours map[time.Time]hour.Hour
-	lock  *sync.RWMutex
+	hours   map[time.Time]hour.Hour
+	history []hour.AvailabilityChange
+	lock    *sync.RWMutex
 
 	hourFactory hour.Factory
 }
@@ -48,15 +51,47 @@ func (m MemoryHourRepository) getOrCreateHour(hourTime time.Time) (*hour.Hour, e
 func (m *MemoryHourRepository) UpdateHour(
 	_ context.Context,
 	hourTime time.Time,
+	source hour.ChangeSource,
 	updateFn func(h *hour.Hour) (*hour.Hour, error),
 ) error {
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
+	return m.updateHour(hourTime, source, func(h *hour.Hour) (*hour.Hour, error) {
+		return updateFn(h)
+	})
+}
+
+func (m *MemoryHourRepository) UpdateHourWithScheduledTrainings(
+	_ context.Context,
+	hourTime time.Time,
+	from time.Time,
+	to time.Time,
+	source hour.ChangeSource,
+	updateFn func(h *hour.Hour, scheduledTrainings []time.Time) (*hour.Hour, error),
+) error {
+	// the lock is held during the whole update, so trainings can't be scheduled concurrently
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	scheduledTrainings := m.scheduledTrainings(from, to)
+
+	return m.updateHour(hourTime, source, func(h *hour.Hour) (*hour.Hour, error) {
+		return updateFn(h, scheduledTrainings)
+	})
+}
+
+// updateHour must be called with the write lock held.
+func (m *MemoryHourRepository) updateHour(
+	hourTime time.Time,
+	source hour.ChangeSource,
+	updateFn func(h *hour.Hour) (*hour.Hour, error),
+) error {
 	currentHour, err := m.getOrCreateHour(hourTime)
 	if err != nil {
 		return err
 	}
+	availabilityBefore := currentHour.Availability()
 
 	updatedHour, err := updateFn(currentHour)
 	if err != nil {
@@ -65,5 +100,98 @@ func (m *MemoryHourRepository) UpdateHour(
 
 	m.hours[hourTime] = *updatedHour
 
+	if change, changed := hour.NewAvailabilityChange(availabilityBefore, updatedHour, source, time.Now()); changed {
+		m.history = append(m.history, change)
+	}
+
+	return nil
+}
+
+func (m MemoryHourRepository) ScheduledTrainings(_ context.Context, from time.Time, to time.Time) ([]time.Time, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return m.scheduledTrainings(from, to), nil
+}
+
+func (m MemoryHourRepository) scheduledTrainings(from time.Time, to time.Time) []time.Time {
+	var trainings []time.Time
+	for hourTime, h := range m.hours {
+		if h.HasTrainingScheduled() && !hourTime.Before(from) && hourTime.Before(to) {
+			trainings = append(trainings, hourTime)
+		}
+	}
+
+	return trainings
+}
+
+func (m MemoryHourRepository) AvailabilityHistory(_ context.Context, from time.Time, to time.Time) ([]query.AvailabilityChange, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	// the same as in the calendar, `to` date includes all hours of this day
+	to = to.AddDate(0, 0, 1)
+
+	var changes []query.AvailabilityChange
+	for i, change := range m.history {
+		if !change.Hour.Before(from) && change.Hour.Before(to) {
+			changes = append(changes, memoryAvailabilityChangeToQuery(i, change))
+		}
+	}
+
+	return changes, nil
+}
+
+func (m MemoryHourRepository) AvailabilityChangesSince(_ context.Context, since time.Time) ([]query.AvailabilityChange, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	var changes []query.AvailabilityChange
+	for i, change := range m.history {
+		if !change.ChangedAt.Before(since) {
+			changes = append(changes, memoryAvailabilityChangeToQuery(i, change))
+		}
+	}
+
+	return changes, nil
+}
+
+func (m MemoryHourRepository) AvailabilityChangesByActor(_ context.Context, actor string) ([]query.AvailabilityChange, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	var changes []query.AvailabilityChange
+	for i, change := range m.history {
+		if change.Actor == actor {
+			changes = append(changes, memoryAvailabilityChangeToQuery(i, change))
+		}
+	}
+
+	return changes, nil
+}
+
+func (m *MemoryHourRepository) EraseActor(_ context.Context, actor string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	for i := range m.history {
+		if m.history[i].Actor == actor {
+			m.history[i].Actor = hour.ErasedActor
+		}
+	}
+
 	return nil
 }
+
+// memoryAvailabilityChangeToQuery uses position of the change in the history as its ID.
+func memoryAvailabilityChangeToQuery(i int, change hour.AvailabilityChange) query.AvailabilityChange {
+	return query.AvailabilityChange{
+		ID:        strconv.Itoa(i),
+		Hour:      change.Hour.UTC(),
+		From:      change.From.String(),
+		To:        change.To.String(),
+		Actor:     change.Actor,
+		Command:   change.Command,
+		ChangedAt: change.ChangedAt.UTC(),
+	}
+}
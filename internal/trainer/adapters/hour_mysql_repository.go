@@ -3,9 +3,11 @@ package adapters
 import (
 	"context"
 	"database/sql"
-	"os"
+	"strconv"
 	"time"
 
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/tracing"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainer/app/query"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainer/domain/hour"
 	"github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
@@ -19,6 +21,16 @@ type mysqlHour struct {
 	Availability string    `db:"availability"`
 }
 
+type mysqlAvailabilityChange struct {
+	ID               int64     `db:"id"`
+	Hour             time.Time `db:"hour"`
+	FromAvailability string    `db:"from_availability"`
+	ToAvailability   string    `db:"to_availability"`
+	Actor            string    `db:"actor"`
+	Command          string    `db:"command"`
+	ChangedAt        time.Time `db:"changed_at"`
+}
+
 type MySQLHourRepository struct {
 	db          *sqlx.DB
 	hourFactory hour.Factory
@@ -40,7 +52,17 @@ type sqlContextGetter interface {
 	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
 }
 
-func (m MySQLHourRepository) GetHour(ctx context.Context, time time.Time) (*hour.Hour, error) {
+// sqlContextSelector is an interface provided both by transaction and standard db connection
+type sqlContextSelector interface {
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+}
+
+func (m MySQLHourRepository) GetHour(ctx context.Context, time time.Time) (h *hour.Hour, err error) {
+	ctx, span := tracing.StartSpan(ctx, "MySQLHourRepository.GetHour")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
 	return m.getOrCreateHour(ctx, m.db, time, false)
 }
 
@@ -78,15 +100,83 @@ func (m MySQLHourRepository) getOrCreateHour(
 	return domainHour, nil
 }
 
+func (m MySQLHourRepository) ScheduledTrainings(ctx context.Context, from time.Time, to time.Time) (scheduledTrainings []time.Time, err error) {
+	ctx, span := tracing.StartSpan(ctx, "MySQLHourRepository.ScheduledTrainings")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	return m.scheduledTrainings(ctx, m.db, from, to)
+}
+
+func (m MySQLHourRepository) scheduledTrainings(
+	ctx context.Context,
+	db sqlContextSelector,
+	from time.Time,
+	to time.Time,
+) ([]time.Time, error) {
+	var trainings []time.Time
+
+	err := db.SelectContext(
+		ctx,
+		&trainings,
+		"SELECT `hour` FROM `hours` WHERE `availability` = ? AND `hour` >= ? AND `hour` < ?",
+		hour.TrainingScheduled.String(),
+		from.UTC(),
+		to.UTC(),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get scheduled trainings from db")
+	}
+
+	for i := range trainings {
+		trainings[i] = trainings[i].Local()
+	}
+
+	return trainings, nil
+}
+
 const mySQLDeadlockErrorCode = 1213
 
 func (m MySQLHourRepository) UpdateHour(
 	ctx context.Context,
 	hourTime time.Time,
+	source hour.ChangeSource,
 	updateFn func(h *hour.Hour) (*hour.Hour, error),
-) error {
+) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "MySQLHourRepository.UpdateHour")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	return m.retryOnDeadlock(func() error {
+		return m.updateHour(ctx, hourTime, source, nil, func(h *hour.Hour, _ []time.Time) (*hour.Hour, error) {
+			return updateFn(h)
+		})
+	})
+}
+
+func (m MySQLHourRepository) UpdateHourWithScheduledTrainings(
+	ctx context.Context,
+	hourTime time.Time,
+	from time.Time,
+	to time.Time,
+	source hour.ChangeSource,
+	updateFn func(h *hour.Hour, scheduledTrainings []time.Time) (*hour.Hour, error),
+) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "MySQLHourRepository.UpdateHourWithScheduledTrainings")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	return m.retryOnDeadlock(func() error {
+		return m.updateHour(ctx, hourTime, source, &scheduledTrainingsPeriod{from: from, to: to}, updateFn)
+	})
+}
+
+func (m MySQLHourRepository) retryOnDeadlock(updateFn func() error) error {
 	for {
-		err := m.updateHour(ctx, hourTime, updateFn)
+		err := updateFn()
 
 		if val, ok := errors.Cause(err).(*mysql.MySQLError); ok && val.Number == mySQLDeadlockErrorCode {
 			continue
@@ -96,10 +186,18 @@ func (m MySQLHourRepository) UpdateHour(
 	}
 }
 
+type scheduledTrainingsPeriod struct {
+	from time.Time
+	to   time.Time
+}
+
+// updateHour reads scheduled trainings in the period and passes them to updateFn, when the period is not nil.
 func (m MySQLHourRepository) updateHour(
 	ctx context.Context,
 	hourTime time.Time,
-	updateFn func(h *hour.Hour) (*hour.Hour, error),
+	source hour.ChangeSource,
+	period *scheduledTrainingsPeriod,
+	updateFn func(h *hour.Hour, scheduledTrainings []time.Time) (*hour.Hour, error),
 ) (err error) {
 	tx, err := m.db.Beginx()
 	if err != nil {
@@ -117,12 +215,28 @@ func (m MySQLHourRepository) updateHour(
 		err = m.finishTransaction(err, tx)
 	}()
 
+	var scheduledTrainings []time.Time
+	if period != nil {
+		// The lock is taken before any other read, so scheduled trainings are read after the transaction,
+		// which held the lock before, is committed.
+		var lockID int
+		if err := tx.GetContext(ctx, &lockID, "SELECT `id` FROM `schedule_lock` WHERE `id` = 1 FOR UPDATE"); err != nil {
+			return errors.Wrap(err, "unable to lock schedule")
+		}
+
+		scheduledTrainings, err = m.scheduledTrainings(ctx, tx, period.from, period.to)
+		if err != nil {
+			return err
+		}
+	}
+
 	existingHour, err := m.getOrCreateHour(ctx, tx, hourTime, true)
 	if err != nil {
 		return err
 	}
+	availabilityBefore := existingHour.Availability()
 
-	updatedHour, err := updateFn(existingHour)
+	updatedHour, err := updateFn(existingHour, scheduledTrainings)
 	if err != nil {
 		return err
 	}
@@ -131,6 +245,12 @@ func (m MySQLHourRepository) updateHour(
 		return err
 	}
 
+	if change, changed := hour.NewAvailabilityChange(availabilityBefore, updatedHour, source, time.Now()); changed {
+		if err := m.insertAvailabilityChange(tx, change); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -158,6 +278,115 @@ func (m MySQLHourRepository) upsertHour(tx *sqlx.Tx, hourToUpdate *hour.Hour) er
 	return nil
 }
 
+func (m MySQLHourRepository) insertAvailabilityChange(tx *sqlx.Tx, change hour.AvailabilityChange) error {
+	_, err := tx.NamedExec(
+		`INSERT INTO 
+			hour_availability_changes (hour, from_availability, to_availability, actor, command, changed_at) 
+		VALUES 
+			(:hour, :from_availability, :to_availability, :actor, :command, :changed_at)`,
+		mysqlAvailabilityChange{
+			Hour:             change.Hour.UTC(),
+			FromAvailability: change.From.String(),
+			ToAvailability:   change.To.String(),
+			Actor:            change.Actor,
+			Command:          change.Command,
+			ChangedAt:        change.ChangedAt.UTC(),
+		},
+	)
+	if err != nil {
+		return errors.Wrap(err, "unable to insert availability change")
+	}
+
+	return nil
+}
+
+func (m MySQLHourRepository) AvailabilityHistory(ctx context.Context, from time.Time, to time.Time) (changes []query.AvailabilityChange, err error) {
+	ctx, span := tracing.StartSpan(ctx, "MySQLHourRepository.AvailabilityHistory")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	// the same as in the calendar, `to` date includes all hours of this day
+	return m.selectAvailabilityChanges(
+		ctx,
+		"SELECT * FROM `hour_availability_changes` WHERE `hour` >= ? AND `hour` < ? ORDER BY `id`",
+		from.UTC(),
+		to.UTC().AddDate(0, 0, 1),
+	)
+}
+
+func (m MySQLHourRepository) AvailabilityChangesSince(ctx context.Context, since time.Time) (changes []query.AvailabilityChange, err error) {
+	ctx, span := tracing.StartSpan(ctx, "MySQLHourRepository.AvailabilityChangesSince")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	return m.selectAvailabilityChanges(
+		ctx,
+		"SELECT * FROM `hour_availability_changes` WHERE `changed_at` >= ? ORDER BY `changed_at`, `id`",
+		since.UTC(),
+	)
+}
+
+func (m MySQLHourRepository) AvailabilityChangesByActor(ctx context.Context, actor string) (changes []query.AvailabilityChange, err error) {
+	ctx, span := tracing.StartSpan(ctx, "MySQLHourRepository.AvailabilityChangesByActor")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	return m.selectAvailabilityChanges(
+		ctx,
+		"SELECT * FROM `hour_availability_changes` WHERE `actor` = ? ORDER BY `id`",
+		actor,
+	)
+}
+
+func (m MySQLHourRepository) EraseActor(ctx context.Context, actor string) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "MySQLHourRepository.EraseActor")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	_, err = m.db.ExecContext(
+		ctx,
+		"UPDATE `hour_availability_changes` SET `actor` = ? WHERE `actor` = ?",
+		hour.ErasedActor,
+		actor,
+	)
+	if err != nil {
+		return errors.Wrap(err, "unable to erase actor of availability changes")
+	}
+
+	return nil
+}
+
+func (m MySQLHourRepository) selectAvailabilityChanges(
+	ctx context.Context,
+	sqlQuery string,
+	args ...interface{},
+) ([]query.AvailabilityChange, error) {
+	var dbChanges []mysqlAvailabilityChange
+
+	if err := m.db.SelectContext(ctx, &dbChanges, sqlQuery, args...); err != nil {
+		return nil, errors.Wrap(err, "unable to get availability changes from db")
+	}
+
+	var changes []query.AvailabilityChange
+	for _, dbChange := range dbChanges {
+		changes = append(changes, query.AvailabilityChange{
+			ID:        strconv.FormatInt(dbChange.ID, 10),
+			Hour:      dbChange.Hour.UTC(),
+			From:      dbChange.FromAvailability,
+			To:        dbChange.ToAvailability,
+			Actor:     dbChange.Actor,
+			Command:   dbChange.Command,
+			ChangedAt: dbChange.ChangedAt.UTC(),
+		})
+	}
+
+	return changes, nil
+}
+
 // finishTransaction rollbacks transaction if error is provided.
 // If err is nil transaction is committed.
 //
@@ -178,21 +407,3 @@ func (m MySQLHourRepository) finishTransaction(err error, tx *sqlx.Tx) error {
 		return nil
 	}
 }
-
-func NewMySQLConnection() (*sqlx.DB, error) {
-	config := mysql.NewConfig()
-
-	config.Net = "tcp"
-	config.Addr = os.Getenv("MYSQL_ADDR")
-	config.User = os.Getenv("MYSQL_USER")
-	config.Passwd = os.Getenv("MYSQL_PASSWORD")
-	config.DBName = os.Getenv("MYSQL_DATABASE")
-	config.ParseTime = true // with that parameter, we can use time.Time in mysqlHour.Hour
-
-	db, err := sqlx.Connect("mysql", config.FormatDSN())
-	if err != nil {
-		return nil, errors.Wrap(err, "cannot connect to MySQL")
-	}
-
-	return db, nil
-}
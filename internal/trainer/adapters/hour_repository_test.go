@@ -5,14 +5,18 @@ import (
 	"errors"
 	"math/rand"
 	"os"
+	"strconv"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/mysqldb"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainer/adapters"
 
 	"cloud.google.com/go/firestore"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainer/app/query"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainer/domain/hour"
+	"github.com/jmoiron/sqlx"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -42,6 +46,10 @@ func TestRepository(t *testing.T) {
 				t.Parallel()
 				testUpdateHour_parallel(t, r.Repository)
 			})
+			t.Run("testUpdateHourWithScheduledTrainings_parallel", func(t *testing.T) {
+				t.Parallel()
+				testUpdateHourWithScheduledTrainings_parallel(t, r.Repository)
+			})
 			t.Run("testHourRepository_update_existing", func(t *testing.T) {
 				t.Parallel()
 				testHourRepository_update_existing(t, r.Repository)
@@ -50,28 +58,53 @@ func TestRepository(t *testing.T) {
 				t.Parallel()
 				testUpdateHour_rollback(t, r.Repository)
 			})
+			t.Run("testScheduledTrainings", func(t *testing.T) {
+				t.Parallel()
+				testScheduledTrainings(t, r.Repository)
+			})
+			t.Run("testAvailabilityHistory", func(t *testing.T) {
+				t.Parallel()
+				testAvailabilityHistory(t, r.Repository, r.History)
+			})
+			t.Run("testEraseActor", func(t *testing.T) {
+				t.Parallel()
+				testEraseActor(t, r.Repository, r.History)
+			})
 		})
 	}
 }
 
+type historyReadModel interface {
+	query.HourAvailabilityHistoryReadModel
+	query.UserAvailabilityChangesReadModel
+}
+
 type Repository struct {
 	Name       string
 	Repository hour.Repository
+	History    historyReadModel
 }
 
 func createRepositories(t *testing.T) []Repository {
+	firebaseRepository := newFirebaseRepository(t, context.Background())
+	mySQLRepository := newMySQLRepository(t)
+	memoryRepository := adapters.NewMemoryHourRepository(testHourFactory)
+
 	return []Repository{
 		{
 			Name:       "Firebase",
-			Repository: newFirebaseRepository(t, context.Background()),
+			Repository: firebaseRepository,
+			History:    firebaseRepository,
 		},
 		{
 			Name:       "MySQL",
-			Repository: newMySQLRepository(t),
+			Repository: mySQLRepository,
+			History:    mySQLRepository,
 		},
 		{
 			Name:       "memory",
-			Repository: adapters.NewMemoryHourRepository(testHourFactory),
+			Repository: memoryRepository,
+			History:    memoryRepository,
 		},
 	}
 }
@@ -116,7 +149,7 @@ func testUpdateHour(t *testing.T, repository hour.Repository) {
 			t.Parallel()
 			newHour := tc.CreateHour(t)
 
-			err := repository.UpdateHour(ctx, newHour.Time(), func(_ *hour.Hour) (*hour.Hour, error) {
+			err := repository.UpdateHour(ctx, newHour.Time(), testChangeSource, func(_ *hour.Hour) (*hour.Hour, error) {
 				// UpdateHour provides us existing/new *hour.Hour,
 				// but we are ignoring this hour and persisting result of `CreateHour`
 				// we can assert this hour later in assertHourInRepository
@@ -141,7 +174,7 @@ func testUpdateHour_parallel(t *testing.T, repository hour.Repository) {
 	hourTime := newValidHourTime()
 
 	// we are adding available hour
-	err := repository.UpdateHour(ctx, hourTime, func(h *hour.Hour) (*hour.Hour, error) {
+	err := repository.UpdateHour(ctx, hourTime, testChangeSource, func(h *hour.Hour) (*hour.Hour, error) {
 		if err := h.MakeAvailable(); err != nil {
 			return nil, err
 		}
@@ -169,7 +202,7 @@ func testUpdateHour_parallel(t *testing.T, repository hour.Repository) {
 
 			schedulingTraining := false
 
-			err := repository.UpdateHour(ctx, hourTime, func(h *hour.Hour) (*hour.Hour, error) {
+			err := repository.UpdateHour(ctx, hourTime, testChangeSource, func(h *hour.Hour) (*hour.Hour, error) {
 				// training is already scheduled, nothing to do there
 				if h.HasTrainingScheduled() {
 					return h, nil
@@ -206,19 +239,125 @@ func testUpdateHour_parallel(t *testing.T, repository hour.Repository) {
 	assert.Len(t, workersScheduledTraining, 1, "only one worker should schedule training")
 }
 
+func testUpdateHourWithScheduledTrainings_parallel(t *testing.T, repository hour.Repository) {
+	if _, ok := repository.(*adapters.FirestoreHourRepository); ok {
+		// todo - enable after fix of https://github.com/googleapis/google-cloud-go/issues/2604
+		t.Skip("because of emulator bug, it's not working in Firebase")
+	}
+
+	t.Helper()
+	ctx := context.Background()
+
+	const maxTrainings = 3
+	workersCount := 10
+
+	// each worker schedules the training in a different hour
+	hours := map[int64]bool{}
+	var hourTimes []time.Time
+	for i := 0; i < workersCount; i++ {
+		hourTime := newValidHourTime()
+		hours[hourTime.Unix()] = true
+		hourTimes = append(hourTimes, hourTime)
+
+		err := repository.UpdateHour(ctx, hourTime, testChangeSource, func(h *hour.Hour) (*hour.Hour, error) {
+			if err := h.MakeAvailable(); err != nil {
+				return nil, err
+			}
+			return h, nil
+		})
+		require.NoError(t, err)
+	}
+
+	// the period contains all hours of workers
+	from, to := hourTimes[0], hourTimes[0]
+	for _, hourTime := range hourTimes {
+		if hourTime.Before(from) {
+			from = hourTime
+		}
+		if !hourTime.Before(to) {
+			to = hourTime.Add(time.Hour)
+		}
+	}
+
+	workersDone := sync.WaitGroup{}
+	workersDone.Add(workersCount)
+
+	// closing startWorkers will unblock all workers at once,
+	// thanks to that it will be more likely to have race condition
+	startWorkers := make(chan struct{})
+	// if training was successfully scheduled, number of the worker is sent to this channel
+	trainingsScheduled := make(chan int, workersCount)
+
+	for worker := 0; worker < workersCount; worker++ {
+		workerNum := worker
+
+		go func() {
+			defer workersDone.Done()
+			<-startWorkers
+
+			err := repository.UpdateHourWithScheduledTrainings(
+				ctx,
+				hourTimes[workerNum],
+				from,
+				to,
+				testChangeSource,
+				func(h *hour.Hour, scheduledTrainings []time.Time) (*hour.Hour, error) {
+					// other tests schedule trainings in the same period, so only hours of this test are counted
+					count := 0
+					for _, training := range scheduledTrainings {
+						if hours[training.Unix()] {
+							count++
+						}
+					}
+					if count >= maxTrainings {
+						return nil, errors.New("too many trainings")
+					}
+
+					if err := h.ScheduleTraining(); err != nil {
+						return nil, err
+					}
+					return h, nil
+				},
+			)
+			if err == nil {
+				trainingsScheduled <- workerNum
+			}
+		}()
+	}
+
+	close(startWorkers)
+
+	// we are waiting, when all workers did the job
+	workersDone.Wait()
+	close(trainingsScheduled)
+
+	assert.Len(t, trainingsScheduled, maxTrainings, "the limit of trainings should not be exceeded")
+
+	scheduledTrainings, err := repository.ScheduledTrainings(ctx, from, to)
+	require.NoError(t, err)
+
+	scheduledCount := 0
+	for _, training := range scheduledTrainings {
+		if hours[training.Unix()] {
+			scheduledCount++
+		}
+	}
+	assert.Equal(t, maxTrainings, scheduledCount)
+}
+
 func testUpdateHour_rollback(t *testing.T, repository hour.Repository) {
 	t.Helper()
 	ctx := context.Background()
 
 	hourTime := newValidHourTime()
 
-	err := repository.UpdateHour(ctx, hourTime, func(h *hour.Hour) (*hour.Hour, error) {
+	err := repository.UpdateHour(ctx, hourTime, testChangeSource, func(h *hour.Hour) (*hour.Hour, error) {
 		require.NoError(t, h.MakeAvailable())
 		return h, nil
 	})
 	require.NoError(t, err)
 
-	err = repository.UpdateHour(ctx, hourTime, func(h *hour.Hour) (*hour.Hour, error) {
+	err = repository.UpdateHour(ctx, hourTime, testChangeSource, func(h *hour.Hour) (*hour.Hour, error) {
 		assert.True(t, h.IsAvailable())
 		require.NoError(t, h.MakeNotAvailable())
 
@@ -239,14 +378,14 @@ func testHourRepository_update_existing(t *testing.T, repository hour.Repository
 
 	testHour := newValidAvailableHour(t)
 
-	err := repository.UpdateHour(ctx, testHour.Time(), func(_ *hour.Hour) (*hour.Hour, error) {
+	err := repository.UpdateHour(ctx, testHour.Time(), testChangeSource, func(_ *hour.Hour) (*hour.Hour, error) {
 		return testHour, nil
 	})
 	require.NoError(t, err)
 	assertHourInRepository(ctx, t, repository, testHour)
 
 	var expectedHour *hour.Hour
-	err = repository.UpdateHour(ctx, testHour.Time(), func(h *hour.Hour) (*hour.Hour, error) {
+	err = repository.UpdateHour(ctx, testHour.Time(), testChangeSource, func(h *hour.Hour) (*hour.Hour, error) {
 		if err := h.ScheduleTraining(); err != nil {
 			return nil, err
 		}
@@ -258,6 +397,121 @@ func testHourRepository_update_existing(t *testing.T, repository hour.Repository
 	assertHourInRepository(ctx, t, repository, expectedHour)
 }
 
+func testScheduledTrainings(t *testing.T, repository hour.Repository) {
+	t.Helper()
+	ctx := context.Background()
+
+	trainingHour := newValidAvailableHour(t)
+	require.NoError(t, trainingHour.ScheduleTraining())
+
+	err := repository.UpdateHour(ctx, trainingHour.Time(), testChangeSource, func(_ *hour.Hour) (*hour.Hour, error) {
+		return trainingHour, nil
+	})
+	require.NoError(t, err)
+
+	availableHour := newValidAvailableHour(t)
+
+	err = repository.UpdateHour(ctx, availableHour.Time(), testChangeSource, func(_ *hour.Hour) (*hour.Hour, error) {
+		return availableHour, nil
+	})
+	require.NoError(t, err)
+
+	trainings, err := repository.ScheduledTrainings(ctx, trainingHour.Time(), trainingHour.Time().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{trainingHour.Time()}, trainings)
+
+	trainings, err = repository.ScheduledTrainings(ctx, availableHour.Time(), availableHour.Time().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, trainings)
+}
+
+func testAvailabilityHistory(t *testing.T, repository hour.Repository, history query.HourAvailabilityHistoryReadModel) {
+	t.Helper()
+	ctx := context.Background()
+
+	hourTime := newValidHourTime()
+
+	makeAvailableSource := hour.ChangeSource{Actor: "trainer-uuid", Command: "test:make-available"}
+	err := repository.UpdateHour(ctx, hourTime, makeAvailableSource, func(h *hour.Hour) (*hour.Hour, error) {
+		require.NoError(t, h.MakeAvailable())
+		return h, nil
+	})
+	require.NoError(t, err)
+
+	// availability is not changed, so nothing should be recorded
+	err = repository.UpdateHour(ctx, hourTime, testChangeSource, func(h *hour.Hour) (*hour.Hour, error) {
+		return h, nil
+	})
+	require.NoError(t, err)
+
+	// rolled back change should be not recorded
+	err = repository.UpdateHour(ctx, hourTime, testChangeSource, func(h *hour.Hour) (*hour.Hour, error) {
+		require.NoError(t, h.MakeNotAvailable())
+		return nil, errors.New("something went wrong")
+	})
+	require.Error(t, err)
+
+	scheduleSource := hour.ChangeSource{Actor: "attendee-uuid", Command: "test:schedule-training"}
+	err = repository.UpdateHour(ctx, hourTime, scheduleSource, func(h *hour.Hour) (*hour.Hour, error) {
+		require.NoError(t, h.ScheduleTraining())
+		return h, nil
+	})
+	require.NoError(t, err)
+
+	day := hourTime.UTC().Truncate(time.Hour * 24)
+	allChanges, err := history.AvailabilityHistory(ctx, day, day)
+	require.NoError(t, err)
+
+	var changes []query.AvailabilityChange
+	for _, c := range allChanges {
+		// other tests may use the same day
+		if c.Hour.Equal(hourTime) {
+			changes = append(changes, c)
+		}
+	}
+
+	require.Len(t, changes, 2)
+
+	assert.Equal(t, hour.NotAvailable.String(), changes[0].From)
+	assert.Equal(t, hour.Available.String(), changes[0].To)
+	assert.Equal(t, makeAvailableSource.Actor, changes[0].Actor)
+	assert.Equal(t, makeAvailableSource.Command, changes[0].Command)
+
+	assert.Equal(t, hour.Available.String(), changes[1].From)
+	assert.Equal(t, hour.TrainingScheduled.String(), changes[1].To)
+	assert.Equal(t, scheduleSource.Actor, changes[1].Actor)
+	assert.Equal(t, scheduleSource.Command, changes[1].Command)
+
+	assert.False(t, changes[1].ChangedAt.Before(changes[0].ChangedAt))
+	assert.WithinDuration(t, time.Now(), changes[1].ChangedAt, time.Minute)
+}
+
+func testEraseActor(t *testing.T, repository hour.Repository, history query.UserAvailabilityChangesReadModel) {
+	t.Helper()
+	ctx := context.Background()
+
+	hourTime := newValidHourTime()
+	actor := "trainer-" + strconv.FormatInt(rand.Int63(), 10)
+
+	source := hour.ChangeSource{Actor: actor, Command: "test:make-available"}
+	err := repository.UpdateHour(ctx, hourTime, source, func(h *hour.Hour) (*hour.Hour, error) {
+		require.NoError(t, h.MakeAvailable())
+		return h, nil
+	})
+	require.NoError(t, err)
+
+	changes, err := history.AvailabilityChangesByActor(ctx, actor)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.True(t, changes[0].Hour.Equal(hourTime))
+
+	require.NoError(t, repository.EraseActor(ctx, actor))
+
+	changes, err = history.AvailabilityChangesByActor(ctx, actor)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
 func TestNewDateDTO(t *testing.T) {
 	t.Parallel()
 	testCases := []struct {
@@ -286,6 +540,8 @@ func TestNewDateDTO(t *testing.T) {
 	}
 }
 
+var testChangeSource = hour.ChangeSource{Actor: "test", Command: "test"}
+
 // in general global state is not the best idea, but sometimes rules have some exceptions!
 // in tests it's just simpler to re-use one instance of the factory
 var testHourFactory = hour.MustNewFactory(hour.FactoryConfig{
@@ -304,10 +560,16 @@ func newFirebaseRepository(t *testing.T, ctx context.Context) *adapters.Firestor
 }
 
 func newMySQLRepository(t *testing.T) *adapters.MySQLHourRepository {
-	db, err := adapters.NewMySQLConnection()
+	return adapters.NewMySQLHourRepository(newMySQLConnection(t), testHourFactory)
+}
+
+func newMySQLConnection(t *testing.T) *sqlx.DB {
+	db, err := mysqldb.NewConnection()
 	require.NoError(t, err)
 
-	return adapters.NewMySQLHourRepository(db, testHourFactory)
+	require.NoError(t, adapters.MigrateMySQL(context.Background(), db))
+
+	return db
 }
 
 func newValidAvailableHour(t *testing.T) *hour.Hour {
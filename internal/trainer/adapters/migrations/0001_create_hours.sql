@@ -1,4 +1,5 @@
-CREATE TABLE `hours`
+-- databases created from the old sql/schema.sql already have this table, but no applied migrations
+CREATE TABLE IF NOT EXISTS `hours`
 (
     hour         TIMESTAMP                                                 NOT NULL DEFAULT 0,
     availability ENUM ('available', 'not_available', 'training_scheduled') NOT NULL,
@@ -0,0 +1,8 @@
+CREATE TABLE `factory_config`
+(
+    id                             TINYINT NOT NULL,
+    max_weeks_in_the_future_to_set INT     NOT NULL,
+    min_utc_hour                   TINYINT NOT NULL,
+    max_utc_hour                   TINYINT NOT NULL,
+    PRIMARY KEY (id)
+);
@@ -0,0 +1,12 @@
+CREATE TABLE `hour_availability_changes`
+(
+    id                BIGINT                                                    NOT NULL AUTO_INCREMENT,
+    hour              TIMESTAMP                                                 NOT NULL DEFAULT 0,
+    from_availability ENUM ('available', 'not_available', 'training_scheduled') NOT NULL,
+    to_availability   ENUM ('available', 'not_available', 'training_scheduled') NOT NULL,
+    actor             VARCHAR(255)                                              NOT NULL,
+    command           VARCHAR(255)                                              NOT NULL,
+    changed_at        TIMESTAMP(6)                                              NOT NULL DEFAULT 0,
+    PRIMARY KEY (id),
+    INDEX (hour)
+);
@@ -0,0 +1 @@
+CREATE INDEX `hour_availability_changes_changed_at` ON `hour_availability_changes` (changed_at);
@@ -0,0 +1 @@
+CREATE INDEX `hour_availability_changes_actor` ON `hour_availability_changes` (actor);
@@ -0,0 +1,6 @@
+CREATE TABLE `schedule_lock`
+(
+    id TINYINT NOT NULL,
+    PRIMARY KEY (id)
+);
+INSERT INTO `schedule_lock` (id) VALUES (1);
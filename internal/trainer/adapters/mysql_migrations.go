@@ -0,0 +1,22 @@
+package adapters
+
+import (
+	"context"
+	"embed"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/mysqldb"
+	"github.com/jmoiron/sqlx"
+)
+
+// Migrations are named `<version>_<description>.sql`.
+// Already applied migrations should never be modified - add a new one instead.
+//
+//go:embed migrations/*.sql
+var mysqlMigrations embed.FS
+
+const mysqlMigrationsTable = "trainer_schema_migrations"
+
+// MigrateMySQL applies all not yet applied migrations of the trainer service.
+func MigrateMySQL(ctx context.Context, db *sqlx.DB) error {
+	return mysqldb.Migrate(ctx, db, mysqlMigrations, mysqlMigrationsTable)
+}
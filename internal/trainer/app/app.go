@@ -16,9 +16,18 @@ type Commands struct {
 
 	MakeHoursAvailable   command.MakeHoursAvailableHandler
 	MakeHoursUnavailable command.MakeHoursUnavailableHandler
+
+	UpdateFactoryConfig command.UpdateFactoryConfigHandler
+
+	EraseUserData command.EraseUserDataHandler
 }
 
 type Queries struct {
 	HourAvailability      query.HourAvailabilityHandler
 	TrainerAvailableHours query.AvailableHoursHandler
+	CurrentFactoryConfig  query.CurrentFactoryConfigHandler
+
+	HourAvailabilityHistory query.HourAvailabilityHistoryHandler
+	AvailabilityChanges     query.AvailabilityChangesHandler
+	UserAvailabilityChanges query.UserAvailabilityChangesHandler
 }
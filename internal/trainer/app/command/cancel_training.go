@@ -5,13 +5,14 @@ import (
 	"time"
 
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/decorator"
-	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/errors"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainer/domain/hour"
 	"github.com/sirupsen/logrus"
 )
 
 type CancelTraining struct {
 	Hour time.Time
+
+	Source hour.ChangeSource
 }
 
 type CancelTrainingHandler decorator.CommandHandler[CancelTraining]
@@ -37,13 +38,13 @@ func NewCancelTrainingHandler(
 }
 
 func (h cancelTrainingHandler) Handle(ctx context.Context, cmd CancelTraining) error {
-	if err := h.hourRepo.UpdateHour(ctx, cmd.Hour, func(h *hour.Hour) (*hour.Hour, error) {
+	if err := h.hourRepo.UpdateHour(ctx, cmd.Hour, cmd.Source, func(h *hour.Hour) (*hour.Hour, error) {
 		if err := h.CancelTraining(); err != nil {
 			return nil, err
 		}
 		return h, nil
 	}); err != nil {
-		return errors.NewSlugError(err.Error(), "unable-to-update-availability")
+		return domainErrorToSlugError(err, "unable-to-update-availability")
 	}
 
 	return nil
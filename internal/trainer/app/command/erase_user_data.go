@@ -0,0 +1,49 @@
+package command
+
+import (
+	"context"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/decorator"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/errors"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainer/domain/hour"
+	"github.com/sirupsen/logrus"
+)
+
+// EraseUserData removes the user from the availability history, the changes itself are kept.
+type EraseUserData struct {
+	UserUUID string
+}
+
+type EraseUserDataHandler decorator.CommandHandler[EraseUserData]
+
+type eraseUserDataHandler struct {
+	hourRepo hour.Repository
+}
+
+func NewEraseUserDataHandler(
+	hourRepo hour.Repository,
+	logger *logrus.Entry,
+	metricsClient decorator.MetricsClient,
+) EraseUserDataHandler {
+	if hourRepo == nil {
+		panic("nil hourRepo")
+	}
+
+	return decorator.ApplyCommandDecorators[EraseUserData](
+		eraseUserDataHandler{hourRepo: hourRepo},
+		logger,
+		metricsClient,
+	)
+}
+
+func (h eraseUserDataHandler) Handle(ctx context.Context, cmd EraseUserData) error {
+	if cmd.UserUUID == "" {
+		return errors.NewIncorrectInputError("empty user UUID", "empty-user-uuid")
+	}
+
+	if err := h.hourRepo.EraseActor(ctx, cmd.UserUUID); err != nil {
+		return errors.NewSlugError(err.Error(), "unable-to-erase-user-data")
+	}
+
+	return nil
+}
@@ -0,0 +1,15 @@
+package command
+
+import (
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/errors"
+)
+
+// domainErrorToSlugError keeps domain errors, which explain why the command was rejected,
+// and returns other errors (like database errors) as unknown errors with the fallbackSlug.
+func domainErrorToSlugError(err error, fallbackSlug string) error {
+	if _, ok := errors.AsSlugError(err); ok {
+		return err
+	}
+
+	return errors.NewSlugError(err.Error(), fallbackSlug)
+}
@@ -5,13 +5,14 @@ import (
 	"time"
 
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/decorator"
-	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/errors"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainer/domain/hour"
 	"github.com/sirupsen/logrus"
 )
 
 type MakeHoursAvailable struct {
 	Hours []time.Time
+
+	Source hour.ChangeSource
 }
 
 type MakeHoursAvailableHandler decorator.CommandHandler[MakeHoursAvailable]
@@ -38,13 +39,13 @@ func NewMakeHoursAvailableHandler(
 
 func (c makeHoursAvailableHandler) Handle(ctx context.Context, cmd MakeHoursAvailable) error {
 	for _, hourToUpdate := range cmd.Hours {
-		if err := c.hourRepo.UpdateHour(ctx, hourToUpdate, func(h *hour.Hour) (*hour.Hour, error) {
+		if err := c.hourRepo.UpdateHour(ctx, hourToUpdate, cmd.Source, func(h *hour.Hour) (*hour.Hour, error) {
 			if err := h.MakeAvailable(); err != nil {
 				return nil, err
 			}
 			return h, nil
 		}); err != nil {
-			return errors.NewSlugError(err.Error(), "unable-to-update-availability")
+			return domainErrorToSlugError(err, "unable-to-update-availability")
 		}
 	}
 
@@ -5,45 +5,77 @@ import (
 	"time"
 
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/decorator"
-	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/errors"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainer/domain/hour"
 	"github.com/sirupsen/logrus"
 )
 
 type ScheduleTraining struct {
 	Hour time.Time
+
+	Source hour.ChangeSource
 }
 
 type ScheduleTrainingHandler decorator.CommandHandler[ScheduleTraining]
 
 type scheduleTrainingHandler struct {
-	hourRepo hour.Repository
+	hourRepo        hour.Repository
+	schedulingRules hour.SchedulingRules
 }
 
 func NewScheduleTrainingHandler(
 	hourRepo hour.Repository,
+	schedulingRules hour.SchedulingRules,
 	logger *logrus.Entry,
 	metricsClient decorator.MetricsClient,
 ) ScheduleTrainingHandler {
 	if hourRepo == nil {
 		panic("nil hourRepo")
 	}
+	if err := schedulingRules.Validate(); err != nil {
+		panic(err)
+	}
 
 	return decorator.ApplyCommandDecorators[ScheduleTraining](
-		scheduleTrainingHandler{hourRepo: hourRepo},
+		scheduleTrainingHandler{hourRepo: hourRepo, schedulingRules: schedulingRules},
 		logger,
 		metricsClient,
 	)
 }
 
 func (h scheduleTrainingHandler) Handle(ctx context.Context, cmd ScheduleTraining) error {
-	if err := h.hourRepo.UpdateHour(ctx, cmd.Hour, func(h *hour.Hour) (*hour.Hour, error) {
-		if err := h.ScheduleTraining(); err != nil {
-			return nil, err
-		}
-		return h, nil
-	}); err != nil {
-		return errors.NewSlugError(err.Error(), "unable-to-update-availability")
+	// Scheduling rules depend on other hours, so they are checked with trainings read in the same transaction,
+	// which is serialized with other trainings being scheduled.
+	from, to := h.schedulingRules.ScheduledTrainingsPeriod(cmd.Hour)
+
+	var rulesErr error
+	err := h.hourRepo.UpdateHourWithScheduledTrainings(
+		ctx,
+		cmd.Hour,
+		from,
+		to,
+		cmd.Source,
+		func(hr *hour.Hour, scheduledTrainings []time.Time) (*hour.Hour, error) {
+			// updateFn is called again, when the transaction is retried
+			rulesErr = nil
+
+			// availability is checked first, so booking an already taken hour is not reported as breaking the rules
+			if err := hr.ScheduleTraining(); err != nil {
+				return nil, err
+			}
+
+			if err := h.schedulingRules.CanScheduleTraining(cmd.Hour, scheduledTrainings, time.Now()); err != nil {
+				rulesErr = err
+				return nil, err
+			}
+
+			return hr, nil
+		},
+	)
+	if rulesErr != nil {
+		return domainErrorToSlugError(rulesErr, "unable-to-schedule-training")
+	}
+	if err != nil {
+		return domainErrorToSlugError(err, "unable-to-update-availability")
 	}
 
 	return nil
@@ -0,0 +1,59 @@
+package command
+
+import (
+	"context"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/decorator"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/errors"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainer/domain/hour"
+	"github.com/sirupsen/logrus"
+)
+
+type UpdateFactoryConfig struct {
+	Config hour.FactoryConfig
+}
+
+type UpdateFactoryConfigHandler decorator.CommandHandler[UpdateFactoryConfig]
+
+type updateFactoryConfigHandler struct {
+	configRepo  hour.FactoryConfigRepository
+	hourFactory hour.Factory
+}
+
+func NewUpdateFactoryConfigHandler(
+	configRepo hour.FactoryConfigRepository,
+	hourFactory hour.Factory,
+	logger *logrus.Entry,
+	metricsClient decorator.MetricsClient,
+) UpdateFactoryConfigHandler {
+	if configRepo == nil {
+		panic("nil configRepo")
+	}
+	if hourFactory.IsZero() {
+		panic("missing hourFactory")
+	}
+
+	return decorator.ApplyCommandDecorators[UpdateFactoryConfig](
+		updateFactoryConfigHandler{configRepo: configRepo, hourFactory: hourFactory},
+		logger,
+		metricsClient,
+	)
+}
+
+func (h updateFactoryConfigHandler) Handle(ctx context.Context, cmd UpdateFactoryConfig) error {
+	if err := cmd.Config.Validate(); err != nil {
+		return errors.NewIncorrectInputError(err.Error(), "invalid-factory-config")
+	}
+
+	if err := h.configRepo.UpdateFactoryConfig(ctx, cmd.Config); err != nil {
+		return errors.NewSlugError(err.Error(), "unable-to-update-factory-config")
+	}
+
+	// other instances will pick up the new config with the next refresh,
+	// this one can use it immediately
+	if err := h.hourFactory.UpdateConfig(cmd.Config); err != nil {
+		return errors.NewSlugError(err.Error(), "unable-to-update-factory-config")
+	}
+
+	return nil
+}
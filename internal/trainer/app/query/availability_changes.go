@@ -0,0 +1,44 @@
+package query
+
+import (
+	"context"
+	"time"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/decorator"
+	"github.com/sirupsen/logrus"
+)
+
+// AvailabilityChanges returns availability changes of all hours made since ChangedSince, ordered by ChangedAt.
+type AvailabilityChanges struct {
+	ChangedSince time.Time
+}
+
+type AvailabilityChangesHandler decorator.QueryHandler[AvailabilityChanges, []AvailabilityChange]
+
+type AvailabilityChangesReadModel interface {
+	AvailabilityChangesSince(ctx context.Context, since time.Time) ([]AvailabilityChange, error)
+}
+
+type availabilityChangesHandler struct {
+	readModel AvailabilityChangesReadModel
+}
+
+func NewAvailabilityChangesHandler(
+	readModel AvailabilityChangesReadModel,
+	logger *logrus.Entry,
+	metricsClient decorator.MetricsClient,
+) AvailabilityChangesHandler {
+	if readModel == nil {
+		panic("nil readModel")
+	}
+
+	return decorator.ApplyQueryDecorators[AvailabilityChanges, []AvailabilityChange](
+		availabilityChangesHandler{readModel: readModel},
+		logger,
+		metricsClient,
+	)
+}
+
+func (h availabilityChangesHandler) Handle(ctx context.Context, query AvailabilityChanges) ([]AvailabilityChange, error) {
+	return h.readModel.AvailabilityChangesSince(ctx, query.ChangedSince)
+}
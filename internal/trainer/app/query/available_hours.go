@@ -38,7 +38,7 @@ func NewAvailableHoursHandler(
 
 func (h availableHoursHandler) Handle(ctx context.Context, query AvailableHours) (d []Date, err error) {
 	if query.From.After(query.To) {
-		return nil, errors.NewIncorrectInputError("date-from-after-date-to", "Date from after date to")
+		return nil, errors.NewIncorrectInputError("date from after date to", "date-from-after-date-to")
 	}
 
 	return h.readModel.AvailableHours(ctx, query.From, query.To)
@@ -0,0 +1,52 @@
+package query
+
+import (
+	"context"
+	"time"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/decorator"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainer/domain/hour"
+	"github.com/sirupsen/logrus"
+)
+
+type CurrentFactoryConfig struct{}
+
+type CurrentFactoryConfigHandler decorator.QueryHandler[CurrentFactoryConfig, FactoryConfig]
+
+type FactoryConfigReadModel interface {
+	GetFactoryConfig(ctx context.Context) (hour.FactoryConfig, error)
+}
+
+type currentFactoryConfigHandler struct {
+	readModel FactoryConfigReadModel
+}
+
+func NewCurrentFactoryConfigHandler(
+	readModel FactoryConfigReadModel,
+	logger *logrus.Entry,
+	metricsClient decorator.MetricsClient,
+) CurrentFactoryConfigHandler {
+	if readModel == nil {
+		panic("nil readModel")
+	}
+
+	return decorator.ApplyQueryDecorators[CurrentFactoryConfig, FactoryConfig](
+		currentFactoryConfigHandler{readModel: readModel},
+		logger,
+		metricsClient,
+	)
+}
+
+func (h currentFactoryConfigHandler) Handle(ctx context.Context, _ CurrentFactoryConfig) (FactoryConfig, error) {
+	fc, err := h.readModel.GetFactoryConfig(ctx)
+	if err != nil {
+		return FactoryConfig{}, err
+	}
+
+	return FactoryConfig{
+		MaxWeeksInTheFutureToSet: fc.MaxWeeksInTheFutureToSet,
+		MinUtcHour:               fc.MinUtcHour,
+		MaxUtcHour:               fc.MaxUtcHour,
+		MaxTimeToSet:             fc.MaxTimeToSet(time.Now()).UTC(),
+	}, nil
+}
@@ -0,0 +1,50 @@
+package query
+
+import (
+	"context"
+	"time"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/decorator"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// HourAvailabilityHistory returns availability changes of all hours from From date to To date (inclusive).
+type HourAvailabilityHistory struct {
+	From time.Time
+	To   time.Time
+}
+
+type HourAvailabilityHistoryHandler decorator.QueryHandler[HourAvailabilityHistory, []AvailabilityChange]
+
+type HourAvailabilityHistoryReadModel interface {
+	AvailabilityHistory(ctx context.Context, from time.Time, to time.Time) ([]AvailabilityChange, error)
+}
+
+type hourAvailabilityHistoryHandler struct {
+	readModel HourAvailabilityHistoryReadModel
+}
+
+func NewHourAvailabilityHistoryHandler(
+	readModel HourAvailabilityHistoryReadModel,
+	logger *logrus.Entry,
+	metricsClient decorator.MetricsClient,
+) HourAvailabilityHistoryHandler {
+	if readModel == nil {
+		panic("nil readModel")
+	}
+
+	return decorator.ApplyQueryDecorators[HourAvailabilityHistory, []AvailabilityChange](
+		hourAvailabilityHistoryHandler{readModel: readModel},
+		logger,
+		metricsClient,
+	)
+}
+
+func (h hourAvailabilityHistoryHandler) Handle(ctx context.Context, query HourAvailabilityHistory) ([]AvailabilityChange, error) {
+	if query.From.After(query.To) {
+		return nil, errors.NewIncorrectInputError("date from after date to", "date-from-after-date-to")
+	}
+
+	return h.readModel.AvailabilityHistory(ctx, query.From, query.To)
+}
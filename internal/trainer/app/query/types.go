@@ -15,3 +15,23 @@ type Hour struct {
 	HasTrainingScheduled bool
 	Hour                 time.Time
 }
+
+type FactoryConfig struct {
+	MaxWeeksInTheFutureToSet int
+	MinUtcHour               int
+	MaxUtcHour               int
+
+	MaxTimeToSet time.Time
+}
+
+type AvailabilityChange struct {
+	ID string
+
+	Hour time.Time
+	From string
+	To   string
+
+	Actor     string
+	Command   string
+	ChangedAt time.Time
+}
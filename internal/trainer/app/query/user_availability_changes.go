@@ -0,0 +1,43 @@
+package query
+
+import (
+	"context"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/decorator"
+	"github.com/sirupsen/logrus"
+)
+
+// UserAvailabilityChanges returns availability changes made by the user, ordered by ChangedAt.
+type UserAvailabilityChanges struct {
+	UserUUID string
+}
+
+type UserAvailabilityChangesHandler decorator.QueryHandler[UserAvailabilityChanges, []AvailabilityChange]
+
+type UserAvailabilityChangesReadModel interface {
+	AvailabilityChangesByActor(ctx context.Context, actor string) ([]AvailabilityChange, error)
+}
+
+type userAvailabilityChangesHandler struct {
+	readModel UserAvailabilityChangesReadModel
+}
+
+func NewUserAvailabilityChangesHandler(
+	readModel UserAvailabilityChangesReadModel,
+	logger *logrus.Entry,
+	metricsClient decorator.MetricsClient,
+) UserAvailabilityChangesHandler {
+	if readModel == nil {
+		panic("nil readModel")
+	}
+
+	return decorator.ApplyQueryDecorators[UserAvailabilityChanges, []AvailabilityChange](
+		userAvailabilityChangesHandler{readModel: readModel},
+		logger,
+		metricsClient,
+	)
+}
+
+func (h userAvailabilityChangesHandler) Handle(ctx context.Context, query UserAvailabilityChanges) ([]AvailabilityChange, error) {
+	return h.readModel.AvailabilityChangesByActor(ctx, query.UserUUID)
+}
@@ -1,6 +1,9 @@
 package hour
 
-import "github.com/pkg/errors"
+import (
+	commonerrors "github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/errors"
+	"github.com/pkg/errors"
+)
 
 var (
 	Available         = Availability{"available"}
@@ -43,9 +46,12 @@ func (h Availability) String() string {
 }
 
 var (
-	ErrTrainingScheduled   = errors.New("unable to modify hour, because scheduled training")
-	ErrNoTrainingScheduled = errors.New("training is not scheduled")
-	ErrHourNotAvailable    = errors.New("hour is not available")
+	ErrTrainingScheduled = commonerrors.NewPreconditionFailedError(
+		"unable to modify hour, because scheduled training",
+		"hour-has-scheduled-training",
+	)
+	ErrNoTrainingScheduled = commonerrors.NewPreconditionFailedError("training is not scheduled", "training-not-scheduled")
+	ErrHourNotAvailable    = commonerrors.NewPreconditionFailedError("hour is not available", "hour-not-available")
 )
 
 func (h Hour) Availability() Availability {
@@ -0,0 +1,56 @@
+package hour
+
+import "time"
+
+// ChangeSource describes who changed the hour availability and how.
+type ChangeSource struct {
+	// Actor is the UUID of the user which requested the change, also when it was requested through another service.
+	Actor string
+	// Command is the name of the command which originated the change, one of the ChangeSource*Command constants.
+	Command string
+}
+
+// Names of commands stored in ChangeSource, they are prefixed with the port which received the request.
+const (
+	ChangeSourceHTTPMakeHourAvailableCommand   = "http:make-hour-available"
+	ChangeSourceHTTPMakeHourUnavailableCommand = "http:make-hour-unavailable"
+	ChangeSourceGRPCMakeHourAvailableCommand   = "grpc:make-hour-available"
+	ChangeSourceGRPCScheduleTrainingCommand    = "grpc:schedule-training"
+	ChangeSourceGRPCCancelTrainingCommand      = "grpc:cancel-training"
+)
+
+// ErasedActor replaces the actor of availability changes, when personal data of the actor is erased.
+const ErasedActor = "erased-user"
+
+// AvailabilityChange is a single availability transition of the hour.
+type AvailabilityChange struct {
+	Hour time.Time
+	From Availability
+	To   Availability
+
+	Actor     string
+	Command   string
+	ChangedAt time.Time
+}
+
+// NewAvailabilityChange returns the transition from the availability before the update to the availability of updatedHour.
+// When availability didn't change, false is returned.
+func NewAvailabilityChange(
+	availabilityBefore Availability,
+	updatedHour *Hour,
+	source ChangeSource,
+	changedAt time.Time,
+) (AvailabilityChange, bool) {
+	if availabilityBefore == updatedHour.Availability() {
+		return AvailabilityChange{}, false
+	}
+
+	return AvailabilityChange{
+		Hour:      updatedHour.Time(),
+		From:      availabilityBefore,
+		To:        updatedHour.Availability(),
+		Actor:     source.Actor,
+		Command:   source.Command,
+		ChangedAt: changedAt,
+	}, true
+}
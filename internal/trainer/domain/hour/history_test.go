@@ -0,0 +1,43 @@
+package hour_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainer/domain/hour"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAvailabilityChange(t *testing.T) {
+	t.Parallel()
+
+	h, err := testHourFactory.NewAvailableHour(validTrainingHour())
+	require.NoError(t, err)
+	require.NoError(t, h.ScheduleTraining())
+
+	source := hour.ChangeSource{Actor: "attendee-uuid", Command: hour.ChangeSourceGRPCScheduleTrainingCommand}
+	changedAt := time.Now()
+
+	change, changed := hour.NewAvailabilityChange(hour.Available, h, source, changedAt)
+	require.True(t, changed)
+
+	assert.Equal(t, hour.AvailabilityChange{
+		Hour:      h.Time(),
+		From:      hour.Available,
+		To:        hour.TrainingScheduled,
+		Actor:     source.Actor,
+		Command:   source.Command,
+		ChangedAt: changedAt,
+	}, change)
+}
+
+func TestNewAvailabilityChange_not_changed(t *testing.T) {
+	t.Parallel()
+
+	h, err := testHourFactory.NewAvailableHour(validTrainingHour())
+	require.NoError(t, err)
+
+	_, changed := hour.NewAvailabilityChange(hour.Available, h, hour.ChangeSource{}, time.Now())
+	assert.False(t, changed)
+}
@@ -2,8 +2,10 @@ package hour
 
 import (
 	"fmt"
+	"sync/atomic"
 	"time"
 
+	commonerrors "github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/errors"
 	"github.com/pkg/errors"
 	"go.uber.org/multierr"
 )
@@ -64,10 +66,19 @@ func (f FactoryConfig) Validate() error {
 	return err
 }
 
+// MaxTimeToSet returns the most distant time for which the hour can be set.
+func (f FactoryConfig) MaxTimeToSet(now time.Time) time.Time {
+	// AddDate is better than Add for adding days, because not every day have 24h!
+	return now.AddDate(0, 0, f.MaxWeeksInTheFutureToSet*7)
+}
+
 type Factory struct {
 	// it's better to keep FactoryConfig as a private attribute,
 	// thanks to that we are always sure that our configuration is not changed in the not allowed way
-	fc FactoryConfig
+	//
+	// config can be changed in the runtime with UpdateConfig,
+	// all copies of the Factory are sharing the same config
+	fc *atomic.Value
 }
 
 func NewFactory(fc FactoryConfig) (Factory, error) {
@@ -75,7 +86,10 @@ func NewFactory(fc FactoryConfig) (Factory, error) {
 		return Factory{}, errors.Wrap(err, "invalid config passed to factory")
 	}
 
-	return Factory{fc: fc}, nil
+	f := Factory{fc: &atomic.Value{}}
+	f.fc.Store(fc)
+
+	return f, nil
 }
 
 func MustNewFactory(fc FactoryConfig) Factory {
@@ -88,7 +102,19 @@ func MustNewFactory(fc FactoryConfig) Factory {
 }
 
 func (f Factory) Config() FactoryConfig {
-	return f.fc
+	return f.fc.Load().(FactoryConfig)
+}
+
+// UpdateConfig replaces config of the factory (and all its copies).
+// Config is validated before the change, invalid config is not applied.
+func (f Factory) UpdateConfig(fc FactoryConfig) error {
+	if err := fc.Validate(); err != nil {
+		return errors.Wrap(err, "invalid config passed to factory")
+	}
+
+	f.fc.Store(fc)
+
+	return nil
 }
 
 func (f Factory) IsZero() bool {
@@ -137,8 +163,8 @@ func (f Factory) UnmarshalHourFromDatabase(hour time.Time, availability Availabi
 }
 
 var (
-	ErrNotFullHour = errors.New("hour should be a full hour")
-	ErrPastHour    = errors.New("cannot create hour from past")
+	ErrNotFullHour = commonerrors.NewIncorrectInputError("hour should be a full hour", "hour-not-full")
+	ErrPastHour    = commonerrors.NewIncorrectInputError("cannot create hour from past", "past-hour")
 )
 
 // If you have the error with a more complex context,
@@ -157,6 +183,14 @@ func (e TooDistantDateError) Error() string {
 	)
 }
 
+func (e TooDistantDateError) Slug() string {
+	return "too-distant-date"
+}
+
+func (e TooDistantDateError) ErrorType() commonerrors.ErrorType {
+	return commonerrors.ErrorTypeIncorrectInput
+}
+
 type TooEarlyHourError struct {
 	MinUtcHour   int
 	ProvidedTime time.Time
@@ -170,6 +204,14 @@ func (e TooEarlyHourError) Error() string {
 	)
 }
 
+func (e TooEarlyHourError) Slug() string {
+	return "too-early-hour"
+}
+
+func (e TooEarlyHourError) ErrorType() commonerrors.ErrorType {
+	return commonerrors.ErrorTypeIncorrectInput
+}
+
 type TooLateHourError struct {
 	MaxUtcHour   int
 	ProvidedTime time.Time
@@ -183,15 +225,24 @@ func (e TooLateHourError) Error() string {
 	)
 }
 
+func (e TooLateHourError) Slug() string {
+	return "too-late-hour"
+}
+
+func (e TooLateHourError) ErrorType() commonerrors.ErrorType {
+	return commonerrors.ErrorTypeIncorrectInput
+}
+
 func (f Factory) validateTime(hour time.Time) error {
+	fc := f.Config()
+
 	if !hour.Round(time.Hour).Equal(hour) {
 		return ErrNotFullHour
 	}
 
-	// AddDate is better than Add for adding days, because not every day have 24h!
-	if hour.After(time.Now().AddDate(0, 0, f.fc.MaxWeeksInTheFutureToSet*7)) {
+	if hour.After(fc.MaxTimeToSet(time.Now())) {
 		return TooDistantDateError{
-			MaxWeeksInTheFutureToSet: f.fc.MaxWeeksInTheFutureToSet,
+			MaxWeeksInTheFutureToSet: fc.MaxWeeksInTheFutureToSet,
 			ProvidedDate:             hour,
 		}
 	}
@@ -200,15 +251,15 @@ func (f Factory) validateTime(hour time.Time) error {
 	if hour.Before(currentHour) || hour.Equal(currentHour) {
 		return ErrPastHour
 	}
-	if hour.UTC().Hour() > f.fc.MaxUtcHour {
+	if hour.UTC().Hour() > fc.MaxUtcHour {
 		return TooLateHourError{
-			MaxUtcHour:   f.fc.MaxUtcHour,
+			MaxUtcHour:   fc.MaxUtcHour,
 			ProvidedTime: hour,
 		}
 	}
-	if hour.UTC().Hour() < f.fc.MinUtcHour {
+	if hour.UTC().Hour() < fc.MinUtcHour {
 		return TooEarlyHourError{
-			MinUtcHour:   f.fc.MinUtcHour,
+			MinUtcHour:   fc.MinUtcHour,
 			ProvidedTime: hour,
 		}
 	}
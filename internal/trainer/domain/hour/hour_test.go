@@ -227,6 +227,61 @@ func TestNewFactory_invalid_config(t *testing.T) {
 	assert.Zero(t, f)
 }
 
+func TestFactory_UpdateConfig(t *testing.T) {
+	t.Parallel()
+
+	factory := hour.MustNewFactory(hour.FactoryConfig{
+		MaxWeeksInTheFutureToSet: 10,
+		MinUtcHour:               12,
+		MaxUtcHour:               18,
+	})
+	factoryCopy := factory
+
+	newConfig := hour.FactoryConfig{
+		MaxWeeksInTheFutureToSet: 2,
+		MinUtcHour:               8,
+		MaxUtcHour:               16,
+	}
+	require.NoError(t, factory.UpdateConfig(newConfig))
+
+	assert.Equal(t, newConfig, factory.Config())
+	assert.Equal(t, newConfig, factoryCopy.Config(), "config should be shared between factory copies")
+
+	// we are using next day, to be sure that provided hour is not in the past
+	currentTime := time.Now().AddDate(0, 0, 1)
+	tooEarlyHour := time.Date(
+		currentTime.Year(), currentTime.Month(), currentTime.Day(),
+		newConfig.MinUtcHour-1, 0, 0, 0,
+		time.UTC,
+	)
+
+	_, err := factory.NewAvailableHour(tooEarlyHour)
+	assert.Equal(
+		t,
+		hour.TooEarlyHourError{
+			MinUtcHour:   newConfig.MinUtcHour,
+			ProvidedTime: tooEarlyHour,
+		},
+		err,
+	)
+}
+
+func TestFactory_UpdateConfig_invalid_config(t *testing.T) {
+	t.Parallel()
+
+	config := hour.FactoryConfig{
+		MaxWeeksInTheFutureToSet: 10,
+		MinUtcHour:               12,
+		MaxUtcHour:               18,
+	}
+	factory := hour.MustNewFactory(config)
+
+	err := factory.UpdateConfig(hour.FactoryConfig{})
+	assert.Error(t, err)
+
+	assert.Equal(t, config, factory.Config(), "invalid config should be not applied")
+}
+
 func validTrainingHour() time.Time {
 	tomorrow := time.Now().Add(time.Hour * 24)
 
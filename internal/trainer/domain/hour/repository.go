@@ -7,9 +7,33 @@ import (
 
 type Repository interface {
 	GetHour(ctx context.Context, hourTime time.Time) (*Hour, error)
+	// UpdateHour updates the hour with updateFn.
+	// If the availability of the hour changed, AvailabilityChange with the source is stored in the same transaction.
 	UpdateHour(
 		ctx context.Context,
 		hourTime time.Time,
+		source ChangeSource,
 		updateFn func(h *Hour) (*Hour, error),
 	) error
+	// UpdateHourWithScheduledTrainings works like UpdateHour, but updateFn also receives times of all hours
+	// with scheduled training in [from, to) period, read in the same transaction.
+	// Concurrent calls are serialized, so rules depending on other hours can't be broken by trainings
+	// scheduled at the same moment.
+	UpdateHourWithScheduledTrainings(
+		ctx context.Context,
+		hourTime time.Time,
+		from time.Time,
+		to time.Time,
+		source ChangeSource,
+		updateFn func(h *Hour, scheduledTrainings []time.Time) (*Hour, error),
+	) error
+	// ScheduledTrainings returns times of all hours with scheduled training in [from, to) period.
+	ScheduledTrainings(ctx context.Context, from time.Time, to time.Time) ([]time.Time, error)
+	// EraseActor replaces actor of all stored availability changes with ErasedActor.
+	EraseActor(ctx context.Context, actor string) error
+}
+
+type FactoryConfigRepository interface {
+	GetFactoryConfig(ctx context.Context) (FactoryConfig, error)
+	UpdateFactoryConfig(ctx context.Context, fc FactoryConfig) error
 }
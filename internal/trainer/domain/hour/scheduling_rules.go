@@ -0,0 +1,254 @@
+package hour
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	commonerrors "github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/errors"
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+)
+
+// SchedulingRules are protecting trainer from being overbooked.
+//
+// Zero value of every rule means, that the rule is disabled.
+type SchedulingRules struct {
+	// MaxConsecutiveTrainings is the number of trainings after which trainer needs a break.
+	MaxConsecutiveTrainings int
+	// MinBreak is the minimal break after MaxConsecutiveTrainings trainings.
+	// Trainings with a shorter break between them are considered as consecutive.
+	MinBreak time.Duration
+
+	MaxTrainingsPerDay  int
+	MaxTrainingsPerWeek int
+
+	// MinLeadTime is the minimal time between scheduling the training and the training.
+	MinLeadTime time.Duration
+}
+
+func (r SchedulingRules) Validate() error {
+	var err error
+
+	if r.MaxConsecutiveTrainings < 0 {
+		err = multierr.Append(
+			err,
+			errors.Errorf("MaxConsecutiveTrainings can't be negative, but is %d", r.MaxConsecutiveTrainings),
+		)
+	}
+	if r.MaxConsecutiveTrainings > 0 && r.MinBreak < time.Hour {
+		err = multierr.Append(
+			err,
+			errors.Errorf("MinBreak should be at least 1h when MaxConsecutiveTrainings is set, but is %s", r.MinBreak),
+		)
+	}
+	if r.MaxTrainingsPerDay < 0 {
+		err = multierr.Append(
+			err,
+			errors.Errorf("MaxTrainingsPerDay can't be negative, but is %d", r.MaxTrainingsPerDay),
+		)
+	}
+	if r.MaxTrainingsPerWeek < 0 {
+		err = multierr.Append(
+			err,
+			errors.Errorf("MaxTrainingsPerWeek can't be negative, but is %d", r.MaxTrainingsPerWeek),
+		)
+	}
+	if r.MinLeadTime < 0 {
+		err = multierr.Append(
+			err,
+			errors.Errorf("MinLeadTime can't be negative, but is %s", r.MinLeadTime),
+		)
+	}
+
+	return err
+}
+
+// ScheduledTrainingsPeriod returns the period of already scheduled trainings,
+// which is needed by CanScheduleTraining to check all rules.
+func (r SchedulingRules) ScheduledTrainingsPeriod(trainingTime time.Time) (from time.Time, to time.Time) {
+	weekStart := startOfWeek(trainingTime)
+
+	// consecutive trainings can cross the week boundary
+	return weekStart.AddDate(0, 0, -1), weekStart.AddDate(0, 0, 8)
+}
+
+// CanScheduleTraining checks if the training can be scheduled at trainingTime,
+// when trainings at scheduledTrainings are already scheduled.
+//
+// scheduledTrainings should contain at least all trainings from ScheduledTrainingsPeriod.
+func (r SchedulingRules) CanScheduleTraining(trainingTime time.Time, scheduledTrainings []time.Time, now time.Time) error {
+	if r.MinLeadTime > 0 && trainingTime.Sub(now) < r.MinLeadTime {
+		return TooShortLeadTimeError{
+			MinLeadTime:  r.MinLeadTime,
+			ProvidedTime: trainingTime,
+		}
+	}
+
+	if r.MaxTrainingsPerDay > 0 {
+		trainingDay := trainingTime.UTC().Truncate(time.Hour * 24)
+
+		if countTrainings(scheduledTrainings, trainingDay, trainingDay.AddDate(0, 0, 1)) >= r.MaxTrainingsPerDay {
+			return DailyTrainingsLimitReachedError{
+				MaxTrainingsPerDay: r.MaxTrainingsPerDay,
+				Date:               trainingDay,
+			}
+		}
+	}
+
+	if r.MaxTrainingsPerWeek > 0 {
+		weekStart := startOfWeek(trainingTime)
+
+		if countTrainings(scheduledTrainings, weekStart, weekStart.AddDate(0, 0, 7)) >= r.MaxTrainingsPerWeek {
+			return WeeklyTrainingsLimitReachedError{
+				MaxTrainingsPerWeek: r.MaxTrainingsPerWeek,
+				WeekStart:           weekStart,
+			}
+		}
+	}
+
+	if r.MaxConsecutiveTrainings > 0 {
+		if r.consecutiveTrainings(trainingTime, scheduledTrainings) > r.MaxConsecutiveTrainings {
+			return TooManyConsecutiveTrainingsError{
+				MaxConsecutiveTrainings: r.MaxConsecutiveTrainings,
+				MinBreak:                r.MinBreak,
+				ProvidedTime:            trainingTime,
+			}
+		}
+	}
+
+	return nil
+}
+
+// consecutiveTrainings returns how many consecutive trainings will be there, if training at trainingTime is scheduled.
+func (r SchedulingRules) consecutiveTrainings(trainingTime time.Time, scheduledTrainings []time.Time) int {
+	trainings := append([]time.Time{trainingTime}, scheduledTrainings...)
+	sort.Slice(trainings, func(i, j int) bool { return trainings[i].Before(trainings[j]) })
+
+	// every training takes one hour, so break is the time between the end of one and the start of the next one
+	isConsecutive := func(first time.Time, second time.Time) bool {
+		return second.Sub(first.Add(time.Hour)) < r.MinBreak
+	}
+
+	count := 0
+	for i := range trainings {
+		if i > 0 && !isConsecutive(trainings[i-1], trainings[i]) {
+			if !trainings[i].After(trainingTime) {
+				// new chain started before or with our training
+				count = 0
+			} else {
+				// chain with our training ended
+				break
+			}
+		}
+
+		count++
+	}
+
+	return count
+}
+
+func countTrainings(trainings []time.Time, from time.Time, to time.Time) int {
+	count := 0
+	for _, t := range trainings {
+		if !t.Before(from) && t.Before(to) {
+			count++
+		}
+	}
+
+	return count
+}
+
+// startOfWeek returns Monday 00:00 UTC of the week of t.
+func startOfWeek(t time.Time) time.Time {
+	day := t.UTC().Truncate(time.Hour * 24)
+	daysSinceMonday := (int(day.Weekday()) + 6) % 7
+
+	return day.AddDate(0, 0, -daysSinceMonday)
+}
+
+type TooShortLeadTimeError struct {
+	MinLeadTime  time.Duration
+	ProvidedTime time.Time
+}
+
+func (e TooShortLeadTimeError) Error() string {
+	return fmt.Sprintf(
+		"training should be scheduled at least %s in advance, provided time: %s",
+		e.MinLeadTime,
+		e.ProvidedTime,
+	)
+}
+
+func (e TooShortLeadTimeError) Slug() string {
+	return "training-lead-time-too-short"
+}
+
+func (e TooShortLeadTimeError) ErrorType() commonerrors.ErrorType {
+	return commonerrors.ErrorTypePreconditionFailed
+}
+
+type DailyTrainingsLimitReachedError struct {
+	MaxTrainingsPerDay int
+	Date               time.Time
+}
+
+func (e DailyTrainingsLimitReachedError) Error() string {
+	return fmt.Sprintf(
+		"trainer can have max %d trainings per day, limit reached for %s",
+		e.MaxTrainingsPerDay,
+		e.Date.Format("2006-01-02"),
+	)
+}
+
+func (e DailyTrainingsLimitReachedError) Slug() string {
+	return "trainer-daily-limit-reached"
+}
+
+func (e DailyTrainingsLimitReachedError) ErrorType() commonerrors.ErrorType {
+	return commonerrors.ErrorTypePreconditionFailed
+}
+
+type WeeklyTrainingsLimitReachedError struct {
+	MaxTrainingsPerWeek int
+	WeekStart           time.Time
+}
+
+func (e WeeklyTrainingsLimitReachedError) Error() string {
+	return fmt.Sprintf(
+		"trainer can have max %d trainings per week, limit reached for week starting %s",
+		e.MaxTrainingsPerWeek,
+		e.WeekStart.Format("2006-01-02"),
+	)
+}
+
+func (e WeeklyTrainingsLimitReachedError) Slug() string {
+	return "trainer-weekly-limit-reached"
+}
+
+func (e WeeklyTrainingsLimitReachedError) ErrorType() commonerrors.ErrorType {
+	return commonerrors.ErrorTypePreconditionFailed
+}
+
+type TooManyConsecutiveTrainingsError struct {
+	MaxConsecutiveTrainings int
+	MinBreak                time.Duration
+	ProvidedTime            time.Time
+}
+
+func (e TooManyConsecutiveTrainingsError) Error() string {
+	return fmt.Sprintf(
+		"trainer needs %s break after %d consecutive trainings, provided time: %s",
+		e.MinBreak,
+		e.MaxConsecutiveTrainings,
+		e.ProvidedTime,
+	)
+}
+
+func (e TooManyConsecutiveTrainingsError) Slug() string {
+	return "trainer-needs-break"
+}
+
+func (e TooManyConsecutiveTrainingsError) ErrorType() commonerrors.ErrorType {
+	return commonerrors.ErrorTypePreconditionFailed
+}
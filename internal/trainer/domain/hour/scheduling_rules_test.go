@@ -0,0 +1,170 @@
+package hour_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainer/domain/hour"
+	"github.com/stretchr/testify/assert"
+)
+
+// 2030-01-07 is Monday
+var schedulingRulesTestDay = time.Date(2030, 1, 7, 0, 0, 0, 0, time.UTC)
+
+func atHour(days int, h int) time.Time {
+	return schedulingRulesTestDay.AddDate(0, 0, days).Add(time.Duration(h) * time.Hour)
+}
+
+func TestSchedulingRules_CanScheduleTraining(t *testing.T) {
+	t.Parallel()
+
+	now := atHour(-1, 0)
+
+	testCases := []struct {
+		Name               string
+		Rules              hour.SchedulingRules
+		TrainingTime       time.Time
+		ScheduledTrainings []time.Time
+		ExpectedErr        error
+	}{
+		{
+			Name:               "no_rules",
+			Rules:              hour.SchedulingRules{},
+			TrainingTime:       atHour(0, 15),
+			ScheduledTrainings: []time.Time{atHour(0, 12), atHour(0, 13), atHour(0, 14), atHour(0, 16)},
+			ExpectedErr:        nil,
+		},
+		{
+			Name:         "too_short_lead_time",
+			Rules:        hour.SchedulingRules{MinLeadTime: time.Hour * 48},
+			TrainingTime: atHour(0, 12),
+			ExpectedErr: hour.TooShortLeadTimeError{
+				MinLeadTime:  time.Hour * 48,
+				ProvidedTime: atHour(0, 12),
+			},
+		},
+		{
+			Name:         "enough_lead_time",
+			Rules:        hour.SchedulingRules{MinLeadTime: time.Hour * 24},
+			TrainingTime: atHour(0, 12),
+			ExpectedErr:  nil,
+		},
+		{
+			Name:               "daily_limit_reached",
+			Rules:              hour.SchedulingRules{MaxTrainingsPerDay: 2},
+			TrainingTime:       atHour(0, 18),
+			ScheduledTrainings: []time.Time{atHour(0, 12), atHour(0, 15), atHour(1, 12)},
+			ExpectedErr: hour.DailyTrainingsLimitReachedError{
+				MaxTrainingsPerDay: 2,
+				Date:               atHour(0, 0),
+			},
+		},
+		{
+			Name:               "daily_limit_not_reached",
+			Rules:              hour.SchedulingRules{MaxTrainingsPerDay: 2},
+			TrainingTime:       atHour(0, 18),
+			ScheduledTrainings: []time.Time{atHour(-1, 12), atHour(0, 15), atHour(1, 12)},
+			ExpectedErr:        nil,
+		},
+		{
+			Name:               "weekly_limit_reached",
+			Rules:              hour.SchedulingRules{MaxTrainingsPerWeek: 3},
+			TrainingTime:       atHour(6, 12),
+			ScheduledTrainings: []time.Time{atHour(0, 12), atHour(2, 12), atHour(4, 12)},
+			ExpectedErr: hour.WeeklyTrainingsLimitReachedError{
+				MaxTrainingsPerWeek: 3,
+				WeekStart:           atHour(0, 0),
+			},
+		},
+		{
+			Name:               "weekly_limit_not_reached_previous_week",
+			Rules:              hour.SchedulingRules{MaxTrainingsPerWeek: 3},
+			TrainingTime:       atHour(6, 12),
+			ScheduledTrainings: []time.Time{atHour(-1, 12), atHour(2, 12), atHour(4, 12)},
+			ExpectedErr:        nil,
+		},
+		{
+			Name:               "too_many_consecutive_trainings",
+			Rules:              hour.SchedulingRules{MaxConsecutiveTrainings: 3, MinBreak: time.Hour},
+			TrainingTime:       atHour(0, 15),
+			ScheduledTrainings: []time.Time{atHour(0, 12), atHour(0, 13), atHour(0, 14)},
+			ExpectedErr: hour.TooManyConsecutiveTrainingsError{
+				MaxConsecutiveTrainings: 3,
+				MinBreak:                time.Hour,
+				ProvidedTime:            atHour(0, 15),
+			},
+		},
+		{
+			Name:               "too_many_consecutive_trainings_in_the_middle",
+			Rules:              hour.SchedulingRules{MaxConsecutiveTrainings: 3, MinBreak: time.Hour},
+			TrainingTime:       atHour(0, 14),
+			ScheduledTrainings: []time.Time{atHour(0, 12), atHour(0, 13), atHour(0, 15)},
+			ExpectedErr: hour.TooManyConsecutiveTrainingsError{
+				MaxConsecutiveTrainings: 3,
+				MinBreak:                time.Hour,
+				ProvidedTime:            atHour(0, 14),
+			},
+		},
+		{
+			Name:               "too_short_break",
+			Rules:              hour.SchedulingRules{MaxConsecutiveTrainings: 2, MinBreak: time.Hour * 2},
+			TrainingTime:       atHour(0, 15),
+			ScheduledTrainings: []time.Time{atHour(0, 12), atHour(0, 13)},
+			ExpectedErr: hour.TooManyConsecutiveTrainingsError{
+				MaxConsecutiveTrainings: 2,
+				MinBreak:                time.Hour * 2,
+				ProvidedTime:            atHour(0, 15),
+			},
+		},
+		{
+			Name:               "enough_break",
+			Rules:              hour.SchedulingRules{MaxConsecutiveTrainings: 2, MinBreak: time.Hour},
+			TrainingTime:       atHour(0, 15),
+			ScheduledTrainings: []time.Time{atHour(0, 12), atHour(0, 13), atHour(0, 17)},
+			ExpectedErr:        nil,
+		},
+	}
+
+	for _, c := range testCases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			t.Parallel()
+
+			err := c.Rules.CanScheduleTraining(c.TrainingTime, c.ScheduledTrainings, now)
+			assert.Equal(t, c.ExpectedErr, err)
+		})
+	}
+}
+
+func TestSchedulingRules_ScheduledTrainingsPeriod(t *testing.T) {
+	t.Parallel()
+
+	from, to := hour.SchedulingRules{}.ScheduledTrainingsPeriod(atHour(3, 12))
+
+	assert.Equal(t, atHour(-1, 0), from)
+	assert.Equal(t, atHour(8, 0), to)
+}
+
+func TestSchedulingRules_Validate(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, hour.SchedulingRules{}.Validate())
+	assert.NoError(t, hour.SchedulingRules{
+		MaxConsecutiveTrainings: 4,
+		MinBreak:                time.Hour,
+		MaxTrainingsPerDay:      6,
+		MaxTrainingsPerWeek:     20,
+		MinLeadTime:             time.Hour * 2,
+	}.Validate())
+
+	assert.EqualError(
+		t,
+		hour.SchedulingRules{MaxConsecutiveTrainings: 4}.Validate(),
+		"MinBreak should be at least 1h when MaxConsecutiveTrainings is set, but is 0s",
+	)
+	assert.EqualError(
+		t,
+		hour.SchedulingRules{MaxTrainingsPerDay: -1, MinLeadTime: -time.Hour}.Validate(),
+		"MaxTrainingsPerDay can't be negative, but is -1; MinLeadTime can't be negative, but is -1h0m0s",
+	)
+}
@@ -47,11 +47,19 @@ func loadFixtures(app app.Application) {
 }
 
 func loadTrainerFixtures(ctx context.Context, application app.Application) error {
+	config, err := application.Queries.CurrentFactoryConfig.Handle(ctx, query.CurrentFactoryConfig{})
+	if err != nil {
+		return errors.Wrap(err, "unable to get factory config")
+	}
+
 	maxDate := time.Now().AddDate(0, 0, daysToSet)
+	if config.MaxTimeToSet.Before(maxDate) {
+		maxDate = config.MaxTimeToSet
+	}
 	localRand := rand.New(rand.NewSource(3))
 
 	for date := time.Now(); date.Before(maxDate); date = date.AddDate(0, 0, 1) {
-		for hour := 12; hour <= 20; hour++ {
+		for hour := config.MinUtcHour; hour <= config.MaxUtcHour; hour++ {
 			trainingTime := time.Date(date.Year(), date.Month(), date.Day(), hour, 0, 0, 0, time.UTC)
 
 			if trainingTime.Add(time.Hour).Before(time.Now()) {
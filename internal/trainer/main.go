@@ -7,9 +7,11 @@ import (
 	"os"
 	"strings"
 
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/client"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/genproto/trainer"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/logs"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/server"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/tracing"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainer/ports"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainer/service"
 	"github.com/go-chi/chi/v5"
@@ -19,26 +21,43 @@ import (
 func main() {
 	logs.Init()
 
+	flushTraces := tracing.Init("trainer")
+	defer flushTraces()
+
 	ctx := context.Background()
 
-	application := service.NewApplication(ctx)
+	application, healthChecks := service.NewApplication(ctx)
+	availabilityChanges := ports.NewAvailabilityChangesHub(application)
 
 	serverType := strings.ToLower(os.Getenv("SERVER_TO_RUN"))
 	switch serverType {
 	case "http":
 		go loadFixtures(application)
 
-		server.RunHTTPServer(func(router chi.Router) http.Handler {
-			return ports.HandlerFromMux(
-				ports.NewHttpServer(application),
-				router,
-			)
-		})
+		sessionRevocationChecker, closeSessionRevocationChecker, err := client.NewSessionRevocationChecker()
+		if err != nil {
+			panic(err)
+		}
+
+		server.RunHTTPServer(
+			func(router chi.Router) http.Handler {
+				return ports.HandlerFromMux(ports.NewHttpServer(application, availabilityChanges), router)
+			},
+			server.WithHTTPHealthChecks(healthChecks...),
+			server.WithRateLimits(ports.HttpRateLimits),
+			server.WithSessionRevocationCheck(sessionRevocationChecker),
+			server.WithHTTPOnShutdown(availabilityChanges.Close),
+			server.WithHTTPShutdownHooks(func() { _ = closeSessionRevocationChecker() }),
+		)
 	case "grpc":
 		server.RunGRPCServer(func(server *grpc.Server) {
-			svc := ports.NewGrpcServer(application)
+			svc := ports.NewGrpcServer(application, availabilityChanges)
 			trainer.RegisterTrainerServiceServer(server, svc)
-		})
+		},
+			server.WithAllowedCallers(ports.GrpcAllowedCallers),
+			server.WithGRPCHealthChecks(healthChecks...),
+			server.WithGRPCOnShutdown(availabilityChanges.Close),
+		)
 	default:
 		panic(fmt.Sprintf("server type '%s' is not supported", serverType))
 	}
@@ -0,0 +1,210 @@
+package ports
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainer/app"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainer/app/query"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	availabilityChangesPollInterval = time.Second
+
+	// availabilityChangesPollOverlap is protecting from missing changes of transactions,
+	// which were committed after the previous poll, but with ChangedAt before it.
+	availabilityChangesPollOverlap = time.Second * 10
+
+	// availabilityChangesBufferSize is how many changes can wait for sending to the client.
+	// Subscribers which are not able to keep up are disconnected.
+	availabilityChangesBufferSize = 100
+)
+
+var (
+	errAvailabilityChangesSubscriberTooSlow = errors.New("subscriber is too slow to receive changes")
+	errAvailabilityChangesHubClosed         = errors.New("availability changes hub is closed")
+)
+
+// AvailabilityChangesHub is polling committed availability changes and sending them to all subscribers.
+// One hub should be shared by all servers of the process, so the history is polled once.
+//
+// Changes can't be published by the command after commit, because hours are changed by other processes as well:
+// trainer-http and trainer-grpc are separate services, and each of them can run multiple instances.
+// Reading the history is the only way to get changes done by all of them.
+// Polling is running only when there is at least one subscriber.
+type AvailabilityChangesHub struct {
+	changesQuery query.AvailabilityChangesHandler
+
+	lock        *sync.Mutex
+	subscribers map[*availabilityChangesSubscriber]struct{}
+	stopPolling context.CancelFunc
+	closed      bool
+}
+
+type availabilityChangesSubscriber struct {
+	// from and to are dates, the same as in the calendar `to` date includes all hours of this day
+	from time.Time
+	to   time.Time
+
+	// changes is closed, when subscriber was too slow to receive them or the hub was closed
+	changes chan query.AvailabilityChange
+	// closeReason is set before changes is closed, so it can be read after receiving from the closed channel
+	closeReason error
+}
+
+func (s *availabilityChangesSubscriber) isInterestedIn(change query.AvailabilityChange) bool {
+	return !change.Hour.Before(s.from) && change.Hour.Before(s.to.AddDate(0, 0, 1))
+}
+
+func NewAvailabilityChangesHub(application app.Application) *AvailabilityChangesHub {
+	return &AvailabilityChangesHub{
+		changesQuery: application.Queries.AvailabilityChanges,
+		lock:         &sync.Mutex{},
+		subscribers:  map[*availabilityChangesSubscriber]struct{}{},
+	}
+}
+
+// Subscribe returns subscriber receiving availability changes of hours from `from` to `to` date.
+// Returned unsubscribe function should be always called, when changes are no longer needed.
+func (h *AvailabilityChangesHub) Subscribe(from time.Time, to time.Time) (*availabilityChangesSubscriber, func()) {
+	subscriber := &availabilityChangesSubscriber{
+		from:    from,
+		to:      to,
+		changes: make(chan query.AvailabilityChange, availabilityChangesBufferSize),
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.closed {
+		subscriber.closeReason = errAvailabilityChangesHubClosed
+		close(subscriber.changes)
+		return subscriber, func() {}
+	}
+
+	h.subscribers[subscriber] = struct{}{}
+
+	if h.stopPolling == nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		h.stopPolling = cancel
+
+		go h.poll(ctx)
+	}
+
+	return subscriber, func() {
+		h.unsubscribe(subscriber)
+	}
+}
+
+func (h *AvailabilityChangesHub) unsubscribe(subscriber *availabilityChangesSubscriber) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.removeSubscriber(subscriber, nil)
+	h.stopPollingWithoutSubscribers()
+}
+
+// Close disconnects all subscribers and stops polling. It's called when the server is shutting down,
+// so streams are finished instead of being canceled after the shutdown timeout.
+func (h *AvailabilityChangesHub) Close() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.closed = true
+
+	for subscriber := range h.subscribers {
+		h.removeSubscriber(subscriber, errAvailabilityChangesHubClosed)
+	}
+	h.stopPollingWithoutSubscribers()
+}
+
+// removeSubscriber should be called with the lock held.
+func (h *AvailabilityChangesHub) removeSubscriber(subscriber *availabilityChangesSubscriber, reason error) {
+	if _, ok := h.subscribers[subscriber]; !ok {
+		return
+	}
+
+	delete(h.subscribers, subscriber)
+	subscriber.closeReason = reason
+	close(subscriber.changes)
+}
+
+// stopPollingWithoutSubscribers should be called with the lock held.
+func (h *AvailabilityChangesHub) stopPollingWithoutSubscribers() {
+	if len(h.subscribers) == 0 && h.stopPolling != nil {
+		h.stopPolling()
+		h.stopPolling = nil
+	}
+}
+
+func (h *AvailabilityChangesHub) poll(ctx context.Context) {
+	pollingStarted := time.Now()
+	lastPoll := pollingStarted
+
+	// sent contains IDs of changes which were already sent,
+	// changes are polled with overlap, so the same change can be returned multiple times
+	sent := map[string]time.Time{}
+
+	ticker := time.NewTicker(availabilityChangesPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		pollStarted := time.Now()
+
+		since := lastPoll.Add(-availabilityChangesPollOverlap)
+		if since.Before(pollingStarted) {
+			// changes done before anybody subscribed are already visible in the calendar
+			since = pollingStarted
+		}
+
+		changes, err := h.changesQuery.Handle(ctx, query.AvailabilityChanges{ChangedSince: since})
+		if err != nil {
+			logrus.WithError(err).Error("Unable to poll availability changes")
+			continue
+		}
+
+		for _, change := range changes {
+			if _, ok := sent[change.ID]; ok {
+				continue
+			}
+
+			h.broadcast(change)
+			sent[change.ID] = change.ChangedAt
+		}
+
+		for id, changedAt := range sent {
+			if changedAt.Before(since) {
+				delete(sent, id)
+			}
+		}
+
+		lastPoll = pollStarted
+	}
+}
+
+func (h *AvailabilityChangesHub) broadcast(change query.AvailabilityChange) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	for subscriber := range h.subscribers {
+		if !subscriber.isInterestedIn(change) {
+			continue
+		}
+
+		select {
+		case subscriber.changes <- change:
+		default:
+			logrus.Warn("Availability changes subscriber is too slow, disconnecting")
+			h.removeSubscriber(subscriber, errAvailabilityChangesSubscriberTooSlow)
+		}
+	}
+}
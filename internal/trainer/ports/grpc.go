@@ -2,51 +2,95 @@ package ports
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/genproto/trainer"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/grpcerr"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainer/app"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainer/app/command"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainer/app/query"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainer/domain/hour"
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/golang/protobuf/ptypes/timestamp"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// GrpcAllowedCallers lists services, which are allowed to call methods of GrpcServer.
+var GrpcAllowedCallers = map[string][]string{
+	"/trainer.TrainerService/IsHourAvailable":       {"trainings"},
+	"/trainer.TrainerService/ScheduleTraining":      {"trainings"},
+	"/trainer.TrainerService/CancelTraining":        {"trainings"},
+	"/trainer.TrainerService/MakeHourAvailable":     {"trainings"},
+	"/trainer.TrainerService/WatchHourAvailability": {"trainings"},
+	"/trainer.TrainerService/ExportUserData":        {"users"},
+	"/trainer.TrainerService/EraseUserData":         {"users"},
+}
+
 type GrpcServer struct {
-	app app.Application
+	app                 app.Application
+	availabilityChanges *AvailabilityChangesHub
 }
 
-func NewGrpcServer(application app.Application) GrpcServer {
-	return GrpcServer{app: application}
+func NewGrpcServer(application app.Application, availabilityChanges *AvailabilityChangesHub) GrpcServer {
+	return GrpcServer{
+		app:                 application,
+		availabilityChanges: availabilityChanges,
+	}
 }
 
 func (g GrpcServer) MakeHourAvailable(ctx context.Context, request *trainer.UpdateHourRequest) (*empty.Empty, error) {
+	source, err := updateHourChangeSource(request, hour.ChangeSourceGRPCMakeHourAvailableCommand)
+	if err != nil {
+		return nil, err
+	}
+
 	trainingTime := protoTimestampToTime(request.Time)
 
-	if err := g.app.Commands.MakeHoursAvailable.Handle(ctx, command.MakeHoursAvailable{Hours: []time.Time{trainingTime}}); err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+	if err := g.app.Commands.MakeHoursAvailable.Handle(ctx, command.MakeHoursAvailable{
+		Hours:  []time.Time{trainingTime},
+		Source: source,
+	}); err != nil {
+		return nil, grpcerr.Status(err)
 	}
 
 	return &empty.Empty{}, nil
 }
 
 func (g GrpcServer) ScheduleTraining(ctx context.Context, request *trainer.UpdateHourRequest) (*empty.Empty, error) {
+	source, err := updateHourChangeSource(request, hour.ChangeSourceGRPCScheduleTrainingCommand)
+	if err != nil {
+		return nil, err
+	}
+
 	trainingTime := protoTimestampToTime(request.Time)
 
-	if err := g.app.Commands.ScheduleTraining.Handle(ctx, command.ScheduleTraining{Hour: trainingTime}); err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+	if err := g.app.Commands.ScheduleTraining.Handle(ctx, command.ScheduleTraining{
+		Hour:   trainingTime,
+		Source: source,
+	}); err != nil {
+		return nil, grpcerr.Status(err)
 	}
 
 	return &empty.Empty{}, nil
 }
 
 func (g GrpcServer) CancelTraining(ctx context.Context, request *trainer.UpdateHourRequest) (*empty.Empty, error) {
+	source, err := updateHourChangeSource(request, hour.ChangeSourceGRPCCancelTrainingCommand)
+	if err != nil {
+		return nil, err
+	}
+
 	trainingTime := protoTimestampToTime(request.Time)
 
-	if err := g.app.Commands.CancelTraining.Handle(ctx, command.CancelTraining{Hour: trainingTime}); err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+	if err := g.app.Commands.CancelTraining.Handle(ctx, command.CancelTraining{
+		Hour:   trainingTime,
+		Source: source,
+	}); err != nil {
+		return nil, grpcerr.Status(err)
 	}
 
 	return &empty.Empty{}, nil
@@ -57,12 +101,121 @@ func (g GrpcServer) IsHourAvailable(ctx context.Context, request *trainer.IsHour
 
 	isAvailable, err := g.app.Queries.HourAvailability.Handle(ctx, query.HourAvailability{Hour: trainingTime})
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, grpcerr.Status(err)
 	}
 
 	return &trainer.IsHourAvailableResponse{IsAvailable: isAvailable}, nil
 }
 
+func (g GrpcServer) WatchHourAvailability(
+	request *trainer.WatchHourAvailabilityRequest,
+	stream trainer.TrainerService_WatchHourAvailabilityServer,
+) error {
+	if request.DateFrom == nil || request.DateTo == nil {
+		return status.Error(codes.InvalidArgument, "date_from and date_to are required")
+	}
+
+	from := request.DateFrom.AsTime().UTC()
+	to := request.DateTo.AsTime().UTC()
+	if from.After(to) {
+		return status.Error(codes.InvalidArgument, "date_from is after date_to")
+	}
+
+	subscriber, unsubscribe := g.availabilityChanges.Subscribe(from, to)
+	defer unsubscribe()
+
+	// sending headers lets the client know, that all changes from now will be received
+	if err := stream.SendHeader(metadata.MD{}); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case change, ok := <-subscriber.changes:
+			if !ok {
+				if subscriber.closeReason == errAvailabilityChangesHubClosed {
+					return status.Error(codes.Unavailable, "server is shutting down")
+				}
+				return status.Error(codes.ResourceExhausted, "client is too slow to receive changes")
+			}
+
+			if err := stream.Send(&trainer.HourAvailabilityChange{
+				Time:                 timestamppb.New(change.Hour),
+				IsAvailable:          change.To == hour.Available.String(),
+				HasTrainingScheduled: change.To == hour.TrainingScheduled.String(),
+				ChangedAt:            timestamppb.New(change.ChangedAt),
+			}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+type userDataExport struct {
+	AvailabilityChanges []exportedAvailabilityChange `json:"availabilityChanges"`
+}
+
+type exportedAvailabilityChange struct {
+	Hour      time.Time `json:"hour"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Command   string    `json:"command"`
+	ChangedAt time.Time `json:"changedAt"`
+}
+
+func (g GrpcServer) ExportUserData(ctx context.Context, request *trainer.ExportUserDataRequest) (*trainer.ExportUserDataResponse, error) {
+	if request.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "empty user_id")
+	}
+
+	changes, err := g.app.Queries.UserAvailabilityChanges.Handle(ctx, query.UserAvailabilityChanges{UserUUID: request.UserId})
+	if err != nil {
+		return nil, grpcerr.Status(err)
+	}
+
+	export := userDataExport{AvailabilityChanges: []exportedAvailabilityChange{}}
+	for _, change := range changes {
+		export.AvailabilityChanges = append(export.AvailabilityChanges, exportedAvailabilityChange{
+			Hour:      change.Hour,
+			From:      change.From,
+			To:        change.To,
+			Command:   change.Command,
+			ChangedAt: change.ChangedAt,
+		})
+	}
+
+	data, err := json.Marshal(export)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &trainer.ExportUserDataResponse{Data: data}, nil
+}
+
+func (g GrpcServer) EraseUserData(ctx context.Context, request *trainer.EraseUserDataRequest) (*empty.Empty, error) {
+	if request.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "empty user_id")
+	}
+
+	if err := g.app.Commands.EraseUserData.Handle(ctx, command.EraseUserData{UserUUID: request.UserId}); err != nil {
+		return nil, grpcerr.Status(err)
+	}
+
+	return &empty.Empty{}, nil
+}
+
+// updateHourChangeSource uses the user who made the change in the calling service as the actor,
+// so the history shows who booked or canceled the training.
+func updateHourChangeSource(request *trainer.UpdateHourRequest, command string) (hour.ChangeSource, error) {
+	if request.UserUuid == "" {
+		return hour.ChangeSource{}, status.Error(codes.InvalidArgument, "empty user_uuid")
+	}
+
+	return hour.ChangeSource{Actor: request.UserUuid, Command: command}, nil
+}
+
 func protoTimestampToTime(timestamp *timestamp.Timestamp) time.Time {
 	return timestamp.AsTime().UTC().Truncate(time.Hour)
 }
@@ -1,24 +1,39 @@
 package ports
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/auth"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/logs"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/ratelimit"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/server/httperr"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainer/app"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainer/app/command"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainer/app/query"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainer/domain/hour"
 	openapi_types "github.com/deepmap/oapi-codegen/pkg/types"
 	"github.com/go-chi/render"
 )
 
+// HttpRateLimits limits requests changing the calendar, which can be sent by scripts much faster than by trainers.
+var HttpRateLimits = map[string]ratelimit.Limit{
+	"PUT /trainer/calendar/make-hour-available":   {Requests: 60, Period: time.Minute},
+	"PUT /trainer/calendar/make-hour-unavailable": {Requests: 60, Period: time.Minute},
+	"PUT /trainer/calendar/config":                {Requests: 10, Period: time.Minute},
+}
+
 type HttpServer struct {
-	app app.Application
+	app                 app.Application
+	availabilityChanges *AvailabilityChangesHub
 }
 
-func NewHttpServer(application app.Application) HttpServer {
+func NewHttpServer(application app.Application, availabilityChanges *AvailabilityChangesHub) HttpServer {
 	return HttpServer{
-		app: application,
+		app:                 application,
+		availabilityChanges: availabilityChanges,
 	}
 }
 
@@ -60,15 +75,94 @@ func dateModelsToResponse(models []query.Date) []Date {
 	return dates
 }
 
+// sseKeepAliveInterval is how often the comment is sent to the open events stream,
+// so proxies are not closing it when there are no changes.
+const sseKeepAliveInterval = time.Second * 15
+
+func (h HttpServer) GetCalendarEvents(w http.ResponseWriter, r *http.Request, params GetCalendarEventsParams) {
+	if params.DateFrom.After(params.DateTo) {
+		httperr.BadRequest("date-from-after-date-to", nil, w, r)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		httperr.InternalError("streaming-not-supported", nil, w, r)
+		return
+	}
+
+	subscriber, unsubscribe := h.availabilityChanges.Subscribe(params.DateFrom.UTC(), params.DateTo.UTC())
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(sseKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case change, ok := <-subscriber.changes:
+			if !ok {
+				// client was too slow or the server is shutting down,
+				// it should reconnect and fetch the calendar again
+				return
+			}
+
+			data, err := json.Marshal(Hour{
+				Hour:                 change.Hour,
+				Available:            change.To == hour.Available.String(),
+				HasTrainingScheduled: change.To == hour.TrainingScheduled.String(),
+			})
+			if err != nil {
+				logs.GetLogEntry(r).WithError(err).Error("Unable to marshal availability change")
+				return
+			}
+
+			if _, err := fmt.Fprintf(w, "event: hour-availability-changed\ndata: %s\n\n", data); err != nil {
+				return
+			}
+		}
+
+		flusher.Flush()
+	}
+}
+
 func (h HttpServer) MakeHourAvailable(w http.ResponseWriter, r *http.Request) {
-	user, err := auth.UserFromCtx(r.Context())
+	user, ok := auth.HttpUserWithPermission(w, r, auth.PermissionCalendarEdit)
+	if !ok {
+		return
+	}
+
+	hourUpdate := &HourUpdate{}
+	if err := render.Decode(r, hourUpdate); err != nil {
+		httperr.RespondWithSlugError(err, w, r)
+		return
+	}
+
+	err := h.app.Commands.MakeHoursAvailable.Handle(r.Context(), command.MakeHoursAvailable{
+		Hours:  hourUpdate.Hours,
+		Source: hour.ChangeSource{Actor: user.UUID, Command: hour.ChangeSourceHTTPMakeHourAvailableCommand},
+	})
 	if err != nil {
 		httperr.RespondWithSlugError(err, w, r)
 		return
 	}
 
-	if user.Role != "trainer" {
-		httperr.Unauthorised("invalid-role", nil, w, r)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h HttpServer) MakeHourUnavailable(w http.ResponseWriter, r *http.Request) {
+	user, ok := auth.HttpUserWithPermission(w, r, auth.PermissionCalendarEdit)
+	if !ok {
 		return
 	}
 
@@ -78,7 +172,10 @@ func (h HttpServer) MakeHourAvailable(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.app.Commands.MakeHoursAvailable.Handle(r.Context(), command.MakeHoursAvailable{Hours: hourUpdate.Hours})
+	err := h.app.Commands.MakeHoursUnavailable.Handle(r.Context(), command.MakeHoursUnavailable{
+		Hours:  hourUpdate.Hours,
+		Source: hour.ChangeSource{Actor: user.UUID, Command: hour.ChangeSourceHTTPMakeHourUnavailableCommand},
+	})
 	if err != nil {
 		httperr.RespondWithSlugError(err, w, r)
 		return
@@ -87,25 +184,68 @@ func (h HttpServer) MakeHourAvailable(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
-func (h HttpServer) MakeHourUnavailable(w http.ResponseWriter, r *http.Request) {
-	user, err := auth.UserFromCtx(r.Context())
+func (h HttpServer) GetCalendarHistory(w http.ResponseWriter, r *http.Request, params GetCalendarHistoryParams) {
+	if _, ok := auth.HttpUserWithPermission(w, r, auth.PermissionCalendarReadHistory); !ok {
+		return
+	}
+
+	changes, err := h.app.Queries.HourAvailabilityHistory.Handle(r.Context(), query.HourAvailabilityHistory{
+		From: params.DateFrom,
+		To:   params.DateTo,
+	})
 	if err != nil {
 		httperr.RespondWithSlugError(err, w, r)
 		return
 	}
 
-	if user.Role != "trainer" {
-		httperr.Unauthorised("invalid-role", nil, w, r)
-		return
+	resp := []AvailabilityChange{}
+	for _, c := range changes {
+		resp = append(resp, AvailabilityChange{
+			Hour:      c.Hour,
+			From:      c.From,
+			To:        c.To,
+			Actor:     c.Actor,
+			Command:   c.Command,
+			ChangedAt: c.ChangedAt,
+		})
 	}
 
-	hourUpdate := &HourUpdate{}
-	if err := render.Decode(r, hourUpdate); err != nil {
+	render.Respond(w, r, resp)
+}
+
+func (h HttpServer) GetCalendarConfig(w http.ResponseWriter, r *http.Request) {
+	config, err := h.app.Queries.CurrentFactoryConfig.Handle(r.Context(), query.CurrentFactoryConfig{})
+	if err != nil {
 		httperr.RespondWithSlugError(err, w, r)
 		return
 	}
 
-	err = h.app.Commands.MakeHoursUnavailable.Handle(r.Context(), command.MakeHoursUnavailable{Hours: hourUpdate.Hours})
+	render.Respond(w, r, CalendarConfig{
+		MaxWeeksInTheFutureToSet: config.MaxWeeksInTheFutureToSet,
+		MinUtcHour:               config.MinUtcHour,
+		MaxUtcHour:               config.MaxUtcHour,
+		MaxTimeToSet:             config.MaxTimeToSet,
+	})
+}
+
+func (h HttpServer) UpdateCalendarConfig(w http.ResponseWriter, r *http.Request) {
+	if _, ok := auth.HttpUserWithPermission(w, r, auth.PermissionCalendarConfigEdit); !ok {
+		return
+	}
+
+	configUpdate := &CalendarConfigUpdate{}
+	if err := render.Decode(r, configUpdate); err != nil {
+		httperr.BadRequest("invalid-request", err, w, r)
+		return
+	}
+
+	err := h.app.Commands.UpdateFactoryConfig.Handle(r.Context(), command.UpdateFactoryConfig{
+		Config: hour.FactoryConfig{
+			MaxWeeksInTheFutureToSet: configUpdate.MaxWeeksInTheFutureToSet,
+			MinUtcHour:               configUpdate.MinUtcHour,
+			MaxUtcHour:               configUpdate.MaxUtcHour,
+		},
+	})
 	if err != nil {
 		httperr.RespondWithSlugError(err, w, r)
 		return
@@ -18,6 +18,18 @@ type ServerInterface interface {
 	// (GET /trainer/calendar)
 	GetTrainerAvailableHours(w http.ResponseWriter, r *http.Request, params GetTrainerAvailableHoursParams)
 
+	// (GET /trainer/calendar/config)
+	GetCalendarConfig(w http.ResponseWriter, r *http.Request)
+
+	// (PUT /trainer/calendar/config)
+	UpdateCalendarConfig(w http.ResponseWriter, r *http.Request)
+
+	// (GET /trainer/calendar/events)
+	GetCalendarEvents(w http.ResponseWriter, r *http.Request, params GetCalendarEventsParams)
+
+	// (GET /trainer/calendar/history)
+	GetCalendarHistory(w http.ResponseWriter, r *http.Request, params GetCalendarHistoryParams)
+
 	// (PUT /trainer/calendar/make-hour-available)
 	MakeHourAvailable(w http.ResponseWriter, r *http.Request)
 
@@ -83,6 +95,140 @@ func (siw *ServerInterfaceWrapper) GetTrainerAvailableHours(w http.ResponseWrite
 	handler(w, r.WithContext(ctx))
 }
 
+// GetCalendarConfig operation middleware
+func (siw *ServerInterfaceWrapper) GetCalendarConfig(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{""})
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetCalendarConfig(w, r)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// UpdateCalendarConfig operation middleware
+func (siw *ServerInterfaceWrapper) UpdateCalendarConfig(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{""})
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UpdateCalendarConfig(w, r)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetCalendarEvents operation middleware
+func (siw *ServerInterfaceWrapper) GetCalendarEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{""})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetCalendarEventsParams
+
+	// ------------- Required query parameter "dateFrom" -------------
+	if paramValue := r.URL.Query().Get("dateFrom"); paramValue != "" {
+
+	} else {
+		http.Error(w, "Query argument dateFrom is required, but not found", http.StatusBadRequest)
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "dateFrom", r.URL.Query(), &params.DateFrom)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter dateFrom: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Required query parameter "dateTo" -------------
+	if paramValue := r.URL.Query().Get("dateTo"); paramValue != "" {
+
+	} else {
+		http.Error(w, "Query argument dateTo is required, but not found", http.StatusBadRequest)
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "dateTo", r.URL.Query(), &params.DateTo)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter dateTo: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetCalendarEvents(w, r, params)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetCalendarHistory operation middleware
+func (siw *ServerInterfaceWrapper) GetCalendarHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{""})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetCalendarHistoryParams
+
+	// ------------- Required query parameter "dateFrom" -------------
+	if paramValue := r.URL.Query().Get("dateFrom"); paramValue != "" {
+
+	} else {
+		http.Error(w, "Query argument dateFrom is required, but not found", http.StatusBadRequest)
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "dateFrom", r.URL.Query(), &params.DateFrom)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter dateFrom: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Required query parameter "dateTo" -------------
+	if paramValue := r.URL.Query().Get("dateTo"); paramValue != "" {
+
+	} else {
+		http.Error(w, "Query argument dateTo is required, but not found", http.StatusBadRequest)
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "dateTo", r.URL.Query(), &params.DateTo)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter dateTo: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetCalendarHistory(w, r, params)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
 // MakeHourAvailable operation middleware
 func (siw *ServerInterfaceWrapper) MakeHourAvailable(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -157,6 +303,18 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/trainer/calendar", wrapper.GetTrainerAvailableHours)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/trainer/calendar/config", wrapper.GetCalendarConfig)
+	})
+	r.Group(func(r chi.Router) {
+		r.Put(options.BaseURL+"/trainer/calendar/config", wrapper.UpdateCalendarConfig)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/trainer/calendar/events", wrapper.GetCalendarEvents)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/trainer/calendar/history", wrapper.GetCalendarHistory)
+	})
 	r.Group(func(r chi.Router) {
 		r.Put(options.BaseURL+"/trainer/calendar/make-hour-available", wrapper.MakeHourAvailable)
 	})
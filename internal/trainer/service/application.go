@@ -2,10 +2,14 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"strings"
 
 	"cloud.google.com/go/firestore"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/metrics"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/mysqldb"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/server"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainer/adapters"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainer/app"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainer/app/command"
@@ -14,40 +18,121 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-func NewApplication(ctx context.Context) app.Application {
-	firestoreClient, err := firestore.NewClient(ctx, os.Getenv("GCP_PROJECT"))
-	if err != nil {
-		panic(err)
+// defaultFactoryConfig is used until the config is changed with the API
+var defaultFactoryConfig = hour.FactoryConfig{
+	MaxWeeksInTheFutureToSet: 6,
+	MinUtcHour:               12,
+	MaxUtcHour:               20,
+}
+
+// NewApplication returns the application and checks of storage, which is required to serve requests.
+func NewApplication(ctx context.Context) (app.Application, []server.HealthCheck) {
+	logger := logrus.NewEntry(logrus.StandardLogger())
+	metricsClient := metrics.Prometheus{}
+
+	r := newRepositories(ctx)
+
+	go refreshFactoryConfig(ctx, r.FactoryConfig, r.HourFactory, logger)
+
+	application := app.Application{
+		Commands: app.Commands{
+			CancelTraining:       command.NewCancelTrainingHandler(r.Hour, logger, metricsClient),
+			ScheduleTraining:     command.NewScheduleTrainingHandler(r.Hour, schedulingRulesFromEnv(), logger, metricsClient),
+			MakeHoursAvailable:   command.NewMakeHoursAvailableHandler(r.Hour, logger, metricsClient),
+			MakeHoursUnavailable: command.NewMakeHoursUnavailableHandler(r.Hour, logger, metricsClient),
+			UpdateFactoryConfig:  command.NewUpdateFactoryConfigHandler(r.FactoryConfig, r.HourFactory, logger, metricsClient),
+
+			EraseUserData: command.NewEraseUserDataHandler(r.Hour, logger, metricsClient),
+		},
+		Queries: app.Queries{
+			HourAvailability:      query.NewHourAvailabilityHandler(r.Hour, logger, metricsClient),
+			TrainerAvailableHours: query.NewAvailableHoursHandler(r.Dates, logger, metricsClient),
+			CurrentFactoryConfig:  query.NewCurrentFactoryConfigHandler(r.FactoryConfig, logger, metricsClient),
+
+			HourAvailabilityHistory: query.NewHourAvailabilityHistoryHandler(r.HourHistory, logger, metricsClient),
+			AvailabilityChanges:     query.NewAvailabilityChangesHandler(r.HourHistory, logger, metricsClient),
+			UserAvailabilityChanges: query.NewUserAvailabilityChangesHandler(r.HourHistory, logger, metricsClient),
+		},
 	}
 
-	factoryConfig := hour.FactoryConfig{
-		MaxWeeksInTheFutureToSet: 6,
-		MinUtcHour:               12,
-		MaxUtcHour:               20,
+	return application, []server.HealthCheck{r.HealthCheck}
+}
+
+type hourHistoryReadModel interface {
+	query.HourAvailabilityHistoryReadModel
+	query.AvailabilityChangesReadModel
+	query.UserAvailabilityChangesReadModel
+}
+
+type repositories struct {
+	HourFactory   hour.Factory
+	Hour          hour.Repository
+	HourHistory   hourHistoryReadModel
+	FactoryConfig hour.FactoryConfigRepository
+	Dates         query.AvailableHoursReadModel
+	HealthCheck   server.HealthCheck
+}
+
+// newRepositories creates repositories for the storage selected with TRAINER_STORAGE env (firestore by default).
+func newRepositories(ctx context.Context) repositories {
+	storage := strings.ToLower(os.Getenv("TRAINER_STORAGE"))
+
+	switch storage {
+	case "", "firestore":
+		firestoreClient, err := firestore.NewClient(ctx, os.Getenv("GCP_PROJECT"))
+		if err != nil {
+			panic(err)
+		}
+
+		factoryConfigRepository := adapters.NewFactoryConfigFirestoreRepository(firestoreClient, defaultFactoryConfig)
+		hourFactory := newHourFactory(ctx, factoryConfigRepository)
+		hourRepository := adapters.NewFirestoreHourRepository(firestoreClient, hourFactory)
+
+		return repositories{
+			HourFactory:   hourFactory,
+			Hour:          hourRepository,
+			HourHistory:   hourRepository,
+			FactoryConfig: factoryConfigRepository,
+			Dates:         adapters.NewDatesFirestoreRepository(firestoreClient, hourFactory),
+			HealthCheck:   server.FirestoreHealthCheck(firestoreClient),
+		}
+	case "mysql":
+		db, err := mysqldb.NewConnection()
+		if err != nil {
+			panic(err)
+		}
+
+		if err := adapters.MigrateMySQL(ctx, db); err != nil {
+			panic(err)
+		}
+
+		factoryConfigRepository := adapters.NewFactoryConfigMySQLRepository(db, defaultFactoryConfig)
+		hourFactory := newHourFactory(ctx, factoryConfigRepository)
+		hourRepository := adapters.NewMySQLHourRepository(db, hourFactory)
+
+		return repositories{
+			HourFactory:   hourFactory,
+			Hour:          hourRepository,
+			HourHistory:   hourRepository,
+			FactoryConfig: factoryConfigRepository,
+			Dates:         adapters.NewDatesMySQLRepository(db, hourFactory),
+			HealthCheck:   server.MySQLHealthCheck(db),
+		}
+	default:
+		panic(fmt.Sprintf("storage '%s' is not supported", storage))
 	}
+}
 
-	datesRepository := adapters.NewDatesFirestoreRepository(firestoreClient, factoryConfig)
+func newHourFactory(ctx context.Context, configRepository hour.FactoryConfigRepository) hour.Factory {
+	factoryConfig, err := configRepository.GetFactoryConfig(ctx)
+	if err != nil {
+		panic(err)
+	}
 
 	hourFactory, err := hour.NewFactory(factoryConfig)
 	if err != nil {
 		panic(err)
 	}
 
-	hourRepository := adapters.NewFirestoreHourRepository(firestoreClient, hourFactory)
-
-	logger := logrus.NewEntry(logrus.StandardLogger())
-	metricsClient := metrics.NoOp{}
-
-	return app.Application{
-		Commands: app.Commands{
-			CancelTraining:       command.NewCancelTrainingHandler(hourRepository, logger, metricsClient),
-			ScheduleTraining:     command.NewScheduleTrainingHandler(hourRepository, logger, metricsClient),
-			MakeHoursAvailable:   command.NewMakeHoursAvailableHandler(hourRepository, logger, metricsClient),
-			MakeHoursUnavailable: command.NewMakeHoursUnavailableHandler(hourRepository, logger, metricsClient),
-		},
-		Queries: app.Queries{
-			HourAvailability:      query.NewHourAvailabilityHandler(hourRepository, logger, metricsClient),
-			TrainerAvailableHours: query.NewAvailableHoursHandler(datesRepository, logger, metricsClient),
-		},
-	}
+	return hourFactory
 }
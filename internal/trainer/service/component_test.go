@@ -2,12 +2,14 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"log"
 	"net/http"
 	"os"
 	"testing"
 	"time"
 
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/client"
 	trainerHTTP "github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/client/trainer"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/genproto/trainer"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/server"
@@ -17,6 +19,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func TestHoursAvailability(t *testing.T) {
@@ -67,20 +70,183 @@ func TestUnauthorizedForAttendee(t *testing.T) {
 	hour := tests.RelativeDate(11, 13)
 
 	code := client.MakeHourAvailable(t, hour)
-	require.Equal(t, http.StatusUnauthorized, code)
+	require.Equal(t, http.StatusForbidden, code)
+}
+
+func TestCalendarHistory(t *testing.T) {
+	t.Parallel()
+
+	trainerUUID := uuid.New().String()
+	token := tests.FakeTrainerJWT(t, trainerUUID)
+	client := tests.NewTrainerHTTPClient(t, token)
+
+	hour := tests.RelativeDate(11, 14)
+	date := hour.Truncate(24 * time.Hour)
+
+	client.MakeHourUnavailable(t, hour)
+	client.MakeHourAvailable(t, hour)
+
+	var changesOfHour []trainerHTTP.AvailabilityChange
+	for _, c := range client.GetCalendarHistory(t, date, date) {
+		if c.Hour.Equal(hour) {
+			changesOfHour = append(changesOfHour, c)
+		}
+	}
+
+	require.NotEmpty(t, changesOfHour)
+
+	lastChange := changesOfHour[len(changesOfHour)-1]
+	require.Equal(t, "not_available", lastChange.From)
+	require.Equal(t, "available", lastChange.To)
+	require.Equal(t, trainerUUID, lastChange.Actor)
+	require.Equal(t, "http:make-hour-available", lastChange.Command)
+}
+
+func TestExportAndEraseUserData(t *testing.T) {
+	t.Parallel()
+
+	trainerUUID := uuid.New().String()
+	httpClient := tests.NewTrainerHTTPClient(t, tests.FakeTrainerJWT(t, trainerUUID))
+
+	grpcClient, closeClient, err := client.NewTrainerClient()
+	require.NoError(t, err)
+	defer func() {
+		_ = closeClient()
+	}()
+
+	hour := tests.RelativeDate(11, 16)
+	httpClient.MakeHourUnavailable(t, hour)
+	httpClient.MakeHourAvailable(t, hour)
+
+	ctx := context.Background()
+
+	export := exportUserData(t, grpcClient, trainerUUID)
+	require.NotEmpty(t, export.AvailabilityChanges)
+
+	_, err = grpcClient.EraseUserData(ctx, &trainer.EraseUserDataRequest{UserId: trainerUUID})
+	require.NoError(t, err)
+
+	export = exportUserData(t, grpcClient, trainerUUID)
+	require.Empty(t, export.AvailabilityChanges)
+}
+
+type exportedUserData struct {
+	AvailabilityChanges []struct {
+		Hour time.Time `json:"hour"`
+	} `json:"availabilityChanges"`
+}
+
+func exportUserData(t *testing.T, grpcClient trainer.TrainerServiceClient, userUUID string) exportedUserData {
+	t.Helper()
+
+	resp, err := grpcClient.ExportUserData(context.Background(), &trainer.ExportUserDataRequest{UserId: userUUID})
+	require.NoError(t, err)
+
+	var export exportedUserData
+	require.NoError(t, json.Unmarshal(resp.Data, &export))
+
+	return export
+}
+
+func TestWatchHourAvailability(t *testing.T) {
+	t.Parallel()
+
+	token := tests.FakeTrainerJWT(t, uuid.New().String())
+	httpClient := tests.NewTrainerHTTPClient(t, token)
+
+	grpcClient, closeClient, err := client.NewTrainerClient()
+	require.NoError(t, err)
+	defer func() {
+		_ = closeClient()
+	}()
+
+	hour := tests.RelativeDate(11, 15)
+	date := hour.Truncate(24 * time.Hour)
+
+	httpClient.MakeHourUnavailable(t, hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	stream, err := grpcClient.WatchHourAvailability(ctx, &trainer.WatchHourAvailabilityRequest{
+		DateFrom: timestamppb.New(date),
+		DateTo:   timestamppb.New(date),
+	})
+	require.NoError(t, err)
+
+	// the stream is established after receiving headers, changes made before that are not sent
+	_, err = stream.Header()
+	require.NoError(t, err)
+
+	httpClient.MakeHourAvailable(t, hour)
+
+	for {
+		change, err := stream.Recv()
+		require.NoError(t, err)
+
+		// other tests are changing hours of the same day
+		if !change.Time.AsTime().Equal(hour) {
+			continue
+		}
+
+		require.True(t, change.IsAvailable)
+		require.False(t, change.HasTrainingScheduled)
+		return
+	}
+}
+
+func TestCalendarConfig(t *testing.T) {
+	t.Parallel()
+
+	token := tests.FakeTrainerJWT(t, uuid.New().String())
+	client := tests.NewTrainerHTTPClient(t, token)
+
+	config := client.GetCalendarConfig(t)
+	require.True(t, config.MaxTimeToSet.After(time.Now()))
+
+	// other tests are running in parallel, so we are not changing the config
+	code := client.UpdateCalendarConfig(t, trainerHTTP.CalendarConfigUpdate{
+		MaxWeeksInTheFutureToSet: config.MaxWeeksInTheFutureToSet,
+		MinUtcHour:               config.MinUtcHour,
+		MaxUtcHour:               config.MaxUtcHour,
+	})
+	require.Equal(t, http.StatusNoContent, code)
+
+	code = client.UpdateCalendarConfig(t, trainerHTTP.CalendarConfigUpdate{
+		MaxWeeksInTheFutureToSet: config.MaxWeeksInTheFutureToSet,
+		MinUtcHour:               config.MaxUtcHour,
+		MaxUtcHour:               config.MinUtcHour - 1,
+	})
+	require.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestUpdateCalendarConfig_unauthorized_for_attendee(t *testing.T) {
+	t.Parallel()
+
+	token := tests.FakeAttendeeJWT(t, uuid.New().String())
+	client := tests.NewTrainerHTTPClient(t, token)
+
+	code := client.UpdateCalendarConfig(t, trainerHTTP.CalendarConfigUpdate{
+		MaxWeeksInTheFutureToSet: 6,
+		MinUtcHour:               12,
+		MaxUtcHour:               20,
+	})
+	require.Equal(t, http.StatusForbidden, code)
 }
 
 func startService() bool {
-	app := NewApplication(context.Background())
+	app, _ := NewApplication(context.Background())
 
 	trainerHTTPAddr := os.Getenv("TRAINER_HTTP_ADDR")
+	availabilityChanges := ports.NewAvailabilityChangesHub(app)
+
 	go server.RunHTTPServerOnAddr(trainerHTTPAddr, func(router chi.Router) http.Handler {
-		return ports.HandlerFromMux(ports.NewHttpServer(app), router)
+		return ports.HandlerFromMux(ports.NewHttpServer(app, availabilityChanges), router)
 	})
 
 	trainerGrpcAddr := os.Getenv("TRAINER_GRPC_ADDR")
 	go server.RunGRPCServerOnAddr(trainerGrpcAddr, func(server *grpc.Server) {
-		svc := ports.NewGrpcServer(app)
+		svc := ports.NewGrpcServer(app, availabilityChanges)
 		trainer.RegisterTrainerServiceServer(server, svc)
 	})
 
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainer/domain/hour"
+	"github.com/sirupsen/logrus"
+)
+
+const factoryConfigRefreshInterval = time.Second * 10
+
+// refreshFactoryConfig periodically applies stored config to the factory.
+//
+// Config can be changed by any instance of the service (for example, HTTP server handles the API call,
+// but gRPC server is scheduling trainings), so all instances need to pick up the changes.
+func refreshFactoryConfig(
+	ctx context.Context,
+	configRepository hour.FactoryConfigRepository,
+	hourFactory hour.Factory,
+	logger *logrus.Entry,
+) {
+	ticker := time.NewTicker(factoryConfigRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		factoryConfig, err := configRepository.GetFactoryConfig(ctx)
+		if err != nil {
+			logger.WithError(err).Error("Unable to get factory config")
+			continue
+		}
+
+		if factoryConfig == hourFactory.Config() {
+			continue
+		}
+
+		if err := hourFactory.UpdateConfig(factoryConfig); err != nil {
+			logger.WithError(err).Error("Unable to update factory config")
+			continue
+		}
+
+		logger.WithField("factory_config", factoryConfig).Info("Factory config updated")
+	}
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainer/domain/hour"
+)
+
+// schedulingRulesFromEnv reads trainer scheduling rules from env.
+// Rules that are not set are disabled.
+func schedulingRulesFromEnv() hour.SchedulingRules {
+	rules := hour.SchedulingRules{
+		MaxConsecutiveTrainings: intFromEnv("TRAINER_MAX_CONSECUTIVE_TRAININGS"),
+		MinBreak:                durationFromEnv("TRAINER_MIN_BREAK"),
+		MaxTrainingsPerDay:      intFromEnv("TRAINER_MAX_TRAININGS_PER_DAY"),
+		MaxTrainingsPerWeek:     intFromEnv("TRAINER_MAX_TRAININGS_PER_WEEK"),
+		MinLeadTime:             durationFromEnv("TRAINER_MIN_LEAD_TIME"),
+	}
+
+	if err := rules.Validate(); err != nil {
+		panic(err)
+	}
+
+	return rules
+}
+
+func intFromEnv(name string) int {
+	value := os.Getenv(name)
+	if value == "" {
+		return 0
+	}
+
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		panic(fmt.Sprintf("invalid %s: %s", name, err))
+	}
+
+	return i
+}
+
+func durationFromEnv(name string) time.Duration {
+	value := os.Getenv(name)
+	if value == "" {
+		return 0
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		panic(fmt.Sprintf("invalid %s: %s", name, err))
+	}
+
+	return d
+}
@@ -8,6 +8,7 @@ import (
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/genproto/trainer"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/grpcerr"
 )
 
 type TrainerGrpc struct {
@@ -18,33 +19,36 @@ func NewTrainerGrpc(client trainer.TrainerServiceClient) TrainerGrpc {
 	return TrainerGrpc{client: client}
 }
 
-func (s TrainerGrpc) ScheduleTraining(ctx context.Context, trainingTime time.Time) error {
+func (s TrainerGrpc) ScheduleTraining(ctx context.Context, trainingTime time.Time, actor string) error {
 	_, err := s.client.ScheduleTraining(ctx, &trainer.UpdateHourRequest{
-		Time: timestamppb.New(trainingTime),
+		Time:     timestamppb.New(trainingTime),
+		UserUuid: actor,
 	})
 
-	return err
+	return grpcerr.SlugError(err)
 }
 
-func (s TrainerGrpc) CancelTraining(ctx context.Context, trainingTime time.Time) error {
+func (s TrainerGrpc) CancelTraining(ctx context.Context, trainingTime time.Time, actor string) error {
 	_, err := s.client.CancelTraining(ctx, &trainer.UpdateHourRequest{
-		Time: timestamppb.New(trainingTime),
+		Time:     timestamppb.New(trainingTime),
+		UserUuid: actor,
 	})
 
-	return err
+	return grpcerr.SlugError(err)
 }
 
 func (s TrainerGrpc) MoveTraining(
 	ctx context.Context,
 	newTime time.Time,
 	originalTrainingTime time.Time,
+	actor string,
 ) error {
-	err := s.ScheduleTraining(ctx, newTime)
+	err := s.ScheduleTraining(ctx, newTime, actor)
 	if err != nil {
 		return errors.Wrap(err, "unable to schedule training")
 	}
 
-	err = s.CancelTraining(ctx, originalTrainingTime)
+	err = s.CancelTraining(ctx, originalTrainingTime, actor)
 	if err != nil {
 		return errors.Wrap(err, "unable to cancel training")
 	}
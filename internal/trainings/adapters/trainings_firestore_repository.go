@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"cloud.google.com/go/firestore"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/tracing"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainings/app/query"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainings/domain/training"
 	"github.com/pkg/errors"
@@ -44,7 +45,12 @@ func (r TrainingsFirestoreRepository) trainingsCollection() *firestore.Collectio
 	return r.firestoreClient.Collection("trainings")
 }
 
-func (r TrainingsFirestoreRepository) AddTraining(ctx context.Context, tr *training.Training) error {
+func (r TrainingsFirestoreRepository) AddTraining(ctx context.Context, tr *training.Training) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "TrainingsFirestoreRepository.AddTraining")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
 	collection := r.trainingsCollection()
 
 	trainingModel := r.marshalTraining(tr)
@@ -58,17 +64,22 @@ func (r TrainingsFirestoreRepository) GetTraining(
 	ctx context.Context,
 	trainingUUID string,
 	user training.User,
-) (*training.Training, error) {
+) (tr *training.Training, err error) {
+	ctx, span := tracing.StartSpan(ctx, "TrainingsFirestoreRepository.GetTraining")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
 	firestoreTraining, err := r.trainingsCollection().Doc(trainingUUID).Get(ctx)
 
 	if status.Code(err) == codes.NotFound {
-		return nil, training.NotFoundError{trainingUUID}
+		return nil, training.NotFoundError{TrainingUUID: trainingUUID}
 	}
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to get actual docs")
 	}
 
-	tr, err := r.unmarshalTraining(firestoreTraining)
+	tr, err = r.unmarshalTraining(firestoreTraining)
 	if err != nil {
 		return nil, err
 	}
@@ -85,7 +96,12 @@ func (r TrainingsFirestoreRepository) UpdateTraining(
 	trainingUUID string,
 	user training.User,
 	updateFn func(ctx context.Context, tr *training.Training) (*training.Training, error),
-) error {
+) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "TrainingsFirestoreRepository.UpdateTraining")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
 	trainingsCollection := r.trainingsCollection()
 
 	return r.firestoreClient.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
@@ -114,6 +130,54 @@ func (r TrainingsFirestoreRepository) UpdateTraining(
 	})
 }
 
+func (r TrainingsFirestoreRepository) UpdateUserTrainings(
+	ctx context.Context,
+	userUUID string,
+	updateFn func(ctx context.Context, tr *training.Training) (*training.Training, error),
+) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "TrainingsFirestoreRepository.UpdateUserTrainings")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	docs, err := r.trainingsCollection().Where("UserUuid", "==", userUUID).Documents(ctx).GetAll()
+	if err != nil {
+		return errors.Wrap(err, "unable to get user's trainings")
+	}
+
+	// the user can have more trainings than the limit of writes in the single transaction
+	for _, doc := range docs {
+		err := r.firestoreClient.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+			firestoreTraining, err := tx.Get(doc.Ref)
+			if err != nil {
+				return errors.Wrap(err, "unable to get actual docs")
+			}
+
+			tr, err := r.unmarshalTraining(firestoreTraining)
+			if err != nil {
+				return err
+			}
+
+			if tr.UserUUID() != userUUID {
+				// training was already updated, for example by the concurrent call
+				return nil
+			}
+
+			updatedTraining, err := updateFn(ctx, tr)
+			if err != nil {
+				return err
+			}
+
+			return tx.Set(doc.Ref, r.marshalTraining(updatedTraining))
+		})
+		if err != nil {
+			return errors.Wrapf(err, "unable to update training %s", doc.Ref.ID)
+		}
+	}
+
+	return nil
+}
+
 func (r TrainingsFirestoreRepository) marshalTraining(tr *training.Training) TrainingModel {
 	trainingModel := TrainingModel{
 		UUID:     tr.UUID(),
@@ -167,7 +231,12 @@ func (r TrainingsFirestoreRepository) unmarshalTraining(doc *firestore.DocumentS
 	)
 }
 
-func (r TrainingsFirestoreRepository) AllTrainings(ctx context.Context) ([]query.Training, error) {
+func (r TrainingsFirestoreRepository) AllTrainings(ctx context.Context) (trainings []query.Training, err error) {
+	ctx, span := tracing.StartSpan(ctx, "TrainingsFirestoreRepository.AllTrainings")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
 	query := r.
 		trainingsCollection().
 		Query.
@@ -179,7 +248,12 @@ func (r TrainingsFirestoreRepository) AllTrainings(ctx context.Context) ([]query
 	return r.trainingModelsToQuery(iter)
 }
 
-func (r TrainingsFirestoreRepository) FindTrainingsForUser(ctx context.Context, userUUID string) ([]query.Training, error) {
+func (r TrainingsFirestoreRepository) FindTrainingsForUser(ctx context.Context, userUUID string) (trainings []query.Training, err error) {
+	ctx, span := tracing.StartSpan(ctx, "TrainingsFirestoreRepository.FindTrainingsForUser")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
 	query := r.trainingsCollection().Query.
 		Where("Time", ">=", time.Now().Add(-time.Hour*24)).
 		Where("UserUuid", "==", userUUID).
@@ -190,8 +264,27 @@ func (r TrainingsFirestoreRepository) FindTrainingsForUser(ctx context.Context,
 	return r.trainingModelsToQuery(iter)
 }
 
+// FindAllTrainingsForUser returns all trainings of the user, including past and canceled ones.
+func (r TrainingsFirestoreRepository) FindAllTrainingsForUser(ctx context.Context, userUUID string) (trainings []query.Training, err error) {
+	ctx, span := tracing.StartSpan(ctx, "TrainingsFirestoreRepository.FindAllTrainingsForUser")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	iter := r.trainingsCollection().Query.
+		Where("UserUuid", "==", userUUID).
+		Documents(ctx)
+
+	return r.trainingModelsToQuery(iter)
+}
+
 // warning: RemoveAllTrainings was designed for tests for doing data cleanups
-func (r TrainingsFirestoreRepository) RemoveAllTrainings(ctx context.Context) error {
+func (r TrainingsFirestoreRepository) RemoveAllTrainings(ctx context.Context) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "TrainingsFirestoreRepository.RemoveAllTrainings")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
 	for {
 		iter := r.trainingsCollection().Limit(100).Documents(ctx)
 		numDeleted := 0
@@ -245,6 +338,7 @@ func (r TrainingsFirestoreRepository) trainingModelsToQuery(iter *firestore.Docu
 			Time:           tr.Time(),
 			Notes:          tr.Notes(),
 			CanBeCancelled: tr.CanBeCanceledForFree(),
+			Canceled:       tr.IsCanceled(),
 		}
 
 		if tr.IsRescheduleProposed() {
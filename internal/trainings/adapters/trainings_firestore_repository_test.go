@@ -106,7 +106,7 @@ func TestTrainingsFirestoreRepository_GetTraining_not_exists(t *testing.T) {
 		training.MustNewUser(uuid.New().String(), training.Attendee),
 	)
 	assert.Nil(t, tr)
-	assert.EqualError(t, err, training.NotFoundError{trainingUUID}.Error())
+	assert.EqualError(t, err, training.NotFoundError{TrainingUUID: trainingUUID}.Error())
 }
 
 func TestTrainingsFirestoreRepository_get_and_update_another_users_training(t *testing.T) {
@@ -296,6 +296,45 @@ func TestTrainingsFirestoreRepository_FindTrainingsForUser(t *testing.T) {
 	})
 }
 
+func TestTrainingsFirestoreRepository_UpdateUserTrainings(t *testing.T) {
+	t.Parallel()
+	repo := newFirebaseRepository(t)
+
+	ctx := context.Background()
+
+	userUUID := uuid.New().String()
+
+	pastTraining, err := training.NewTraining(uuid.New().String(), userUUID, "User", time.Now().AddDate(0, 0, -7))
+	require.NoError(t, err)
+	require.NoError(t, repo.AddTraining(ctx, pastTraining))
+
+	canceledTraining, err := training.NewTraining(uuid.New().String(), userUUID, "User", newRandomTrainingTime())
+	require.NoError(t, err)
+	require.NoError(t, canceledTraining.Cancel())
+	require.NoError(t, repo.AddTraining(ctx, canceledTraining))
+
+	trainings, err := repo.FindAllTrainingsForUser(ctx, userUUID)
+	require.NoError(t, err)
+	require.Len(t, trainings, 2, "past and canceled trainings should be returned")
+
+	anonymousUserUUID := uuid.New().String()
+	err = repo.UpdateUserTrainings(ctx, userUUID, func(ctx context.Context, tr *training.Training) (*training.Training, error) {
+		if err := tr.Anonymize(anonymousUserUUID); err != nil {
+			return nil, err
+		}
+		return tr, nil
+	})
+	require.NoError(t, err)
+
+	trainings, err = repo.FindAllTrainingsForUser(ctx, userUUID)
+	require.NoError(t, err)
+	assert.Empty(t, trainings)
+
+	trainings, err = repo.FindAllTrainingsForUser(ctx, anonymousUserUUID)
+	require.NoError(t, err)
+	assert.Len(t, trainings, 2)
+}
+
 func newRandomTrainingTime() time.Time {
 	min := time.Now().AddDate(0, 0, 5).Unix()
 	max := time.Date(2070, 1, 0, 0, 0, 0, 0, time.UTC).Unix()
@@ -4,6 +4,8 @@ import (
 	"context"
 
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/genproto/users"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/grpcerr"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainings/domain/training"
 )
 
 type UsersGrpc struct {
@@ -14,11 +16,40 @@ func NewUsersGrpc(client users.UsersServiceClient) UsersGrpc {
 	return UsersGrpc{client: client}
 }
 
-func (s UsersGrpc) UpdateTrainingBalance(ctx context.Context, userID string, amountChange int) error {
+func (s UsersGrpc) UpdateTrainingBalance(ctx context.Context, change training.BalanceChange, actor string) error {
 	_, err := s.client.UpdateTrainingBalance(ctx, &users.UpdateTrainingBalanceRequest{
-		UserId:       userID,
-		AmountChange: int64(amountChange),
+		UserId:       change.UserUUID,
+		AmountChange: int64(change.AmountChange),
+		OperationId:  change.OperationID,
+		Reason:       change.Reason.String(),
+		TrainingUuid: change.TrainingUUID,
+		Actor:        actor,
 	})
 
-	return err
+	return grpcerr.SlugError(err)
+}
+
+// batchGetUsersLimit is the maximum number of users returned by one BatchGetUsers call.
+const batchGetUsersLimit = 500
+
+func (s UsersGrpc) DisplayNames(ctx context.Context, userUUIDs []string) (map[string]string, error) {
+	names := make(map[string]string, len(userUUIDs))
+
+	for start := 0; start < len(userUUIDs); start += batchGetUsersLimit {
+		end := start + batchGetUsersLimit
+		if end > len(userUUIDs) {
+			end = len(userUUIDs)
+		}
+
+		resp, err := s.client.BatchGetUsers(ctx, &users.BatchGetUsersRequest{UserIds: userUUIDs[start:end]})
+		if err != nil {
+			return nil, grpcerr.SlugError(err)
+		}
+
+		for _, u := range resp.Users {
+			names[u.Uuid] = u.DisplayName
+		}
+	}
+
+	return names, nil
 }
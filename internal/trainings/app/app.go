@@ -17,9 +17,13 @@ type Commands struct {
 	RescheduleTraining        command.RescheduleTrainingHandler
 	RequestTrainingReschedule command.RequestTrainingRescheduleHandler
 	ScheduleTraining          command.ScheduleTrainingHandler
+
+	EraseUserData command.EraseUserDataHandler
 }
 
 type Queries struct {
 	AllTrainings     query.AllTrainingsHandler
 	TrainingsForUser query.TrainingsForUserHandler
+
+	UserTrainingsHistory query.UserTrainingsHistoryHandler
 }
@@ -62,7 +62,7 @@ func (h approveTrainingRescheduleHandler) Handle(ctx context.Context, cmd Approv
 				return nil, err
 			}
 
-			err := h.trainerService.MoveTraining(ctx, tr.Time(), originalTrainingTime)
+			err := h.trainerService.MoveTraining(ctx, tr.Time(), originalTrainingTime, cmd.User.UUID())
 			if err != nil {
 				return nil, err
 			}
@@ -61,14 +61,14 @@ func (h cancelTrainingHandler) Handle(ctx context.Context, cmd CancelTraining) (
 				return nil, err
 			}
 
-			if balanceDelta := training.CancelBalanceDelta(*tr, cmd.User.Type()); balanceDelta != 0 {
-				err := h.userService.UpdateTrainingBalance(ctx, tr.UserUUID(), balanceDelta)
+			for _, balanceChange := range training.CancelBalanceChanges(*tr, cmd.User.Type()) {
+				err := h.userService.UpdateTrainingBalance(ctx, balanceChange, cmd.User.UUID())
 				if err != nil {
 					return nil, errors.Wrap(err, "unable to change trainings balance")
 				}
 			}
 
-			if err := h.trainerService.CancelTraining(ctx, tr.Time()); err != nil {
+			if err := h.trainerService.CancelTraining(ctx, tr.Time(), cmd.User.UUID()); err != nil {
 				return nil, errors.Wrap(err, "unable to cancel training")
 			}
 
@@ -27,8 +27,7 @@ func TestCancelTraining(t *testing.T) {
 		ShouldFail    bool
 		ExpectedError string
 
-		ShouldUpdateBalance   bool
-		ExpectedBalanceChange int
+		ExpectedBalanceChanges []balanceChange
 	}{
 		{
 			Name:     "return_training_balance_when_attendee_cancels",
@@ -36,8 +35,7 @@ func TestCancelTraining(t *testing.T) {
 			TrainingConstructor: func() *training.Training {
 				return createExampleTraining(t, requestingUserID, time.Now().Add(48*time.Hour))
 			},
-			ShouldUpdateBalance:   true,
-			ExpectedBalanceChange: 1,
+			ExpectedBalanceChanges: []balanceChange{{training.CancelRefund, 1}},
 		},
 		{
 			Name:     "return_training_balance_when_trainer_cancels",
@@ -45,8 +43,7 @@ func TestCancelTraining(t *testing.T) {
 			TrainingConstructor: func() *training.Training {
 				return createExampleTraining(t, "trainer-id", time.Now().Add(48*time.Hour))
 			},
-			ShouldUpdateBalance:   true,
-			ExpectedBalanceChange: 1,
+			ExpectedBalanceChanges: []balanceChange{{training.CancelRefund, 1}},
 		},
 		{
 			Name:     "extra_training_balance_when_trainer_cancels_before_24h",
@@ -54,8 +51,7 @@ func TestCancelTraining(t *testing.T) {
 			TrainingConstructor: func() *training.Training {
 				return createExampleTraining(t, "trainer-id", time.Now().Add(12*time.Hour))
 			},
-			ShouldUpdateBalance:   true,
-			ExpectedBalanceChange: 2,
+			ExpectedBalanceChanges: []balanceChange{{training.CancelRefund, 1}, {training.TrainerPenalty, 1}},
 		},
 		{
 			Name:     "no_training_balance_returned_when_attendee_cancels_before_24h",
@@ -63,7 +59,6 @@ func TestCancelTraining(t *testing.T) {
 			TrainingConstructor: func() *training.Training {
 				return createExampleTraining(t, requestingUserID, time.Now().Add(12*time.Hour))
 			},
-			ShouldUpdateBalance: false,
 		},
 	}
 
@@ -92,12 +87,15 @@ func TestCancelTraining(t *testing.T) {
 
 			require.NoError(t, err)
 
-			if tc.ShouldUpdateBalance {
-				require.Len(t, deps.userService.balanceUpdates, 1)
-				require.Equal(t, tr.UserUUID(), deps.userService.balanceUpdates[0].userID)
-				require.Equal(t, tc.ExpectedBalanceChange, deps.userService.balanceUpdates[0].amountChange)
-			} else {
-				require.Len(t, deps.userService.balanceUpdates, 0)
+			require.Len(t, deps.userService.balanceUpdates, len(tc.ExpectedBalanceChanges))
+			for i, expected := range tc.ExpectedBalanceChanges {
+				update := deps.userService.balanceUpdates[i]
+
+				require.Equal(t, tr.UserUUID(), update.change.UserUUID)
+				require.Equal(t, tr.UUID(), update.change.TrainingUUID)
+				require.Equal(t, expected.reason, update.change.Reason)
+				require.Equal(t, expected.amountChange, update.change.AmountChange)
+				require.Equal(t, requestingUserID, update.actor)
 			}
 
 			require.Len(t, deps.trainerService.trainingsCancelled, 1)
@@ -174,33 +172,59 @@ func (r repositoryMock) AddTraining(ctx context.Context, tr *training.Training)
 	panic("implement me")
 }
 
+func (r repositoryMock) UpdateUserTrainings(
+	ctx context.Context,
+	userUUID string,
+	updateFn func(ctx context.Context, tr *training.Training) (*training.Training, error),
+) error {
+	for trainingUUID, tr := range r.Trainings {
+		if tr.UserUUID() != userUUID {
+			continue
+		}
+
+		updatedTraining, err := updateFn(ctx, &tr)
+		if err != nil {
+			return err
+		}
+
+		r.Trainings[trainingUUID] = *updatedTraining
+	}
+
+	return nil
+}
+
 type trainerServiceMock struct {
 	trainingsCancelled []time.Time
 }
 
-func (t *trainerServiceMock) MoveTraining(ctx context.Context, newTime time.Time, originalTrainingTime time.Time) error {
+func (t *trainerServiceMock) MoveTraining(ctx context.Context, newTime time.Time, originalTrainingTime time.Time, actor string) error {
 	panic("implement me")
 }
 
-func (t *trainerServiceMock) ScheduleTraining(ctx context.Context, trainingTime time.Time) error {
+func (t *trainerServiceMock) ScheduleTraining(ctx context.Context, trainingTime time.Time, actor string) error {
 	panic("implement me")
 }
 
-func (t *trainerServiceMock) CancelTraining(ctx context.Context, trainingTime time.Time) error {
+func (t *trainerServiceMock) CancelTraining(ctx context.Context, trainingTime time.Time, actor string) error {
 	t.trainingsCancelled = append(t.trainingsCancelled, trainingTime)
 	return nil
 }
 
-type balanceUpdate struct {
-	userID       string
+type balanceChange struct {
+	reason       training.BalanceChangeReason
 	amountChange int
 }
 
+type balanceUpdate struct {
+	change training.BalanceChange
+	actor  string
+}
+
 type userServiceMock struct {
 	balanceUpdates []balanceUpdate
 }
 
-func (u *userServiceMock) UpdateTrainingBalance(ctx context.Context, userID string, amountChange int) error {
-	u.balanceUpdates = append(u.balanceUpdates, balanceUpdate{userID, amountChange})
+func (u *userServiceMock) UpdateTrainingBalance(ctx context.Context, change training.BalanceChange, actor string) error {
+	u.balanceUpdates = append(u.balanceUpdates, balanceUpdate{change, actor})
 	return nil
 }
@@ -0,0 +1,79 @@
+package command
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/decorator"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainings/domain/training"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// EraseUserData anonymises all trainings of the user.
+//
+// It's safe to retry it, all trainings of the user are assigned to the same anonymous user.
+type EraseUserData struct {
+	UserUUID string
+}
+
+type EraseUserDataHandler decorator.CommandHandler[EraseUserData]
+
+type eraseUserDataHandler struct {
+	repo             training.Repository
+	anonymizationKey []byte
+}
+
+// NewEraseUserDataHandler creates the handler, anonymizationKey is a secret used to derive UUIDs of anonymous users.
+func NewEraseUserDataHandler(
+	repo training.Repository,
+	anonymizationKey []byte,
+	logger *logrus.Entry,
+	metricsClient decorator.MetricsClient,
+) EraseUserDataHandler {
+	if repo == nil {
+		panic("nil repo")
+	}
+	if len(anonymizationKey) == 0 {
+		panic("empty anonymizationKey")
+	}
+
+	return decorator.ApplyCommandDecorators[EraseUserData](
+		eraseUserDataHandler{repo: repo, anonymizationKey: anonymizationKey},
+		logger,
+		metricsClient,
+	)
+}
+
+func (h eraseUserDataHandler) Handle(ctx context.Context, cmd EraseUserData) error {
+	anonymousUserUUID := h.anonymousUserUUID(cmd.UserUUID)
+
+	return h.repo.UpdateUserTrainings(
+		ctx,
+		cmd.UserUUID,
+		func(ctx context.Context, tr *training.Training) (*training.Training, error) {
+			if err := tr.Anonymize(anonymousUserUUID); err != nil {
+				return nil, err
+			}
+
+			return tr, nil
+		},
+	)
+}
+
+// anonymousUserUUID is the same for all calls with the user, so retried erasures keep trainings of the user grouped.
+// It's derived with HMAC, so it can't be connected with the user without the key.
+func (h eraseUserDataHandler) anonymousUserUUID(userUUID string) string {
+	mac := hmac.New(sha256.New, h.anonymizationKey)
+	mac.Write([]byte(userUUID))
+
+	var anonymousUUID uuid.UUID
+	copy(anonymousUUID[:], mac.Sum(nil))
+
+	// the UUID is marked as version 8 (custom data), as defined by RFC 9562
+	anonymousUUID[6] = (anonymousUUID[6] & 0x0f) | 0x80
+	anonymousUUID[8] = (anonymousUUID[8] & 0x3f) | 0x80
+
+	return anonymousUUID.String()
+}
@@ -0,0 +1,47 @@
+package command_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/metrics"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainings/app/command"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainings/domain/training"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEraseUserData_retry(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	userUUID := uuid.New().String()
+
+	repository := &repositoryMock{Trainings: map[string]training.Training{}}
+	handler := command.NewEraseUserDataHandler(
+		repository,
+		[]byte("test-key"),
+		logrus.NewEntry(logrus.StandardLogger()),
+		metrics.NoOp{},
+	)
+
+	erasedTraining := createExampleTraining(t, userUUID, time.Now().Add(48*time.Hour))
+	repository.Trainings[erasedTraining.UUID()] = *erasedTraining
+
+	require.NoError(t, handler.Handle(ctx, command.EraseUserData{UserUUID: userUUID}))
+
+	// the training was not anonymized by the first call, for example because its transaction failed
+	notErasedTraining := createExampleTraining(t, userUUID, time.Now().Add(72*time.Hour))
+	repository.Trainings[notErasedTraining.UUID()] = *notErasedTraining
+
+	require.NoError(t, handler.Handle(ctx, command.EraseUserData{UserUUID: userUUID}))
+
+	anonymousUserUUID := repository.Trainings[erasedTraining.UUID()].UserUUID()
+	assert.NotEqual(t, userUUID, anonymousUserUUID)
+	assert.Equal(t, anonymousUserUUID, repository.Trainings[notErasedTraining.UUID()].UserUUID())
+
+	_, err := uuid.Parse(anonymousUserUUID)
+	assert.NoError(t, err)
+}
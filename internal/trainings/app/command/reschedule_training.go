@@ -71,7 +71,7 @@ func (h rescheduleTrainingHandler) Handle(ctx context.Context, cmd RescheduleTra
 				return nil, err
 			}
 
-			err := h.trainerService.MoveTraining(ctx, cmd.NewTime, originalTrainingTime)
+			err := h.trainerService.MoveTraining(ctx, cmd.NewTime, originalTrainingTime, cmd.User.UUID())
 			if err != nil {
 				return nil, err
 			}
@@ -67,12 +67,12 @@ func (h scheduleTrainingHandler) Handle(ctx context.Context, cmd ScheduleTrainin
 		return err
 	}
 
-	err = h.userService.UpdateTrainingBalance(ctx, tr.UserUUID(), -1)
+	err = h.userService.UpdateTrainingBalance(ctx, training.BookingBalanceChange(*tr), cmd.UserUUID)
 	if err != nil {
 		return errors.Wrap(err, "unable to change trainings balance")
 	}
 
-	err = h.trainerService.ScheduleTraining(ctx, tr.Time())
+	err = h.trainerService.ScheduleTraining(ctx, tr.Time(), cmd.UserUUID)
 	if err != nil {
 		return errors.Wrap(err, "unable to schedule training")
 	}
@@ -3,19 +3,24 @@ package command
 import (
 	"context"
 	"time"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainings/domain/training"
 )
 
 type UserService interface {
-	UpdateTrainingBalance(ctx context.Context, userID string, amountChange int) error
+	// UpdateTrainingBalance applies the change to the user's balance, actor is the UUID of the user who caused it.
+	UpdateTrainingBalance(ctx context.Context, change training.BalanceChange, actor string) error
 }
 
+// TrainerService changes hours in the trainer's calendar, actor is the UUID of the user who made the change.
 type TrainerService interface {
-	ScheduleTraining(ctx context.Context, trainingTime time.Time) error
-	CancelTraining(ctx context.Context, trainingTime time.Time) error
+	ScheduleTraining(ctx context.Context, trainingTime time.Time, actor string) error
+	CancelTraining(ctx context.Context, trainingTime time.Time, actor string) error
 
 	MoveTraining(
 		ctx context.Context,
 		newTime time.Time,
 		originalTrainingTime time.Time,
+		actor string,
 	) error
 }
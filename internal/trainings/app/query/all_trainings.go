@@ -12,20 +12,26 @@ type AllTrainings struct{}
 type AllTrainingsHandler decorator.QueryHandler[AllTrainings, []Training]
 
 type allTrainingsHandler struct {
-	readModel AllTrainingsReadModel
+	readModel    AllTrainingsReadModel
+	usersService UsersService
+	logger       *logrus.Entry
 }
 
 func NewAllTrainingsHandler(
 	readModel AllTrainingsReadModel,
+	usersService UsersService,
 	logger *logrus.Entry,
 	metricsClient decorator.MetricsClient,
 ) AllTrainingsHandler {
 	if readModel == nil {
 		panic("nil readModel")
 	}
+	if usersService == nil {
+		panic("nil usersService")
+	}
 
 	return decorator.ApplyQueryDecorators[AllTrainings, []Training](
-		allTrainingsHandler{readModel: readModel},
+		allTrainingsHandler{readModel: readModel, usersService: usersService, logger: logger},
 		logger,
 		metricsClient,
 	)
@@ -36,5 +42,10 @@ type AllTrainingsReadModel interface {
 }
 
 func (h allTrainingsHandler) Handle(ctx context.Context, _ AllTrainings) (tr []Training, err error) {
-	return h.readModel.AllTrainings(ctx)
+	trainings, err := h.readModel.AllTrainings(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return resolveUserNames(ctx, trainings, h.usersService, h.logger), nil
 }
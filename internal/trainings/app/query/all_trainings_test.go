@@ -0,0 +1,92 @@
+package query_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/metrics"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainings/app/query"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllTrainings_resolves_user_names(t *testing.T) {
+	t.Parallel()
+
+	readModel := trainingsReadModelMock{trainings: []query.Training{
+		{UUID: "training-1", UserUUID: "renamed-user", User: "Old Name"},
+		{UUID: "training-2", UserUUID: "erased-user", User: "Anonymous"},
+	}}
+	usersService := &usersServiceMock{names: map[string]string{"renamed-user": "New Name"}}
+
+	handler := query.NewAllTrainingsHandler(readModel, usersService, logrus.NewEntry(logrus.StandardLogger()), metrics.NoOp{})
+
+	trainings, err := handler.Handle(context.Background(), query.AllTrainings{})
+	require.NoError(t, err)
+	require.Len(t, trainings, 2)
+	assert.Equal(t, "New Name", trainings[0].User)
+	assert.Equal(t, "Anonymous", trainings[1].User)
+}
+
+func TestAllTrainings_requests_each_user_once(t *testing.T) {
+	t.Parallel()
+
+	readModel := trainingsReadModelMock{}
+	for i := 0; i < 1000; i++ {
+		readModel.trainings = append(readModel.trainings, query.Training{UserUUID: "user-1", User: "Old Name"})
+	}
+	usersService := &usersServiceMock{names: map[string]string{"user-1": "New Name"}}
+
+	handler := query.NewAllTrainingsHandler(readModel, usersService, logrus.NewEntry(logrus.StandardLogger()), metrics.NoOp{})
+
+	trainings, err := handler.Handle(context.Background(), query.AllTrainings{})
+	require.NoError(t, err)
+	assert.Equal(t, "New Name", trainings[999].User)
+	assert.Equal(t, []string{"user-1"}, usersService.requestedUserUUIDs)
+}
+
+func TestAllTrainings_users_service_unavailable(t *testing.T) {
+	t.Parallel()
+
+	readModel := trainingsReadModelMock{trainings: []query.Training{
+		{UUID: "training-1", UserUUID: "user-1", User: "Stored Name"},
+	}}
+	usersService := &usersServiceMock{err: errors.New("connection refused")}
+
+	handler := query.NewAllTrainingsHandler(readModel, usersService, logrus.NewEntry(logrus.StandardLogger()), metrics.NoOp{})
+
+	trainings, err := handler.Handle(context.Background(), query.AllTrainings{})
+	require.NoError(t, err)
+	require.Len(t, trainings, 1)
+	assert.Equal(t, "Stored Name", trainings[0].User)
+}
+
+type trainingsReadModelMock struct {
+	trainings []query.Training
+}
+
+func (m trainingsReadModelMock) AllTrainings(ctx context.Context) ([]query.Training, error) {
+	trainings := make([]query.Training, len(m.trainings))
+	copy(trainings, m.trainings)
+
+	return trainings, nil
+}
+
+type usersServiceMock struct {
+	names map[string]string
+	err   error
+
+	requestedUserUUIDs []string
+}
+
+func (m *usersServiceMock) DisplayNames(ctx context.Context, userUUIDs []string) (map[string]string, error) {
+	m.requestedUserUUIDs = userUUIDs
+
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	return m.names, nil
+}
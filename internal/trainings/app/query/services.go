@@ -0,0 +1,8 @@
+package query
+
+import "context"
+
+type UsersService interface {
+	// DisplayNames returns the current display names of the users, users that don't exist are omitted.
+	DisplayNames(ctx context.Context, userUUIDs []string) (map[string]string, error)
+}
@@ -15,20 +15,26 @@ type TrainingsForUser struct {
 type TrainingsForUserHandler decorator.QueryHandler[TrainingsForUser, []Training]
 
 type trainingsForUserHandler struct {
-	readModel TrainingsForUserReadModel
+	readModel    TrainingsForUserReadModel
+	usersService UsersService
+	logger       *logrus.Entry
 }
 
 func NewTrainingsForUserHandler(
 	readModel TrainingsForUserReadModel,
+	usersService UsersService,
 	logger *logrus.Entry,
 	metricsClient decorator.MetricsClient,
 ) TrainingsForUserHandler {
 	if readModel == nil {
 		panic("nil readModel")
 	}
+	if usersService == nil {
+		panic("nil usersService")
+	}
 
 	return decorator.ApplyQueryDecorators[TrainingsForUser, []Training](
-		trainingsForUserHandler{readModel: readModel},
+		trainingsForUserHandler{readModel: readModel, usersService: usersService, logger: logger},
 		logger,
 		metricsClient,
 	)
@@ -39,5 +45,10 @@ type TrainingsForUserReadModel interface {
 }
 
 func (h trainingsForUserHandler) Handle(ctx context.Context, query TrainingsForUser) (tr []Training, err error) {
-	return h.readModel.FindTrainingsForUser(ctx, query.User.UUID)
+	trainings, err := h.readModel.FindTrainingsForUser(ctx, query.User.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	return resolveUserNames(ctx, trainings, h.usersService, h.logger), nil
 }
@@ -14,4 +14,5 @@ type Training struct {
 	MoveProposedBy *string
 
 	CanBeCancelled bool
+	Canceled       bool
 }
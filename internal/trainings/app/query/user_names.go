@@ -0,0 +1,42 @@
+package query
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+// resolveUserNames replaces the user names stored with trainings with the current display names.
+//
+// The stored names are a snapshot from the moment of scheduling the training, so they are used
+// as a fallback when the users service is not available or the user was erased.
+func resolveUserNames(ctx context.Context, trainings []Training, usersService UsersService, logger *logrus.Entry) []Training {
+	if len(trainings) == 0 {
+		return trainings
+	}
+
+	// most of trainings belong to the same users, so each user is requested once
+	userUUIDs := make([]string, 0, len(trainings))
+	seen := map[string]struct{}{}
+	for _, tr := range trainings {
+		if _, ok := seen[tr.UserUUID]; ok {
+			continue
+		}
+		seen[tr.UserUUID] = struct{}{}
+		userUUIDs = append(userUUIDs, tr.UserUUID)
+	}
+
+	names, err := usersService.DisplayNames(ctx, userUUIDs)
+	if err != nil {
+		logger.WithError(err).Warn("Unable to resolve user names, using names stored with trainings")
+		return trainings
+	}
+
+	for i := range trainings {
+		if name := names[trainings[i].UserUUID]; name != "" {
+			trainings[i].User = name
+		}
+	}
+
+	return trainings
+}
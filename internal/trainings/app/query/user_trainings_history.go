@@ -0,0 +1,43 @@
+package query
+
+import (
+	"context"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/decorator"
+	"github.com/sirupsen/logrus"
+)
+
+// UserTrainingsHistory returns all trainings of the user, including past and canceled ones.
+type UserTrainingsHistory struct {
+	UserUUID string
+}
+
+type UserTrainingsHistoryHandler decorator.QueryHandler[UserTrainingsHistory, []Training]
+
+type UserTrainingsHistoryReadModel interface {
+	FindAllTrainingsForUser(ctx context.Context, userUUID string) ([]Training, error)
+}
+
+type userTrainingsHistoryHandler struct {
+	readModel UserTrainingsHistoryReadModel
+}
+
+func NewUserTrainingsHistoryHandler(
+	readModel UserTrainingsHistoryReadModel,
+	logger *logrus.Entry,
+	metricsClient decorator.MetricsClient,
+) UserTrainingsHistoryHandler {
+	if readModel == nil {
+		panic("nil readModel")
+	}
+
+	return decorator.ApplyQueryDecorators[UserTrainingsHistory, []Training](
+		userTrainingsHistoryHandler{readModel: readModel},
+		logger,
+		metricsClient,
+	)
+}
+
+func (h userTrainingsHistoryHandler) Handle(ctx context.Context, query UserTrainingsHistory) ([]Training, error) {
+	return h.readModel.FindAllTrainingsForUser(ctx, query.UserUUID)
+}
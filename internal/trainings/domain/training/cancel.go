@@ -1,15 +1,19 @@
 package training
 
 import (
-	"errors"
 	"time"
+
+	commonerrors "github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/errors"
 )
 
 func (t Training) CanBeCanceledForFree() bool {
 	return time.Until(t.time) >= time.Hour*24
 }
 
-var ErrTrainingAlreadyCanceled = errors.New("training is already canceled")
+var ErrTrainingAlreadyCanceled = commonerrors.NewPreconditionFailedError(
+	"training is already canceled",
+	"training-already-canceled",
+)
 
 func (t *Training) Cancel() error {
 	if t.IsCanceled() {
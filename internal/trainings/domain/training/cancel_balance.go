@@ -2,20 +2,64 @@ package training
 
 import "fmt"
 
-// CancelBalanceDelta return trainings balance delta that should be adjusted after training cancellation.
-func CancelBalanceDelta(tr Training, cancelingUserType UserType) int {
+// BalanceChangeReason is enum-like type, values are the same as ledger reasons of the users service.
+type BalanceChangeReason struct {
+	s string
+}
+
+func (r BalanceChangeReason) String() string {
+	return r.s
+}
+
+var (
+	Booking        = BalanceChangeReason{"booking"}
+	CancelRefund   = BalanceChangeReason{"cancel_refund"}
+	TrainerPenalty = BalanceChangeReason{"trainer_penalty"}
+)
+
+// BalanceChange is a change of the attendee's trainings balance caused by the training.
+type BalanceChange struct {
+	// OperationID is deterministic, so retried change is applied by the users service only once.
+	OperationID string
+
+	UserUUID     string
+	TrainingUUID string
+	Reason       BalanceChangeReason
+	AmountChange int
+}
+
+func newBalanceChange(tr Training, reason BalanceChangeReason, amountChange int) BalanceChange {
+	return BalanceChange{
+		OperationID:  fmt.Sprintf("%s-%s", reason, tr.UUID()),
+		UserUUID:     tr.UserUUID(),
+		TrainingUUID: tr.UUID(),
+		Reason:       reason,
+		AmountChange: amountChange,
+	}
+}
+
+// BookingBalanceChange returns balance change which should be applied when the training is scheduled.
+func BookingBalanceChange(tr Training) BalanceChange {
+	return newBalanceChange(tr, Booking, -1)
+}
+
+// CancelBalanceChanges return trainings balance changes that should be applied after training cancellation.
+func CancelBalanceChanges(tr Training, cancelingUserType UserType) []BalanceChange {
 	if tr.CanBeCanceledForFree() {
 		// just give training back
-		return 1
+		return []BalanceChange{newBalanceChange(tr, CancelRefund, 1)}
 	}
 
 	switch cancelingUserType {
 	case Trainer:
 		// 1 for cancelled training +1 "fine" for cancelling by trainer less than 24h before training
-		return 2
+		return []BalanceChange{
+			newBalanceChange(tr, CancelRefund, 1),
+			newBalanceChange(tr, TrainerPenalty, 1),
+		}
 	case Attendee:
 		// "fine" for cancelling less than 24h before training
-		return 0
+		return nil
 	default:
 		panic(fmt.Sprintf("not supported user type %s", cancelingUserType))
 	}
@@ -0,0 +1,80 @@
+package training_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainings/domain/training"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBookingBalanceChange(t *testing.T) {
+	t.Parallel()
+	tr := newExampleTraining(t)
+
+	assert.Equal(t, training.BalanceChange{
+		OperationID:  "booking-" + tr.UUID(),
+		UserUUID:     tr.UserUUID(),
+		TrainingUUID: tr.UUID(),
+		Reason:       training.Booking,
+		AmountChange: -1,
+	}, training.BookingBalanceChange(*tr))
+}
+
+func TestCancelBalanceChanges(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		Name              string
+		TrainingTime      time.Time
+		CancelingUserType training.UserType
+
+		ExpectedReasons []training.BalanceChangeReason
+	}{
+		{
+			Name:              "attendee_cancels_before_24h",
+			TrainingTime:      time.Now().Add(48 * time.Hour),
+			CancelingUserType: training.Attendee,
+			ExpectedReasons:   []training.BalanceChangeReason{training.CancelRefund},
+		},
+		{
+			Name:              "trainer_cancels_before_24h",
+			TrainingTime:      time.Now().Add(48 * time.Hour),
+			CancelingUserType: training.Trainer,
+			ExpectedReasons:   []training.BalanceChangeReason{training.CancelRefund},
+		},
+		{
+			Name:              "attendee_cancels_less_than_24h_before",
+			TrainingTime:      time.Now().Add(12 * time.Hour),
+			CancelingUserType: training.Attendee,
+			ExpectedReasons:   nil,
+		},
+		{
+			Name:              "trainer_cancels_less_than_24h_before",
+			TrainingTime:      time.Now().Add(12 * time.Hour),
+			CancelingUserType: training.Trainer,
+			ExpectedReasons:   []training.BalanceChangeReason{training.CancelRefund, training.TrainerPenalty},
+		},
+	}
+
+	for _, c := range testCases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			t.Parallel()
+			tr := newExampleTrainingWithTime(t, c.TrainingTime)
+
+			changes := training.CancelBalanceChanges(*tr, c.CancelingUserType)
+
+			var reasons []training.BalanceChangeReason
+			for _, change := range changes {
+				reasons = append(reasons, change.Reason)
+
+				assert.Equal(t, 1, change.AmountChange)
+				assert.Equal(t, tr.UserUUID(), change.UserUUID)
+				assert.Equal(t, tr.UUID(), change.TrainingUUID)
+				assert.Equal(t, change.Reason.String()+"-"+tr.UUID(), change.OperationID)
+			}
+			assert.Equal(t, c.ExpectedReasons, reasons)
+		})
+	}
+}
@@ -3,6 +3,8 @@ package training
 import (
 	"context"
 	"fmt"
+
+	commonerrors "github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/errors"
 )
 
 type NotFoundError struct {
@@ -13,6 +15,14 @@ func (e NotFoundError) Error() string {
 	return fmt.Sprintf("training '%s' not found", e.TrainingUUID)
 }
 
+func (e NotFoundError) Slug() string {
+	return "training-not-found"
+}
+
+func (e NotFoundError) ErrorType() commonerrors.ErrorType {
+	return commonerrors.ErrorTypeNotFound
+}
+
 type Repository interface {
 	AddTraining(ctx context.Context, tr *Training) error
 
@@ -24,4 +34,12 @@ type Repository interface {
 		user User,
 		updateFn func(ctx context.Context, tr *Training) (*Training, error),
 	) error
+
+	// UpdateUserTrainings updates all trainings of the user, including past and canceled ones.
+	// Each training is updated in a separate transaction.
+	UpdateUserTrainings(
+		ctx context.Context,
+		userUUID string,
+		updateFn func(ctx context.Context, tr *Training) (*Training, error),
+	) error
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	commonerrors "github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/errors"
 	"github.com/pkg/errors"
 )
 
@@ -26,6 +27,14 @@ func (c CantRescheduleBeforeTimeError) Error() string {
 	)
 }
 
+func (c CantRescheduleBeforeTimeError) Slug() string {
+	return "cant-reschedule-before-time"
+}
+
+func (c CantRescheduleBeforeTimeError) ErrorType() commonerrors.ErrorType {
+	return commonerrors.ErrorTypePreconditionFailed
+}
+
 func (t *Training) RescheduleTraining(newTime time.Time) error {
 	if !t.CanBeCanceledForFree() {
 		err := CantRescheduleBeforeTimeError{
@@ -48,7 +57,10 @@ func (t *Training) IsRescheduleProposed() bool {
 	return !t.moveProposedBy.IsZero() && !t.proposedNewTime.IsZero()
 }
 
-var ErrNoRescheduleRequested = errors.New("no training reschedule was requested yet")
+var ErrNoRescheduleRequested = commonerrors.NewPreconditionFailedError(
+	"no training reschedule was requested yet",
+	"no-reschedule-requested",
+)
 
 func (t *Training) ApproveReschedule(userType UserType) error {
 	if !t.IsRescheduleProposed() {
@@ -56,10 +68,13 @@ func (t *Training) ApproveReschedule(userType UserType) error {
 	}
 
 	if t.moveProposedBy == userType {
-		return errors.Errorf(
-			"trying to approve reschedule by the same user type which proposed reschedule (%s)",
-			userType.String(),
-		)
+		return errors.WithStack(commonerrors.NewPreconditionFailedError(
+			fmt.Sprintf(
+				"trying to approve reschedule by the same user type which proposed reschedule (%s)",
+				userType.String(),
+			),
+			"reschedule-proposed-by-same-user-type",
+		))
 	}
 
 	t.time = t.proposedNewTime
@@ -101,3 +101,22 @@ func (t *Training) UpdateNotes(notes string) error {
 func (t Training) Notes() string {
 	return t.notes
 }
+
+// anonymousUserName replaces the name of the attendee, whose personal data was erased.
+const anonymousUserName = "Anonymous"
+
+// Anonymize removes personal data of the attendee from the training.
+//
+// The training is assigned to anonymousUserUUID instead of being removed,
+// so statistics (like the number of trainings per attendee) are not changed.
+func (t *Training) Anonymize(anonymousUserUUID string) error {
+	if anonymousUserUUID == "" {
+		return errors.New("empty anonymousUserUUID")
+	}
+
+	t.userUUID = anonymousUserUUID
+	t.userName = anonymousUserName
+	t.notes = ""
+
+	return nil
+}
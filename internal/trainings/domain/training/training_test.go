@@ -47,6 +47,21 @@ func TestNewTraining_invalid(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestTraining_Anonymize(t *testing.T) {
+	t.Parallel()
+	tr := newExampleTraining(t)
+	require.NoError(t, tr.UpdateNotes("I have a knee injury"))
+
+	anonymousUserUUID := uuid.New().String()
+	require.NoError(t, tr.Anonymize(anonymousUserUUID))
+
+	assert.Equal(t, anonymousUserUUID, tr.UserUUID())
+	assert.Equal(t, "Anonymous", tr.UserName())
+	assert.Empty(t, tr.Notes())
+
+	assert.Error(t, tr.Anonymize(""))
+}
+
 func TestTraining_UpdateNotes(t *testing.T) {
 	t.Parallel()
 	tr := newExampleTraining(t)
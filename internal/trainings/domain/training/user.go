@@ -43,6 +43,8 @@ func NewUserTypeFromString(userType string) (UserType, error) {
 type User struct {
 	userUUID string
 	userType UserType
+
+	canSeeAllTrainings bool
 }
 
 func (u User) UUID() string {
@@ -57,6 +59,17 @@ func (u User) IsEmpty() bool {
 	return u == User{}
 }
 
+// CanSeeAllTrainings is decided by the permissions of the user, not by the user type.
+func (u User) CanSeeAllTrainings() bool {
+	return u.canSeeAllTrainings
+}
+
+// WithAllTrainingsVisible returns a copy of the user, who can see trainings of all users.
+func (u User) WithAllTrainingsVisible() User {
+	u.canSeeAllTrainings = true
+	return u
+}
+
 func NewUser(userUUID string, userType UserType) (User, error) {
 	if userUUID == "" {
 		return User{}, errors.New("missing user UUID")
@@ -89,13 +102,24 @@ func (f ForbiddenToSeeTrainingError) Error() string {
 	)
 }
 
+func (f ForbiddenToSeeTrainingError) Slug() string {
+	return "forbidden-to-see-training"
+}
+
+func (f ForbiddenToSeeTrainingError) ErrorType() commonErrors.ErrorType {
+	return commonErrors.ErrorTypeForbidden
+}
+
 func CanUserSeeTraining(user User, training Training) error {
-	if user.Type() == Trainer {
+	if user.CanSeeAllTrainings() {
 		return nil
 	}
 	if user.UUID() == training.UserUUID() {
 		return nil
 	}
 
-	return ForbiddenToSeeTrainingError{user.UUID(), training.UserUUID()}
+	return ForbiddenToSeeTrainingError{
+		RequestingUserUUID: user.UUID(),
+		TrainingOwnerUUID:  training.UserUUID(),
+	}
 }
@@ -20,6 +20,10 @@ func TestIsUserAllowedToSeeTraining(t *testing.T) {
 
 	trainer, err := training.NewUser(uuid.New().String(), training.Trainer)
 	require.NoError(t, err)
+	trainer = trainer.WithAllTrainingsVisible()
+
+	trainerWithoutPermission, err := training.NewUser(uuid.New().String(), training.Trainer)
+	require.NoError(t, err)
 
 	testCases := []struct {
 		Name              string
@@ -75,6 +79,22 @@ func TestIsUserAllowedToSeeTraining(t *testing.T) {
 			User:              trainer,
 			ExpectedIsAllowed: true, // trainer have access to all trainings
 		},
+		{
+			Name: "trainer_without_permission",
+			CreateTraining: func(t *testing.T) *training.Training {
+				tr, err := training.NewTraining(
+					uuid.New().String(),
+					attendee1.UUID(),
+					"user name",
+					time.Now(),
+				)
+				require.NoError(t, err)
+
+				return tr
+			},
+			User:              trainerWithoutPermission,
+			ExpectedIsAllowed: false, // access is granted by the permission, not by the user type
+		},
 	}
 
 	for _, c := range testCases {
@@ -86,7 +106,7 @@ func TestIsUserAllowedToSeeTraining(t *testing.T) {
 			err := training.CanUserSeeTraining(c.User, *tr)
 
 			if c.ExpectedIsAllowed {
-
+				assert.NoError(t, err)
 			} else {
 				assert.EqualError(
 					t,
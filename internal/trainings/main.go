@@ -2,24 +2,60 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"os"
+	"strings"
 
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/client"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/genproto/trainings"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/logs"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/server"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/tracing"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainings/ports"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainings/service"
 	"github.com/go-chi/chi/v5"
+	"google.golang.org/grpc"
 )
 
 func main() {
 	logs.Init()
 
+	flushTraces := tracing.Init("trainings")
+	defer flushTraces()
+
 	ctx := context.Background()
 
-	app, cleanup := service.NewApplication(ctx)
-	defer cleanup()
+	app, healthChecks, cleanup := service.NewApplication(ctx)
+
+	serverType := strings.ToLower(os.Getenv("SERVER_TO_RUN"))
+	switch serverType {
+	case "http":
+		sessionRevocationChecker, closeSessionRevocationChecker, err := client.NewSessionRevocationChecker()
+		if err != nil {
+			panic(err)
+		}
 
-	server.RunHTTPServer(func(router chi.Router) http.Handler {
-		return ports.HandlerFromMux(ports.NewHttpServer(app), router)
-	})
+		server.RunHTTPServer(
+			func(router chi.Router) http.Handler {
+				return ports.HandlerFromMux(ports.NewHttpServer(app), router)
+			},
+			server.WithHTTPHealthChecks(healthChecks...),
+			server.WithRateLimits(ports.HttpRateLimits),
+			server.WithSessionRevocationCheck(sessionRevocationChecker),
+			server.WithHTTPShutdownHooks(cleanup, func() { _ = closeSessionRevocationChecker() }),
+		)
+	case "grpc":
+		server.RunGRPCServer(
+			func(server *grpc.Server) {
+				svc := ports.NewGrpcServer(app)
+				trainings.RegisterTrainingsServiceServer(server, svc)
+			},
+			server.WithAllowedCallers(ports.GrpcAllowedCallers),
+			server.WithGRPCHealthChecks(healthChecks...),
+			server.WithGRPCShutdownHooks(cleanup),
+		)
+	default:
+		panic(fmt.Sprintf("server type '%s' is not supported", serverType))
+	}
 }
@@ -0,0 +1,88 @@
+package ports
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/genproto/trainings"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/grpcerr"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainings/app"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainings/app/command"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainings/app/query"
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GrpcAllowedCallers lists services, which are allowed to call methods of GrpcServer.
+var GrpcAllowedCallers = map[string][]string{
+	"/trainings.TrainingsService/ExportUserData": {"users"},
+	"/trainings.TrainingsService/EraseUserData":  {"users"},
+}
+
+type GrpcServer struct {
+	app app.Application
+}
+
+func NewGrpcServer(application app.Application) GrpcServer {
+	return GrpcServer{app: application}
+}
+
+type userDataExport struct {
+	Trainings []exportedTraining `json:"trainings"`
+}
+
+type exportedTraining struct {
+	UUID     string    `json:"uuid"`
+	UserName string    `json:"userName"`
+	Time     time.Time `json:"time"`
+	Notes    string    `json:"notes"`
+	Canceled bool      `json:"canceled"`
+
+	ProposedTime   *time.Time `json:"proposedTime,omitempty"`
+	MoveProposedBy *string    `json:"moveProposedBy,omitempty"`
+}
+
+func (g GrpcServer) ExportUserData(ctx context.Context, request *trainings.ExportUserDataRequest) (*trainings.ExportUserDataResponse, error) {
+	if request.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "empty user_id")
+	}
+
+	userTrainings, err := g.app.Queries.UserTrainingsHistory.Handle(ctx, query.UserTrainingsHistory{UserUUID: request.UserId})
+	if err != nil {
+		return nil, grpcerr.Status(err)
+	}
+
+	export := userDataExport{Trainings: []exportedTraining{}}
+	for _, tr := range userTrainings {
+		export.Trainings = append(export.Trainings, exportedTraining{
+			UUID:           tr.UUID,
+			UserName:       tr.User,
+			Time:           tr.Time,
+			Notes:          tr.Notes,
+			Canceled:       tr.Canceled,
+			ProposedTime:   tr.ProposedTime,
+			MoveProposedBy: tr.MoveProposedBy,
+		})
+	}
+
+	data, err := json.Marshal(export)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &trainings.ExportUserDataResponse{Data: data}, nil
+}
+
+func (g GrpcServer) EraseUserData(ctx context.Context, request *trainings.EraseUserDataRequest) (*empty.Empty, error) {
+	if request.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "empty user_id")
+	}
+
+	if err := g.app.Commands.EraseUserData.Handle(ctx, command.EraseUserData{UserUUID: request.UserId}); err != nil {
+		return nil, grpcerr.Status(err)
+	}
+
+	return &empty.Empty{}, nil
+}
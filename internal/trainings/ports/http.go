@@ -2,9 +2,13 @@ package ports
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/auth"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/errors"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/ratelimit"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/server/httperr"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainings/app"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainings/app/command"
@@ -14,6 +18,13 @@ import (
 	"github.com/google/uuid"
 )
 
+// HttpRateLimits limits requests scheduling trainings, as each of them reserves an hour of the trainer.
+var HttpRateLimits = map[string]ratelimit.Limit{
+	"POST /trainings":                                  {Requests: 10, Period: time.Minute},
+	"PUT /trainings/{trainingUUID}/reschedule":         {Requests: 10, Period: time.Minute},
+	"PUT /trainings/{trainingUUID}/request-reschedule": {Requests: 10, Period: time.Minute},
+}
+
 type HttpServer struct {
 	app app.Application
 }
@@ -31,7 +42,7 @@ func (h HttpServer) GetTrainings(w http.ResponseWriter, r *http.Request) {
 
 	var appTrainings []query.Training
 
-	if user.Role == "trainer" {
+	if user.HasPermission(auth.PermissionTrainingsReadAll) {
 		appTrainings, err = h.app.Queries.AllTrainings.Handle(r.Context(), query.AllTrainings{})
 	} else {
 		appTrainings, err = h.app.Queries.TrainingsForUser.Handle(r.Context(), query.TrainingsForUser{User: user})
@@ -55,25 +66,19 @@ func (h HttpServer) CreateTraining(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := auth.UserFromCtx(r.Context())
-	if err != nil {
-		httperr.RespondWithSlugError(err, w, r)
-		return
-	}
-
-	if user.Role != "attendee" {
-		httperr.Unauthorised("invalid-role", nil, w, r)
+	user, ok := auth.HttpUserWithPermission(w, r, auth.PermissionTrainingsSchedule)
+	if !ok {
 		return
 	}
 
 	cmd := command.ScheduleTraining{
 		TrainingUUID: uuid.New().String(),
 		UserUUID:     user.UUID,
-		UserName:     user.DisplayName,
+		UserName:     trainingUserName(user),
 		TrainingTime: postTraining.Time,
 		Notes:        postTraining.Notes,
 	}
-	err = h.app.Commands.ScheduleTraining.Handle(r.Context(), cmd)
+	err := h.app.Commands.ScheduleTraining.Handle(r.Context(), cmd)
 	if err != nil {
 		httperr.RespondWithSlugError(err, w, r)
 		return
@@ -205,16 +210,50 @@ func appTrainingsToResponse(appTrainings []query.Training) []Training {
 	return trainings
 }
 
+// trainingUserName falls back to the email, as tokens of some identity providers don't contain the name.
+// The stored name is shown only when the current name can't be resolved by the users service.
+func trainingUserName(user auth.User) string {
+	if user.DisplayName != "" {
+		return user.DisplayName
+	}
+
+	return user.Email
+}
+
 func newDomainUserFromAuthUser(ctx context.Context) (training.User, error) {
 	user, err := auth.UserFromCtx(ctx)
 	if err != nil {
 		return training.User{}, err
 	}
 
-	userType, err := training.NewUserTypeFromString(user.Role)
+	userType, err := userTypeFromPermissions(user)
+	if err != nil {
+		return training.User{}, err
+	}
+
+	domainUser, err := training.NewUser(user.UUID, userType)
 	if err != nil {
 		return training.User{}, err
 	}
 
-	return training.NewUser(user.UUID, userType)
+	if user.HasPermission(auth.PermissionTrainingsReadAll) {
+		domainUser = domainUser.WithAllTrainingsVisible()
+	}
+
+	return domainUser, nil
+}
+
+// userTypeFromPermissions decides, if the user acts on trainings as the trainer or as the attendee.
+func userTypeFromPermissions(user auth.User) (training.UserType, error) {
+	switch {
+	case user.HasPermission(auth.PermissionTrainingsManageAll):
+		return training.Trainer, nil
+	case user.HasPermission(auth.PermissionTrainingsSchedule):
+		return training.Attendee, nil
+	default:
+		return training.UserType{}, errors.NewForbiddenError(
+			fmt.Sprintf("user with role '%s' has no permissions to trainings", user.Role),
+			"missing-permission",
+		)
+	}
 }
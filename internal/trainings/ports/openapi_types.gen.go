@@ -13,6 +13,15 @@ const (
 
 // Error defines model for Error.
 type Error struct {
+	// Invalid fields of the request, returned only for some of the incorrect input errors.
+	Details *[]FieldError `json:"details,omitempty"`
+	Message string        `json:"message"`
+	Slug    string        `json:"slug"`
+}
+
+// FieldError defines model for FieldError.
+type FieldError struct {
+	Field   string `json:"field"`
 	Message string `json:"message"`
 	Slug    string `json:"slug"`
 }
@@ -2,17 +2,22 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"log"
 	"net/http"
 	"os"
 	"testing"
 
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/client"
+	trainingsHTTP "github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/client/trainings"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/genproto/trainings"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/server"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/tests"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainings/ports"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
 )
 
 func TestCreateTraining(t *testing.T) {
@@ -55,6 +60,76 @@ func TestCancelTraining(t *testing.T) {
 	require.NotContains(t, trainingsUUIDs, trainingUUID)
 }
 
+func TestExportAndEraseUserData(t *testing.T) {
+	t.Parallel()
+
+	userUUID := uuid.New().String()
+	client := tests.NewTrainingsHTTPClient(t, tests.FakeAttendeeJWT(t, userUUID))
+
+	hour := tests.RelativeDate(10, 14)
+	trainingUUID := client.CreateTraining(t, "personal note", hour)
+
+	grpcClient := newTrainingsGrpcClient(t)
+	ctx := context.Background()
+
+	export := exportUserData(t, grpcClient, userUUID)
+	require.Len(t, export.Trainings, 1)
+	require.Equal(t, trainingUUID, export.Trainings[0].UUID)
+	require.Equal(t, "personal note", export.Trainings[0].Notes)
+
+	_, err := grpcClient.EraseUserData(ctx, &trainings.EraseUserDataRequest{UserId: userUUID})
+	require.NoError(t, err)
+
+	export = exportUserData(t, grpcClient, userUUID)
+	require.Empty(t, export.Trainings)
+
+	// the training is kept for statistics, but without personal data
+	trainerClient := tests.NewTrainingsHTTPClient(t, tests.FakeTrainerJWT(t, uuid.New().String()))
+
+	var erasedTraining *trainingsHTTP.Training
+	for _, tr := range trainerClient.GetTrainings(t).Trainings {
+		if tr.Uuid == trainingUUID {
+			tr := tr
+			erasedTraining = &tr
+		}
+	}
+	require.NotNil(t, erasedTraining)
+	require.Equal(t, "Anonymous", erasedTraining.User)
+	require.NotEqual(t, userUUID, erasedTraining.UserUuid)
+	require.Empty(t, erasedTraining.Notes)
+}
+
+type exportedUserData struct {
+	Trainings []struct {
+		UUID  string `json:"uuid"`
+		Notes string `json:"notes"`
+	} `json:"trainings"`
+}
+
+func exportUserData(t *testing.T, grpcClient trainings.TrainingsServiceClient, userUUID string) exportedUserData {
+	t.Helper()
+
+	resp, err := grpcClient.ExportUserData(context.Background(), &trainings.ExportUserDataRequest{UserId: userUUID})
+	require.NoError(t, err)
+
+	var export exportedUserData
+	require.NoError(t, json.Unmarshal(resp.Data, &export))
+
+	return export
+}
+
+func newTrainingsGrpcClient(t *testing.T) trainings.TrainingsServiceClient {
+	t.Helper()
+
+	grpcClient, closeClient, err := client.NewTrainingsClient()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = closeClient()
+	})
+
+	return grpcClient
+}
+
 func startService() bool {
 	app := NewComponentTestApplication(context.Background())
 
@@ -63,9 +138,21 @@ func startService() bool {
 		return ports.HandlerFromMux(ports.NewHttpServer(app), router)
 	})
 
+	trainingsGrpcAddr := os.Getenv("TRAININGS_GRPC_ADDR")
+	go server.RunGRPCServerOnAddr(trainingsGrpcAddr, func(server *grpc.Server) {
+		svc := ports.NewGrpcServer(app)
+		trainings.RegisterTrainingsServiceServer(server, svc)
+	})
+
 	ok := tests.WaitForPort(trainingsHTTPAddr)
 	if !ok {
 		log.Println("Timed out waiting for trainings HTTP to come up")
+		return false
+	}
+
+	ok = tests.WaitForPort(trainingsGrpcAddr)
+	if !ok {
+		log.Println("Timed out waiting for trainings gRPC to come up")
 	}
 
 	return ok
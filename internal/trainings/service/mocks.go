@@ -3,26 +3,32 @@ package service
 import (
 	"context"
 	"time"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainings/domain/training"
 )
 
 type TrainerServiceMock struct {
 }
 
-func (t TrainerServiceMock) ScheduleTraining(ctx context.Context, trainingTime time.Time) error {
+func (t TrainerServiceMock) ScheduleTraining(ctx context.Context, trainingTime time.Time, actor string) error {
 	return nil
 }
 
-func (t TrainerServiceMock) CancelTraining(ctx context.Context, trainingTime time.Time) error {
+func (t TrainerServiceMock) CancelTraining(ctx context.Context, trainingTime time.Time, actor string) error {
 	return nil
 }
 
-func (t TrainerServiceMock) MoveTraining(ctx context.Context, newTime time.Time, originalTrainingTime time.Time) error {
+func (t TrainerServiceMock) MoveTraining(ctx context.Context, newTime time.Time, originalTrainingTime time.Time, actor string) error {
 	return nil
 }
 
 type UserServiceMock struct {
 }
 
-func (u UserServiceMock) UpdateTrainingBalance(ctx context.Context, userID string, amountChange int) error {
+func (u UserServiceMock) UpdateTrainingBalance(ctx context.Context, change training.BalanceChange, actor string) error {
 	return nil
 }
+
+func (u UserServiceMock) DisplayNames(ctx context.Context, userUUIDs []string) (map[string]string, error) {
+	return map[string]string{}, nil
+}
@@ -7,6 +7,7 @@ import (
 	"cloud.google.com/go/firestore"
 	grpcClient "github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/client"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/metrics"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/server"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainings/adapters"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainings/app"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/trainings/app/command"
@@ -14,7 +15,9 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-func NewApplication(ctx context.Context) (app.Application, func()) {
+// NewApplication returns the application, checks of services required to serve requests
+// and the function closing connections to other services.
+func NewApplication(ctx context.Context) (app.Application, []server.HealthCheck, func()) {
 	trainerClient, closeTrainerClient, err := grpcClient.NewTrainerClient()
 	if err != nil {
 		panic(err)
@@ -24,21 +27,48 @@ func NewApplication(ctx context.Context) (app.Application, func()) {
 	if err != nil {
 		panic(err)
 	}
+
+	trainerHealthClient, closeTrainerHealthClient, err := grpcClient.NewTrainerHealthClient()
+	if err != nil {
+		panic(err)
+	}
+
+	usersHealthClient, closeUsersHealthClient, err := grpcClient.NewUsersHealthClient()
+	if err != nil {
+		panic(err)
+	}
+
 	trainerGrpc := adapters.NewTrainerGrpc(trainerClient)
 	usersGrpc := adapters.NewUsersGrpc(usersClient)
 
-	return newApplication(ctx, trainerGrpc, usersGrpc),
+	application, healthChecks := newApplication(ctx, trainerGrpc, usersGrpc, usersGrpc)
+	healthChecks = append(
+		healthChecks,
+		server.GRPCServiceHealthCheck("trainer", trainerHealthClient),
+		server.GRPCServiceHealthCheck("users", usersHealthClient),
+	)
+
+	return application,
+		healthChecks,
 		func() {
 			_ = closeTrainerClient()
 			_ = closeUsersClient()
+			_ = closeTrainerHealthClient()
+			_ = closeUsersHealthClient()
 		}
 }
 
 func NewComponentTestApplication(ctx context.Context) app.Application {
-	return newApplication(ctx, TrainerServiceMock{}, UserServiceMock{})
+	application, _ := newApplication(ctx, TrainerServiceMock{}, UserServiceMock{}, UserServiceMock{})
+	return application
 }
 
-func newApplication(ctx context.Context, trainerGrpc command.TrainerService, usersGrpc command.UserService) app.Application {
+func newApplication(
+	ctx context.Context,
+	trainerGrpc command.TrainerService,
+	usersGrpc command.UserService,
+	usersReadModel query.UsersService,
+) (app.Application, []server.HealthCheck) {
 	client, err := firestore.NewClient(ctx, os.Getenv("GCP_PROJECT"))
 	if err != nil {
 		panic(err)
@@ -47,9 +77,9 @@ func newApplication(ctx context.Context, trainerGrpc command.TrainerService, use
 	trainingsRepository := adapters.NewTrainingsFirestoreRepository(client)
 
 	logger := logrus.NewEntry(logrus.StandardLogger())
-	metricsClient := metrics.NoOp{}
+	metricsClient := metrics.Prometheus{}
 
-	return app.Application{
+	application := app.Application{
 		Commands: app.Commands{
 			ApproveTrainingReschedule: command.NewApproveTrainingRescheduleHandler(trainingsRepository, usersGrpc, trainerGrpc, logger, metricsClient),
 			CancelTraining:            command.NewCancelTrainingHandler(trainingsRepository, usersGrpc, trainerGrpc, logger, metricsClient),
@@ -57,10 +87,21 @@ func newApplication(ctx context.Context, trainerGrpc command.TrainerService, use
 			RescheduleTraining:        command.NewRescheduleTrainingHandler(trainingsRepository, usersGrpc, trainerGrpc, logger, metricsClient),
 			RequestTrainingReschedule: command.NewRequestTrainingRescheduleHandler(trainingsRepository, logger, metricsClient),
 			ScheduleTraining:          command.NewScheduleTrainingHandler(trainingsRepository, usersGrpc, trainerGrpc, logger, metricsClient),
+
+			EraseUserData: command.NewEraseUserDataHandler(
+				trainingsRepository,
+				[]byte(os.Getenv("ANONYMIZATION_KEY")),
+				logger,
+				metricsClient,
+			),
 		},
 		Queries: app.Queries{
-			AllTrainings:     query.NewAllTrainingsHandler(trainingsRepository, logger, metricsClient),
-			TrainingsForUser: query.NewTrainingsForUserHandler(trainingsRepository, logger, metricsClient),
+			AllTrainings:     query.NewAllTrainingsHandler(trainingsRepository, usersReadModel, logger, metricsClient),
+			TrainingsForUser: query.NewTrainingsForUserHandler(trainingsRepository, usersReadModel, logger, metricsClient),
+
+			UserTrainingsHistory: query.NewUserTrainingsHistoryHandler(trainingsRepository, logger, metricsClient),
 		},
 	}
+
+	return application, []server.HealthCheck{server.FirestoreHealthCheck(client)}
 }
@@ -0,0 +1,97 @@
+package adapters
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/app/command"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const fakePaymentSignatureHeader = "X-Fake-Payment-Signature"
+
+type fakePaymentWebhookPayload struct {
+	PurchaseUUID string `json:"purchase_uuid"`
+	Status       string `json:"status"`
+}
+
+// FakePaymentProvider is the payment provider for local development and tests.
+//
+// No payment is done, the webhook confirming the payment can be created with SignedWebhook
+// and sent to the payments webhook endpoint.
+type FakePaymentProvider struct {
+	webhookSecret []byte
+}
+
+func NewFakePaymentProvider(webhookSecret string) FakePaymentProvider {
+	if webhookSecret == "" {
+		panic("empty webhookSecret")
+	}
+
+	return FakePaymentProvider{webhookSecret: []byte(webhookSecret)}
+}
+
+func (p FakePaymentProvider) CreateCheckout(ctx context.Context, checkout command.Checkout) (string, error) {
+	logrus.WithFields(logrus.Fields{
+		"purchase_uuid": checkout.PurchaseUUID,
+		"price":         checkout.Price,
+		"currency":      checkout.Currency,
+	}).Info("Fake checkout created")
+
+	return fmt.Sprintf("https://fake-payment-provider.localhost/checkout/%s", checkout.PurchaseUUID), nil
+}
+
+func (p FakePaymentProvider) ParseWebhook(payload []byte, headers map[string][]string) (command.PaymentResult, error) {
+	signature, err := hex.DecodeString(http.Header(headers).Get(fakePaymentSignatureHeader))
+	if err != nil {
+		return command.PaymentResult{}, errors.Wrap(err, "invalid signature")
+	}
+
+	if !hmac.Equal(signature, p.sign(payload)) {
+		return command.PaymentResult{}, errors.New("signature doesn't match")
+	}
+
+	webhook := fakePaymentWebhookPayload{}
+	if err := json.Unmarshal(payload, &webhook); err != nil {
+		return command.PaymentResult{}, errors.Wrap(err, "unable to unmarshal webhook payload")
+	}
+
+	switch webhook.Status {
+	case "paid":
+		return command.PaymentResult{PurchaseUUID: webhook.PurchaseUUID, Paid: true}, nil
+	case "failed":
+		return command.PaymentResult{PurchaseUUID: webhook.PurchaseUUID, Paid: false}, nil
+	default:
+		return command.PaymentResult{}, errors.Errorf("unknown payment status '%s'", webhook.Status)
+	}
+}
+
+// SignedWebhook returns payload and headers of the webhook, which would be sent by the provider after the payment.
+func (p FakePaymentProvider) SignedWebhook(purchaseUUID string, paid bool) ([]byte, http.Header, error) {
+	webhook := fakePaymentWebhookPayload{PurchaseUUID: purchaseUUID, Status: "paid"}
+	if !paid {
+		webhook.Status = "failed"
+	}
+
+	payload, err := json.Marshal(webhook)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	headers := http.Header{}
+	headers.Set(fakePaymentSignatureHeader, hex.EncodeToString(p.sign(payload)))
+
+	return payload, headers, nil
+}
+
+func (p FakePaymentProvider) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, p.webhookSecret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
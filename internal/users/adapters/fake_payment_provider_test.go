@@ -0,0 +1,42 @@
+package adapters_test
+
+import (
+	"testing"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/adapters"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/app/command"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakePaymentProvider_ParseWebhook(t *testing.T) {
+	t.Parallel()
+	provider := adapters.NewFakePaymentProvider("secret")
+
+	purchaseUUID := uuid.New().String()
+
+	payload, headers, err := provider.SignedWebhook(purchaseUUID, true)
+	require.NoError(t, err)
+
+	result, err := provider.ParseWebhook(payload, headers)
+	require.NoError(t, err)
+	assert.Equal(t, command.PaymentResult{PurchaseUUID: purchaseUUID, Paid: true}, result)
+
+	payload, headers, err = provider.SignedWebhook(purchaseUUID, false)
+	require.NoError(t, err)
+
+	result, err = provider.ParseWebhook(payload, headers)
+	require.NoError(t, err)
+	assert.False(t, result.Paid)
+}
+
+func TestFakePaymentProvider_ParseWebhook_invalid_signature(t *testing.T) {
+	t.Parallel()
+
+	payload, headers, err := adapters.NewFakePaymentProvider("other-secret").SignedWebhook(uuid.New().String(), true)
+	require.NoError(t, err)
+
+	_, err = adapters.NewFakePaymentProvider("secret").ParseWebhook(payload, headers)
+	assert.Error(t, err)
+}
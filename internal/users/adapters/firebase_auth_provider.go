@@ -0,0 +1,56 @@
+package adapters
+
+import (
+	"context"
+
+	"firebase.google.com/go/v4/auth"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/domain/user"
+	"github.com/pkg/errors"
+)
+
+const roleClaim = "role"
+
+type FirebaseAuthProvider struct {
+	authClient *auth.Client
+}
+
+func NewFirebaseAuthProvider(authClient *auth.Client) FirebaseAuthProvider {
+	if authClient == nil {
+		panic("missing authClient")
+	}
+
+	return FirebaseAuthProvider{authClient: authClient}
+}
+
+func (f FirebaseAuthProvider) CanChangeRoles() bool {
+	return true
+}
+
+// SetRole updates the role claim. Firebase replaces all custom claims, so other claims are copied.
+func (f FirebaseAuthProvider) SetRole(ctx context.Context, userUUID string, role user.Role) error {
+	record, err := f.authClient.GetUser(ctx, userUUID)
+	if err != nil {
+		return errors.Wrap(err, "unable to get firebase user")
+	}
+
+	claims := map[string]interface{}{}
+	for k, v := range record.CustomClaims {
+		claims[k] = v
+	}
+	claims[roleClaim] = role.String()
+
+	if err := f.authClient.SetCustomUserClaims(ctx, userUUID, claims); err != nil {
+		return errors.Wrap(err, "unable to set firebase custom claims")
+	}
+
+	return nil
+}
+
+func (f FirebaseAuthProvider) DeleteUser(ctx context.Context, userUUID string) error {
+	err := f.authClient.DeleteUser(ctx, userUUID)
+	if auth.IsUserNotFound(err) {
+		return nil
+	}
+
+	return errors.Wrap(err, "unable to delete firebase user")
+}
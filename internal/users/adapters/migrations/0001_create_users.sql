@@ -0,0 +1,47 @@
+CREATE TABLE `users`
+(
+    uuid                    VARCHAR(36)  NOT NULL,
+    display_name            VARCHAR(255) NOT NULL DEFAULT '',
+    email                   VARCHAR(255) NOT NULL DEFAULT '',
+    role                    VARCHAR(16)  NOT NULL DEFAULT '',
+    -- balance is the sum of remaining credits of not expired batches, it's stored to enforce it on the database level
+    balance                 INT          NOT NULL DEFAULT 0,
+    -- next_credits_expiration is NULL, when no credits expire
+    next_credits_expiration DATETIME(6)  NULL,
+    PRIMARY KEY (uuid),
+    INDEX users_next_credits_expiration_idx (next_credits_expiration),
+    CONSTRAINT users_balance_not_negative CHECK (balance >= 0)
+);
+
+CREATE TABLE `user_credit_batches`
+(
+    user_uuid        VARCHAR(36)  NOT NULL,
+    id               VARCHAR(255) NOT NULL,
+    -- granted_at and expires_at are NULL for legacy credits, which never expire
+    granted_at       DATETIME(6)  NULL,
+    expires_at       DATETIME(6)  NULL,
+    granted          INT          NOT NULL,
+    remaining        INT          NOT NULL,
+    expired          BOOLEAN      NOT NULL,
+    -- booked_trainings is a JSON array of UUIDs of trainings booked with credits from the batch
+    booked_trainings TEXT         NOT NULL,
+    PRIMARY KEY (user_uuid, id),
+    CONSTRAINT user_credit_batches_remaining_not_negative CHECK (remaining >= 0),
+    CONSTRAINT user_credit_batches_user_fk FOREIGN KEY (user_uuid) REFERENCES users (uuid)
+);
+
+CREATE TABLE `user_ledger`
+(
+    user_uuid     VARCHAR(36)  NOT NULL,
+    operation_id  VARCHAR(255) NOT NULL,
+    reason        VARCHAR(32)  NOT NULL,
+    amount_change INT          NOT NULL,
+    training_uuid VARCHAR(36)  NOT NULL DEFAULT '',
+    actor         VARCHAR(255) NOT NULL,
+    note          TEXT         NOT NULL,
+    created_at    DATETIME(6)  NOT NULL,
+    -- the same operation can be applied only once
+    PRIMARY KEY (user_uuid, operation_id),
+    INDEX user_ledger_created_at_idx (user_uuid, created_at),
+    CONSTRAINT user_ledger_user_fk FOREIGN KEY (user_uuid) REFERENCES users (uuid)
+);
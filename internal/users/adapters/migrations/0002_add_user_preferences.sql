@@ -0,0 +1,7 @@
+-- preferences are empty for users created before they were introduced, defaults are used then
+ALTER TABLE `users`
+    ADD COLUMN custom_display_name        BOOLEAN     NOT NULL DEFAULT FALSE,
+    ADD COLUMN time_zone                  VARCHAR(64) NOT NULL DEFAULT '',
+    ADD COLUMN locale                     VARCHAR(35) NOT NULL DEFAULT '',
+    ADD COLUMN notification_channel       VARCHAR(16) NOT NULL DEFAULT '',
+    ADD COLUMN reminder_lead_time_minutes INT         NOT NULL DEFAULT 0;
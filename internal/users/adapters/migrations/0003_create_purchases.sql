@@ -0,0 +1,14 @@
+CREATE TABLE `purchases`
+(
+    uuid         VARCHAR(36) NOT NULL,
+    user_uuid    VARCHAR(36) NOT NULL,
+    package_id   VARCHAR(64) NOT NULL,
+    credits      INT         NOT NULL,
+    -- price is in the smallest unit of the currency
+    price        INT         NOT NULL,
+    currency     VARCHAR(3)  NOT NULL,
+    status       VARCHAR(16) NOT NULL,
+    checkout_url TEXT        NOT NULL,
+    PRIMARY KEY (uuid),
+    INDEX purchases_user_uuid_idx (user_uuid)
+);
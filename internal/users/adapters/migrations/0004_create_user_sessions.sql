@@ -0,0 +1,20 @@
+-- the row is locked when the history is updated, so concurrent updates of the user's sessions are serialised
+CREATE TABLE `user_session_histories`
+(
+    user_uuid VARCHAR(36) NOT NULL,
+    PRIMARY KEY (user_uuid)
+);
+
+CREATE TABLE `user_sessions`
+(
+    user_uuid    VARCHAR(36)  NOT NULL,
+    id           VARCHAR(255) NOT NULL,
+    ip           VARCHAR(45)  NOT NULL,
+    user_agent   TEXT         NOT NULL,
+    started_at   DATETIME(6)  NOT NULL,
+    last_seen_at DATETIME(6)  NOT NULL,
+    -- revoked_at is NULL for sessions which are not revoked
+    revoked_at   DATETIME(6)  NULL,
+    PRIMARY KEY (user_uuid, id),
+    CONSTRAINT user_sessions_history_fk FOREIGN KEY (user_uuid) REFERENCES user_session_histories (user_uuid)
+);
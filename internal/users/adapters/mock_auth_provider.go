@@ -0,0 +1,31 @@
+package adapters
+
+import (
+	"context"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/domain/user"
+	"github.com/sirupsen/logrus"
+)
+
+// MockAuthProvider is used in the local environment, where tokens are signed with the mock secret.
+// Users are not stored anywhere outside of the service, so changes are only logged.
+type MockAuthProvider struct{}
+
+func (MockAuthProvider) CanChangeRoles() bool {
+	return true
+}
+
+func (MockAuthProvider) SetRole(ctx context.Context, userUUID string, role user.Role) error {
+	logrus.WithFields(logrus.Fields{
+		"user_uuid": userUUID,
+		"role":      role.String(),
+	}).Info("Mock auth provider: role changed")
+
+	return nil
+}
+
+func (MockAuthProvider) DeleteUser(ctx context.Context, userUUID string) error {
+	logrus.WithField("user_uuid", userUUID).Info("Mock auth provider: user deleted")
+
+	return nil
+}
@@ -0,0 +1,25 @@
+package adapters
+
+import (
+	"context"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/domain/user"
+	"github.com/pkg/errors"
+)
+
+// OIDCAuthProvider is used with an external OIDC identity provider.
+// Accounts and their role claims are managed by the identity provider, which has no standard admin API,
+// so roles have to be changed and accounts deleted in the identity provider itself.
+type OIDCAuthProvider struct{}
+
+func (OIDCAuthProvider) CanChangeRoles() bool {
+	return false
+}
+
+func (OIDCAuthProvider) SetRole(ctx context.Context, userUUID string, role user.Role) error {
+	return errors.New("changing roles is not supported by the oidc auth provider, change the role claim in the identity provider")
+}
+
+func (OIDCAuthProvider) DeleteUser(ctx context.Context, userUUID string) error {
+	return errors.New("deleting accounts is not supported by the oidc auth provider, delete the account in the identity provider")
+}
@@ -0,0 +1,206 @@
+package adapters
+
+import (
+	"context"
+
+	"cloud.google.com/go/firestore"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/tracing"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/app/query"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/domain/purchase"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type PurchaseModel struct {
+	UUID     string `firestore:"Uuid"`
+	UserUUID string `firestore:"UserUuid"`
+
+	PackageID string `firestore:"PackageId"`
+	Credits   int    `firestore:"Credits"`
+	Price     int    `firestore:"Price"`
+	Currency  string `firestore:"Currency"`
+
+	Status      string `firestore:"Status"`
+	CheckoutURL string `firestore:"CheckoutUrl"`
+}
+
+type PurchasesFirestoreRepository struct {
+	firestoreClient *firestore.Client
+}
+
+func NewPurchasesFirestoreRepository(firestoreClient *firestore.Client) PurchasesFirestoreRepository {
+	if firestoreClient == nil {
+		panic("missing firestoreClient")
+	}
+
+	return PurchasesFirestoreRepository{firestoreClient: firestoreClient}
+}
+
+func (r PurchasesFirestoreRepository) purchasesCollection() *firestore.CollectionRef {
+	return r.firestoreClient.Collection("purchases")
+}
+
+func (r PurchasesFirestoreRepository) AddPurchase(ctx context.Context, p *purchase.Purchase) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "PurchasesFirestoreRepository.AddPurchase")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	_, err = r.purchasesCollection().Doc(p.UUID()).Create(ctx, r.marshalPurchase(p))
+	return errors.Wrap(err, "unable to add purchase")
+}
+
+func (r PurchasesFirestoreRepository) GetPurchase(ctx context.Context, purchaseUUID string) (p *purchase.Purchase, err error) {
+	ctx, span := tracing.StartSpan(ctx, "PurchasesFirestoreRepository.GetPurchase")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	doc, err := r.purchasesCollection().Doc(purchaseUUID).Get(ctx)
+
+	model, err := r.purchaseModelFromDocument(purchaseUUID, doc, err)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.unmarshalPurchase(model)
+}
+
+func (r PurchasesFirestoreRepository) UpdatePurchase(
+	ctx context.Context,
+	purchaseUUID string,
+	updateFn func(ctx context.Context, p *purchase.Purchase) (*purchase.Purchase, error),
+) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "PurchasesFirestoreRepository.UpdatePurchase")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	return r.firestoreClient.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		documentRef := r.purchasesCollection().Doc(purchaseUUID)
+
+		doc, err := tx.Get(documentRef)
+
+		model, err := r.purchaseModelFromDocument(purchaseUUID, doc, err)
+		if err != nil {
+			return err
+		}
+
+		p, err := r.unmarshalPurchase(model)
+		if err != nil {
+			return err
+		}
+
+		updatedPurchase, err := updateFn(ctx, p)
+		if err != nil {
+			return err
+		}
+
+		return tx.Set(documentRef, r.marshalPurchase(updatedPurchase))
+	})
+}
+
+func (r PurchasesFirestoreRepository) Purchase(ctx context.Context, purchaseUUID string) (p query.Purchase, err error) {
+	ctx, span := tracing.StartSpan(ctx, "PurchasesFirestoreRepository.Purchase")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	doc, err := r.purchasesCollection().Doc(purchaseUUID).Get(ctx)
+
+	model, err := r.purchaseModelFromDocument(purchaseUUID, doc, err)
+	if err != nil {
+		return query.Purchase{}, err
+	}
+
+	return purchaseModelToQuery(model), nil
+}
+
+func (r PurchasesFirestoreRepository) UserPurchases(ctx context.Context, userUUID string) (purchases []query.Purchase, err error) {
+	ctx, span := tracing.StartSpan(ctx, "PurchasesFirestoreRepository.UserPurchases")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	docs, err := r.purchasesCollection().Where("UserUuid", "==", userUUID).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get user's purchases")
+	}
+
+	purchases = []query.Purchase{}
+	for _, doc := range docs {
+		model, err := r.purchaseModelFromDocument(doc.Ref.ID, doc, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		purchases = append(purchases, purchaseModelToQuery(model))
+	}
+
+	return purchases, nil
+}
+
+func purchaseModelToQuery(model PurchaseModel) query.Purchase {
+	return query.Purchase{
+		UUID:        model.UUID,
+		UserUUID:    model.UserUUID,
+		PackageID:   model.PackageID,
+		Credits:     model.Credits,
+		Price:       model.Price,
+		Currency:    model.Currency,
+		Status:      model.Status,
+		CheckoutURL: model.CheckoutURL,
+	}
+}
+
+func (r PurchasesFirestoreRepository) purchaseModelFromDocument(
+	purchaseUUID string,
+	doc *firestore.DocumentSnapshot,
+	err error,
+) (PurchaseModel, error) {
+	if status.Code(err) == codes.NotFound {
+		return PurchaseModel{}, purchase.NotFoundError{PurchaseUUID: purchaseUUID}
+	}
+	if err != nil {
+		return PurchaseModel{}, errors.Wrap(err, "unable to get purchase")
+	}
+
+	model := PurchaseModel{}
+	if err := doc.DataTo(&model); err != nil {
+		return PurchaseModel{}, errors.Wrap(err, "unable to unmarshal PurchaseModel from Firestore")
+	}
+
+	return model, nil
+}
+
+func (r PurchasesFirestoreRepository) marshalPurchase(p *purchase.Purchase) PurchaseModel {
+	return PurchaseModel{
+		UUID:        p.UUID(),
+		UserUUID:    p.UserUUID(),
+		PackageID:   p.PackageID(),
+		Credits:     p.Credits(),
+		Price:       p.Price(),
+		Currency:    p.Currency(),
+		Status:      p.Status().String(),
+		CheckoutURL: p.CheckoutURL(),
+	}
+}
+
+func (r PurchasesFirestoreRepository) unmarshalPurchase(model PurchaseModel) (*purchase.Purchase, error) {
+	purchaseStatus, err := purchase.NewStatusFromString(model.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	return purchase.UnmarshalPurchaseFromDatabase(
+		model.UUID,
+		model.UserUUID,
+		model.PackageID,
+		model.Credits,
+		model.Price,
+		model.Currency,
+		purchaseStatus,
+		model.CheckoutURL,
+	)
+}
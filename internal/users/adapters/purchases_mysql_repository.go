@@ -0,0 +1,229 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/tracing"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/app/query"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/domain/purchase"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+type mysqlPurchase struct {
+	UUID     string `db:"uuid"`
+	UserUUID string `db:"user_uuid"`
+
+	PackageID string `db:"package_id"`
+	Credits   int    `db:"credits"`
+	Price     int    `db:"price"`
+	Currency  string `db:"currency"`
+
+	Status      string `db:"status"`
+	CheckoutURL string `db:"checkout_url"`
+}
+
+type PurchasesMySQLRepository struct {
+	db *sqlx.DB
+}
+
+func NewPurchasesMySQLRepository(db *sqlx.DB) PurchasesMySQLRepository {
+	if db == nil {
+		panic("missing db")
+	}
+
+	return PurchasesMySQLRepository{db: db}
+}
+
+func (r PurchasesMySQLRepository) AddPurchase(ctx context.Context, p *purchase.Purchase) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "PurchasesMySQLRepository.AddPurchase")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	_, err = r.db.NamedExecContext(
+		ctx,
+		"INSERT INTO `purchases` "+
+			"(uuid, user_uuid, package_id, credits, price, currency, status, checkout_url) VALUES "+
+			"(:uuid, :user_uuid, :package_id, :credits, :price, :currency, :status, :checkout_url)",
+		marshalMySQLPurchase(p),
+	)
+
+	return errors.Wrap(err, "unable to add purchase")
+}
+
+func (r PurchasesMySQLRepository) GetPurchase(ctx context.Context, purchaseUUID string) (p *purchase.Purchase, err error) {
+	ctx, span := tracing.StartSpan(ctx, "PurchasesMySQLRepository.GetPurchase")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	model, err := r.getPurchase(ctx, r.db, purchaseUUID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return unmarshalMySQLPurchase(model)
+}
+
+func (r PurchasesMySQLRepository) UpdatePurchase(
+	ctx context.Context,
+	purchaseUUID string,
+	updateFn func(ctx context.Context, p *purchase.Purchase) (*purchase.Purchase, error),
+) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "PurchasesMySQLRepository.UpdatePurchase")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	for {
+		err := r.updatePurchase(ctx, purchaseUUID, updateFn)
+
+		if isMySQLDeadlock(err) {
+			continue
+		}
+
+		return err
+	}
+}
+
+func (r PurchasesMySQLRepository) updatePurchase(
+	ctx context.Context,
+	purchaseUUID string,
+	updateFn func(ctx context.Context, p *purchase.Purchase) (*purchase.Purchase, error),
+) (err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "unable to start transaction")
+	}
+	defer func() {
+		err = finishMySQLTransaction(err, tx)
+	}()
+
+	model, err := r.getPurchase(ctx, tx, purchaseUUID, true)
+	if err != nil {
+		return err
+	}
+
+	p, err := unmarshalMySQLPurchase(model)
+	if err != nil {
+		return err
+	}
+
+	updatedPurchase, err := updateFn(ctx, p)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.NamedExecContext(
+		ctx,
+		"UPDATE `purchases` SET "+
+			"user_uuid = :user_uuid, package_id = :package_id, credits = :credits, price = :price, "+
+			"currency = :currency, status = :status, checkout_url = :checkout_url "+
+			"WHERE uuid = :uuid",
+		marshalMySQLPurchase(updatedPurchase),
+	)
+
+	return errors.Wrap(err, "unable to update purchase")
+}
+
+func (r PurchasesMySQLRepository) Purchase(ctx context.Context, purchaseUUID string) (p query.Purchase, err error) {
+	ctx, span := tracing.StartSpan(ctx, "PurchasesMySQLRepository.Purchase")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	model, err := r.getPurchase(ctx, r.db, purchaseUUID, false)
+	if err != nil {
+		return query.Purchase{}, err
+	}
+
+	return mysqlPurchaseToQuery(model), nil
+}
+
+func (r PurchasesMySQLRepository) UserPurchases(ctx context.Context, userUUID string) (purchases []query.Purchase, err error) {
+	ctx, span := tracing.StartSpan(ctx, "PurchasesMySQLRepository.UserPurchases")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	var models []mysqlPurchase
+	if err := r.db.SelectContext(ctx, &models, "SELECT * FROM `purchases` WHERE user_uuid = ?", userUUID); err != nil {
+		return nil, errors.Wrap(err, "unable to get user's purchases")
+	}
+
+	purchases = []query.Purchase{}
+	for _, model := range models {
+		purchases = append(purchases, mysqlPurchaseToQuery(model))
+	}
+
+	return purchases, nil
+}
+
+func (r PurchasesMySQLRepository) getPurchase(
+	ctx context.Context,
+	db sqlQueryer,
+	purchaseUUID string,
+	forUpdate bool,
+) (mysqlPurchase, error) {
+	purchaseQuery := "SELECT * FROM `purchases` WHERE uuid = ?"
+	if forUpdate {
+		purchaseQuery += " FOR UPDATE"
+	}
+
+	model := mysqlPurchase{}
+	err := db.GetContext(ctx, &model, purchaseQuery, purchaseUUID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return mysqlPurchase{}, purchase.NotFoundError{PurchaseUUID: purchaseUUID}
+	}
+	if err != nil {
+		return mysqlPurchase{}, errors.Wrap(err, "unable to get purchase")
+	}
+
+	return model, nil
+}
+
+func mysqlPurchaseToQuery(model mysqlPurchase) query.Purchase {
+	return query.Purchase{
+		UUID:        model.UUID,
+		UserUUID:    model.UserUUID,
+		PackageID:   model.PackageID,
+		Credits:     model.Credits,
+		Price:       model.Price,
+		Currency:    model.Currency,
+		Status:      model.Status,
+		CheckoutURL: model.CheckoutURL,
+	}
+}
+
+func marshalMySQLPurchase(p *purchase.Purchase) mysqlPurchase {
+	return mysqlPurchase{
+		UUID:        p.UUID(),
+		UserUUID:    p.UserUUID(),
+		PackageID:   p.PackageID(),
+		Credits:     p.Credits(),
+		Price:       p.Price(),
+		Currency:    p.Currency(),
+		Status:      p.Status().String(),
+		CheckoutURL: p.CheckoutURL(),
+	}
+}
+
+func unmarshalMySQLPurchase(model mysqlPurchase) (*purchase.Purchase, error) {
+	purchaseStatus, err := purchase.NewStatusFromString(model.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	return purchase.UnmarshalPurchaseFromDatabase(
+		model.UUID,
+		model.UserUUID,
+		model.PackageID,
+		model.Credits,
+		model.Price,
+		model.Currency,
+		purchaseStatus,
+		model.CheckoutURL,
+	)
+}
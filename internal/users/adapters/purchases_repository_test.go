@@ -0,0 +1,172 @@
+package adapters_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"cloud.google.com/go/firestore"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/mysqldb"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/adapters"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/app/query"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/domain/purchase"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPurchasesRepository(t *testing.T) {
+	t.Parallel()
+
+	repositories := createPurchasesRepositories(t)
+
+	for i := range repositories {
+		r := repositories[i]
+
+		t.Run(r.Name, func(t *testing.T) {
+			t.Parallel()
+
+			t.Run("testAddPurchase", func(t *testing.T) {
+				t.Parallel()
+				testAddPurchase(t, r.Repository)
+			})
+			t.Run("testUpdatePurchase", func(t *testing.T) {
+				t.Parallel()
+				testUpdatePurchase(t, r.Repository)
+			})
+			t.Run("testUserPurchases", func(t *testing.T) {
+				t.Parallel()
+				testUserPurchases(t, r.Repository)
+			})
+			t.Run("testPurchase_not_exists", func(t *testing.T) {
+				t.Parallel()
+				testPurchase_not_exists(t, r.Repository)
+			})
+		})
+	}
+}
+
+type purchasesRepository interface {
+	purchase.Repository
+	query.PurchaseReadModel
+	query.UserPurchasesReadModel
+}
+
+type purchasesRepositoryUnderTest struct {
+	Name       string
+	Repository purchasesRepository
+}
+
+func createPurchasesRepositories(t *testing.T) []purchasesRepositoryUnderTest {
+	return []purchasesRepositoryUnderTest{
+		{
+			Name:       "Firebase",
+			Repository: newPurchasesFirebaseRepository(t),
+		},
+		{
+			Name:       "MySQL",
+			Repository: newPurchasesMySQLRepository(t),
+		},
+	}
+}
+
+func testAddPurchase(t *testing.T, repo purchasesRepository) {
+	ctx := context.Background()
+
+	p := newExamplePurchase(t)
+
+	err := repo.AddPurchase(ctx, p)
+	require.NoError(t, err)
+
+	fromRepo, err := repo.GetPurchase(ctx, p.UUID())
+	require.NoError(t, err)
+	assert.Equal(t, p, fromRepo)
+
+	readModel, err := repo.Purchase(ctx, p.UUID())
+	require.NoError(t, err)
+	assert.Equal(t, p.UserUUID(), readModel.UserUUID)
+	assert.Equal(t, "pending", readModel.Status)
+	assert.Equal(t, p.CheckoutURL(), readModel.CheckoutURL)
+}
+
+func testUpdatePurchase(t *testing.T, repo purchasesRepository) {
+	ctx := context.Background()
+
+	p := newExamplePurchase(t)
+	require.NoError(t, repo.AddPurchase(ctx, p))
+
+	err := repo.UpdatePurchase(ctx, p.UUID(), func(ctx context.Context, p *purchase.Purchase) (*purchase.Purchase, error) {
+		if err := p.MarkAsPaid(); err != nil {
+			return nil, err
+		}
+		return p, nil
+	})
+	require.NoError(t, err)
+
+	fromRepo, err := repo.GetPurchase(ctx, p.UUID())
+	require.NoError(t, err)
+	assert.True(t, fromRepo.IsPaid())
+}
+
+func testUserPurchases(t *testing.T, repo purchasesRepository) {
+	ctx := context.Background()
+
+	p := newExamplePurchase(t)
+	require.NoError(t, repo.AddPurchase(ctx, p))
+	require.NoError(t, repo.AddPurchase(ctx, newExamplePurchase(t)))
+
+	purchases, err := repo.UserPurchases(ctx, p.UserUUID())
+	require.NoError(t, err)
+	require.Len(t, purchases, 1)
+	assert.Equal(t, p.UUID(), purchases[0].UUID)
+
+	purchases, err = repo.UserPurchases(ctx, uuid.New().String())
+	require.NoError(t, err)
+	assert.Empty(t, purchases)
+}
+
+func testPurchase_not_exists(t *testing.T, repo purchasesRepository) {
+	ctx := context.Background()
+
+	purchaseUUID := uuid.New().String()
+
+	_, err := repo.GetPurchase(ctx, purchaseUUID)
+	assert.Equal(t, purchase.NotFoundError{PurchaseUUID: purchaseUUID}, err)
+
+	_, err = repo.Purchase(ctx, purchaseUUID)
+	assert.Equal(t, purchase.NotFoundError{PurchaseUUID: purchaseUUID}, err)
+
+	err = repo.UpdatePurchase(ctx, purchaseUUID, func(ctx context.Context, p *purchase.Purchase) (*purchase.Purchase, error) {
+		return p, nil
+	})
+	assert.Equal(t, purchase.NotFoundError{PurchaseUUID: purchaseUUID}, err)
+}
+
+func newExamplePurchase(t *testing.T) *purchase.Purchase {
+	t.Helper()
+
+	creditPackage := purchase.MustNewCreditPackage("ten", "10 trainings", 10, 12000, "EUR")
+
+	p, err := purchase.NewPurchase(uuid.New().String(), uuid.New().String(), creditPackage, "https://example.com/checkout")
+	require.NoError(t, err)
+
+	return p
+}
+
+func newPurchasesFirebaseRepository(t *testing.T) adapters.PurchasesFirestoreRepository {
+	t.Helper()
+	firestoreClient, err := firestore.NewClient(context.Background(), os.Getenv("GCP_PROJECT"))
+	require.NoError(t, err)
+
+	return adapters.NewPurchasesFirestoreRepository(firestoreClient)
+}
+
+func newPurchasesMySQLRepository(t *testing.T) adapters.PurchasesMySQLRepository {
+	t.Helper()
+	db, err := mysqldb.NewConnection()
+	require.NoError(t, err)
+
+	require.NoError(t, adapters.MigrateMySQL(context.Background(), db))
+
+	return adapters.NewPurchasesMySQLRepository(db)
+}
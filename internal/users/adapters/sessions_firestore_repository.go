@@ -0,0 +1,168 @@
+package adapters
+
+import (
+	"context"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/tracing"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/app/query"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/domain/session"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SessionHistoryModel is stored in the "user-sessions" collection, with user UUID as the document ID.
+type SessionHistoryModel struct {
+	Sessions []SessionModel `firestore:"Sessions"`
+}
+
+type SessionModel struct {
+	ID         string    `firestore:"ID"`
+	IP         string    `firestore:"IP"`
+	UserAgent  string    `firestore:"UserAgent"`
+	StartedAt  time.Time `firestore:"StartedAt"`
+	LastSeenAt time.Time `firestore:"LastSeenAt"`
+	RevokedAt  time.Time `firestore:"RevokedAt"`
+}
+
+type SessionsFirestoreRepository struct {
+	firestoreClient *firestore.Client
+}
+
+func NewSessionsFirestoreRepository(firestoreClient *firestore.Client) SessionsFirestoreRepository {
+	if firestoreClient == nil {
+		panic("missing firestoreClient")
+	}
+
+	return SessionsFirestoreRepository{firestoreClient: firestoreClient}
+}
+
+func (r SessionsFirestoreRepository) sessionsCollection() *firestore.CollectionRef {
+	return r.firestoreClient.Collection("user-sessions")
+}
+
+func (r SessionsFirestoreRepository) GetHistory(ctx context.Context, userUUID string) (h *session.History, err error) {
+	ctx, span := tracing.StartSpan(ctx, "SessionsFirestoreRepository.GetHistory")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	doc, err := r.sessionsCollection().Doc(userUUID).Get(ctx)
+
+	model, err := r.historyModelFromDocument(doc, err)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.unmarshalHistory(userUUID, model)
+}
+
+func (r SessionsFirestoreRepository) UpdateHistory(
+	ctx context.Context,
+	userUUID string,
+	updateFn func(ctx context.Context, h *session.History) (*session.History, error),
+) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "SessionsFirestoreRepository.UpdateHistory")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	return r.firestoreClient.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		documentRef := r.sessionsCollection().Doc(userUUID)
+
+		model, err := r.historyModelFromDocument(tx.Get(documentRef))
+		if err != nil {
+			return err
+		}
+
+		h, err := r.unmarshalHistory(userUUID, model)
+		if err != nil {
+			return err
+		}
+
+		updatedHistory, err := updateFn(ctx, h)
+		if err != nil {
+			return err
+		}
+
+		return tx.Set(documentRef, r.marshalHistory(updatedHistory))
+	})
+}
+
+func (r SessionsFirestoreRepository) Sessions(ctx context.Context, userUUID string) (sessions []query.Session, err error) {
+	ctx, span := tracing.StartSpan(ctx, "SessionsFirestoreRepository.Sessions")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	h, err := r.GetHistory(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	return historyToQuery(h), nil
+}
+
+func historyToQuery(h *session.History) []query.Session {
+	sessions := []query.Session{}
+	for _, s := range h.Sessions() {
+		sessions = append(sessions, query.Session{
+			ID:         s.ID(),
+			IP:         s.IP(),
+			UserAgent:  s.UserAgent(),
+			StartedAt:  s.StartedAt(),
+			LastSeenAt: s.LastSeenAt(),
+			Revoked:    s.IsRevoked(),
+		})
+	}
+
+	return sessions
+}
+
+func (r SessionsFirestoreRepository) historyModelFromDocument(doc *firestore.DocumentSnapshot, err error) (SessionHistoryModel, error) {
+	if status.Code(err) == codes.NotFound {
+		return SessionHistoryModel{}, nil
+	}
+	if err != nil {
+		return SessionHistoryModel{}, errors.Wrap(err, "unable to get sessions")
+	}
+
+	model := SessionHistoryModel{}
+	if err := doc.DataTo(&model); err != nil {
+		return SessionHistoryModel{}, errors.Wrap(err, "unable to unmarshal SessionHistoryModel from Firestore")
+	}
+
+	return model, nil
+}
+
+func (r SessionsFirestoreRepository) marshalHistory(h *session.History) SessionHistoryModel {
+	model := SessionHistoryModel{Sessions: []SessionModel{}}
+	for _, s := range h.Sessions() {
+		model.Sessions = append(model.Sessions, SessionModel{
+			ID:         s.ID(),
+			IP:         s.IP(),
+			UserAgent:  s.UserAgent(),
+			StartedAt:  s.StartedAt(),
+			LastSeenAt: s.LastSeenAt(),
+			RevokedAt:  s.RevokedAt(),
+		})
+	}
+
+	return model
+}
+
+func (r SessionsFirestoreRepository) unmarshalHistory(userUUID string, model SessionHistoryModel) (*session.History, error) {
+	sessions := make([]session.Session, 0, len(model.Sessions))
+	for _, m := range model.Sessions {
+		s, err := session.UnmarshalSessionFromDatabase(m.ID, m.IP, m.UserAgent, m.StartedAt, m.LastSeenAt, m.RevokedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		sessions = append(sessions, s)
+	}
+
+	return session.UnmarshalHistoryFromDatabase(userUUID, sessions)
+}
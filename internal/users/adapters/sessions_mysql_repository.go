@@ -0,0 +1,174 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/tracing"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/app/query"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/domain/session"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+type mysqlSession struct {
+	UserUUID   string       `db:"user_uuid"`
+	ID         string       `db:"id"`
+	IP         string       `db:"ip"`
+	UserAgent  string       `db:"user_agent"`
+	StartedAt  time.Time    `db:"started_at"`
+	LastSeenAt time.Time    `db:"last_seen_at"`
+	RevokedAt  sql.NullTime `db:"revoked_at"`
+}
+
+type SessionsMySQLRepository struct {
+	db *sqlx.DB
+}
+
+func NewSessionsMySQLRepository(db *sqlx.DB) SessionsMySQLRepository {
+	if db == nil {
+		panic("missing db")
+	}
+
+	return SessionsMySQLRepository{db: db}
+}
+
+func (r SessionsMySQLRepository) GetHistory(ctx context.Context, userUUID string) (h *session.History, err error) {
+	ctx, span := tracing.StartSpan(ctx, "SessionsMySQLRepository.GetHistory")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	return r.getHistory(ctx, r.db, userUUID)
+}
+
+func (r SessionsMySQLRepository) UpdateHistory(
+	ctx context.Context,
+	userUUID string,
+	updateFn func(ctx context.Context, h *session.History) (*session.History, error),
+) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "SessionsMySQLRepository.UpdateHistory")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	for {
+		err := r.updateHistory(ctx, userUUID, updateFn)
+
+		if isMySQLDeadlock(err) {
+			continue
+		}
+
+		return err
+	}
+}
+
+func (r SessionsMySQLRepository) updateHistory(
+	ctx context.Context,
+	userUUID string,
+	updateFn func(ctx context.Context, h *session.History) (*session.History, error),
+) (err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "unable to start transaction")
+	}
+	defer func() {
+		err = finishMySQLTransaction(err, tx)
+	}()
+
+	// The row is created before locking, so the first sessions of the user are serialised as well.
+	if _, err := tx.ExecContext(ctx, "INSERT IGNORE INTO `user_session_histories` (user_uuid) VALUES (?)", userUUID); err != nil {
+		return errors.Wrap(err, "unable to create sessions history")
+	}
+
+	var lockedUserUUID string
+	if err := tx.GetContext(
+		ctx,
+		&lockedUserUUID,
+		"SELECT user_uuid FROM `user_session_histories` WHERE user_uuid = ? FOR UPDATE",
+		userUUID,
+	); err != nil {
+		return errors.Wrap(err, "unable to lock sessions history")
+	}
+
+	h, err := r.getHistory(ctx, tx, userUUID)
+	if err != nil {
+		return err
+	}
+
+	updatedHistory, err := updateFn(ctx, h)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM `user_sessions` WHERE user_uuid = ?", userUUID); err != nil {
+		return errors.Wrap(err, "unable to remove sessions")
+	}
+
+	for _, s := range updatedHistory.Sessions() {
+		if _, err := tx.NamedExecContext(
+			ctx,
+			"INSERT INTO `user_sessions` "+
+				"(user_uuid, id, ip, user_agent, started_at, last_seen_at, revoked_at) VALUES "+
+				"(:user_uuid, :id, :ip, :user_agent, :started_at, :last_seen_at, :revoked_at)",
+			marshalMySQLSession(userUUID, s),
+		); err != nil {
+			return errors.Wrap(err, "unable to add session")
+		}
+	}
+
+	return nil
+}
+
+func (r SessionsMySQLRepository) Sessions(ctx context.Context, userUUID string) (sessions []query.Session, err error) {
+	ctx, span := tracing.StartSpan(ctx, "SessionsMySQLRepository.Sessions")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	h, err := r.getHistory(ctx, r.db, userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	return historyToQuery(h), nil
+}
+
+func (r SessionsMySQLRepository) getHistory(ctx context.Context, db sqlQueryer, userUUID string) (*session.History, error) {
+	var models []mysqlSession
+	if err := db.SelectContext(ctx, &models, "SELECT * FROM `user_sessions` WHERE user_uuid = ?", userUUID); err != nil {
+		return nil, errors.Wrap(err, "unable to get sessions")
+	}
+
+	sessions := make([]session.Session, 0, len(models))
+	for _, m := range models {
+		s, err := session.UnmarshalSessionFromDatabase(
+			m.ID,
+			m.IP,
+			m.UserAgent,
+			m.StartedAt,
+			m.LastSeenAt,
+			nullTimeToTime(m.RevokedAt),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		sessions = append(sessions, s)
+	}
+
+	return session.UnmarshalHistoryFromDatabase(userUUID, sessions)
+}
+
+func marshalMySQLSession(userUUID string, s session.Session) mysqlSession {
+	return mysqlSession{
+		UserUUID:   userUUID,
+		ID:         s.ID(),
+		IP:         s.IP(),
+		UserAgent:  s.UserAgent(),
+		StartedAt:  s.StartedAt().UTC(),
+		LastSeenAt: s.LastSeenAt().UTC(),
+		RevokedAt:  timeToNullTime(s.RevokedAt()),
+	}
+}
@@ -0,0 +1,124 @@
+package adapters_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/mysqldb"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/adapters"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/app/query"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/domain/session"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionsRepository(t *testing.T) {
+	t.Parallel()
+
+	repositories := createSessionsRepositories(t)
+
+	for i := range repositories {
+		r := repositories[i]
+
+		t.Run(r.Name, func(t *testing.T) {
+			t.Parallel()
+
+			t.Run("testUpdateHistory", func(t *testing.T) {
+				t.Parallel()
+				testUpdateHistory(t, r.Repository)
+			})
+			t.Run("testGetHistory_not_exists", func(t *testing.T) {
+				t.Parallel()
+				testGetHistory_not_exists(t, r.Repository)
+			})
+		})
+	}
+}
+
+type sessionsRepository interface {
+	session.Repository
+	query.UserSessionsReadModel
+}
+
+type sessionsRepositoryUnderTest struct {
+	Name       string
+	Repository sessionsRepository
+}
+
+func createSessionsRepositories(t *testing.T) []sessionsRepositoryUnderTest {
+	return []sessionsRepositoryUnderTest{
+		{
+			Name:       "Firebase",
+			Repository: newSessionsFirebaseRepository(t),
+		},
+		{
+			Name:       "MySQL",
+			Repository: newSessionsMySQLRepository(t),
+		},
+	}
+}
+
+func testUpdateHistory(t *testing.T, repo sessionsRepository) {
+	ctx := context.Background()
+
+	userUUID := uuid.New().String()
+	// Firestore and MySQL keep timestamps with microsecond precision
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	err := repo.UpdateHistory(ctx, userUUID, func(ctx context.Context, h *session.History) (*session.History, error) {
+		if err := h.RecordActivity("first", "127.0.0.1", "Firefox", now.Add(-time.Hour)); err != nil {
+			return nil, err
+		}
+		if err := h.RecordActivity("second", "127.0.0.2", "Chrome", now); err != nil {
+			return nil, err
+		}
+		if err := h.Revoke("first", now); err != nil {
+			return nil, err
+		}
+
+		return h, nil
+	})
+	require.NoError(t, err)
+
+	h, err := repo.GetHistory(ctx, userUUID)
+	require.NoError(t, err)
+	require.Len(t, h.Sessions(), 2)
+	assert.Equal(t, "second", h.Sessions()[0].ID())
+	assert.Equal(t, "Chrome", h.Sessions()[0].UserAgent())
+	assert.False(t, h.Sessions()[0].IsRevoked())
+	assert.Equal(t, now, h.Sessions()[1].RevokedAt().UTC())
+
+	sessions, err := repo.Sessions(ctx, userUUID)
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	assert.Equal(t, "127.0.0.2", sessions[0].IP)
+	assert.True(t, sessions[1].Revoked)
+}
+
+func testGetHistory_not_exists(t *testing.T, repo sessionsRepository) {
+	h, err := repo.GetHistory(context.Background(), uuid.New().String())
+	require.NoError(t, err)
+	assert.Empty(t, h.Sessions())
+}
+
+func newSessionsFirebaseRepository(t *testing.T) adapters.SessionsFirestoreRepository {
+	t.Helper()
+	firestoreClient, err := firestore.NewClient(context.Background(), os.Getenv("GCP_PROJECT"))
+	require.NoError(t, err)
+
+	return adapters.NewSessionsFirestoreRepository(firestoreClient)
+}
+
+func newSessionsMySQLRepository(t *testing.T) adapters.SessionsMySQLRepository {
+	t.Helper()
+	db, err := mysqldb.NewConnection()
+	require.NoError(t, err)
+
+	require.NoError(t, adapters.MigrateMySQL(context.Background(), db))
+
+	return adapters.NewSessionsMySQLRepository(db)
+}
@@ -0,0 +1,30 @@
+package adapters
+
+import (
+	"context"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/genproto/trainer"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/grpcerr"
+)
+
+type TrainerGrpc struct {
+	client trainer.TrainerServiceClient
+}
+
+func NewTrainerGrpc(client trainer.TrainerServiceClient) TrainerGrpc {
+	return TrainerGrpc{client: client}
+}
+
+func (s TrainerGrpc) ExportUserData(ctx context.Context, userUUID string) ([]byte, error) {
+	resp, err := s.client.ExportUserData(ctx, &trainer.ExportUserDataRequest{UserId: userUUID})
+	if err != nil {
+		return nil, grpcerr.SlugError(err)
+	}
+
+	return resp.Data, nil
+}
+
+func (s TrainerGrpc) EraseUserData(ctx context.Context, userUUID string) error {
+	_, err := s.client.EraseUserData(ctx, &trainer.EraseUserDataRequest{UserId: userUUID})
+	return grpcerr.SlugError(err)
+}
@@ -0,0 +1,30 @@
+package adapters
+
+import (
+	"context"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/genproto/trainings"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/grpcerr"
+)
+
+type TrainingsGrpc struct {
+	client trainings.TrainingsServiceClient
+}
+
+func NewTrainingsGrpc(client trainings.TrainingsServiceClient) TrainingsGrpc {
+	return TrainingsGrpc{client: client}
+}
+
+func (s TrainingsGrpc) ExportUserData(ctx context.Context, userUUID string) ([]byte, error) {
+	resp, err := s.client.ExportUserData(ctx, &trainings.ExportUserDataRequest{UserId: userUUID})
+	if err != nil {
+		return nil, grpcerr.SlugError(err)
+	}
+
+	return resp.Data, nil
+}
+
+func (s TrainingsGrpc) EraseUserData(ctx context.Context, userUUID string) error {
+	_, err := s.client.EraseUserData(ctx, &trainings.EraseUserDataRequest{UserId: userUUID})
+	return grpcerr.SlugError(err)
+}
@@ -0,0 +1,513 @@
+package adapters
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/tracing"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/app/query"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/domain/user"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type UserModel struct {
+	// Balance is the sum of remaining credits of not expired batches.
+	// For users created before credit batches were introduced, it's the only information about credits.
+	Balance     int    `firestore:"Balance"`
+	DisplayName string `firestore:"DisplayName"`
+	Email       string `firestore:"Email"`
+	Role        string `firestore:"Role"`
+
+	CustomDisplayName bool             `firestore:"CustomDisplayName"`
+	Preferences       PreferencesModel `firestore:"Preferences"`
+
+	CreditBatches []CreditBatchModel `firestore:"CreditBatches"`
+	// NextCreditsExpiration is used to find users with credits to expire, it's nil when no credits expire.
+	NextCreditsExpiration *time.Time `firestore:"NextCreditsExpiration"`
+}
+
+// PreferencesModel is empty for users, who were persisted before preferences were introduced.
+type PreferencesModel struct {
+	TimeZone                string `firestore:"TimeZone"`
+	Locale                  string `firestore:"Locale"`
+	NotificationChannel     string `firestore:"NotificationChannel"`
+	ReminderLeadTimeMinutes int    `firestore:"ReminderLeadTimeMinutes"`
+}
+
+type CreditBatchModel struct {
+	ID              string    `firestore:"ID"`
+	GrantedAt       time.Time `firestore:"GrantedAt"`
+	ExpiresAt       time.Time `firestore:"ExpiresAt"`
+	Granted         int       `firestore:"Granted"`
+	Remaining       int       `firestore:"Remaining"`
+	Expired         bool      `firestore:"Expired"`
+	BookedTrainings []string  `firestore:"BookedTrainings"`
+}
+
+// LedgerEntryModel is stored in the "ledger" sub-collection of the user, with operation ID as the document ID.
+type LedgerEntryModel struct {
+	OperationID  string    `firestore:"OperationID"`
+	Reason       string    `firestore:"Reason"`
+	AmountChange int       `firestore:"AmountChange"`
+	TrainingUUID string    `firestore:"TrainingUUID"`
+	Actor        string    `firestore:"Actor"`
+	Note         string    `firestore:"Note"`
+	CreatedAt    time.Time `firestore:"CreatedAt"`
+}
+
+type UsersFirestoreRepository struct {
+	firestoreClient *firestore.Client
+}
+
+func NewUsersFirestoreRepository(firestoreClient *firestore.Client) UsersFirestoreRepository {
+	if firestoreClient == nil {
+		panic("missing firestoreClient")
+	}
+
+	return UsersFirestoreRepository{firestoreClient: firestoreClient}
+}
+
+func (r UsersFirestoreRepository) usersCollection() *firestore.CollectionRef {
+	return r.firestoreClient.Collection("users")
+}
+
+func (r UsersFirestoreRepository) ledgerCollection(userUUID string) *firestore.CollectionRef {
+	return r.usersCollection().Doc(userUUID).Collection("ledger")
+}
+
+func (r UsersFirestoreRepository) GetUser(ctx context.Context, userUUID string) (u *user.User, err error) {
+	ctx, span := tracing.StartSpan(ctx, "UsersFirestoreRepository.GetUser")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	doc, err := r.usersCollection().Doc(userUUID).Get(ctx)
+
+	model, err := r.userModelFromDocument(doc, err)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.unmarshalUser(userUUID, model)
+}
+
+func (r UsersFirestoreRepository) UpdateUser(
+	ctx context.Context,
+	userUUID string,
+	updateFn func(ctx context.Context, u *user.User) (*user.User, error),
+) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "UsersFirestoreRepository.UpdateUser")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	return r.updateUser(ctx, userUUID, "", updateFn)
+}
+
+func (r UsersFirestoreRepository) ApplyOperation(
+	ctx context.Context,
+	userUUID string,
+	operationID string,
+	updateFn func(ctx context.Context, u *user.User) (*user.User, error),
+) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "UsersFirestoreRepository.ApplyOperation")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	if operationID == "" {
+		return errors.New("empty operation id")
+	}
+
+	return r.updateUser(ctx, userUUID, operationID, updateFn)
+}
+
+// updateUser checks operationID before calling updateFn, when it's not empty.
+func (r UsersFirestoreRepository) updateUser(
+	ctx context.Context,
+	userUUID string,
+	operationID string,
+	updateFn func(ctx context.Context, u *user.User) (*user.User, error),
+) error {
+	return r.firestoreClient.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		documentRef := r.usersCollection().Doc(userUUID)
+
+		model, err := r.userModelFromDocument(tx.Get(documentRef))
+		if err != nil {
+			return err
+		}
+
+		if operationID != "" {
+			if err := r.checkOperationNotApplied(tx, userUUID, operationID); err != nil {
+				return err
+			}
+		}
+
+		u, err := r.unmarshalUser(userUUID, model)
+		if err != nil {
+			return err
+		}
+
+		updatedUser, err := updateFn(ctx, u)
+		if err != nil {
+			return err
+		}
+
+		newEntries := updatedUser.NewLedgerEntries()
+
+		// all reads in the transaction must be done before writes
+		for _, entry := range newEntries {
+			if err := r.checkOperationNotApplied(tx, userUUID, entry.OperationID()); err != nil {
+				return err
+			}
+		}
+
+		model.Balance = updatedUser.Balance()
+		model.DisplayName = updatedUser.DisplayName()
+		model.Email = updatedUser.Email()
+		model.Role = updatedUser.Role().String()
+		model.CustomDisplayName = updatedUser.CustomDisplayName()
+		model.Preferences = marshalPreferences(updatedUser.Preferences())
+		model.CreditBatches = marshalCreditBatches(updatedUser.CreditBatches())
+		model.NextCreditsExpiration = nil
+		if nextExpiration, ok := updatedUser.NextCreditsExpiration(); ok {
+			model.NextCreditsExpiration = &nextExpiration
+		}
+
+		if err := tx.Set(documentRef, model); err != nil {
+			return err
+		}
+
+		for _, entry := range newEntries {
+			err := tx.Create(r.ledgerCollection(userUUID).Doc(entry.OperationID()), marshalLedgerEntry(entry))
+			if err != nil {
+				return errors.Wrap(err, "unable to add ledger entry")
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r UsersFirestoreRepository) checkOperationNotApplied(tx *firestore.Transaction, userUUID string, operationID string) error {
+	_, err := tx.Get(r.ledgerCollection(userUUID).Doc(operationID))
+	if err == nil {
+		return user.OperationAlreadyAppliedError{OperationID: operationID}
+	}
+	if status.Code(err) != codes.NotFound {
+		return errors.Wrap(err, "unable to get ledger entry")
+	}
+
+	return nil
+}
+
+func (r UsersFirestoreRepository) UsersWithCreditsExpiringBefore(ctx context.Context, t time.Time) (userUUIDs []string, err error) {
+	ctx, span := tracing.StartSpan(ctx, "UsersFirestoreRepository.UsersWithCreditsExpiringBefore")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	docs, err := r.usersCollection().
+		Where("NextCreditsExpiration", "<=", t).
+		Select().
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get users with expiring credits")
+	}
+
+	for _, doc := range docs {
+		userUUIDs = append(userUUIDs, doc.Ref.ID)
+	}
+
+	return userUUIDs, nil
+}
+
+func (r UsersFirestoreRepository) User(ctx context.Context, userUUID string) (queryUser query.User, err error) {
+	ctx, span := tracing.StartSpan(ctx, "UsersFirestoreRepository.User")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	doc, err := r.usersCollection().Doc(userUUID).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return query.User{}, user.NotFoundError{UserUUID: userUUID}
+	}
+
+	model, err := r.userModelFromDocument(doc, err)
+	if err != nil {
+		return query.User{}, err
+	}
+
+	return r.userModelToQuery(userUUID, model)
+}
+
+// Users returns users ordered by the display name.
+//
+// Firestore doesn't support full-text search, so users are filtered in memory.
+// It's fine for the number of users of a single gym.
+func (r UsersFirestoreRepository) Users(ctx context.Context, search string, limit int) (users []query.User, err error) {
+	ctx, span := tracing.StartSpan(ctx, "UsersFirestoreRepository.Users")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	docs, err := r.usersCollection().OrderBy("DisplayName", firestore.Asc).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get users")
+	}
+
+	search = strings.ToLower(search)
+
+	users = []query.User{}
+	for _, doc := range docs {
+		if len(users) >= limit {
+			break
+		}
+
+		model, err := r.userModelFromDocument(doc, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if search != "" &&
+			!strings.Contains(strings.ToLower(model.DisplayName), search) &&
+			!strings.Contains(strings.ToLower(model.Email), search) {
+			continue
+		}
+
+		u, err := r.userModelToQuery(doc.Ref.ID, model)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+
+	return users, nil
+}
+
+func (r UsersFirestoreRepository) UsersByUUIDs(ctx context.Context, userUUIDs []string) (users []query.User, err error) {
+	ctx, span := tracing.StartSpan(ctx, "UsersFirestoreRepository.UsersByUUIDs")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	refs := make([]*firestore.DocumentRef, 0, len(userUUIDs))
+	for _, userUUID := range userUUIDs {
+		refs = append(refs, r.usersCollection().Doc(userUUID))
+	}
+
+	docs, err := r.firestoreClient.GetAll(ctx, refs)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get users")
+	}
+
+	users = []query.User{}
+	for _, doc := range docs {
+		if !doc.Exists() {
+			continue
+		}
+
+		model, err := r.userModelFromDocument(doc, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		u, err := r.userModelToQuery(doc.Ref.ID, model)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+
+	return users, nil
+}
+
+func (r UsersFirestoreRepository) userModelToQuery(userUUID string, model UserModel) (query.User, error) {
+	u, err := r.unmarshalUser(userUUID, model)
+	if err != nil {
+		return query.User{}, err
+	}
+
+	return userToQuery(u, time.Now()), nil
+}
+
+func (r UsersFirestoreRepository) TrainingBalance(ctx context.Context, userUUID string) (balance int, err error) {
+	ctx, span := tracing.StartSpan(ctx, "UsersFirestoreRepository.TrainingBalance")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	u, err := r.GetUser(ctx, userUUID)
+	if err != nil {
+		return 0, err
+	}
+
+	// credits may be already expired, even if expiration was not processed yet
+	return u.BalanceAt(time.Now()), nil
+}
+
+func (r UsersFirestoreRepository) ExpiringCredits(ctx context.Context, userUUID string) (credits []query.ExpiringCredits, err error) {
+	ctx, span := tracing.StartSpan(ctx, "UsersFirestoreRepository.ExpiringCredits")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	u, err := r.GetUser(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	return expiringCredits(u, time.Now()), nil
+}
+
+func (r UsersFirestoreRepository) Ledger(ctx context.Context, userUUID string) (entries []query.LedgerEntry, err error) {
+	ctx, span := tracing.StartSpan(ctx, "UsersFirestoreRepository.Ledger")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	docs, err := r.ledgerCollection(userUUID).
+		OrderBy("CreatedAt", firestore.Desc).
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get ledger")
+	}
+
+	for _, doc := range docs {
+		model := LedgerEntryModel{}
+		if err := doc.DataTo(&model); err != nil {
+			return nil, errors.Wrap(err, "unable to unmarshal LedgerEntryModel from Firestore")
+		}
+
+		entries = append(entries, query.LedgerEntry{
+			OperationID:  model.OperationID,
+			Reason:       model.Reason,
+			AmountChange: model.AmountChange,
+			TrainingUUID: model.TrainingUUID,
+			Actor:        model.Actor,
+			Note:         model.Note,
+			CreatedAt:    model.CreatedAt.UTC(),
+		})
+	}
+
+	return entries, nil
+}
+
+// userModelFromDocument returns empty model for not existing user,
+// in reality every user exists, even if it's not persisted yet.
+func (r UsersFirestoreRepository) userModelFromDocument(doc *firestore.DocumentSnapshot, err error) (UserModel, error) {
+	if status.Code(err) == codes.NotFound {
+		return UserModel{}, nil
+	}
+	if err != nil {
+		return UserModel{}, errors.Wrap(err, "unable to get user")
+	}
+
+	model := UserModel{}
+	if err := doc.DataTo(&model); err != nil {
+		return UserModel{}, errors.Wrap(err, "unable to unmarshal UserModel from Firestore")
+	}
+
+	return model, nil
+}
+
+func (r UsersFirestoreRepository) unmarshalUser(userUUID string, model UserModel) (*user.User, error) {
+	var batches []user.CreditBatch
+
+	if len(model.CreditBatches) == 0 && model.Balance > 0 {
+		legacyBatch, err := user.NewLegacyCreditBatch(model.Balance)
+		if err != nil {
+			return nil, err
+		}
+		batches = append(batches, legacyBatch)
+	}
+
+	for _, b := range model.CreditBatches {
+		batch, err := user.UnmarshalCreditBatchFromDatabase(
+			b.ID,
+			b.GrantedAt.UTC(),
+			b.ExpiresAt.UTC(),
+			b.Granted,
+			b.Remaining,
+			b.Expired,
+			b.BookedTrainings,
+		)
+		if err != nil {
+			return nil, err
+		}
+		batches = append(batches, batch)
+	}
+
+	var role user.Role
+	if model.Role != "" {
+		var err error
+		role, err = user.NewRoleFromString(model.Role)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	preferences, err := user.UnmarshalPreferencesFromDatabase(
+		model.Preferences.TimeZone,
+		model.Preferences.Locale,
+		model.Preferences.NotificationChannel,
+		time.Duration(model.Preferences.ReminderLeadTimeMinutes)*time.Minute,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return user.UnmarshalUserFromDatabase(
+		userUUID,
+		model.DisplayName,
+		model.CustomDisplayName,
+		model.Email,
+		role,
+		preferences,
+		batches,
+	)
+}
+
+func marshalPreferences(p user.Preferences) PreferencesModel {
+	return PreferencesModel{
+		TimeZone:                p.TimeZone(),
+		Locale:                  p.Locale(),
+		NotificationChannel:     p.NotificationChannel().String(),
+		ReminderLeadTimeMinutes: int(p.ReminderLeadTime() / time.Minute),
+	}
+}
+
+func marshalCreditBatches(batches []user.CreditBatch) []CreditBatchModel {
+	var models []CreditBatchModel
+	for _, b := range batches {
+		models = append(models, CreditBatchModel{
+			ID:              b.ID(),
+			GrantedAt:       b.GrantedAt(),
+			ExpiresAt:       b.ExpiresAt(),
+			Granted:         b.Granted(),
+			Remaining:       b.Remaining(),
+			Expired:         b.Expired(),
+			BookedTrainings: b.BookedTrainings(),
+		})
+	}
+
+	return models
+}
+
+func marshalLedgerEntry(entry user.LedgerEntry) LedgerEntryModel {
+	return LedgerEntryModel{
+		OperationID:  entry.OperationID(),
+		Reason:       entry.Reason().String(),
+		AmountChange: entry.AmountChange(),
+		TrainingUUID: entry.TrainingUUID(),
+		Actor:        entry.Actor(),
+		Note:         entry.Note(),
+		CreatedAt:    entry.CreatedAt(),
+	}
+}
@@ -0,0 +1,646 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/mysqldb"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/tracing"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/app/query"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/domain/user"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+)
+
+// Migrations are named `<version>_<description>.sql`.
+// Already applied migrations should never be modified - add a new one instead.
+//
+//go:embed migrations/*.sql
+var mysqlMigrations embed.FS
+
+const mysqlMigrationsTable = "users_schema_migrations"
+
+// MigrateMySQL applies all not yet applied migrations of the users service.
+func MigrateMySQL(ctx context.Context, db *sqlx.DB) error {
+	return mysqldb.Migrate(ctx, db, mysqlMigrations, mysqlMigrationsTable)
+}
+
+type mysqlUser struct {
+	UUID                  string       `db:"uuid"`
+	DisplayName           string       `db:"display_name"`
+	Email                 string       `db:"email"`
+	Role                  string       `db:"role"`
+	Balance               int          `db:"balance"`
+	NextCreditsExpiration sql.NullTime `db:"next_credits_expiration"`
+
+	CustomDisplayName       bool   `db:"custom_display_name"`
+	TimeZone                string `db:"time_zone"`
+	Locale                  string `db:"locale"`
+	NotificationChannel     string `db:"notification_channel"`
+	ReminderLeadTimeMinutes int    `db:"reminder_lead_time_minutes"`
+}
+
+type mysqlCreditBatch struct {
+	UserUUID        string       `db:"user_uuid"`
+	ID              string       `db:"id"`
+	GrantedAt       sql.NullTime `db:"granted_at"`
+	ExpiresAt       sql.NullTime `db:"expires_at"`
+	Granted         int          `db:"granted"`
+	Remaining       int          `db:"remaining"`
+	Expired         bool         `db:"expired"`
+	BookedTrainings string       `db:"booked_trainings"`
+}
+
+type mysqlLedgerEntry struct {
+	UserUUID     string    `db:"user_uuid"`
+	OperationID  string    `db:"operation_id"`
+	Reason       string    `db:"reason"`
+	AmountChange int       `db:"amount_change"`
+	TrainingUUID string    `db:"training_uuid"`
+	Actor        string    `db:"actor"`
+	Note         string    `db:"note"`
+	CreatedAt    time.Time `db:"created_at"`
+}
+
+type UsersMySQLRepository struct {
+	db *sqlx.DB
+}
+
+func NewUsersMySQLRepository(db *sqlx.DB) UsersMySQLRepository {
+	if db == nil {
+		panic("missing db")
+	}
+
+	return UsersMySQLRepository{db: db}
+}
+
+// sqlQueryer is an interface provided both by transaction and standard db connection
+type sqlQueryer interface {
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+}
+
+func (r UsersMySQLRepository) GetUser(ctx context.Context, userUUID string) (u *user.User, err error) {
+	ctx, span := tracing.StartSpan(ctx, "UsersMySQLRepository.GetUser")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	u, err = r.getUser(ctx, r.db, userUUID, false)
+	if _, ok := err.(user.NotFoundError); ok {
+		// in reality every user exists, even if it's not persisted yet
+		return user.NewUser(userUUID)
+	}
+
+	return u, err
+}
+
+const mySQLDeadlockErrorCode = 1213
+
+// isMySQLDeadlock returns true, when the transaction was rolled back because of the deadlock and it can be retried.
+func isMySQLDeadlock(err error) bool {
+	val, ok := errors.Cause(err).(*mysql.MySQLError)
+	return ok && val.Number == mySQLDeadlockErrorCode
+}
+
+func (r UsersMySQLRepository) UpdateUser(
+	ctx context.Context,
+	userUUID string,
+	updateFn func(ctx context.Context, u *user.User) (*user.User, error),
+) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "UsersMySQLRepository.UpdateUser")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	return r.updateUserWithRetries(ctx, userUUID, "", updateFn)
+}
+
+func (r UsersMySQLRepository) ApplyOperation(
+	ctx context.Context,
+	userUUID string,
+	operationID string,
+	updateFn func(ctx context.Context, u *user.User) (*user.User, error),
+) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "UsersMySQLRepository.ApplyOperation")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	if operationID == "" {
+		return errors.New("empty operation id")
+	}
+
+	return r.updateUserWithRetries(ctx, userUUID, operationID, updateFn)
+}
+
+func (r UsersMySQLRepository) updateUserWithRetries(
+	ctx context.Context,
+	userUUID string,
+	operationID string,
+	updateFn func(ctx context.Context, u *user.User) (*user.User, error),
+) error {
+	for {
+		err := r.updateUser(ctx, userUUID, operationID, updateFn)
+
+		if isMySQLDeadlock(err) {
+			continue
+		}
+
+		return err
+	}
+}
+
+// updateUser checks operationID before calling updateFn, when it's not empty.
+func (r UsersMySQLRepository) updateUser(
+	ctx context.Context,
+	userUUID string,
+	operationID string,
+	updateFn func(ctx context.Context, u *user.User) (*user.User, error),
+) (err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "unable to start transaction")
+	}
+	defer func() {
+		err = finishMySQLTransaction(err, tx)
+	}()
+
+	// The row is created before locking, so concurrent updates of a not persisted user are serialised as well.
+	if _, err := tx.ExecContext(ctx, "INSERT IGNORE INTO `users` (uuid) VALUES (?)", userUUID); err != nil {
+		return errors.Wrap(err, "unable to create user")
+	}
+
+	u, err := r.getUser(ctx, tx, userUUID, true)
+	if err != nil {
+		return err
+	}
+
+	if operationID != "" {
+		if err := r.checkOperationsNotApplied(ctx, tx, userUUID, []string{operationID}); err != nil {
+			return err
+		}
+	}
+
+	updatedUser, err := updateFn(ctx, u)
+	if err != nil {
+		return err
+	}
+
+	newEntries := updatedUser.NewLedgerEntries()
+
+	newOperationIDs := make([]string, 0, len(newEntries))
+	for _, entry := range newEntries {
+		newOperationIDs = append(newOperationIDs, entry.OperationID())
+	}
+	if err := r.checkOperationsNotApplied(ctx, tx, userUUID, newOperationIDs); err != nil {
+		return err
+	}
+
+	if err := r.updateUserRow(ctx, tx, updatedUser); err != nil {
+		return err
+	}
+
+	if err := r.replaceCreditBatches(ctx, tx, updatedUser); err != nil {
+		return err
+	}
+
+	for _, entry := range newEntries {
+		if _, err := tx.NamedExecContext(
+			ctx,
+			"INSERT INTO `user_ledger` "+
+				"(user_uuid, operation_id, reason, amount_change, training_uuid, actor, note, created_at) VALUES "+
+				"(:user_uuid, :operation_id, :reason, :amount_change, :training_uuid, :actor, :note, :created_at)",
+			marshalMySQLLedgerEntry(userUUID, entry),
+		); err != nil {
+			return errors.Wrap(err, "unable to add ledger entry")
+		}
+	}
+
+	return nil
+}
+
+func (r UsersMySQLRepository) checkOperationsNotApplied(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	userUUID string,
+	operationIDs []string,
+) error {
+	if len(operationIDs) == 0 {
+		return nil
+	}
+
+	query, args, err := sqlx.In(
+		"SELECT operation_id FROM `user_ledger` WHERE user_uuid = ? AND operation_id IN (?)",
+		userUUID,
+		operationIDs,
+	)
+	if err != nil {
+		return err
+	}
+
+	var applied []string
+	if err := tx.SelectContext(ctx, &applied, query, args...); err != nil {
+		return errors.Wrap(err, "unable to get ledger entries")
+	}
+	if len(applied) > 0 {
+		return user.OperationAlreadyAppliedError{OperationID: applied[0]}
+	}
+
+	return nil
+}
+
+func (r UsersMySQLRepository) updateUserRow(ctx context.Context, tx *sqlx.Tx, u *user.User) error {
+	model := mysqlUser{
+		UUID:        u.UUID(),
+		DisplayName: u.DisplayName(),
+		Email:       u.Email(),
+		Role:        u.Role().String(),
+		Balance:     u.Balance(),
+
+		CustomDisplayName:       u.CustomDisplayName(),
+		TimeZone:                u.Preferences().TimeZone(),
+		Locale:                  u.Preferences().Locale(),
+		NotificationChannel:     u.Preferences().NotificationChannel().String(),
+		ReminderLeadTimeMinutes: int(u.Preferences().ReminderLeadTime() / time.Minute),
+	}
+	if nextExpiration, ok := u.NextCreditsExpiration(); ok {
+		model.NextCreditsExpiration = sql.NullTime{Time: nextExpiration.UTC(), Valid: true}
+	}
+
+	// the balance is validated by the domain, the check constraint is the last line of defence
+	_, err := tx.NamedExecContext(
+		ctx,
+		"UPDATE `users` SET "+
+			"display_name = :display_name, email = :email, role = :role, "+
+			"balance = :balance, next_credits_expiration = :next_credits_expiration, "+
+			"custom_display_name = :custom_display_name, time_zone = :time_zone, locale = :locale, "+
+			"notification_channel = :notification_channel, reminder_lead_time_minutes = :reminder_lead_time_minutes "+
+			"WHERE uuid = :uuid",
+		model,
+	)
+
+	return errors.Wrap(err, "unable to update user")
+}
+
+func (r UsersMySQLRepository) replaceCreditBatches(ctx context.Context, tx *sqlx.Tx, u *user.User) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM `user_credit_batches` WHERE user_uuid = ?", u.UUID()); err != nil {
+		return errors.Wrap(err, "unable to remove credit batches")
+	}
+
+	for _, b := range u.CreditBatches() {
+		model, err := marshalMySQLCreditBatch(u.UUID(), b)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.NamedExecContext(
+			ctx,
+			"INSERT INTO `user_credit_batches` "+
+				"(user_uuid, id, granted_at, expires_at, granted, remaining, expired, booked_trainings) VALUES "+
+				"(:user_uuid, :id, :granted_at, :expires_at, :granted, :remaining, :expired, :booked_trainings)",
+			model,
+		); err != nil {
+			return errors.Wrap(err, "unable to add credit batch")
+		}
+	}
+
+	return nil
+}
+
+func (r UsersMySQLRepository) UsersWithCreditsExpiringBefore(ctx context.Context, t time.Time) (userUUIDs []string, err error) {
+	ctx, span := tracing.StartSpan(ctx, "UsersMySQLRepository.UsersWithCreditsExpiringBefore")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	err = r.db.SelectContext(
+		ctx,
+		&userUUIDs,
+		"SELECT uuid FROM `users` WHERE next_credits_expiration <= ?",
+		t.UTC(),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get users with expiring credits")
+	}
+
+	return userUUIDs, nil
+}
+
+func (r UsersMySQLRepository) User(ctx context.Context, userUUID string) (queryUser query.User, err error) {
+	ctx, span := tracing.StartSpan(ctx, "UsersMySQLRepository.User")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	u, err := r.getUser(ctx, r.db, userUUID, false)
+	if err != nil {
+		return query.User{}, err
+	}
+
+	return userToQuery(u, time.Now()), nil
+}
+
+func (r UsersMySQLRepository) Users(ctx context.Context, search string, limit int) (users []query.User, err error) {
+	ctx, span := tracing.StartSpan(ctx, "UsersMySQLRepository.Users")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	sqlQuery := "SELECT * FROM `users`"
+	var args []interface{}
+
+	if search != "" {
+		pattern := "%" + escapeLikePattern(strings.ToLower(search)) + "%"
+		sqlQuery += " WHERE LOWER(display_name) LIKE ? OR LOWER(email) LIKE ?"
+		args = append(args, pattern, pattern)
+	}
+
+	sqlQuery += " ORDER BY display_name LIMIT ?"
+	args = append(args, limit)
+
+	return r.findUsers(ctx, sqlQuery, args...)
+}
+
+func (r UsersMySQLRepository) UsersByUUIDs(ctx context.Context, userUUIDs []string) (users []query.User, err error) {
+	ctx, span := tracing.StartSpan(ctx, "UsersMySQLRepository.UsersByUUIDs")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	sqlQuery, args, err := sqlx.In("SELECT * FROM `users` WHERE uuid IN (?)", userUUIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.findUsers(ctx, sqlQuery, args...)
+}
+
+func (r UsersMySQLRepository) TrainingBalance(ctx context.Context, userUUID string) (balance int, err error) {
+	ctx, span := tracing.StartSpan(ctx, "UsersMySQLRepository.TrainingBalance")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	u, err := r.GetUser(ctx, userUUID)
+	if err != nil {
+		return 0, err
+	}
+
+	// credits may be already expired, even if expiration was not processed yet
+	return u.BalanceAt(time.Now()), nil
+}
+
+func (r UsersMySQLRepository) ExpiringCredits(ctx context.Context, userUUID string) (credits []query.ExpiringCredits, err error) {
+	ctx, span := tracing.StartSpan(ctx, "UsersMySQLRepository.ExpiringCredits")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	u, err := r.GetUser(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	return expiringCredits(u, time.Now()), nil
+}
+
+func (r UsersMySQLRepository) Ledger(ctx context.Context, userUUID string) (entries []query.LedgerEntry, err error) {
+	ctx, span := tracing.StartSpan(ctx, "UsersMySQLRepository.Ledger")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	var models []mysqlLedgerEntry
+
+	err = r.db.SelectContext(
+		ctx,
+		&models,
+		"SELECT * FROM `user_ledger` WHERE user_uuid = ? ORDER BY created_at DESC",
+		userUUID,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get ledger")
+	}
+
+	for _, model := range models {
+		entries = append(entries, query.LedgerEntry{
+			OperationID:  model.OperationID,
+			Reason:       model.Reason,
+			AmountChange: model.AmountChange,
+			TrainingUUID: model.TrainingUUID,
+			Actor:        model.Actor,
+			Note:         model.Note,
+			CreatedAt:    model.CreatedAt.UTC(),
+		})
+	}
+
+	return entries, nil
+}
+
+// getUser returns user.NotFoundError, when the user is not persisted.
+func (r UsersMySQLRepository) getUser(
+	ctx context.Context,
+	db sqlQueryer,
+	userUUID string,
+	forUpdate bool,
+) (*user.User, error) {
+	userQuery := "SELECT * FROM `users` WHERE uuid = ?"
+	if forUpdate {
+		userQuery += " FOR UPDATE"
+	}
+
+	model := mysqlUser{}
+	err := db.GetContext(ctx, &model, userQuery, userUUID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, user.NotFoundError{UserUUID: userUUID}
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get user")
+	}
+
+	var batches []mysqlCreditBatch
+	err = db.SelectContext(ctx, &batches, "SELECT * FROM `user_credit_batches` WHERE user_uuid = ?", userUUID)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get credit batches")
+	}
+
+	return r.unmarshalUser(model, batches)
+}
+
+func (r UsersMySQLRepository) findUsers(ctx context.Context, sqlQuery string, args ...interface{}) ([]query.User, error) {
+	var models []mysqlUser
+	if err := r.db.SelectContext(ctx, &models, sqlQuery, args...); err != nil {
+		return nil, errors.Wrap(err, "unable to get users")
+	}
+
+	if len(models) == 0 {
+		return []query.User{}, nil
+	}
+
+	userUUIDs := make([]string, 0, len(models))
+	for _, model := range models {
+		userUUIDs = append(userUUIDs, model.UUID)
+	}
+
+	batchesQuery, batchesArgs, err := sqlx.In("SELECT * FROM `user_credit_batches` WHERE user_uuid IN (?)", userUUIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var batches []mysqlCreditBatch
+	if err := r.db.SelectContext(ctx, &batches, batchesQuery, batchesArgs...); err != nil {
+		return nil, errors.Wrap(err, "unable to get credit batches")
+	}
+
+	batchesByUser := map[string][]mysqlCreditBatch{}
+	for _, b := range batches {
+		batchesByUser[b.UserUUID] = append(batchesByUser[b.UserUUID], b)
+	}
+
+	now := time.Now()
+	users := make([]query.User, 0, len(models))
+	for _, model := range models {
+		u, err := r.unmarshalUser(model, batchesByUser[model.UUID])
+		if err != nil {
+			return nil, err
+		}
+
+		users = append(users, userToQuery(u, now))
+	}
+
+	return users, nil
+}
+
+func (r UsersMySQLRepository) unmarshalUser(model mysqlUser, batchModels []mysqlCreditBatch) (*user.User, error) {
+	batches := make([]user.CreditBatch, 0, len(batchModels))
+	for _, b := range batchModels {
+		var bookedTrainings []string
+		if err := json.Unmarshal([]byte(b.BookedTrainings), &bookedTrainings); err != nil {
+			return nil, errors.Wrap(err, "unable to unmarshal booked trainings")
+		}
+
+		batch, err := user.UnmarshalCreditBatchFromDatabase(
+			b.ID,
+			nullTimeToTime(b.GrantedAt),
+			nullTimeToTime(b.ExpiresAt),
+			b.Granted,
+			b.Remaining,
+			b.Expired,
+			bookedTrainings,
+		)
+		if err != nil {
+			return nil, err
+		}
+		batches = append(batches, batch)
+	}
+
+	var role user.Role
+	if model.Role != "" {
+		var err error
+		role, err = user.NewRoleFromString(model.Role)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	preferences, err := user.UnmarshalPreferencesFromDatabase(
+		model.TimeZone,
+		model.Locale,
+		model.NotificationChannel,
+		time.Duration(model.ReminderLeadTimeMinutes)*time.Minute,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return user.UnmarshalUserFromDatabase(
+		model.UUID,
+		model.DisplayName,
+		model.CustomDisplayName,
+		model.Email,
+		role,
+		preferences,
+		batches,
+	)
+}
+
+func marshalMySQLCreditBatch(userUUID string, b user.CreditBatch) (mysqlCreditBatch, error) {
+	bookedTrainings := b.BookedTrainings()
+	if bookedTrainings == nil {
+		bookedTrainings = []string{}
+	}
+
+	bookedTrainingsJSON, err := json.Marshal(bookedTrainings)
+	if err != nil {
+		return mysqlCreditBatch{}, errors.Wrap(err, "unable to marshal booked trainings")
+	}
+
+	return mysqlCreditBatch{
+		UserUUID:        userUUID,
+		ID:              b.ID(),
+		GrantedAt:       timeToNullTime(b.GrantedAt()),
+		ExpiresAt:       timeToNullTime(b.ExpiresAt()),
+		Granted:         b.Granted(),
+		Remaining:       b.Remaining(),
+		Expired:         b.Expired(),
+		BookedTrainings: string(bookedTrainingsJSON),
+	}, nil
+}
+
+func marshalMySQLLedgerEntry(userUUID string, entry user.LedgerEntry) mysqlLedgerEntry {
+	return mysqlLedgerEntry{
+		UserUUID:     userUUID,
+		OperationID:  entry.OperationID(),
+		Reason:       entry.Reason().String(),
+		AmountChange: entry.AmountChange(),
+		TrainingUUID: entry.TrainingUUID(),
+		Actor:        entry.Actor(),
+		Note:         entry.Note(),
+		CreatedAt:    entry.CreatedAt().UTC(),
+	}
+}
+
+func timeToNullTime(t time.Time) sql.NullTime {
+	if t.IsZero() {
+		return sql.NullTime{}
+	}
+
+	return sql.NullTime{Time: t.UTC(), Valid: true}
+}
+
+func nullTimeToTime(t sql.NullTime) time.Time {
+	if !t.Valid {
+		return time.Time{}
+	}
+
+	return t.Time.UTC()
+}
+
+func escapeLikePattern(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// finishMySQLTransaction rollbacks transaction if error is provided.
+// If err is nil transaction is committed.
+func finishMySQLTransaction(err error, tx *sqlx.Tx) error {
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return multierr.Combine(err, rollbackErr)
+		}
+
+		return err
+	}
+
+	if commitErr := tx.Commit(); commitErr != nil {
+		return errors.Wrap(commitErr, "failed to commit tx")
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package adapters
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/app/query"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/domain/user"
+)
+
+// Read models of users are built from the domain model, so all storages calculate balances the same way.
+
+func userToQuery(u *user.User, now time.Time) query.User {
+	return query.User{
+		UUID:        u.UUID(),
+		DisplayName: u.DisplayName(),
+		Email:       u.Email(),
+		Role:        u.Role().String(),
+		Balance:     u.BalanceAt(now),
+		Preferences: query.UserPreferences{
+			TimeZone:            u.Preferences().TimeZone(),
+			Locale:              u.Preferences().Locale(),
+			NotificationChannel: u.Preferences().NotificationChannel().String(),
+			ReminderLeadTime:    u.Preferences().ReminderLeadTime(),
+		},
+	}
+}
+
+func expiringCredits(u *user.User, now time.Time) []query.ExpiringCredits {
+	var expiring []query.ExpiringCredits
+	for _, b := range u.CreditBatches() {
+		if b.Expired() || b.Remaining() == 0 || b.ExpiresAt().IsZero() || !b.ExpiresAt().After(now) {
+			continue
+		}
+
+		expiring = append(expiring, query.ExpiringCredits{
+			Amount:    b.Remaining(),
+			ExpiresAt: b.ExpiresAt().UTC(),
+		})
+	}
+
+	sort.Slice(expiring, func(i, j int) bool {
+		return expiring[i].ExpiresAt.Before(expiring[j].ExpiresAt)
+	})
+
+	return expiring
+}
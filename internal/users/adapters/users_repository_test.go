@@ -0,0 +1,495 @@
+package adapters_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/mysqldb"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/adapters"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/app/query"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/domain/user"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsersRepository(t *testing.T) {
+	t.Parallel()
+
+	repositories := createUsersRepositories(t)
+
+	for i := range repositories {
+		r := repositories[i]
+
+		t.Run(r.Name, func(t *testing.T) {
+			t.Parallel()
+
+			t.Run("testGetUser_not_exists", func(t *testing.T) {
+				t.Parallel()
+				testGetUser_not_exists(t, r.Repository)
+			})
+			t.Run("testUpdateUser", func(t *testing.T) {
+				t.Parallel()
+				testUpdateUser(t, r.Repository)
+			})
+			t.Run("testUpdateUser_rollback", func(t *testing.T) {
+				t.Parallel()
+				testUpdateUser_rollback(t, r.Repository)
+			})
+			t.Run("testUpdateUser_parallel_non_negative_balance", func(t *testing.T) {
+				t.Parallel()
+				testUpdateUser_parallel_non_negative_balance(t, r.Repository)
+			})
+			t.Run("testUpdateUser_operation_already_applied", func(t *testing.T) {
+				t.Parallel()
+				testUpdateUser_operation_already_applied(t, r.Repository)
+			})
+			t.Run("testApplyOperation_retry_at_zero_balance", func(t *testing.T) {
+				t.Parallel()
+				testApplyOperation_retry_at_zero_balance(t, r.Repository)
+			})
+			t.Run("testLedger", func(t *testing.T) {
+				t.Parallel()
+				testLedger(t, r.Repository)
+			})
+			t.Run("testCreditBatches", func(t *testing.T) {
+				t.Parallel()
+				testCreditBatches(t, r.Repository)
+			})
+			t.Run("testUserReadModels", func(t *testing.T) {
+				t.Parallel()
+				testUserReadModels(t, r.Repository)
+			})
+			t.Run("testUserProfile", func(t *testing.T) {
+				t.Parallel()
+				testUserProfile(t, r.Repository)
+			})
+		})
+	}
+}
+
+type usersRepository interface {
+	user.Repository
+	query.UserDetailsReadModel
+	query.UsersReadModel
+	query.UsersByUUIDsReadModel
+	query.TrainingBalanceReadModel
+	query.ExpiringCreditsReadModel
+	query.LedgerReadModel
+}
+
+type usersRepositoryUnderTest struct {
+	Name       string
+	Repository usersRepository
+}
+
+func createUsersRepositories(t *testing.T) []usersRepositoryUnderTest {
+	return []usersRepositoryUnderTest{
+		{
+			Name:       "Firebase",
+			Repository: newFirebaseRepository(t),
+		},
+		{
+			Name:       "MySQL",
+			Repository: newMySQLRepository(t),
+		},
+	}
+}
+
+func testGetUser_not_exists(t *testing.T, repo usersRepository) {
+	t.Helper()
+
+	userUUID := uuid.New().String()
+
+	u, err := repo.GetUser(context.Background(), userUUID)
+	require.NoError(t, err)
+
+	assert.Equal(t, userUUID, u.UUID())
+	assert.Equal(t, 0, u.Balance())
+}
+
+func testUpdateUser(t *testing.T, repo usersRepository) {
+	t.Helper()
+	ctx := context.Background()
+
+	userUUID := uuid.New().String()
+
+	err := repo.UpdateUser(ctx, userUUID, func(ctx context.Context, u *user.User) (*user.User, error) {
+		if err := u.ChangeBalance(newExampleLedgerEntry(t, uuid.New().String(), 10)); err != nil {
+			return nil, err
+		}
+		u.SyncAuthProfile("Test User", "test-user@threedots.tech", user.Attendee)
+
+		return u, nil
+	})
+	require.NoError(t, err)
+
+	err = repo.UpdateUser(ctx, userUUID, changeBalance(newExampleLedgerEntry(t, uuid.New().String(), -3)))
+	require.NoError(t, err)
+
+	u, err := repo.GetUser(ctx, userUUID)
+	require.NoError(t, err)
+
+	assert.Equal(t, 7, u.Balance())
+	assert.Equal(t, "Test User", u.DisplayName())
+
+	balance, err := repo.TrainingBalance(ctx, userUUID)
+	require.NoError(t, err)
+	assert.Equal(t, 7, balance)
+}
+
+func testUpdateUser_rollback(t *testing.T, repo usersRepository) {
+	t.Helper()
+	ctx := context.Background()
+
+	userUUID := uuid.New().String()
+
+	err := repo.UpdateUser(ctx, userUUID, func(ctx context.Context, u *user.User) (*user.User, error) {
+		if err := u.ChangeBalance(newExampleLedgerEntry(t, uuid.New().String(), 10)); err != nil {
+			return nil, err
+		}
+
+		return u, errors.New("something went wrong")
+	})
+	require.Error(t, err)
+
+	u, err := repo.GetUser(ctx, userUUID)
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, u.Balance(), "balance change was persisted, not rolled back")
+
+	ledger, err := repo.Ledger(ctx, userUUID)
+	require.NoError(t, err)
+	assert.Empty(t, ledger, "ledger entry was persisted, not rolled back")
+}
+
+func testLedger(t *testing.T, repo usersRepository) {
+	t.Helper()
+	ctx := context.Background()
+
+	userUUID := uuid.New().String()
+	trainingUUID := uuid.New().String()
+
+	topUp := newExampleLedgerEntry(t, uuid.New().String(), 5)
+	err := repo.UpdateUser(ctx, userUUID, changeBalance(topUp))
+	require.NoError(t, err)
+
+	booking, err := user.NewLedgerEntry(
+		"booking-"+trainingUUID,
+		user.Booking,
+		-1,
+		trainingUUID,
+		userUUID,
+		topUp.CreatedAt().Add(time.Second),
+	)
+	require.NoError(t, err)
+
+	err = repo.UpdateUser(ctx, userUUID, changeBalance(booking))
+	require.NoError(t, err)
+
+	ledger, err := repo.Ledger(ctx, userUUID)
+	require.NoError(t, err)
+	require.Len(t, ledger, 2)
+
+	assert.Equal(t, booking.OperationID(), ledger[0].OperationID)
+	assert.Equal(t, "booking", ledger[0].Reason)
+	assert.Equal(t, -1, ledger[0].AmountChange)
+	assert.Equal(t, trainingUUID, ledger[0].TrainingUUID)
+	assert.Equal(t, userUUID, ledger[0].Actor)
+	assert.WithinDuration(t, booking.CreatedAt(), ledger[0].CreatedAt, time.Millisecond)
+
+	assert.Equal(t, topUp.OperationID(), ledger[1].OperationID)
+	assert.Equal(t, "manual_adjustment", ledger[1].Reason)
+}
+
+func testUpdateUser_parallel_non_negative_balance(t *testing.T, repo usersRepository) {
+	if _, ok := repo.(adapters.UsersFirestoreRepository); ok {
+		// todo - enable after fix of https://github.com/googleapis/google-cloud-go/issues/2604
+		t.Skip("because of emulator bug, it's not working in Firebase")
+	}
+
+	t.Helper()
+	ctx := context.Background()
+
+	userUUID := uuid.New().String()
+
+	err := repo.UpdateUser(ctx, userUUID, changeBalance(newExampleLedgerEntry(t, uuid.New().String(), 1)))
+	require.NoError(t, err)
+
+	workersCount := 20
+	workersDone := sync.WaitGroup{}
+	workersDone.Add(workersCount)
+
+	// closing startWorkers will unblock all workers at once,
+	// thanks to that it will be more likely to have race condition
+	startWorkers := make(chan struct{})
+	creditsUsed := make(chan struct{}, workersCount)
+
+	// all workers are trying to use the only credit, just one of them should succeed
+	for worker := 0; worker < workersCount; worker++ {
+		go func() {
+			defer workersDone.Done()
+			<-startWorkers
+
+			entry := newExampleLedgerEntry(t, uuid.New().String(), -1)
+
+			err := repo.UpdateUser(ctx, userUUID, changeBalance(entry))
+			if err == nil {
+				creditsUsed <- struct{}{}
+				return
+			}
+
+			assert.IsType(t, user.NegativeBalanceError{}, err)
+		}()
+	}
+
+	close(startWorkers)
+	workersDone.Wait()
+	close(creditsUsed)
+
+	assert.Len(t, creditsUsed, 1, "only one credit should be used")
+
+	balance, err := repo.TrainingBalance(ctx, userUUID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, balance)
+}
+
+func testUpdateUser_operation_already_applied(t *testing.T, repo usersRepository) {
+	t.Helper()
+	ctx := context.Background()
+
+	userUUID := uuid.New().String()
+	entry := newExampleLedgerEntry(t, uuid.New().String(), 5)
+
+	err := repo.UpdateUser(ctx, userUUID, changeBalance(entry))
+	require.NoError(t, err)
+
+	err = repo.UpdateUser(ctx, userUUID, changeBalance(entry))
+	assert.Equal(t, user.OperationAlreadyAppliedError{OperationID: entry.OperationID()}, err)
+
+	balance, err := repo.TrainingBalance(ctx, userUUID)
+	require.NoError(t, err)
+	assert.Equal(t, 5, balance)
+
+	ledger, err := repo.Ledger(ctx, userUUID)
+	require.NoError(t, err)
+	assert.Len(t, ledger, 1)
+}
+
+func testApplyOperation_retry_at_zero_balance(t *testing.T, repo usersRepository) {
+	t.Helper()
+	ctx := context.Background()
+
+	userUUID := uuid.New().String()
+
+	credit := newExampleLedgerEntry(t, uuid.New().String(), 1)
+	err := repo.ApplyOperation(ctx, userUUID, credit.OperationID(), changeBalance(credit))
+	require.NoError(t, err)
+
+	debit := newExampleLedgerEntry(t, uuid.New().String(), -1)
+	err = repo.ApplyOperation(ctx, userUUID, debit.OperationID(), changeBalance(debit))
+	require.NoError(t, err)
+
+	// the retried debit would make the balance negative, but it was already applied
+	err = repo.ApplyOperation(ctx, userUUID, debit.OperationID(), changeBalance(debit))
+	assert.Equal(t, user.OperationAlreadyAppliedError{OperationID: debit.OperationID()}, err)
+
+	balance, err := repo.TrainingBalance(ctx, userUUID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, balance)
+}
+
+func testCreditBatches(t *testing.T, repo usersRepository) {
+	t.Helper()
+	ctx := context.Background()
+
+	userUUID := uuid.New().String()
+	trainingUUID := uuid.New().String()
+	grantedAt := time.Now().UTC().Truncate(time.Millisecond)
+
+	grant, err := user.NewLedgerEntry(uuid.New().String(), user.Purchase, 5, "", "payment-provider", grantedAt)
+	require.NoError(t, err)
+	booking, err := user.NewLedgerEntry(uuid.New().String(), user.Booking, -1, trainingUUID, userUUID, grantedAt)
+	require.NoError(t, err)
+
+	require.NoError(t, repo.UpdateUser(ctx, userUUID, changeBalance(grant)))
+	require.NoError(t, repo.UpdateUser(ctx, userUUID, changeBalance(booking)))
+
+	u, err := repo.GetUser(ctx, userUUID)
+	require.NoError(t, err)
+	require.Len(t, u.CreditBatches(), 1)
+
+	batch := u.CreditBatches()[0]
+	assert.Equal(t, grant.OperationID(), batch.ID())
+	assert.Equal(t, 5, batch.Granted())
+	assert.Equal(t, 4, batch.Remaining())
+	assert.True(t, batch.GrantedAt().Equal(grantedAt))
+	assert.Equal(t, []string{trainingUUID}, batch.BookedTrainings())
+
+	expiring, err := repo.ExpiringCredits(ctx, userUUID)
+	require.NoError(t, err)
+	require.Len(t, expiring, 1)
+	assert.Equal(t, 4, expiring[0].Amount)
+	assert.True(t, expiring[0].ExpiresAt.Equal(batch.ExpiresAt()))
+
+	expiringUsers, err := repo.UsersWithCreditsExpiringBefore(ctx, batch.ExpiresAt())
+	require.NoError(t, err)
+	assert.Contains(t, expiringUsers, userUUID)
+
+	expiringUsers, err = repo.UsersWithCreditsExpiringBefore(ctx, batch.ExpiresAt().Add(-time.Second))
+	require.NoError(t, err)
+	assert.NotContains(t, expiringUsers, userUUID)
+}
+
+func TestUsersFirestoreRepository_legacy_balance(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	firestoreClient, err := firestore.NewClient(ctx, os.Getenv("GCP_PROJECT"))
+	require.NoError(t, err)
+	repo := adapters.NewUsersFirestoreRepository(firestoreClient)
+
+	userUUID := uuid.New().String()
+
+	// users created before credit batches have only the balance
+	_, err = firestoreClient.Collection("users").Doc(userUUID).Set(ctx, map[string]interface{}{
+		"Balance": 3,
+	})
+	require.NoError(t, err)
+
+	balance, err := repo.TrainingBalance(ctx, userUUID)
+	require.NoError(t, err)
+	assert.Equal(t, 3, balance)
+
+	require.NoError(t, repo.UpdateUser(ctx, userUUID, changeBalance(newExampleLedgerEntry(t, uuid.New().String(), -1))))
+
+	u, err := repo.GetUser(ctx, userUUID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, u.Balance())
+
+	_, expires := u.NextCreditsExpiration()
+	assert.False(t, expires, "legacy credits should not expire")
+}
+
+func testUserReadModels(t *testing.T, repo usersRepository) {
+	t.Helper()
+	ctx := context.Background()
+
+	userUUID := uuid.New().String()
+	// unique name, so the search is not matching users created by other tests
+	displayName := "Test User " + userUUID
+
+	err := repo.UpdateUser(ctx, userUUID, func(ctx context.Context, u *user.User) (*user.User, error) {
+		u.SyncAuthProfile(displayName, "test-user@threedots.tech", user.Attendee)
+		if err := u.ChangeRole(user.Trainer); err != nil {
+			return nil, err
+		}
+		if err := u.ChangeBalance(newExampleLedgerEntry(t, uuid.New().String(), 2)); err != nil {
+			return nil, err
+		}
+
+		return u, nil
+	})
+	require.NoError(t, err)
+
+	u, err := repo.User(ctx, userUUID)
+	require.NoError(t, err)
+	assert.Equal(t, displayName, u.DisplayName)
+	assert.Equal(t, "test-user@threedots.tech", u.Email)
+	assert.Equal(t, "trainer", u.Role)
+	assert.Equal(t, 2, u.Balance)
+
+	found, err := repo.Users(ctx, strings.ToUpper(userUUID), 10)
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, userUUID, found[0].UUID)
+
+	found, err = repo.UsersByUUIDs(ctx, []string{userUUID, uuid.New().String()})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, displayName, found[0].DisplayName)
+
+	_, err = repo.User(ctx, uuid.New().String())
+	assert.IsType(t, user.NotFoundError{}, err)
+}
+
+func testUserProfile(t *testing.T, repo usersRepository) {
+	t.Helper()
+	ctx := context.Background()
+
+	userUUID := uuid.New().String()
+
+	preferences, err := user.NewPreferences("Europe/Warsaw", "pl-PL", user.PushNotifications, time.Minute*90)
+	require.NoError(t, err)
+
+	err = repo.UpdateUser(ctx, userUUID, func(ctx context.Context, u *user.User) (*user.User, error) {
+		u.SyncAuthProfile("Test User", "test-user@threedots.tech", user.Attendee)
+		if err := u.ChangeDisplayName("Custom Name"); err != nil {
+			return nil, err
+		}
+		u.ChangePreferences(preferences)
+
+		return u, nil
+	})
+	require.NoError(t, err)
+
+	u, err := repo.GetUser(ctx, userUUID)
+	require.NoError(t, err)
+	assert.Equal(t, "Custom Name", u.DisplayName())
+	assert.True(t, u.CustomDisplayName())
+	assert.Equal(t, preferences, u.Preferences())
+
+	details, err := repo.User(ctx, userUUID)
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/Warsaw", details.Preferences.TimeZone)
+	assert.Equal(t, "pl-PL", details.Preferences.Locale)
+	assert.Equal(t, "push", details.Preferences.NotificationChannel)
+	assert.Equal(t, time.Minute*90, details.Preferences.ReminderLeadTime)
+
+	notPersisted, err := repo.GetUser(ctx, uuid.New().String())
+	require.NoError(t, err)
+	assert.Equal(t, user.DefaultPreferences(), notPersisted.Preferences())
+}
+
+func changeBalance(entry user.LedgerEntry) func(ctx context.Context, u *user.User) (*user.User, error) {
+	return func(ctx context.Context, u *user.User) (*user.User, error) {
+		if err := u.ChangeBalance(entry); err != nil {
+			return nil, err
+		}
+
+		return u, nil
+	}
+}
+
+func newExampleLedgerEntry(t *testing.T, operationID string, amountChange int) user.LedgerEntry {
+	t.Helper()
+
+	entry, err := user.NewLedgerEntry(operationID, user.ManualAdjustment, amountChange, "", "admin", time.Now().UTC())
+	require.NoError(t, err)
+
+	return entry
+}
+
+func newFirebaseRepository(t *testing.T) adapters.UsersFirestoreRepository {
+	t.Helper()
+	firestoreClient, err := firestore.NewClient(context.Background(), os.Getenv("GCP_PROJECT"))
+	require.NoError(t, err)
+
+	return adapters.NewUsersFirestoreRepository(firestoreClient)
+}
+
+func newMySQLRepository(t *testing.T) adapters.UsersMySQLRepository {
+	t.Helper()
+	db, err := mysqldb.NewConnection()
+	require.NoError(t, err)
+
+	require.NoError(t, adapters.MigrateMySQL(context.Background(), db))
+
+	return adapters.NewUsersMySQLRepository(db)
+}
@@ -0,0 +1,51 @@
+package app
+
+import (
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/app/command"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/app/query"
+)
+
+type Application struct {
+	Commands Commands
+	Queries  Queries
+}
+
+type Commands struct {
+	UpdateTrainingBalance command.UpdateTrainingBalanceHandler
+	SyncAuthProfile       command.SyncAuthProfileHandler
+	UpdateUserProfile     command.UpdateUserProfileHandler
+
+	RecordSessionActivity command.RecordSessionActivityHandler
+	RevokeSession         command.RevokeSessionHandler
+
+	AdjustCredits  command.AdjustCreditsHandler
+	ChangeUserRole command.ChangeUserRoleHandler
+
+	EraseUserData command.EraseUserDataHandler
+	ForgetUser    command.ForgetUserHandler
+
+	ExpireCredits command.ExpireCreditsHandler
+
+	CreatePurchaseCheckout command.CreatePurchaseCheckoutHandler
+	// HandlePaymentWebhook is nil, when purchases are disabled.
+	HandlePaymentWebhook command.HandlePaymentWebhookHandler
+}
+
+type Queries struct {
+	TrainingBalance query.TrainingBalanceHandler
+	Ledger          query.LedgerHandler
+	ExpiringCredits query.UserExpiringCreditsHandler
+
+	CreditPackages query.CreditPackagesHandler
+	UserPurchase   query.UserPurchaseHandler
+
+	Users        query.UsersHandler
+	UserDetails  query.UserDetailsHandler
+	UsersByUUIDs query.UsersByUUIDsHandler
+
+	UserSessions   query.UserSessionsHandler
+	SessionRevoked query.SessionRevokedHandler
+
+	UserData        query.UserDataHandler
+	UserDataArchive query.UserDataArchiveHandler
+}
@@ -0,0 +1,66 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/decorator"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/errors"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/domain/user"
+	"github.com/sirupsen/logrus"
+)
+
+// AdjustCredits is a manual change of the balance done by the staff.
+type AdjustCredits struct {
+	UserUUID string
+
+	OperationID  string
+	AmountChange int
+	Note         string
+
+	// ActorUUID is the admin adjusting credits
+	ActorUUID string
+}
+
+type AdjustCreditsHandler decorator.CommandHandler[AdjustCredits]
+
+type adjustCreditsHandler struct {
+	repo user.Repository
+}
+
+func NewAdjustCreditsHandler(
+	repo user.Repository,
+	logger *logrus.Entry,
+	metricsClient decorator.MetricsClient,
+) AdjustCreditsHandler {
+	if repo == nil {
+		panic("nil repo")
+	}
+
+	return decorator.ApplyCommandDecorators[AdjustCredits](
+		adjustCreditsHandler{repo: repo},
+		logger,
+		metricsClient,
+	)
+}
+
+func (h adjustCreditsHandler) Handle(ctx context.Context, cmd AdjustCredits) error {
+	entry, err := user.NewManualAdjustmentLedgerEntry(
+		cmd.OperationID,
+		cmd.AmountChange,
+		cmd.Note,
+		cmd.ActorUUID,
+		time.Now().UTC(),
+	)
+	if err != nil {
+		return errors.NewIncorrectInputError(err.Error(), "invalid-credits-adjustment")
+	}
+
+	return h.repo.ApplyOperation(ctx, cmd.UserUUID, entry.OperationID(), func(ctx context.Context, u *user.User) (*user.User, error) {
+		if err := u.ChangeBalance(entry); err != nil {
+			return nil, err
+		}
+
+		return u, nil
+	})
+}
@@ -0,0 +1,95 @@
+package command
+
+import (
+	"context"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/decorator"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/errors"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/domain/user"
+	"github.com/sirupsen/logrus"
+)
+
+type ChangeUserRole struct {
+	UserUUID string
+	Role     string
+
+	// ActorUUID is the admin changing the role
+	ActorUUID string
+}
+
+type ChangeUserRoleHandler decorator.CommandHandler[ChangeUserRole]
+
+type changeUserRoleHandler struct {
+	repo         user.Repository
+	authProvider AuthProvider
+}
+
+func NewChangeUserRoleHandler(
+	repo user.Repository,
+	authProvider AuthProvider,
+	logger *logrus.Entry,
+	metricsClient decorator.MetricsClient,
+) ChangeUserRoleHandler {
+	if repo == nil {
+		panic("nil repo")
+	}
+	if authProvider == nil {
+		panic("nil authProvider")
+	}
+
+	return decorator.ApplyCommandDecorators[ChangeUserRole](
+		changeUserRoleHandler{repo: repo, authProvider: authProvider},
+		logger,
+		metricsClient,
+	)
+}
+
+func (h changeUserRoleHandler) Handle(ctx context.Context, cmd ChangeUserRole) error {
+	if cmd.UserUUID == cmd.ActorUUID {
+		// it protects from locking out the last admin
+		return errors.NewIncorrectInputError("admin can't change own role", "cannot-change-own-role")
+	}
+
+	role, err := user.NewRoleFromString(cmd.Role)
+	if err != nil {
+		return err
+	}
+
+	if !h.authProvider.CanChangeRoles() {
+		return errors.NewIncorrectInputError(
+			"roles are managed by the identity provider",
+			"roles-managed-by-identity-provider",
+		)
+	}
+
+	err = h.repo.UpdateUser(ctx, cmd.UserUUID, func(ctx context.Context, u *user.User) (*user.User, error) {
+		if err := u.ChangeRole(role); err != nil {
+			return nil, err
+		}
+
+		return u, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// The auth provider is updated after the role is saved, because it can't be rolled back with the transaction.
+	// When it fails, the saved role is synced again by retrying the command.
+	// The role is applied to the user's new tokens, after the current one expires.
+	if err := h.syncAuthProviderRole(ctx, cmd.UserUUID); err != nil {
+		return errors.NewSlugError(err.Error(), "unable-to-update-auth-provider")
+	}
+
+	return nil
+}
+
+// syncAuthProviderRole sets the stored role in the auth provider, so the last saved role wins
+// when the role is changed concurrently.
+func (h changeUserRoleHandler) syncAuthProviderRole(ctx context.Context, userUUID string) error {
+	u, err := h.repo.GetUser(ctx, userUUID)
+	if err != nil {
+		return err
+	}
+
+	return h.authProvider.SetRole(ctx, u.UUID(), u.Role())
+}
@@ -0,0 +1,83 @@
+package command
+
+import (
+	"context"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/decorator"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/errors"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/domain/purchase"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrPurchasesDisabled is returned when no payment provider is configured.
+var ErrPurchasesDisabled = errors.NewPreconditionFailedError(
+	"purchases are disabled, no payment provider is configured",
+	"purchases-disabled",
+)
+
+type CreatePurchaseCheckout struct {
+	PurchaseUUID string
+	UserUUID     string
+	PackageID    string
+}
+
+type CreatePurchaseCheckoutHandler decorator.CommandHandler[CreatePurchaseCheckout]
+
+type createPurchaseCheckoutHandler struct {
+	catalogue       purchase.Catalogue
+	purchaseRepo    purchase.Repository
+	paymentProvider PaymentProvider
+}
+
+// NewCreatePurchaseCheckoutHandler creates the handler, paymentProvider is nil when purchases are disabled.
+func NewCreatePurchaseCheckoutHandler(
+	catalogue purchase.Catalogue,
+	purchaseRepo purchase.Repository,
+	paymentProvider PaymentProvider,
+	logger *logrus.Entry,
+	metricsClient decorator.MetricsClient,
+) CreatePurchaseCheckoutHandler {
+	if len(catalogue.Packages()) == 0 {
+		panic("empty catalogue")
+	}
+	if purchaseRepo == nil {
+		panic("nil purchaseRepo")
+	}
+	return decorator.ApplyCommandDecorators[CreatePurchaseCheckout](
+		createPurchaseCheckoutHandler{
+			catalogue:       catalogue,
+			purchaseRepo:    purchaseRepo,
+			paymentProvider: paymentProvider,
+		},
+		logger,
+		metricsClient,
+	)
+}
+
+func (h createPurchaseCheckoutHandler) Handle(ctx context.Context, cmd CreatePurchaseCheckout) error {
+	if h.paymentProvider == nil {
+		return ErrPurchasesDisabled
+	}
+
+	creditPackage, err := h.catalogue.Package(cmd.PackageID)
+	if err != nil {
+		return errors.NewIncorrectInputError(err.Error(), "credit-package-not-found")
+	}
+
+	checkoutURL, err := h.paymentProvider.CreateCheckout(ctx, Checkout{
+		PurchaseUUID: cmd.PurchaseUUID,
+		Description:  creditPackage.Name(),
+		Price:        creditPackage.Price(),
+		Currency:     creditPackage.Currency(),
+	})
+	if err != nil {
+		return errors.NewSlugError(err.Error(), "unable-to-create-checkout")
+	}
+
+	p, err := purchase.NewPurchase(cmd.PurchaseUUID, cmd.UserUUID, creditPackage, checkoutURL)
+	if err != nil {
+		return err
+	}
+
+	return h.purchaseRepo.AddPurchase(ctx, p)
+}
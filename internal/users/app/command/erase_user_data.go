@@ -0,0 +1,88 @@
+package command
+
+import (
+	"context"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/decorator"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/errors"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/domain/session"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/domain/user"
+	"github.com/sirupsen/logrus"
+)
+
+// EraseUserData erases personal data of the user stored by the users service and removes the user's account.
+// Data stored by other services is erased with ForgetUser.
+type EraseUserData struct {
+	UserUUID string
+}
+
+type EraseUserDataHandler decorator.CommandHandler[EraseUserData]
+
+type eraseUserDataHandler struct {
+	repo         user.Repository
+	sessionsRepo session.Repository
+	authProvider AuthProvider
+}
+
+func NewEraseUserDataHandler(
+	repo user.Repository,
+	sessionsRepo session.Repository,
+	authProvider AuthProvider,
+	logger *logrus.Entry,
+	metricsClient decorator.MetricsClient,
+) EraseUserDataHandler {
+	if repo == nil {
+		panic("nil repo")
+	}
+	if sessionsRepo == nil {
+		panic("nil sessionsRepo")
+	}
+	if authProvider == nil {
+		panic("nil authProvider")
+	}
+
+	return decorator.ApplyCommandDecorators[EraseUserData](
+		eraseUserDataHandler{repo: repo, sessionsRepo: sessionsRepo, authProvider: authProvider},
+		logger,
+		metricsClient,
+	)
+}
+
+func (h eraseUserDataHandler) Handle(ctx context.Context, cmd EraseUserData) error {
+	return eraseUserData(ctx, h.repo, h.sessionsRepo, h.authProvider, cmd.UserUUID)
+}
+
+func eraseUserData(
+	ctx context.Context,
+	repo user.Repository,
+	sessionsRepo session.Repository,
+	authProvider AuthProvider,
+	userUUID string,
+) error {
+	if userUUID == "" {
+		return errors.NewIncorrectInputError("empty user UUID", "empty-user-uuid")
+	}
+
+	err := repo.UpdateUser(ctx, userUUID, func(ctx context.Context, u *user.User) (*user.User, error) {
+		u.Anonymize()
+		return u, nil
+	})
+	if err != nil {
+		return typedErrorToSlugError(err, "unable-to-erase-user-data")
+	}
+
+	err = sessionsRepo.UpdateHistory(ctx, userUUID, func(ctx context.Context, h *session.History) (*session.History, error) {
+		h.Clear()
+		return h, nil
+	})
+	if err != nil {
+		return typedErrorToSlugError(err, "unable-to-erase-user-data")
+	}
+
+	// the account is removed as the last step, so the user is not able to log in and recreate the erased data
+	if err := authProvider.DeleteUser(ctx, userUUID); err != nil {
+		return typedErrorToSlugError(err, "unable-to-delete-user-account")
+	}
+
+	return nil
+}
@@ -0,0 +1,15 @@
+package command
+
+import (
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/errors"
+)
+
+// typedErrorToSlugError keeps errors with a type (like not found or incorrect input errors returned by other services),
+// and returns other errors (like database errors) as unknown errors with the fallbackSlug.
+func typedErrorToSlugError(err error, fallbackSlug string) error {
+	if _, ok := errors.AsSlugError(err); ok {
+		return err
+	}
+
+	return errors.NewSlugError(err.Error(), fallbackSlug)
+}
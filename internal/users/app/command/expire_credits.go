@@ -0,0 +1,67 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/decorator"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/domain/user"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// ExpireCredits removes credits, which expired before Now, from balances of all users.
+type ExpireCredits struct {
+	Now time.Time
+}
+
+type ExpireCreditsHandler decorator.CommandHandler[ExpireCredits]
+
+type expireCreditsHandler struct {
+	repo   user.Repository
+	logger *logrus.Entry
+}
+
+func NewExpireCreditsHandler(
+	repo user.Repository,
+	logger *logrus.Entry,
+	metricsClient decorator.MetricsClient,
+) ExpireCreditsHandler {
+	if repo == nil {
+		panic("nil repo")
+	}
+
+	return decorator.ApplyCommandDecorators[ExpireCredits](
+		expireCreditsHandler{repo: repo, logger: logger},
+		logger,
+		metricsClient,
+	)
+}
+
+func (h expireCreditsHandler) Handle(ctx context.Context, cmd ExpireCredits) error {
+	userUUIDs, err := h.repo.UsersWithCreditsExpiringBefore(ctx, cmd.Now)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, userUUID := range userUUIDs {
+		err := h.repo.UpdateUser(ctx, userUUID, func(ctx context.Context, u *user.User) (*user.User, error) {
+			if err := u.ExpireCredits(cmd.Now); err != nil {
+				return nil, err
+			}
+			return u, nil
+		})
+		if err != nil {
+			// other users should not be blocked by one failing update, it will be retried with the next run
+			h.logger.WithError(err).WithField("user_uuid", userUUID).Error("Unable to expire credits")
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return errors.Errorf("unable to expire credits of %d of %d users", failed, len(userUUIDs))
+	}
+
+	return nil
+}
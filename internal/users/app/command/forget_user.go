@@ -0,0 +1,79 @@
+package command
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/decorator"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/domain/session"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/domain/user"
+	"github.com/sirupsen/logrus"
+)
+
+// ForgetUser erases personal data of the user in all services.
+//
+// It's safe to retry it, when it failed in the middle.
+type ForgetUser struct {
+	UserUUID string
+}
+
+type ForgetUserHandler decorator.CommandHandler[ForgetUser]
+
+type forgetUserHandler struct {
+	repo         user.Repository
+	sessionsRepo session.Repository
+	authProvider AuthProvider
+
+	trainingsService UserDataService
+	trainerService   UserDataService
+}
+
+func NewForgetUserHandler(
+	repo user.Repository,
+	sessionsRepo session.Repository,
+	authProvider AuthProvider,
+	trainingsService UserDataService,
+	trainerService UserDataService,
+	logger *logrus.Entry,
+	metricsClient decorator.MetricsClient,
+) ForgetUserHandler {
+	if repo == nil {
+		panic("nil repo")
+	}
+	if sessionsRepo == nil {
+		panic("nil sessionsRepo")
+	}
+	if authProvider == nil {
+		panic("nil authProvider")
+	}
+	if trainingsService == nil {
+		panic("nil trainingsService")
+	}
+	if trainerService == nil {
+		panic("nil trainerService")
+	}
+
+	return decorator.ApplyCommandDecorators[ForgetUser](
+		forgetUserHandler{
+			repo:             repo,
+			sessionsRepo:     sessionsRepo,
+			authProvider:     authProvider,
+			trainingsService: trainingsService,
+			trainerService:   trainerService,
+		},
+		logger,
+		metricsClient,
+	)
+}
+
+func (h forgetUserHandler) Handle(ctx context.Context, cmd ForgetUser) error {
+	if err := h.trainingsService.EraseUserData(ctx, cmd.UserUUID); err != nil {
+		return typedErrorToSlugError(fmt.Errorf("trainings: %w", err), "unable-to-erase-user-data")
+	}
+
+	if err := h.trainerService.EraseUserData(ctx, cmd.UserUUID); err != nil {
+		return typedErrorToSlugError(fmt.Errorf("trainer: %w", err), "unable-to-erase-user-data")
+	}
+
+	return eraseUserData(ctx, h.repo, h.sessionsRepo, h.authProvider, cmd.UserUUID)
+}
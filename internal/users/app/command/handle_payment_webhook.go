@@ -0,0 +1,121 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/decorator"
+	commonerrors "github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/errors"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/domain/purchase"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/domain/user"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// paymentProviderActor is stored as the actor of credits granted for purchases.
+const paymentProviderActor = "payment-provider"
+
+type HandlePaymentWebhook struct {
+	Payload []byte
+	Headers map[string][]string
+}
+
+type HandlePaymentWebhookHandler decorator.CommandHandler[HandlePaymentWebhook]
+
+type handlePaymentWebhookHandler struct {
+	purchaseRepo    purchase.Repository
+	userRepo        user.Repository
+	paymentProvider PaymentProvider
+}
+
+func NewHandlePaymentWebhookHandler(
+	purchaseRepo purchase.Repository,
+	userRepo user.Repository,
+	paymentProvider PaymentProvider,
+	logger *logrus.Entry,
+	metricsClient decorator.MetricsClient,
+) HandlePaymentWebhookHandler {
+	if purchaseRepo == nil {
+		panic("nil purchaseRepo")
+	}
+	if userRepo == nil {
+		panic("nil userRepo")
+	}
+	if paymentProvider == nil {
+		panic("nil paymentProvider")
+	}
+
+	return decorator.ApplyCommandDecorators[HandlePaymentWebhook](
+		handlePaymentWebhookHandler{
+			purchaseRepo:    purchaseRepo,
+			userRepo:        userRepo,
+			paymentProvider: paymentProvider,
+		},
+		logger,
+		metricsClient,
+	)
+}
+
+func (h handlePaymentWebhookHandler) Handle(ctx context.Context, cmd HandlePaymentWebhook) error {
+	result, err := h.paymentProvider.ParseWebhook(cmd.Payload, cmd.Headers)
+	if err != nil {
+		return commonerrors.NewIncorrectInputError(err.Error(), "invalid-payment-webhook")
+	}
+
+	if !result.Paid {
+		return h.purchaseRepo.UpdatePurchase(ctx, result.PurchaseUUID, func(ctx context.Context, p *purchase.Purchase) (*purchase.Purchase, error) {
+			if err := p.MarkAsFailed(); err != nil {
+				return nil, err
+			}
+			return p, nil
+		})
+	}
+
+	p, err := h.purchaseRepo.GetPurchase(ctx, result.PurchaseUUID)
+	if err != nil {
+		return err
+	}
+	if p.IsPaid() {
+		// webhooks are delivered at least once
+		return nil
+	}
+
+	// Credits are granted before marking the purchase as paid.
+	// When marking fails, the webhook is retried by the provider and the ledger doesn't grant credits twice.
+	if err := h.grantCredits(ctx, p); err != nil {
+		return err
+	}
+
+	return h.purchaseRepo.UpdatePurchase(ctx, p.UUID(), func(ctx context.Context, p *purchase.Purchase) (*purchase.Purchase, error) {
+		if err := p.MarkAsPaid(); err != nil {
+			return nil, err
+		}
+		return p, nil
+	})
+}
+
+func (h handlePaymentWebhookHandler) grantCredits(ctx context.Context, p *purchase.Purchase) error {
+	entry, err := user.NewLedgerEntry(
+		p.CreditsOperationID(),
+		user.Purchase,
+		p.Credits(),
+		"",
+		paymentProviderActor,
+		time.Now().UTC(),
+	)
+	if err != nil {
+		return err
+	}
+
+	err = h.userRepo.ApplyOperation(ctx, p.UserUUID(), entry.OperationID(), func(ctx context.Context, u *user.User) (*user.User, error) {
+		if err := u.ChangeBalance(entry); err != nil {
+			return nil, err
+		}
+		return u, nil
+	})
+	if errors.As(err, &user.OperationAlreadyAppliedError{}) {
+		return nil
+	}
+
+	return errors.Wrap(err, "unable to grant credits")
+}
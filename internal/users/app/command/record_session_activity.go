@@ -0,0 +1,67 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/decorator"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/domain/session"
+	"github.com/sirupsen/logrus"
+)
+
+// RecordSessionActivity records the authenticated request in the user's session history.
+// It fails with the "session-revoked" authorization error, when the session was revoked.
+type RecordSessionActivity struct {
+	UserUUID  string
+	SessionID string
+	IP        string
+	UserAgent string
+}
+
+type RecordSessionActivityHandler decorator.CommandHandler[RecordSessionActivity]
+
+type recordSessionActivityHandler struct {
+	repo session.Repository
+}
+
+func NewRecordSessionActivityHandler(
+	repo session.Repository,
+	logger *logrus.Entry,
+	metricsClient decorator.MetricsClient,
+) RecordSessionActivityHandler {
+	if repo == nil {
+		panic("nil repo")
+	}
+
+	return decorator.ApplyCommandDecorators[RecordSessionActivity](
+		recordSessionActivityHandler{repo: repo},
+		logger,
+		metricsClient,
+	)
+}
+
+func (h recordSessionActivityHandler) Handle(ctx context.Context, cmd RecordSessionActivity) error {
+	now := time.Now()
+
+	// most of the requests are done within an already recorded session, so the transaction is not needed for them
+	history, err := h.repo.GetHistory(ctx, cmd.UserUUID)
+	if err != nil {
+		return err
+	}
+
+	shouldRecord, err := history.ShouldRecordActivity(cmd.SessionID, cmd.IP, cmd.UserAgent, now)
+	if err != nil {
+		return err
+	}
+	if !shouldRecord {
+		return nil
+	}
+
+	return h.repo.UpdateHistory(ctx, cmd.UserUUID, func(ctx context.Context, h *session.History) (*session.History, error) {
+		if err := h.RecordActivity(cmd.SessionID, cmd.IP, cmd.UserAgent, now); err != nil {
+			return nil, err
+		}
+
+		return h, nil
+	})
+}
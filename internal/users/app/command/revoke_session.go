@@ -0,0 +1,47 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/decorator"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/domain/session"
+	"github.com/sirupsen/logrus"
+)
+
+type RevokeSession struct {
+	UserUUID  string
+	SessionID string
+}
+
+type RevokeSessionHandler decorator.CommandHandler[RevokeSession]
+
+type revokeSessionHandler struct {
+	repo session.Repository
+}
+
+func NewRevokeSessionHandler(
+	repo session.Repository,
+	logger *logrus.Entry,
+	metricsClient decorator.MetricsClient,
+) RevokeSessionHandler {
+	if repo == nil {
+		panic("nil repo")
+	}
+
+	return decorator.ApplyCommandDecorators[RevokeSession](
+		revokeSessionHandler{repo: repo},
+		logger,
+		metricsClient,
+	)
+}
+
+func (h revokeSessionHandler) Handle(ctx context.Context, cmd RevokeSession) error {
+	return h.repo.UpdateHistory(ctx, cmd.UserUUID, func(ctx context.Context, h *session.History) (*session.History, error) {
+		if err := h.Revoke(cmd.SessionID, time.Now()); err != nil {
+			return nil, err
+		}
+
+		return h, nil
+	})
+}
@@ -0,0 +1,44 @@
+package command
+
+import (
+	"context"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/domain/user"
+)
+
+// PaymentProvider is an external system handling payments for credit packages.
+type PaymentProvider interface {
+	// CreateCheckout starts the payment and returns URL, where the user should be redirected to pay.
+	CreateCheckout(ctx context.Context, checkout Checkout) (checkoutURL string, err error)
+
+	// ParseWebhook verifies that the webhook was sent by the provider and returns the payment result.
+	ParseWebhook(payload []byte, headers map[string][]string) (PaymentResult, error)
+}
+
+type Checkout struct {
+	PurchaseUUID string
+	Description  string
+
+	// Price is in the smallest currency unit, for example in cents
+	Price    int
+	Currency string
+}
+
+type PaymentResult struct {
+	PurchaseUUID string
+	Paid         bool
+}
+
+// AuthProvider is the source of truth of users' identity, the role is stored in its custom claims.
+type AuthProvider interface {
+	// CanChangeRoles is false, when roles are managed by the identity provider instead of the service.
+	CanChangeRoles() bool
+	SetRole(ctx context.Context, userUUID string, role user.Role) error
+	// DeleteUser removes the user's account, deleting not existing user is not an error.
+	DeleteUser(ctx context.Context, userUUID string) error
+}
+
+// UserDataService is another service, which stores personal data of users.
+type UserDataService interface {
+	EraseUserData(ctx context.Context, userUUID string) error
+}
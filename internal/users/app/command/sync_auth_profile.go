@@ -0,0 +1,51 @@
+package command
+
+import (
+	"context"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/decorator"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/domain/user"
+	"github.com/sirupsen/logrus"
+)
+
+// SyncAuthProfile copies the profile from the auth provider, so staff can search users by it.
+type SyncAuthProfile struct {
+	UserUUID    string
+	DisplayName string
+	Email       string
+	Role        string
+}
+
+type SyncAuthProfileHandler decorator.CommandHandler[SyncAuthProfile]
+
+type syncAuthProfileHandler struct {
+	repo user.Repository
+}
+
+func NewSyncAuthProfileHandler(
+	repo user.Repository,
+	logger *logrus.Entry,
+	metricsClient decorator.MetricsClient,
+) SyncAuthProfileHandler {
+	if repo == nil {
+		panic("nil repo")
+	}
+
+	return decorator.ApplyCommandDecorators[SyncAuthProfile](
+		syncAuthProfileHandler{repo: repo},
+		logger,
+		metricsClient,
+	)
+}
+
+func (h syncAuthProfileHandler) Handle(ctx context.Context, cmd SyncAuthProfile) error {
+	role, err := user.NewRoleFromString(cmd.Role)
+	if err != nil {
+		return err
+	}
+
+	return h.repo.UpdateUser(ctx, cmd.UserUUID, func(ctx context.Context, u *user.User) (*user.User, error) {
+		u.SyncAuthProfile(cmd.DisplayName, cmd.Email, role)
+		return u, nil
+	})
+}
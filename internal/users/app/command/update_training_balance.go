@@ -0,0 +1,75 @@
+package command
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/decorator"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/errors"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/domain/user"
+	"github.com/sirupsen/logrus"
+)
+
+type UpdateTrainingBalance struct {
+	UserUUID string
+
+	// OperationID is provided by the caller, retried operations with the same ID are applied only once.
+	OperationID  string
+	Reason       user.LedgerReason
+	AmountChange int
+	TrainingUUID string
+	Actor        string
+}
+
+type UpdateTrainingBalanceHandler decorator.CommandHandler[UpdateTrainingBalance]
+
+type updateTrainingBalanceHandler struct {
+	repo   user.Repository
+	logger *logrus.Entry
+}
+
+func NewUpdateTrainingBalanceHandler(
+	repo user.Repository,
+	logger *logrus.Entry,
+	metricsClient decorator.MetricsClient,
+) UpdateTrainingBalanceHandler {
+	if repo == nil {
+		panic("nil repo")
+	}
+
+	return decorator.ApplyCommandDecorators[UpdateTrainingBalance](
+		updateTrainingBalanceHandler{repo: repo, logger: logger},
+		logger,
+		metricsClient,
+	)
+}
+
+func (h updateTrainingBalanceHandler) Handle(ctx context.Context, cmd UpdateTrainingBalance) error {
+	entry, err := user.NewLedgerEntry(
+		cmd.OperationID,
+		cmd.Reason,
+		cmd.AmountChange,
+		cmd.TrainingUUID,
+		cmd.Actor,
+		time.Now().UTC(),
+	)
+	if err != nil {
+		return errors.NewIncorrectInputError(err.Error(), "invalid-balance-change")
+	}
+
+	err = h.repo.ApplyOperation(ctx, cmd.UserUUID, entry.OperationID(), func(ctx context.Context, u *user.User) (*user.User, error) {
+		if err := u.ChangeBalance(entry); err != nil {
+			return nil, err
+		}
+
+		return u, nil
+	})
+
+	if stderrors.As(err, &user.OperationAlreadyAppliedError{}) {
+		h.logger.WithField("operation_id", cmd.OperationID).Info("Balance change was already applied, skipping")
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,90 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/decorator"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/domain/user"
+	"github.com/sirupsen/logrus"
+)
+
+// UpdateUserProfile changes the profile of the user. Only not nil fields are changed.
+type UpdateUserProfile struct {
+	UserUUID string
+
+	DisplayName         *string
+	TimeZone            *string
+	Locale              *string
+	NotificationChannel *string
+	ReminderLeadTime    *time.Duration
+}
+
+type UpdateUserProfileHandler decorator.CommandHandler[UpdateUserProfile]
+
+type updateUserProfileHandler struct {
+	repo user.Repository
+}
+
+func NewUpdateUserProfileHandler(
+	repo user.Repository,
+	logger *logrus.Entry,
+	metricsClient decorator.MetricsClient,
+) UpdateUserProfileHandler {
+	if repo == nil {
+		panic("nil repo")
+	}
+
+	return decorator.ApplyCommandDecorators[UpdateUserProfile](
+		updateUserProfileHandler{repo: repo},
+		logger,
+		metricsClient,
+	)
+}
+
+func (h updateUserProfileHandler) Handle(ctx context.Context, cmd UpdateUserProfile) error {
+	return h.repo.UpdateUser(ctx, cmd.UserUUID, func(ctx context.Context, u *user.User) (*user.User, error) {
+		if cmd.DisplayName != nil {
+			if err := u.ChangeDisplayName(*cmd.DisplayName); err != nil {
+				return nil, err
+			}
+		}
+
+		preferences, err := updatedPreferences(u.Preferences(), cmd)
+		if err != nil {
+			return nil, err
+		}
+		u.ChangePreferences(preferences)
+
+		return u, nil
+	})
+}
+
+// updatedPreferences validates preferences again, even if only one of them is changed.
+func updatedPreferences(current user.Preferences, cmd UpdateUserProfile) (user.Preferences, error) {
+	timeZone := current.TimeZone()
+	if cmd.TimeZone != nil {
+		timeZone = *cmd.TimeZone
+	}
+
+	locale := current.Locale()
+	if cmd.Locale != nil {
+		locale = *cmd.Locale
+	}
+
+	notificationChannel := current.NotificationChannel()
+	if cmd.NotificationChannel != nil {
+		var err error
+		notificationChannel, err = user.NewNotificationChannelFromString(*cmd.NotificationChannel)
+		if err != nil {
+			return user.Preferences{}, err
+		}
+	}
+
+	reminderLeadTime := current.ReminderLeadTime()
+	if cmd.ReminderLeadTime != nil {
+		reminderLeadTime = *cmd.ReminderLeadTime
+	}
+
+	return user.NewPreferences(timeZone, locale, notificationChannel, reminderLeadTime)
+}
@@ -0,0 +1,48 @@
+package query
+
+import (
+	"context"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/decorator"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/domain/purchase"
+	"github.com/sirupsen/logrus"
+)
+
+type CreditPackages struct{}
+
+type CreditPackagesHandler decorator.QueryHandler[CreditPackages, []CreditPackage]
+
+type creditPackagesHandler struct {
+	catalogue purchase.Catalogue
+}
+
+func NewCreditPackagesHandler(
+	catalogue purchase.Catalogue,
+	logger *logrus.Entry,
+	metricsClient decorator.MetricsClient,
+) CreditPackagesHandler {
+	if len(catalogue.Packages()) == 0 {
+		panic("empty catalogue")
+	}
+
+	return decorator.ApplyQueryDecorators[CreditPackages, []CreditPackage](
+		creditPackagesHandler{catalogue: catalogue},
+		logger,
+		metricsClient,
+	)
+}
+
+func (h creditPackagesHandler) Handle(ctx context.Context, _ CreditPackages) ([]CreditPackage, error) {
+	var packages []CreditPackage
+	for _, p := range h.catalogue.Packages() {
+		packages = append(packages, CreditPackage{
+			ID:       p.ID(),
+			Name:     p.Name(),
+			Credits:  p.Credits(),
+			Price:    p.Price(),
+			Currency: p.Currency(),
+		})
+	}
+
+	return packages, nil
+}
@@ -0,0 +1,43 @@
+package query
+
+import (
+	"context"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/decorator"
+	"github.com/sirupsen/logrus"
+)
+
+type UserExpiringCredits struct {
+	UserUUID string
+}
+
+type UserExpiringCreditsHandler decorator.QueryHandler[UserExpiringCredits, []ExpiringCredits]
+
+type ExpiringCreditsReadModel interface {
+	// ExpiringCredits returns not expired credits, which will expire, ordered by the expiration time.
+	ExpiringCredits(ctx context.Context, userUUID string) ([]ExpiringCredits, error)
+}
+
+type userExpiringCreditsHandler struct {
+	readModel ExpiringCreditsReadModel
+}
+
+func NewUserExpiringCreditsHandler(
+	readModel ExpiringCreditsReadModel,
+	logger *logrus.Entry,
+	metricsClient decorator.MetricsClient,
+) UserExpiringCreditsHandler {
+	if readModel == nil {
+		panic("nil readModel")
+	}
+
+	return decorator.ApplyQueryDecorators[UserExpiringCredits, []ExpiringCredits](
+		userExpiringCreditsHandler{readModel: readModel},
+		logger,
+		metricsClient,
+	)
+}
+
+func (h userExpiringCreditsHandler) Handle(ctx context.Context, query UserExpiringCredits) ([]ExpiringCredits, error) {
+	return h.readModel.ExpiringCredits(ctx, query.UserUUID)
+}
@@ -0,0 +1,43 @@
+package query
+
+import (
+	"context"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/decorator"
+	"github.com/sirupsen/logrus"
+)
+
+type Ledger struct {
+	UserUUID string
+}
+
+type LedgerHandler decorator.QueryHandler[Ledger, []LedgerEntry]
+
+type LedgerReadModel interface {
+	// Ledger returns all ledger entries of the user, starting from the newest.
+	Ledger(ctx context.Context, userUUID string) ([]LedgerEntry, error)
+}
+
+type ledgerHandler struct {
+	readModel LedgerReadModel
+}
+
+func NewLedgerHandler(
+	readModel LedgerReadModel,
+	logger *logrus.Entry,
+	metricsClient decorator.MetricsClient,
+) LedgerHandler {
+	if readModel == nil {
+		panic("nil readModel")
+	}
+
+	return decorator.ApplyQueryDecorators[Ledger, []LedgerEntry](
+		ledgerHandler{readModel: readModel},
+		logger,
+		metricsClient,
+	)
+}
+
+func (h ledgerHandler) Handle(ctx context.Context, query Ledger) ([]LedgerEntry, error) {
+	return h.readModel.Ledger(ctx, query.UserUUID)
+}
@@ -0,0 +1,54 @@
+package query
+
+import (
+	"context"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/decorator"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/domain/purchase"
+	"github.com/sirupsen/logrus"
+)
+
+type UserPurchase struct {
+	PurchaseUUID string
+	UserUUID     string
+}
+
+type UserPurchaseHandler decorator.QueryHandler[UserPurchase, Purchase]
+
+type PurchaseReadModel interface {
+	Purchase(ctx context.Context, purchaseUUID string) (Purchase, error)
+}
+
+type userPurchaseHandler struct {
+	readModel PurchaseReadModel
+}
+
+func NewUserPurchaseHandler(
+	readModel PurchaseReadModel,
+	logger *logrus.Entry,
+	metricsClient decorator.MetricsClient,
+) UserPurchaseHandler {
+	if readModel == nil {
+		panic("nil readModel")
+	}
+
+	return decorator.ApplyQueryDecorators[UserPurchase, Purchase](
+		userPurchaseHandler{readModel: readModel},
+		logger,
+		metricsClient,
+	)
+}
+
+func (h userPurchaseHandler) Handle(ctx context.Context, query UserPurchase) (Purchase, error) {
+	p, err := h.readModel.Purchase(ctx, query.PurchaseUUID)
+	if err != nil {
+		return Purchase{}, err
+	}
+
+	if p.UserUUID != query.UserUUID {
+		// the same error as for not existing purchase, so it's not revealed that the purchase exists
+		return Purchase{}, purchase.NotFoundError{PurchaseUUID: query.PurchaseUUID}
+	}
+
+	return p, nil
+}
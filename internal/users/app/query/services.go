@@ -0,0 +1,11 @@
+package query
+
+import (
+	"context"
+)
+
+// UserDataExporter is another service, which stores personal data of users.
+type UserDataExporter interface {
+	// ExportUserData returns a JSON document with all personal data of the user stored by the service.
+	ExportUserData(ctx context.Context, userUUID string) ([]byte, error)
+}
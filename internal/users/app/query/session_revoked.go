@@ -0,0 +1,56 @@
+package query
+
+import (
+	"context"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/decorator"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// SessionRevoked checks if the session was revoked, sessions which were never recorded are not revoked.
+type SessionRevoked struct {
+	UserUUID  string
+	SessionID string
+}
+
+type SessionRevokedHandler decorator.QueryHandler[SessionRevoked, bool]
+
+type sessionRevokedHandler struct {
+	readModel UserSessionsReadModel
+}
+
+func NewSessionRevokedHandler(
+	readModel UserSessionsReadModel,
+	logger *logrus.Entry,
+	metricsClient decorator.MetricsClient,
+) SessionRevokedHandler {
+	if readModel == nil {
+		panic("nil readModel")
+	}
+
+	return decorator.ApplyQueryDecorators[SessionRevoked, bool](
+		sessionRevokedHandler{readModel: readModel},
+		logger,
+		metricsClient,
+	)
+}
+
+func (h sessionRevokedHandler) Handle(ctx context.Context, query SessionRevoked) (bool, error) {
+	if query.UserUUID == "" || query.SessionID == "" {
+		return false, errors.NewIncorrectInputError("empty user UUID or session ID", "empty-session")
+	}
+
+	sessions, err := h.readModel.Sessions(ctx, query.UserUUID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, s := range sessions {
+		if s.ID == query.SessionID {
+			return s.Revoked, nil
+		}
+	}
+
+	return false, nil
+}
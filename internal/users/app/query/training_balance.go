@@ -0,0 +1,42 @@
+package query
+
+import (
+	"context"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/decorator"
+	"github.com/sirupsen/logrus"
+)
+
+type TrainingBalance struct {
+	UserUUID string
+}
+
+type TrainingBalanceHandler decorator.QueryHandler[TrainingBalance, int]
+
+type TrainingBalanceReadModel interface {
+	TrainingBalance(ctx context.Context, userUUID string) (int, error)
+}
+
+type trainingBalanceHandler struct {
+	readModel TrainingBalanceReadModel
+}
+
+func NewTrainingBalanceHandler(
+	readModel TrainingBalanceReadModel,
+	logger *logrus.Entry,
+	metricsClient decorator.MetricsClient,
+) TrainingBalanceHandler {
+	if readModel == nil {
+		panic("nil readModel")
+	}
+
+	return decorator.ApplyQueryDecorators[TrainingBalance, int](
+		trainingBalanceHandler{readModel: readModel},
+		logger,
+		metricsClient,
+	)
+}
+
+func (h trainingBalanceHandler) Handle(ctx context.Context, query TrainingBalance) (int, error) {
+	return h.readModel.TrainingBalance(ctx, query.UserUUID)
+}
@@ -0,0 +1,86 @@
+package query
+
+import (
+	"time"
+)
+
+type LedgerEntry struct {
+	OperationID  string
+	Reason       string
+	AmountChange int
+	TrainingUUID string
+	Actor        string
+	Note         string
+	CreatedAt    time.Time
+}
+
+type CreditPackage struct {
+	ID      string
+	Name    string
+	Credits int
+
+	Price    int
+	Currency string
+}
+
+type Purchase struct {
+	UUID      string
+	UserUUID  string
+	PackageID string
+	Credits   int
+
+	Price    int
+	Currency string
+
+	Status      string
+	CheckoutURL string
+}
+
+type ExpiringCredits struct {
+	Amount    int
+	ExpiresAt time.Time
+}
+
+type User struct {
+	UUID        string
+	DisplayName string
+	Email       string
+	Role        string
+	Balance     int
+
+	Preferences UserPreferences
+}
+
+type UserPreferences struct {
+	TimeZone            string
+	Locale              string
+	NotificationChannel string
+	// ReminderLeadTime is zero, when the user doesn't want to be reminded about trainings
+	ReminderLeadTime time.Duration
+}
+
+type Session struct {
+	ID         string
+	IP         string
+	UserAgent  string
+	StartedAt  time.Time
+	LastSeenAt time.Time
+	Revoked    bool
+	Current    bool
+}
+
+type PersonalData struct {
+	// User is nil, when the user is not known to the users service
+	User      *User
+	Ledger    []LedgerEntry
+	Purchases []Purchase
+	Sessions  []Session
+}
+
+type DataArchive struct {
+	Users PersonalData
+
+	// Trainings and Trainer are JSON documents exported by these services
+	Trainings []byte
+	Trainer   []byte
+}
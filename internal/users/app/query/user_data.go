@@ -0,0 +1,101 @@
+package query
+
+import (
+	"context"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/decorator"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/domain/user"
+	"github.com/sirupsen/logrus"
+)
+
+// UserData returns all personal data of the user stored by the users service.
+type UserData struct {
+	UserUUID string
+}
+
+type UserDataHandler decorator.QueryHandler[UserData, PersonalData]
+
+type UserDataReadModel interface {
+	UserDetailsReadModel
+	LedgerReadModel
+}
+
+type UserPurchasesReadModel interface {
+	UserPurchases(ctx context.Context, userUUID string) ([]Purchase, error)
+}
+
+type userDataHandler struct {
+	usersReadModel     UserDataReadModel
+	purchasesReadModel UserPurchasesReadModel
+	sessionsReadModel  UserSessionsReadModel
+}
+
+func NewUserDataHandler(
+	usersReadModel UserDataReadModel,
+	purchasesReadModel UserPurchasesReadModel,
+	sessionsReadModel UserSessionsReadModel,
+	logger *logrus.Entry,
+	metricsClient decorator.MetricsClient,
+) UserDataHandler {
+	if usersReadModel == nil {
+		panic("nil usersReadModel")
+	}
+	if purchasesReadModel == nil {
+		panic("nil purchasesReadModel")
+	}
+	if sessionsReadModel == nil {
+		panic("nil sessionsReadModel")
+	}
+
+	return decorator.ApplyQueryDecorators[UserData, PersonalData](
+		userDataHandler{
+			usersReadModel:     usersReadModel,
+			purchasesReadModel: purchasesReadModel,
+			sessionsReadModel:  sessionsReadModel,
+		},
+		logger,
+		metricsClient,
+	)
+}
+
+func (h userDataHandler) Handle(ctx context.Context, query UserData) (PersonalData, error) {
+	return personalData(ctx, h.usersReadModel, h.purchasesReadModel, h.sessionsReadModel, query.UserUUID)
+}
+
+func personalData(
+	ctx context.Context,
+	usersReadModel UserDataReadModel,
+	purchasesReadModel UserPurchasesReadModel,
+	sessionsReadModel UserSessionsReadModel,
+	userUUID string,
+) (PersonalData, error) {
+	data := PersonalData{}
+
+	sessions, err := sessionsReadModel.Sessions(ctx, userUUID)
+	if err != nil {
+		return PersonalData{}, err
+	}
+	data.Sessions = sessions
+
+	u, err := usersReadModel.User(ctx, userUUID)
+	if _, ok := err.(user.NotFoundError); ok {
+		// other services may still have data of the user
+		return data, nil
+	}
+	if err != nil {
+		return PersonalData{}, err
+	}
+	data.User = &u
+
+	data.Ledger, err = usersReadModel.Ledger(ctx, userUUID)
+	if err != nil {
+		return PersonalData{}, err
+	}
+
+	data.Purchases, err = purchasesReadModel.UserPurchases(ctx, userUUID)
+	if err != nil {
+		return PersonalData{}, err
+	}
+
+	return data, nil
+}
@@ -0,0 +1,86 @@
+package query
+
+import (
+	"context"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/decorator"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// UserDataArchive gathers personal data of the user from all services.
+type UserDataArchive struct {
+	UserUUID string
+}
+
+type UserDataArchiveHandler decorator.QueryHandler[UserDataArchive, DataArchive]
+
+type userDataArchiveHandler struct {
+	usersReadModel     UserDataReadModel
+	purchasesReadModel UserPurchasesReadModel
+	sessionsReadModel  UserSessionsReadModel
+
+	trainingsService UserDataExporter
+	trainerService   UserDataExporter
+}
+
+func NewUserDataArchiveHandler(
+	usersReadModel UserDataReadModel,
+	purchasesReadModel UserPurchasesReadModel,
+	sessionsReadModel UserSessionsReadModel,
+	trainingsService UserDataExporter,
+	trainerService UserDataExporter,
+	logger *logrus.Entry,
+	metricsClient decorator.MetricsClient,
+) UserDataArchiveHandler {
+	if usersReadModel == nil {
+		panic("nil usersReadModel")
+	}
+	if purchasesReadModel == nil {
+		panic("nil purchasesReadModel")
+	}
+	if sessionsReadModel == nil {
+		panic("nil sessionsReadModel")
+	}
+	if trainingsService == nil {
+		panic("nil trainingsService")
+	}
+	if trainerService == nil {
+		panic("nil trainerService")
+	}
+
+	return decorator.ApplyQueryDecorators[UserDataArchive, DataArchive](
+		userDataArchiveHandler{
+			usersReadModel:     usersReadModel,
+			purchasesReadModel: purchasesReadModel,
+			sessionsReadModel:  sessionsReadModel,
+			trainingsService:   trainingsService,
+			trainerService:     trainerService,
+		},
+		logger,
+		metricsClient,
+	)
+}
+
+func (h userDataArchiveHandler) Handle(ctx context.Context, query UserDataArchive) (DataArchive, error) {
+	users, err := personalData(ctx, h.usersReadModel, h.purchasesReadModel, h.sessionsReadModel, query.UserUUID)
+	if err != nil {
+		return DataArchive{}, err
+	}
+
+	trainings, err := h.trainingsService.ExportUserData(ctx, query.UserUUID)
+	if err != nil {
+		return DataArchive{}, errors.NewSlugError("trainings: "+err.Error(), "unable-to-export-user-data")
+	}
+
+	trainer, err := h.trainerService.ExportUserData(ctx, query.UserUUID)
+	if err != nil {
+		return DataArchive{}, errors.NewSlugError("trainer: "+err.Error(), "unable-to-export-user-data")
+	}
+
+	return DataArchive{
+		Users:     users,
+		Trainings: trainings,
+		Trainer:   trainer,
+	}, nil
+}
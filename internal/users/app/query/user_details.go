@@ -0,0 +1,42 @@
+package query
+
+import (
+	"context"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/decorator"
+	"github.com/sirupsen/logrus"
+)
+
+type UserDetails struct {
+	UserUUID string
+}
+
+type UserDetailsHandler decorator.QueryHandler[UserDetails, User]
+
+type UserDetailsReadModel interface {
+	User(ctx context.Context, userUUID string) (User, error)
+}
+
+type userDetailsHandler struct {
+	readModel UserDetailsReadModel
+}
+
+func NewUserDetailsHandler(
+	readModel UserDetailsReadModel,
+	logger *logrus.Entry,
+	metricsClient decorator.MetricsClient,
+) UserDetailsHandler {
+	if readModel == nil {
+		panic("nil readModel")
+	}
+
+	return decorator.ApplyQueryDecorators[UserDetails, User](
+		userDetailsHandler{readModel: readModel},
+		logger,
+		metricsClient,
+	)
+}
+
+func (h userDetailsHandler) Handle(ctx context.Context, query UserDetails) (User, error) {
+	return h.readModel.User(ctx, query.UserUUID)
+}
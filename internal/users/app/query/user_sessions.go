@@ -0,0 +1,54 @@
+package query
+
+import (
+	"context"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/decorator"
+	"github.com/sirupsen/logrus"
+)
+
+type UserSessions struct {
+	UserUUID string
+	// CurrentSessionID is the session used for the request, it's marked as current in the result
+	CurrentSessionID string
+}
+
+type UserSessionsHandler decorator.QueryHandler[UserSessions, []Session]
+
+type UserSessionsReadModel interface {
+	// Sessions returns sessions of the user sorted from the most recently active.
+	Sessions(ctx context.Context, userUUID string) ([]Session, error)
+}
+
+type userSessionsHandler struct {
+	readModel UserSessionsReadModel
+}
+
+func NewUserSessionsHandler(
+	readModel UserSessionsReadModel,
+	logger *logrus.Entry,
+	metricsClient decorator.MetricsClient,
+) UserSessionsHandler {
+	if readModel == nil {
+		panic("nil readModel")
+	}
+
+	return decorator.ApplyQueryDecorators[UserSessions, []Session](
+		userSessionsHandler{readModel: readModel},
+		logger,
+		metricsClient,
+	)
+}
+
+func (h userSessionsHandler) Handle(ctx context.Context, query UserSessions) ([]Session, error) {
+	sessions, err := h.readModel.Sessions(ctx, query.UserUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range sessions {
+		sessions[i].Current = query.CurrentSessionID != "" && sessions[i].ID == query.CurrentSessionID
+	}
+
+	return sessions, nil
+}
@@ -0,0 +1,57 @@
+package query
+
+import (
+	"context"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/decorator"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultUsersLimit = 50
+	maxUsersLimit     = 500
+)
+
+type Users struct {
+	// Search is matched against the display name and the email, empty Search returns all users
+	Search string
+	Limit  int
+}
+
+type UsersHandler decorator.QueryHandler[Users, []User]
+
+type UsersReadModel interface {
+	Users(ctx context.Context, search string, limit int) ([]User, error)
+}
+
+type usersHandler struct {
+	readModel UsersReadModel
+}
+
+func NewUsersHandler(
+	readModel UsersReadModel,
+	logger *logrus.Entry,
+	metricsClient decorator.MetricsClient,
+) UsersHandler {
+	if readModel == nil {
+		panic("nil readModel")
+	}
+
+	return decorator.ApplyQueryDecorators[Users, []User](
+		usersHandler{readModel: readModel},
+		logger,
+		metricsClient,
+	)
+}
+
+func (h usersHandler) Handle(ctx context.Context, query Users) ([]User, error) {
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultUsersLimit
+	}
+	if limit > maxUsersLimit {
+		limit = maxUsersLimit
+	}
+
+	return h.readModel.Users(ctx, query.Search, limit)
+}
@@ -0,0 +1,66 @@
+package query
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/decorator"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// UsersByUUIDs returns users with the given UUIDs, users that don't exist are omitted.
+type UsersByUUIDs struct {
+	UserUUIDs []string
+}
+
+type UsersByUUIDsHandler decorator.QueryHandler[UsersByUUIDs, []User]
+
+type UsersByUUIDsReadModel interface {
+	UsersByUUIDs(ctx context.Context, userUUIDs []string) ([]User, error)
+}
+
+type usersByUUIDsHandler struct {
+	readModel UsersByUUIDsReadModel
+}
+
+func NewUsersByUUIDsHandler(
+	readModel UsersByUUIDsReadModel,
+	logger *logrus.Entry,
+	metricsClient decorator.MetricsClient,
+) UsersByUUIDsHandler {
+	if readModel == nil {
+		panic("nil readModel")
+	}
+
+	return decorator.ApplyQueryDecorators[UsersByUUIDs, []User](
+		usersByUUIDsHandler{readModel: readModel},
+		logger,
+		metricsClient,
+	)
+}
+
+func (h usersByUUIDsHandler) Handle(ctx context.Context, query UsersByUUIDs) ([]User, error) {
+	uniqueUUIDs := make([]string, 0, len(query.UserUUIDs))
+	seen := map[string]struct{}{}
+	for _, userUUID := range query.UserUUIDs {
+		if _, ok := seen[userUUID]; ok || userUUID == "" {
+			continue
+		}
+		seen[userUUID] = struct{}{}
+		uniqueUUIDs = append(uniqueUUIDs, userUUID)
+	}
+
+	if len(uniqueUUIDs) == 0 {
+		return []User{}, nil
+	}
+	// the limit is checked after removing duplicates, so callers can pass UUIDs of users of all their items
+	if len(uniqueUUIDs) > maxUsersLimit {
+		return nil, errors.NewIncorrectInputError(
+			fmt.Sprintf("up to %d users can be fetched at once, requested %d", maxUsersLimit, len(uniqueUUIDs)),
+			"too-many-users-requested",
+		)
+	}
+
+	return h.readModel.UsersByUUIDs(ctx, uniqueUUIDs)
+}
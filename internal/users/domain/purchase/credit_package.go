@@ -0,0 +1,128 @@
+package purchase
+
+import (
+	"fmt"
+
+	commonerrors "github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/errors"
+	"github.com/pkg/errors"
+)
+
+// CreditPackage is a number of credits (trainings) sold together.
+type CreditPackage struct {
+	id   string
+	name string
+
+	credits int
+
+	// price is in the smallest currency unit, for example in cents
+	price    int
+	currency string
+}
+
+func NewCreditPackage(id string, name string, credits int, price int, currency string) (CreditPackage, error) {
+	if id == "" {
+		return CreditPackage{}, errors.New("empty credit package id")
+	}
+	if name == "" {
+		return CreditPackage{}, errors.New("empty credit package name")
+	}
+	if credits <= 0 {
+		return CreditPackage{}, errors.Errorf("credits must be greater than 0, is %d", credits)
+	}
+	if price <= 0 {
+		return CreditPackage{}, errors.Errorf("price must be greater than 0, is %d", price)
+	}
+	if len(currency) != 3 {
+		return CreditPackage{}, errors.Errorf("invalid currency '%s', ISO 4217 code expected", currency)
+	}
+
+	return CreditPackage{
+		id:       id,
+		name:     name,
+		credits:  credits,
+		price:    price,
+		currency: currency,
+	}, nil
+}
+
+// MustNewCreditPackage creates a new CreditPackage and panics on invalid arguments.
+// It should be used only for packages defined in the code.
+func MustNewCreditPackage(id string, name string, credits int, price int, currency string) CreditPackage {
+	p, err := NewCreditPackage(id, name, credits, price, currency)
+	if err != nil {
+		panic(err)
+	}
+
+	return p
+}
+
+func (p CreditPackage) ID() string {
+	return p.id
+}
+
+func (p CreditPackage) Name() string {
+	return p.name
+}
+
+func (p CreditPackage) Credits() int {
+	return p.credits
+}
+
+func (p CreditPackage) Price() int {
+	return p.price
+}
+
+func (p CreditPackage) Currency() string {
+	return p.currency
+}
+
+// Catalogue contains all credit packages, which can be bought.
+type Catalogue struct {
+	packages []CreditPackage
+}
+
+func NewCatalogue(packages []CreditPackage) (Catalogue, error) {
+	if len(packages) == 0 {
+		return Catalogue{}, errors.New("catalogue must contain at least one credit package")
+	}
+
+	ids := map[string]struct{}{}
+	for _, p := range packages {
+		if _, ok := ids[p.ID()]; ok {
+			return Catalogue{}, errors.Errorf("duplicated credit package id '%s'", p.ID())
+		}
+		ids[p.ID()] = struct{}{}
+	}
+
+	return Catalogue{packages: packages}, nil
+}
+
+func (c Catalogue) Packages() []CreditPackage {
+	return c.packages
+}
+
+type CreditPackageNotFoundError struct {
+	PackageID string
+}
+
+func (e CreditPackageNotFoundError) Error() string {
+	return fmt.Sprintf("credit package '%s' not found", e.PackageID)
+}
+
+func (e CreditPackageNotFoundError) Slug() string {
+	return "credit-package-not-found"
+}
+
+func (e CreditPackageNotFoundError) ErrorType() commonerrors.ErrorType {
+	return commonerrors.ErrorTypeNotFound
+}
+
+func (c Catalogue) Package(id string) (CreditPackage, error) {
+	for _, p := range c.packages {
+		if p.ID() == id {
+			return p, nil
+		}
+	}
+
+	return CreditPackage{}, CreditPackageNotFoundError{PackageID: id}
+}
@@ -0,0 +1,75 @@
+package purchase_test
+
+import (
+	"testing"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/domain/purchase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCreditPackage(t *testing.T) {
+	t.Parallel()
+
+	p, err := purchase.NewCreditPackage("10-trainings", "10 trainings", 10, 9900, "EUR")
+	require.NoError(t, err)
+
+	assert.Equal(t, "10-trainings", p.ID())
+	assert.Equal(t, "10 trainings", p.Name())
+	assert.Equal(t, 10, p.Credits())
+	assert.Equal(t, 9900, p.Price())
+	assert.Equal(t, "EUR", p.Currency())
+}
+
+func TestNewCreditPackage_invalid(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		Name     string
+		ID       string
+		Credits  int
+		Price    int
+		Currency string
+	}{
+		{Name: "empty_id", ID: "", Credits: 1, Price: 100, Currency: "EUR"},
+		{Name: "zero_credits", ID: "id", Credits: 0, Price: 100, Currency: "EUR"},
+		{Name: "zero_price", ID: "id", Credits: 1, Price: 0, Currency: "EUR"},
+		{Name: "invalid_currency", ID: "id", Credits: 1, Price: 100, Currency: "EURO"},
+	}
+
+	for _, c := range testCases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := purchase.NewCreditPackage(c.ID, "name", c.Credits, c.Price, c.Currency)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCatalogue_Package(t *testing.T) {
+	t.Parallel()
+
+	single := purchase.MustNewCreditPackage("single", "Single training", 1, 1500, "EUR")
+	ten := purchase.MustNewCreditPackage("ten", "10 trainings", 10, 12000, "EUR")
+
+	catalogue, err := purchase.NewCatalogue([]purchase.CreditPackage{single, ten})
+	require.NoError(t, err)
+
+	p, err := catalogue.Package("ten")
+	require.NoError(t, err)
+	assert.Equal(t, ten, p)
+
+	_, err = catalogue.Package("hundred")
+	assert.Equal(t, purchase.CreditPackageNotFoundError{PackageID: "hundred"}, err)
+}
+
+func TestNewCatalogue_duplicated_package(t *testing.T) {
+	t.Parallel()
+
+	single := purchase.MustNewCreditPackage("single", "Single training", 1, 1500, "EUR")
+
+	_, err := purchase.NewCatalogue([]purchase.CreditPackage{single, single})
+	assert.Error(t, err)
+}
@@ -0,0 +1,181 @@
+package purchase
+
+import (
+	commonerrors "github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/errors"
+	"github.com/pkg/errors"
+)
+
+// Status is enum-like type.
+type Status struct {
+	s string
+}
+
+var (
+	Pending = Status{"pending"}
+	Paid    = Status{"paid"}
+	Failed  = Status{"failed"}
+)
+
+var statusValues = []Status{
+	Pending,
+	Paid,
+	Failed,
+}
+
+func NewStatusFromString(statusStr string) (Status, error) {
+	for _, status := range statusValues {
+		if status.String() == statusStr {
+			return status, nil
+		}
+	}
+	return Status{}, errors.Errorf("unknown '%s' purchase status", statusStr)
+}
+
+func (s Status) IsZero() bool {
+	return s == Status{}
+}
+
+func (s Status) String() string {
+	return s.s
+}
+
+// Purchase is a single checkout of the credit package.
+//
+// Credits are granted, when the payment provider confirms the payment.
+type Purchase struct {
+	uuid     string
+	userUUID string
+
+	packageID string
+	credits   int
+	price     int
+	currency  string
+
+	status      Status
+	checkoutURL string
+}
+
+func NewPurchase(uuid string, userUUID string, creditPackage CreditPackage, checkoutURL string) (*Purchase, error) {
+	if uuid == "" {
+		return nil, errors.New("empty purchase uuid")
+	}
+	if userUUID == "" {
+		return nil, errors.New("empty user uuid")
+	}
+	if creditPackage.ID() == "" {
+		return nil, errors.New("empty credit package")
+	}
+	if checkoutURL == "" {
+		return nil, errors.New("empty checkout url")
+	}
+
+	return &Purchase{
+		uuid:        uuid,
+		userUUID:    userUUID,
+		packageID:   creditPackage.ID(),
+		credits:     creditPackage.Credits(),
+		price:       creditPackage.Price(),
+		currency:    creditPackage.Currency(),
+		status:      Pending,
+		checkoutURL: checkoutURL,
+	}, nil
+}
+
+// UnmarshalPurchaseFromDatabase unmarshals Purchase from the database.
+//
+// It should be used only for unmarshalling from the database!
+// You can't use UnmarshalPurchaseFromDatabase as constructor - It may put domain into the invalid state!
+func UnmarshalPurchaseFromDatabase(
+	uuid string,
+	userUUID string,
+	packageID string,
+	credits int,
+	price int,
+	currency string,
+	status Status,
+	checkoutURL string,
+) (*Purchase, error) {
+	if uuid == "" {
+		return nil, errors.New("empty purchase uuid")
+	}
+	if status.IsZero() {
+		return nil, errors.New("empty purchase status")
+	}
+
+	return &Purchase{
+		uuid:        uuid,
+		userUUID:    userUUID,
+		packageID:   packageID,
+		credits:     credits,
+		price:       price,
+		currency:    currency,
+		status:      status,
+		checkoutURL: checkoutURL,
+	}, nil
+}
+
+func (p Purchase) UUID() string {
+	return p.uuid
+}
+
+func (p Purchase) UserUUID() string {
+	return p.userUUID
+}
+
+func (p Purchase) PackageID() string {
+	return p.packageID
+}
+
+// Credits are copied from the package at the moment of the checkout,
+// so changes of the catalogue don't affect already started purchases.
+func (p Purchase) Credits() int {
+	return p.credits
+}
+
+func (p Purchase) Price() int {
+	return p.price
+}
+
+func (p Purchase) Currency() string {
+	return p.currency
+}
+
+func (p Purchase) Status() Status {
+	return p.status
+}
+
+func (p Purchase) CheckoutURL() string {
+	return p.checkoutURL
+}
+
+// CreditsOperationID is the operation ID of the ledger entry granting credits,
+// thanks to that credits are granted only once, even if the payment confirmation is delivered multiple times.
+func (p Purchase) CreditsOperationID() string {
+	return "purchase-" + p.uuid
+}
+
+var ErrPurchaseAlreadyFailed = commonerrors.NewConflictError("purchase has already failed", "purchase-already-failed")
+
+func (p *Purchase) MarkAsPaid() error {
+	if p.status == Failed {
+		return ErrPurchaseAlreadyFailed
+	}
+
+	p.status = Paid
+	return nil
+}
+
+var ErrPurchaseAlreadyPaid = commonerrors.NewConflictError("purchase is already paid", "purchase-already-paid")
+
+func (p *Purchase) MarkAsFailed() error {
+	if p.status == Paid {
+		return ErrPurchaseAlreadyPaid
+	}
+
+	p.status = Failed
+	return nil
+}
+
+func (p Purchase) IsPaid() bool {
+	return p.status == Paid
+}
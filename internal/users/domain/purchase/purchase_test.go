@@ -0,0 +1,67 @@
+package purchase_test
+
+import (
+	"testing"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/domain/purchase"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPurchase(t *testing.T) {
+	t.Parallel()
+
+	purchaseUUID := uuid.New().String()
+	userUUID := uuid.New().String()
+	creditPackage := purchase.MustNewCreditPackage("ten", "10 trainings", 10, 12000, "EUR")
+
+	p, err := purchase.NewPurchase(purchaseUUID, userUUID, creditPackage, "https://example.com/checkout")
+	require.NoError(t, err)
+
+	assert.Equal(t, purchaseUUID, p.UUID())
+	assert.Equal(t, userUUID, p.UserUUID())
+	assert.Equal(t, "ten", p.PackageID())
+	assert.Equal(t, 10, p.Credits())
+	assert.Equal(t, 12000, p.Price())
+	assert.Equal(t, "EUR", p.Currency())
+	assert.Equal(t, purchase.Pending, p.Status())
+	assert.Equal(t, "https://example.com/checkout", p.CheckoutURL())
+	assert.Equal(t, "purchase-"+purchaseUUID, p.CreditsOperationID())
+}
+
+func TestPurchase_MarkAsPaid(t *testing.T) {
+	t.Parallel()
+	p := newExamplePurchase(t)
+
+	require.NoError(t, p.MarkAsPaid())
+	assert.True(t, p.IsPaid())
+
+	// payment confirmation may be delivered more than once
+	require.NoError(t, p.MarkAsPaid())
+	assert.True(t, p.IsPaid())
+
+	assert.Equal(t, purchase.ErrPurchaseAlreadyPaid, p.MarkAsFailed())
+}
+
+func TestPurchase_MarkAsFailed(t *testing.T) {
+	t.Parallel()
+	p := newExamplePurchase(t)
+
+	require.NoError(t, p.MarkAsFailed())
+	assert.Equal(t, purchase.Failed, p.Status())
+
+	assert.Equal(t, purchase.ErrPurchaseAlreadyFailed, p.MarkAsPaid())
+	assert.False(t, p.IsPaid())
+}
+
+func newExamplePurchase(t *testing.T) *purchase.Purchase {
+	t.Helper()
+
+	creditPackage := purchase.MustNewCreditPackage("ten", "10 trainings", 10, 12000, "EUR")
+
+	p, err := purchase.NewPurchase(uuid.New().String(), uuid.New().String(), creditPackage, "https://example.com/checkout")
+	require.NoError(t, err)
+
+	return p
+}
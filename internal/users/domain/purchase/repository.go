@@ -0,0 +1,36 @@
+package purchase
+
+import (
+	"context"
+	"fmt"
+
+	commonerrors "github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/errors"
+)
+
+type NotFoundError struct {
+	PurchaseUUID string
+}
+
+func (e NotFoundError) Error() string {
+	return fmt.Sprintf("purchase '%s' not found", e.PurchaseUUID)
+}
+
+func (e NotFoundError) Slug() string {
+	return "purchase-not-found"
+}
+
+func (e NotFoundError) ErrorType() commonerrors.ErrorType {
+	return commonerrors.ErrorTypeNotFound
+}
+
+type Repository interface {
+	AddPurchase(ctx context.Context, p *Purchase) error
+
+	GetPurchase(ctx context.Context, purchaseUUID string) (*Purchase, error)
+
+	UpdatePurchase(
+		ctx context.Context,
+		purchaseUUID string,
+		updateFn func(ctx context.Context, p *Purchase) (*Purchase, error),
+	) error
+}
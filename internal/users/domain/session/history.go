@@ -0,0 +1,195 @@
+package session
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	commonerrors "github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/errors"
+	"github.com/pkg/errors"
+)
+
+// MaxSessionsPerUser is the limit of sessions kept in the history, the least recently active sessions are removed first.
+const MaxSessionsPerUser = 20
+
+// activityResolution is how often the activity of the same session is recorded.
+// Recording every request would require a write for every request done by the user.
+const activityResolution = 5 * time.Minute
+
+type RevokedError struct {
+	SessionID string
+}
+
+func (e RevokedError) Error() string {
+	return fmt.Sprintf("session '%s' was revoked", e.SessionID)
+}
+
+func (e RevokedError) Slug() string {
+	return "session-revoked"
+}
+
+func (e RevokedError) ErrorType() commonerrors.ErrorType {
+	return commonerrors.ErrorTypeAuthorization
+}
+
+type NotFoundError struct {
+	SessionID string
+}
+
+func (e NotFoundError) Error() string {
+	return fmt.Sprintf("session '%s' not found", e.SessionID)
+}
+
+func (e NotFoundError) Slug() string {
+	return "session-not-found"
+}
+
+func (e NotFoundError) ErrorType() commonerrors.ErrorType {
+	return commonerrors.ErrorTypeNotFound
+}
+
+// History contains recent sessions of the user.
+type History struct {
+	userUUID string
+
+	// sessions are sorted from the most recently active
+	sessions []Session
+}
+
+func NewHistory(userUUID string) (*History, error) {
+	if userUUID == "" {
+		return nil, errors.New("empty user uuid")
+	}
+
+	return &History{userUUID: userUUID}, nil
+}
+
+// UnmarshalHistoryFromDatabase unmarshals History from the database.
+//
+// It should be used only for unmarshalling from the database!
+// You can't use UnmarshalHistoryFromDatabase as constructor - It may put domain into the invalid state!
+func UnmarshalHistoryFromDatabase(userUUID string, sessions []Session) (*History, error) {
+	h, err := NewHistory(userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	h.sessions = append([]Session(nil), sessions...)
+	h.sortSessions()
+
+	return h, nil
+}
+
+func (h History) UserUUID() string {
+	return h.userUUID
+}
+
+// Sessions returns sessions sorted from the most recently active.
+func (h History) Sessions() []Session {
+	return h.sessions
+}
+
+// ShouldRecordActivity returns true, when RecordActivity would change the history.
+// It allows to skip writing the history for most of the requests.
+func (h History) ShouldRecordActivity(sessionID string, ip string, userAgent string, at time.Time) (bool, error) {
+	i, ok := h.findSession(sessionID)
+	if !ok {
+		return true, nil
+	}
+
+	s := h.sessions[i]
+	if s.IsRevoked() {
+		return false, RevokedError{SessionID: sessionID}
+	}
+
+	if s.ip != ip || s.userAgent != userAgent {
+		return true, nil
+	}
+
+	return at.Sub(s.lastSeenAt) >= activityResolution, nil
+}
+
+// RecordActivity records that the session was used at the given time.
+// New sessions are added to the history, RevokedError is returned for revoked sessions.
+func (h *History) RecordActivity(sessionID string, ip string, userAgent string, at time.Time) error {
+	if i, ok := h.findSession(sessionID); ok {
+		s := &h.sessions[i]
+		if s.IsRevoked() {
+			return RevokedError{SessionID: sessionID}
+		}
+
+		s.ip = ip
+		s.userAgent = userAgent
+		if at.After(s.lastSeenAt) {
+			s.lastSeenAt = at
+		}
+
+		h.sortSessions()
+
+		return nil
+	}
+
+	s, err := newSession(sessionID, ip, userAgent, at)
+	if err != nil {
+		return err
+	}
+
+	h.sessions = append(h.sessions, s)
+	h.sortSessions()
+	h.removeExcessSessions()
+
+	return nil
+}
+
+// Revoke revokes the session, revoking already revoked session is a no-op.
+func (h *History) Revoke(sessionID string, at time.Time) error {
+	i, ok := h.findSession(sessionID)
+	if !ok {
+		return NotFoundError{SessionID: sessionID}
+	}
+
+	if !h.sessions[i].IsRevoked() {
+		h.sessions[i].revokedAt = at
+	}
+
+	return nil
+}
+
+// Clear removes all sessions, it's used when the user's personal data is erased.
+func (h *History) Clear() {
+	h.sessions = nil
+}
+
+func (h History) findSession(sessionID string) (int, bool) {
+	for i, s := range h.sessions {
+		if s.id == sessionID {
+			return i, true
+		}
+	}
+
+	return 0, false
+}
+
+func (h *History) sortSessions() {
+	sort.SliceStable(h.sessions, func(i, j int) bool {
+		return h.sessions[i].lastSeenAt.After(h.sessions[j].lastSeenAt)
+	})
+}
+
+// removeExcessSessions removes the least recently active sessions above MaxSessionsPerUser.
+//
+// Revoked sessions are removed only when there are no active sessions to remove,
+// because a session removed from the history can't be recognised as revoked anymore.
+func (h *History) removeExcessSessions() {
+	for len(h.sessions) > MaxSessionsPerUser {
+		toRemove := len(h.sessions) - 1
+		for i := len(h.sessions) - 1; i >= 0; i-- {
+			if !h.sessions[i].IsRevoked() {
+				toRemove = i
+				break
+			}
+		}
+
+		h.sessions = append(h.sessions[:toRemove], h.sessions[toRemove+1:]...)
+	}
+}
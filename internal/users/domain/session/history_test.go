@@ -0,0 +1,165 @@
+package session_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/domain/session"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistory_RecordActivity(t *testing.T) {
+	t.Parallel()
+	h := newExampleHistory(t)
+	now := time.Now()
+
+	require.NoError(t, h.RecordActivity("first", "127.0.0.1", "Firefox", now.Add(-time.Hour)))
+	require.NoError(t, h.RecordActivity("second", "127.0.0.2", "Chrome", now.Add(-time.Minute)))
+	require.NoError(t, h.RecordActivity("first", "127.0.0.3", "Firefox", now))
+
+	sessions := h.Sessions()
+	require.Len(t, sessions, 2)
+
+	assert.Equal(t, "first", sessions[0].ID())
+	assert.Equal(t, "127.0.0.3", sessions[0].IP())
+	assert.Equal(t, now.Add(-time.Hour), sessions[0].StartedAt())
+	assert.Equal(t, now, sessions[0].LastSeenAt())
+
+	assert.Equal(t, "second", sessions[1].ID())
+	assert.Equal(t, "Chrome", sessions[1].UserAgent())
+}
+
+func TestHistory_RecordActivity_empty_session_id(t *testing.T) {
+	t.Parallel()
+	h := newExampleHistory(t)
+
+	assert.Error(t, h.RecordActivity("", "127.0.0.1", "Firefox", time.Now()))
+	assert.Empty(t, h.Sessions())
+}
+
+func TestHistory_RecordActivity_bounded(t *testing.T) {
+	t.Parallel()
+	h := newExampleHistory(t)
+	start := time.Now().Add(-time.Hour)
+
+	require.NoError(t, h.RecordActivity("revoked", "127.0.0.1", "Firefox", start))
+	require.NoError(t, h.Revoke("revoked", start))
+
+	for i := 0; i < session.MaxSessionsPerUser+5; i++ {
+		sessionID := fmt.Sprintf("session-%d", i)
+		require.NoError(t, h.RecordActivity(sessionID, "127.0.0.1", "Firefox", start.Add(time.Duration(i+1)*time.Minute)))
+	}
+
+	sessions := h.Sessions()
+	require.Len(t, sessions, session.MaxSessionsPerUser)
+	assert.Equal(t, fmt.Sprintf("session-%d", session.MaxSessionsPerUser+4), sessions[0].ID())
+
+	// the revoked session is kept, so it can't be used again
+	assert.Equal(t, "revoked", sessions[len(sessions)-1].ID())
+	assert.Equal(t, "session-6", sessions[len(sessions)-2].ID())
+}
+
+func TestHistory_Revoke(t *testing.T) {
+	t.Parallel()
+	h := newExampleHistory(t)
+	now := time.Now()
+
+	require.NoError(t, h.RecordActivity("session", "127.0.0.1", "Firefox", now))
+	require.NoError(t, h.Revoke("session", now))
+	require.NoError(t, h.Revoke("session", now.Add(time.Minute)), "revoking should be idempotent")
+
+	assert.True(t, h.Sessions()[0].IsRevoked())
+	assert.Equal(t, now, h.Sessions()[0].RevokedAt())
+
+	err := h.RecordActivity("session", "127.0.0.1", "Firefox", now.Add(time.Hour))
+	assert.Equal(t, session.RevokedError{SessionID: "session"}, err)
+
+	_, err = h.ShouldRecordActivity("session", "127.0.0.1", "Firefox", now.Add(time.Hour))
+	assert.Equal(t, session.RevokedError{SessionID: "session"}, err)
+
+	err = h.Revoke("not-existing", now)
+	assert.Equal(t, session.NotFoundError{SessionID: "not-existing"}, err)
+}
+
+func TestHistory_ShouldRecordActivity(t *testing.T) {
+	t.Parallel()
+	h := newExampleHistory(t)
+	now := time.Now()
+
+	require.NoError(t, h.RecordActivity("session", "127.0.0.1", "Firefox", now))
+
+	testCases := []struct {
+		Name      string
+		SessionID string
+		IP        string
+		UserAgent string
+		At        time.Time
+		Expected  bool
+	}{
+		{
+			Name:      "recently_recorded",
+			SessionID: "session",
+			IP:        "127.0.0.1",
+			UserAgent: "Firefox",
+			At:        now.Add(time.Minute),
+			Expected:  false,
+		},
+		{
+			Name:      "not_recorded_for_long_time",
+			SessionID: "session",
+			IP:        "127.0.0.1",
+			UserAgent: "Firefox",
+			At:        now.Add(time.Hour),
+			Expected:  true,
+		},
+		{
+			Name:      "ip_changed",
+			SessionID: "session",
+			IP:        "127.0.0.2",
+			UserAgent: "Firefox",
+			At:        now.Add(time.Minute),
+			Expected:  true,
+		},
+		{
+			Name:      "new_session",
+			SessionID: "other-session",
+			IP:        "127.0.0.1",
+			UserAgent: "Firefox",
+			At:        now,
+			Expected:  true,
+		},
+	}
+
+	for _, c := range testCases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			t.Parallel()
+
+			shouldRecord, err := h.ShouldRecordActivity(c.SessionID, c.IP, c.UserAgent, c.At)
+			require.NoError(t, err)
+			assert.Equal(t, c.Expected, shouldRecord)
+		})
+	}
+}
+
+func TestHistory_Clear(t *testing.T) {
+	t.Parallel()
+	h := newExampleHistory(t)
+
+	require.NoError(t, h.RecordActivity("session", "127.0.0.1", "Firefox", time.Now()))
+	h.Clear()
+
+	assert.Empty(t, h.Sessions())
+}
+
+func newExampleHistory(t *testing.T) *session.History {
+	t.Helper()
+
+	h, err := session.NewHistory(uuid.New().String())
+	require.NoError(t, err)
+
+	return h
+}
@@ -0,0 +1,16 @@
+package session
+
+import (
+	"context"
+)
+
+type Repository interface {
+	// GetHistory returns the history of the user, it's empty when no session was recorded yet.
+	GetHistory(ctx context.Context, userUUID string) (*History, error)
+
+	UpdateHistory(
+		ctx context.Context,
+		userUUID string,
+		updateFn func(ctx context.Context, h *History) (*History, error),
+	) error
+}
@@ -0,0 +1,87 @@
+package session
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Session is a single sign-in of the user, tokens refreshed from the same sign-in belong to the same session.
+type Session struct {
+	id        string
+	ip        string
+	userAgent string
+
+	startedAt  time.Time
+	lastSeenAt time.Time
+	revokedAt  time.Time
+}
+
+func newSession(id string, ip string, userAgent string, at time.Time) (Session, error) {
+	if id == "" {
+		return Session{}, errors.New("empty session id")
+	}
+	if at.IsZero() {
+		return Session{}, errors.New("zero session time")
+	}
+
+	return Session{
+		id:         id,
+		ip:         ip,
+		userAgent:  userAgent,
+		startedAt:  at,
+		lastSeenAt: at,
+	}, nil
+}
+
+// UnmarshalSessionFromDatabase unmarshals Session from the database.
+//
+// It should be used only for unmarshalling from the database!
+// You can't use UnmarshalSessionFromDatabase as constructor - It may put domain into the invalid state!
+func UnmarshalSessionFromDatabase(
+	id string,
+	ip string,
+	userAgent string,
+	startedAt time.Time,
+	lastSeenAt time.Time,
+	revokedAt time.Time,
+) (Session, error) {
+	s, err := newSession(id, ip, userAgent, startedAt)
+	if err != nil {
+		return Session{}, err
+	}
+
+	s.lastSeenAt = lastSeenAt
+	s.revokedAt = revokedAt
+
+	return s, nil
+}
+
+func (s Session) ID() string {
+	return s.id
+}
+
+func (s Session) IP() string {
+	return s.ip
+}
+
+func (s Session) UserAgent() string {
+	return s.userAgent
+}
+
+func (s Session) StartedAt() time.Time {
+	return s.startedAt
+}
+
+func (s Session) LastSeenAt() time.Time {
+	return s.lastSeenAt
+}
+
+// RevokedAt returns zero time, when the session is not revoked.
+func (s Session) RevokedAt() time.Time {
+	return s.revokedAt
+}
+
+func (s Session) IsRevoked() bool {
+	return !s.revokedAt.IsZero()
+}
@@ -0,0 +1,149 @@
+package user
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// creditsValidityMonths is how long granted credits can be used.
+const creditsValidityMonths = 3
+
+// legacyCreditBatchID is the ID of credits granted before credit batches were introduced.
+const legacyCreditBatchID = "legacy"
+
+// CreditBatch is a group of credits granted at the same time, which expire together.
+type CreditBatch struct {
+	id string
+
+	grantedAt time.Time
+	// expiresAt is zero for legacy credits, which never expire
+	expiresAt time.Time
+
+	granted   int
+	remaining int
+	expired   bool
+
+	// bookedTrainings are UUIDs of trainings booked with credits from this batch,
+	// credits are refunded to the same batch when the training is canceled.
+	bookedTrainings []string
+}
+
+func newCreditBatch(id string, grantedAt time.Time, amount int) CreditBatch {
+	return CreditBatch{
+		id:        id,
+		grantedAt: grantedAt,
+		expiresAt: grantedAt.AddDate(0, creditsValidityMonths, 0),
+		granted:   amount,
+		remaining: amount,
+	}
+}
+
+// NewLegacyCreditBatch creates batch for balance stored before credit batches were introduced.
+// Legacy credits never expire.
+func NewLegacyCreditBatch(balance int) (CreditBatch, error) {
+	if balance <= 0 {
+		return CreditBatch{}, errors.Errorf("legacy balance must be greater than 0, is %d", balance)
+	}
+
+	return CreditBatch{
+		id:        legacyCreditBatchID,
+		granted:   balance,
+		remaining: balance,
+	}, nil
+}
+
+// UnmarshalCreditBatchFromDatabase unmarshals CreditBatch from the database.
+//
+// It should be used only for unmarshalling from the database!
+func UnmarshalCreditBatchFromDatabase(
+	id string,
+	grantedAt time.Time,
+	expiresAt time.Time,
+	granted int,
+	remaining int,
+	expired bool,
+	bookedTrainings []string,
+) (CreditBatch, error) {
+	if id == "" {
+		return CreditBatch{}, errors.New("empty credit batch id")
+	}
+	if remaining < 0 {
+		return CreditBatch{}, errors.Errorf("remaining credits cannot be negative, is %d", remaining)
+	}
+
+	return CreditBatch{
+		id:              id,
+		grantedAt:       grantedAt,
+		expiresAt:       expiresAt,
+		granted:         granted,
+		remaining:       remaining,
+		expired:         expired,
+		bookedTrainings: bookedTrainings,
+	}, nil
+}
+
+func (b CreditBatch) ID() string {
+	return b.id
+}
+
+func (b CreditBatch) GrantedAt() time.Time {
+	return b.grantedAt
+}
+
+func (b CreditBatch) ExpiresAt() time.Time {
+	return b.expiresAt
+}
+
+func (b CreditBatch) Granted() int {
+	return b.granted
+}
+
+func (b CreditBatch) Remaining() int {
+	return b.remaining
+}
+
+func (b CreditBatch) Expired() bool {
+	return b.expired
+}
+
+func (b CreditBatch) BookedTrainings() []string {
+	return b.bookedTrainings
+}
+
+func (b CreditBatch) neverExpires() bool {
+	return b.expiresAt.IsZero()
+}
+
+// isExpiredAt returns true if credits from the batch can't be used at t anymore.
+func (b CreditBatch) isExpiredAt(t time.Time) bool {
+	if b.expired {
+		return true
+	}
+	if b.neverExpires() {
+		return false
+	}
+
+	return !t.Before(b.expiresAt)
+}
+
+func (b CreditBatch) hasBookedTraining(trainingUUID string) bool {
+	for _, uuid := range b.bookedTrainings {
+		if uuid == trainingUUID {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (b *CreditBatch) removeBookedTraining(trainingUUID string) {
+	var bookedTrainings []string
+	for _, uuid := range b.bookedTrainings {
+		if uuid != trainingUUID {
+			bookedTrainings = append(bookedTrainings, uuid)
+		}
+	}
+
+	b.bookedTrainings = bookedTrainings
+}
@@ -0,0 +1,211 @@
+package user_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/domain/user"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var creditsGrantedAt = time.Date(2021, 1, 1, 10, 0, 0, 0, time.UTC)
+
+func TestUser_ChangeBalance_grant_creates_batch(t *testing.T) {
+	t.Parallel()
+	u := newUserWithLegacyBalance(t, 0)
+
+	grant := newLedgerEntryAt(t, user.Purchase, 10, "", creditsGrantedAt)
+	require.NoError(t, u.ChangeBalance(grant))
+
+	require.Len(t, u.CreditBatches(), 1)
+	batch := u.CreditBatches()[0]
+	assert.Equal(t, grant.OperationID(), batch.ID())
+	assert.Equal(t, 10, batch.Remaining())
+	assert.Equal(t, creditsGrantedAt, batch.GrantedAt())
+	assert.Equal(t, creditsGrantedAt.AddDate(0, 3, 0), batch.ExpiresAt())
+
+	nextExpiration, ok := u.NextCreditsExpiration()
+	require.True(t, ok)
+	assert.Equal(t, creditsGrantedAt.AddDate(0, 3, 0), nextExpiration)
+}
+
+func TestUser_ChangeBalance_uses_oldest_batch_first(t *testing.T) {
+	t.Parallel()
+	u := newUserWithLegacyBalance(t, 1)
+
+	older := newLedgerEntryAt(t, user.Purchase, 2, "", creditsGrantedAt)
+	newer := newLedgerEntryAt(t, user.Purchase, 5, "", creditsGrantedAt.AddDate(0, 1, 0))
+	require.NoError(t, u.ChangeBalance(newer))
+	require.NoError(t, u.ChangeBalance(older))
+
+	trainingUUID := uuid.New().String()
+	manualDebit := newLedgerEntryAt(t, user.ManualAdjustment, -2, "", creditsGrantedAt.AddDate(0, 1, 1))
+	booking := newLedgerEntryAt(t, user.Booking, -1, trainingUUID, creditsGrantedAt.AddDate(0, 1, 1))
+	require.NoError(t, u.ChangeBalance(manualDebit))
+	require.NoError(t, u.ChangeBalance(booking))
+
+	assert.Equal(t, map[string]int{
+		"legacy":            0,
+		older.OperationID(): 0,
+		newer.OperationID(): 5,
+	}, remainingByBatch(u))
+	assert.Equal(t, 5, u.Balance())
+
+	assert.Equal(t, []string{trainingUUID}, findBatch(t, u, older.OperationID()).BookedTrainings())
+	assert.Empty(t, findBatch(t, u, newer.OperationID()).BookedTrainings())
+}
+
+func TestUser_ChangeBalance_skips_expired_batches(t *testing.T) {
+	t.Parallel()
+	u := newUserWithLegacyBalance(t, 0)
+
+	expiring := newLedgerEntryAt(t, user.Purchase, 2, "", creditsGrantedAt)
+	valid := newLedgerEntryAt(t, user.Purchase, 2, "", creditsGrantedAt.AddDate(0, 2, 0))
+	require.NoError(t, u.ChangeBalance(expiring))
+	require.NoError(t, u.ChangeBalance(valid))
+
+	afterExpiration := creditsGrantedAt.AddDate(0, 3, 1)
+	assert.Equal(t, 4, u.Balance())
+	assert.Equal(t, 2, u.BalanceAt(afterExpiration))
+
+	booking := newLedgerEntryAt(t, user.Booking, -1, uuid.New().String(), afterExpiration)
+	require.NoError(t, u.ChangeBalance(booking))
+
+	assert.Equal(t, 1, u.Balance())
+	assert.True(t, findBatch(t, u, expiring.OperationID()).Expired())
+	assert.Equal(t, 1, findBatch(t, u, valid.OperationID()).Remaining())
+
+	expiry, ok := findLedgerEntry(u, "expiry-"+expiring.OperationID())
+	require.True(t, ok, "expiry was not recorded in the ledger")
+	assert.Equal(t, user.Expiry, expiry.Reason())
+	assert.Equal(t, -2, expiry.AmountChange())
+	assert.Equal(t, creditsGrantedAt.AddDate(0, 3, 0), expiry.CreatedAt())
+}
+
+func TestUser_ChangeBalance_expired_credits_cannot_be_used(t *testing.T) {
+	t.Parallel()
+	u := newUserWithLegacyBalance(t, 0)
+
+	require.NoError(t, u.ChangeBalance(newLedgerEntryAt(t, user.Purchase, 1, "", creditsGrantedAt)))
+
+	booking := newLedgerEntryAt(t, user.Booking, -1, uuid.New().String(), creditsGrantedAt.AddDate(0, 4, 0))
+	err := u.ChangeBalance(booking)
+
+	assert.Equal(t, user.NegativeBalanceError{CurrentBalance: 0, AmountChange: -1}, err)
+}
+
+func TestUser_ChangeBalance_cancel_refund_returns_to_booking_batch(t *testing.T) {
+	t.Parallel()
+	u := newUserWithLegacyBalance(t, 0)
+
+	older := newLedgerEntryAt(t, user.Purchase, 1, "", creditsGrantedAt)
+	newer := newLedgerEntryAt(t, user.Purchase, 1, "", creditsGrantedAt.AddDate(0, 0, 7))
+	require.NoError(t, u.ChangeBalance(older))
+	require.NoError(t, u.ChangeBalance(newer))
+
+	firstTraining := uuid.New().String()
+	secondTraining := uuid.New().String()
+	require.NoError(t, u.ChangeBalance(newLedgerEntryAt(t, user.Booking, -1, firstTraining, creditsGrantedAt.AddDate(0, 0, 8))))
+	require.NoError(t, u.ChangeBalance(newLedgerEntryAt(t, user.Booking, -1, secondTraining, creditsGrantedAt.AddDate(0, 0, 8))))
+
+	refund := newLedgerEntryAt(t, user.CancelRefund, 1, secondTraining, creditsGrantedAt.AddDate(0, 0, 9))
+	require.NoError(t, u.ChangeBalance(refund))
+
+	assert.Equal(t, map[string]int{
+		older.OperationID(): 0,
+		newer.OperationID(): 1,
+	}, remainingByBatch(u))
+	assert.Empty(t, findBatch(t, u, newer.OperationID()).BookedTrainings())
+	assert.Equal(t, []string{firstTraining}, findBatch(t, u, older.OperationID()).BookedTrainings())
+}
+
+func TestUser_ChangeBalance_cancel_refund_to_expired_batch(t *testing.T) {
+	t.Parallel()
+	u := newUserWithLegacyBalance(t, 0)
+
+	grant := newLedgerEntryAt(t, user.Purchase, 1, "", creditsGrantedAt)
+	require.NoError(t, u.ChangeBalance(grant))
+
+	trainingUUID := uuid.New().String()
+	require.NoError(t, u.ChangeBalance(newLedgerEntryAt(t, user.Booking, -1, trainingUUID, creditsGrantedAt)))
+
+	refundedAt := creditsGrantedAt.AddDate(0, 4, 0)
+	refund := newLedgerEntryAt(t, user.CancelRefund, 1, trainingUUID, refundedAt)
+	require.NoError(t, u.ChangeBalance(refund))
+
+	assert.Equal(t, 1, u.BalanceAt(refundedAt))
+
+	refundBatch := findBatch(t, u, refund.OperationID())
+	assert.Equal(t, refundedAt.AddDate(0, 3, 0), refundBatch.ExpiresAt())
+}
+
+func TestUser_ExpireCredits(t *testing.T) {
+	t.Parallel()
+	u := newUserWithLegacyBalance(t, 3)
+
+	grant := newLedgerEntryAt(t, user.Purchase, 5, "", creditsGrantedAt)
+	require.NoError(t, u.ChangeBalance(grant))
+
+	require.NoError(t, u.ExpireCredits(creditsGrantedAt.AddDate(0, 1, 0)))
+	assert.Equal(t, 8, u.Balance(), "credits expired too early")
+
+	require.NoError(t, u.ExpireCredits(creditsGrantedAt.AddDate(0, 3, 0)))
+	assert.Equal(t, 3, u.Balance(), "legacy credits should never expire")
+
+	_, ok := u.NextCreditsExpiration()
+	assert.False(t, ok)
+
+	// expiring again doesn't change anything
+	entriesCount := len(u.NewLedgerEntries())
+	require.NoError(t, u.ExpireCredits(creditsGrantedAt.AddDate(1, 0, 0)))
+	assert.Len(t, u.NewLedgerEntries(), entriesCount)
+}
+
+func newLedgerEntryAt(
+	t *testing.T,
+	reason user.LedgerReason,
+	amountChange int,
+	trainingUUID string,
+	createdAt time.Time,
+) user.LedgerEntry {
+	t.Helper()
+
+	entry, err := user.NewLedgerEntry(uuid.New().String(), reason, amountChange, trainingUUID, "actor", createdAt)
+	require.NoError(t, err)
+
+	return entry
+}
+
+func remainingByBatch(u *user.User) map[string]int {
+	remaining := map[string]int{}
+	for _, b := range u.CreditBatches() {
+		remaining[b.ID()] = b.Remaining()
+	}
+
+	return remaining
+}
+
+func findBatch(t *testing.T, u *user.User, id string) user.CreditBatch {
+	t.Helper()
+
+	for _, b := range u.CreditBatches() {
+		if b.ID() == id {
+			return b
+		}
+	}
+
+	t.Fatalf("batch %s not found", id)
+	return user.CreditBatch{}
+}
+
+func findLedgerEntry(u *user.User, operationID string) (user.LedgerEntry, bool) {
+	for _, e := range u.NewLedgerEntries() {
+		if e.OperationID() == operationID {
+			return e, true
+		}
+	}
+
+	return user.LedgerEntry{}, false
+}
@@ -0,0 +1,172 @@
+package user
+
+import (
+	"fmt"
+	"time"
+
+	commonerrors "github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/errors"
+	"github.com/pkg/errors"
+)
+
+// LedgerReason is the reason of the training balance change.
+type LedgerReason struct {
+	s string
+}
+
+var (
+	Booking          = LedgerReason{"booking"}
+	CancelRefund     = LedgerReason{"cancel_refund"}
+	TrainerPenalty   = LedgerReason{"trainer_penalty"}
+	ManualAdjustment = LedgerReason{"manual_adjustment"}
+	Purchase         = LedgerReason{"purchase"}
+	Expiry           = LedgerReason{"expiry"}
+)
+
+var ledgerReasonValues = []LedgerReason{
+	Booking,
+	CancelRefund,
+	TrainerPenalty,
+	ManualAdjustment,
+	Purchase,
+	Expiry,
+}
+
+func NewLedgerReasonFromString(reasonStr string) (LedgerReason, error) {
+	for _, reason := range ledgerReasonValues {
+		if reason.String() == reasonStr {
+			return reason, nil
+		}
+	}
+	return LedgerReason{}, errors.Errorf("unknown '%s' ledger reason", reasonStr)
+}
+
+func (r LedgerReason) IsZero() bool {
+	return r == LedgerReason{}
+}
+
+func (r LedgerReason) String() string {
+	return r.s
+}
+
+// isTrainingRelated returns true for reasons which are always caused by some training.
+func (r LedgerReason) isTrainingRelated() bool {
+	return r == Booking || r == CancelRefund || r == TrainerPenalty
+}
+
+// LedgerEntry is a single, never modified, change of the training balance.
+type LedgerEntry struct {
+	operationID  string
+	reason       LedgerReason
+	amountChange int
+	trainingUUID string
+	actor        string
+	// note is the explanation of the manual adjustment
+	note      string
+	createdAt time.Time
+}
+
+// NewLedgerEntry creates a new balance change.
+//
+// operationID should be generated by the caller, the same operation ID is applied only once.
+func NewLedgerEntry(
+	operationID string,
+	reason LedgerReason,
+	amountChange int,
+	trainingUUID string,
+	actor string,
+	createdAt time.Time,
+) (LedgerEntry, error) {
+	if operationID == "" {
+		return LedgerEntry{}, errors.New("empty operation id")
+	}
+	if reason.IsZero() {
+		return LedgerEntry{}, errors.New("empty ledger reason")
+	}
+	if amountChange == 0 {
+		return LedgerEntry{}, errors.New("amount change cannot be 0")
+	}
+	if reason.isTrainingRelated() && trainingUUID == "" {
+		return LedgerEntry{}, errors.Errorf("training uuid is required for '%s' reason", reason)
+	}
+	if actor == "" {
+		return LedgerEntry{}, errors.New("empty actor")
+	}
+	if createdAt.IsZero() {
+		return LedgerEntry{}, errors.New("zero ledger entry time")
+	}
+
+	return LedgerEntry{
+		operationID:  operationID,
+		reason:       reason,
+		amountChange: amountChange,
+		trainingUUID: trainingUUID,
+		actor:        actor,
+		createdAt:    createdAt,
+	}, nil
+}
+
+// NewManualAdjustmentLedgerEntry creates balance change done by the staff, note should explain the reason of the change.
+func NewManualAdjustmentLedgerEntry(
+	operationID string,
+	amountChange int,
+	note string,
+	actor string,
+	createdAt time.Time,
+) (LedgerEntry, error) {
+	if note == "" {
+		return LedgerEntry{}, errors.New("manual adjustment requires a note")
+	}
+
+	entry, err := NewLedgerEntry(operationID, ManualAdjustment, amountChange, "", actor, createdAt)
+	if err != nil {
+		return LedgerEntry{}, err
+	}
+
+	entry.note = note
+	return entry, nil
+}
+
+func (e LedgerEntry) OperationID() string {
+	return e.operationID
+}
+
+func (e LedgerEntry) Reason() LedgerReason {
+	return e.reason
+}
+
+func (e LedgerEntry) AmountChange() int {
+	return e.amountChange
+}
+
+func (e LedgerEntry) TrainingUUID() string {
+	return e.trainingUUID
+}
+
+func (e LedgerEntry) Actor() string {
+	return e.actor
+}
+
+func (e LedgerEntry) Note() string {
+	return e.note
+}
+
+func (e LedgerEntry) CreatedAt() time.Time {
+	return e.createdAt
+}
+
+// OperationAlreadyAppliedError is returned by the repository, when ledger entry with the same operation ID was already saved.
+type OperationAlreadyAppliedError struct {
+	OperationID string
+}
+
+func (e OperationAlreadyAppliedError) Error() string {
+	return fmt.Sprintf("operation %s was already applied", e.OperationID)
+}
+
+func (e OperationAlreadyAppliedError) Slug() string {
+	return "operation-already-applied"
+}
+
+func (e OperationAlreadyAppliedError) ErrorType() commonerrors.ErrorType {
+	return commonerrors.ErrorTypeConflict
+}
@@ -0,0 +1,124 @@
+package user_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/domain/user"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLedgerEntry(t *testing.T) {
+	t.Parallel()
+
+	operationID := uuid.New().String()
+	trainingUUID := uuid.New().String()
+	createdAt := time.Now()
+
+	entry, err := user.NewLedgerEntry(operationID, user.Booking, -1, trainingUUID, "attendee-uuid", createdAt)
+	require.NoError(t, err)
+
+	assert.Equal(t, operationID, entry.OperationID())
+	assert.Equal(t, user.Booking, entry.Reason())
+	assert.Equal(t, -1, entry.AmountChange())
+	assert.Equal(t, trainingUUID, entry.TrainingUUID())
+	assert.Equal(t, "attendee-uuid", entry.Actor())
+	assert.Equal(t, createdAt, entry.CreatedAt())
+}
+
+func TestNewLedgerEntry_invalid(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		Name         string
+		OperationID  string
+		Reason       user.LedgerReason
+		AmountChange int
+		TrainingUUID string
+		Actor        string
+		CreatedAt    time.Time
+	}{
+		{
+			Name:         "empty_operation_id",
+			OperationID:  "",
+			Reason:       user.ManualAdjustment,
+			AmountChange: 1,
+			Actor:        "admin",
+			CreatedAt:    time.Now(),
+		},
+		{
+			Name:         "empty_reason",
+			OperationID:  "operation-id",
+			AmountChange: 1,
+			Actor:        "admin",
+			CreatedAt:    time.Now(),
+		},
+		{
+			Name:         "zero_amount_change",
+			OperationID:  "operation-id",
+			Reason:       user.ManualAdjustment,
+			AmountChange: 0,
+			Actor:        "admin",
+			CreatedAt:    time.Now(),
+		},
+		{
+			Name:         "missing_training_uuid",
+			OperationID:  "operation-id",
+			Reason:       user.CancelRefund,
+			AmountChange: 1,
+			Actor:        "attendee-uuid",
+			CreatedAt:    time.Now(),
+		},
+		{
+			Name:         "empty_actor",
+			OperationID:  "operation-id",
+			Reason:       user.ManualAdjustment,
+			AmountChange: 1,
+			CreatedAt:    time.Now(),
+		},
+		{
+			Name:         "zero_time",
+			OperationID:  "operation-id",
+			Reason:       user.ManualAdjustment,
+			AmountChange: 1,
+			Actor:        "admin",
+		},
+	}
+
+	for _, c := range testCases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := user.NewLedgerEntry(c.OperationID, c.Reason, c.AmountChange, c.TrainingUUID, c.Actor, c.CreatedAt)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestNewManualAdjustmentLedgerEntry(t *testing.T) {
+	t.Parallel()
+
+	entry, err := user.NewManualAdjustmentLedgerEntry("operation-id", 2, "compensation for broken shower", "admin-uuid", time.Now())
+	require.NoError(t, err)
+
+	assert.Equal(t, user.ManualAdjustment, entry.Reason())
+	assert.Equal(t, "compensation for broken shower", entry.Note())
+	assert.Empty(t, entry.TrainingUUID())
+
+	_, err = user.NewManualAdjustmentLedgerEntry("operation-id", 2, "", "admin-uuid", time.Now())
+	assert.Error(t, err, "note should be required")
+}
+
+func TestNewLedgerReasonFromString(t *testing.T) {
+	t.Parallel()
+
+	reason, err := user.NewLedgerReasonFromString("trainer_penalty")
+	require.NoError(t, err)
+	assert.Equal(t, user.TrainerPenalty, reason)
+
+	_, err = user.NewLedgerReasonFromString("gift")
+	assert.Error(t, err)
+}
@@ -0,0 +1,166 @@
+package user
+
+import (
+	"fmt"
+	"time"
+
+	commonerrors "github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/errors"
+	"golang.org/x/text/language"
+)
+
+// NotificationChannel is enum-like type of the channel used to notify the user, for example about upcoming trainings.
+type NotificationChannel struct {
+	s string
+}
+
+var (
+	EmailNotifications = NotificationChannel{"email"}
+	PushNotifications  = NotificationChannel{"push"}
+)
+
+var notificationChannelValues = []NotificationChannel{
+	EmailNotifications,
+	PushNotifications,
+}
+
+func NewNotificationChannelFromString(channelStr string) (NotificationChannel, error) {
+	for _, channel := range notificationChannelValues {
+		if channel.String() == channelStr {
+			return channel, nil
+		}
+	}
+	return NotificationChannel{}, commonerrors.NewIncorrectInputError(
+		fmt.Sprintf("unknown '%s' notification channel", channelStr),
+		"invalid-notification-channel",
+	)
+}
+
+func (c NotificationChannel) IsZero() bool {
+	return c == NotificationChannel{}
+}
+
+func (c NotificationChannel) String() string {
+	return c.s
+}
+
+// MaxReminderLeadTime limits how early the user can be reminded about the training.
+const MaxReminderLeadTime = time.Hour * 24 * 7
+
+// Preferences are chosen by the user, other services should respect them when presenting data or notifying the user.
+type Preferences struct {
+	// timeZone is the IANA time zone name, for example "Europe/Warsaw"
+	timeZone string
+	// locale is the BCP 47 language tag, for example "pl-PL"
+	locale string
+
+	notificationChannel NotificationChannel
+	// reminderLeadTime is how long before the training the user is reminded about it, zero disables reminders
+	reminderLeadTime time.Duration
+}
+
+// DefaultPreferences are used until the user changes them.
+func DefaultPreferences() Preferences {
+	return Preferences{
+		timeZone:            "UTC",
+		locale:              "en",
+		notificationChannel: EmailNotifications,
+		reminderLeadTime:    time.Hour * 24,
+	}
+}
+
+func NewPreferences(
+	timeZone string,
+	locale string,
+	notificationChannel NotificationChannel,
+	reminderLeadTime time.Duration,
+) (Preferences, error) {
+	// empty name and "Local" are accepted by time.LoadLocation, but they are not meaningful for the user
+	if timeZone == "" || timeZone == "Local" {
+		return Preferences{}, invalidTimeZoneError(timeZone)
+	}
+	if _, err := time.LoadLocation(timeZone); err != nil {
+		return Preferences{}, invalidTimeZoneError(timeZone)
+	}
+
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return Preferences{}, commonerrors.NewIncorrectInputError(
+			fmt.Sprintf("invalid locale '%s'", locale),
+			"invalid-locale",
+		)
+	}
+
+	if notificationChannel.IsZero() {
+		return Preferences{}, commonerrors.NewIncorrectInputError("empty notification channel", "invalid-notification-channel")
+	}
+
+	if reminderLeadTime < 0 || reminderLeadTime > MaxReminderLeadTime || reminderLeadTime%time.Minute != 0 {
+		return Preferences{}, commonerrors.NewIncorrectInputError(
+			fmt.Sprintf(
+				"reminder lead time should be a whole number of minutes between 0 and %s, is %s",
+				MaxReminderLeadTime,
+				reminderLeadTime,
+			),
+			"invalid-reminder-lead-time",
+		)
+	}
+
+	return Preferences{
+		timeZone:            timeZone,
+		locale:              tag.String(),
+		notificationChannel: notificationChannel,
+		reminderLeadTime:    reminderLeadTime,
+	}, nil
+}
+
+func invalidTimeZoneError(timeZone string) error {
+	return commonerrors.NewIncorrectInputError(
+		fmt.Sprintf("invalid time zone '%s'", timeZone),
+		"invalid-time-zone",
+	)
+}
+
+// UnmarshalPreferencesFromDatabase unmarshals Preferences from the database.
+//
+// Users persisted before preferences were introduced have no time zone stored, they get DefaultPreferences.
+//
+// It should be used only for unmarshalling from the database!
+// You can't use UnmarshalPreferencesFromDatabase as constructor - It may put domain into the invalid state!
+func UnmarshalPreferencesFromDatabase(
+	timeZone string,
+	locale string,
+	notificationChannel string,
+	reminderLeadTime time.Duration,
+) (Preferences, error) {
+	if timeZone == "" {
+		return DefaultPreferences(), nil
+	}
+
+	channel, err := NewNotificationChannelFromString(notificationChannel)
+	if err != nil {
+		return Preferences{}, err
+	}
+
+	return Preferences{
+		timeZone:            timeZone,
+		locale:              locale,
+		notificationChannel: channel,
+		reminderLeadTime:    reminderLeadTime,
+	}, nil
+}
+
+func (p Preferences) TimeZone() string {
+	return p.timeZone
+}
+
+func (p Preferences) Locale() string {
+	return p.locale
+}
+
+func (p Preferences) NotificationChannel() NotificationChannel {
+	return p.notificationChannel
+}
+
+func (p Preferences) ReminderLeadTime() time.Duration {
+	return p.reminderLeadTime
+}
@@ -0,0 +1,132 @@
+package user_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/errors"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/domain/user"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewUser_default_preferences(t *testing.T) {
+	t.Parallel()
+
+	u, err := user.NewUser(uuid.New().String())
+	require.NoError(t, err)
+
+	preferences := u.Preferences()
+	assert.Equal(t, "UTC", preferences.TimeZone())
+	assert.Equal(t, "en", preferences.Locale())
+	assert.Equal(t, user.EmailNotifications, preferences.NotificationChannel())
+	assert.Equal(t, time.Hour*24, preferences.ReminderLeadTime())
+}
+
+func TestNewPreferences(t *testing.T) {
+	t.Parallel()
+
+	preferences, err := user.NewPreferences("Europe/Warsaw", "pl-pl", user.PushNotifications, time.Minute*90)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Europe/Warsaw", preferences.TimeZone())
+	assert.Equal(t, "pl-PL", preferences.Locale(), "locale should be canonicalized")
+	assert.Equal(t, user.PushNotifications, preferences.NotificationChannel())
+	assert.Equal(t, time.Minute*90, preferences.ReminderLeadTime())
+}
+
+func TestNewPreferences_invalid(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		Name                string
+		TimeZone            string
+		Locale              string
+		NotificationChannel user.NotificationChannel
+		ReminderLeadTime    time.Duration
+		ExpectedSlug        string
+	}{
+		{
+			Name:                "empty_time_zone",
+			TimeZone:            "",
+			Locale:              "en",
+			NotificationChannel: user.EmailNotifications,
+			ExpectedSlug:        "invalid-time-zone",
+		},
+		{
+			Name:                "local_time_zone",
+			TimeZone:            "Local",
+			Locale:              "en",
+			NotificationChannel: user.EmailNotifications,
+			ExpectedSlug:        "invalid-time-zone",
+		},
+		{
+			Name:                "unknown_time_zone",
+			TimeZone:            "Europe/Atlantis",
+			Locale:              "en",
+			NotificationChannel: user.EmailNotifications,
+			ExpectedSlug:        "invalid-time-zone",
+		},
+		{
+			Name:                "invalid_locale",
+			TimeZone:            "UTC",
+			Locale:              "not a locale",
+			NotificationChannel: user.EmailNotifications,
+			ExpectedSlug:        "invalid-locale",
+		},
+		{
+			Name:         "empty_notification_channel",
+			TimeZone:     "UTC",
+			Locale:       "en",
+			ExpectedSlug: "invalid-notification-channel",
+		},
+		{
+			Name:                "negative_reminder_lead_time",
+			TimeZone:            "UTC",
+			Locale:              "en",
+			NotificationChannel: user.EmailNotifications,
+			ReminderLeadTime:    -time.Hour,
+			ExpectedSlug:        "invalid-reminder-lead-time",
+		},
+		{
+			Name:                "too_long_reminder_lead_time",
+			TimeZone:            "UTC",
+			Locale:              "en",
+			NotificationChannel: user.EmailNotifications,
+			ReminderLeadTime:    user.MaxReminderLeadTime + time.Minute,
+			ExpectedSlug:        "invalid-reminder-lead-time",
+		},
+		{
+			Name:                "reminder_lead_time_not_in_minutes",
+			TimeZone:            "UTC",
+			Locale:              "en",
+			NotificationChannel: user.EmailNotifications,
+			ReminderLeadTime:    time.Second * 30,
+			ExpectedSlug:        "invalid-reminder-lead-time",
+		},
+	}
+
+	for _, c := range testCases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := user.NewPreferences(c.TimeZone, c.Locale, c.NotificationChannel, c.ReminderLeadTime)
+			require.Error(t, err)
+
+			slugErr, ok := err.(errors.SlugError)
+			require.True(t, ok, "expected SlugError, got %T", err)
+			assert.Equal(t, c.ExpectedSlug, slugErr.Slug())
+		})
+	}
+}
+
+func TestUnmarshalPreferencesFromDatabase_not_stored(t *testing.T) {
+	t.Parallel()
+
+	preferences, err := user.UnmarshalPreferencesFromDatabase("", "", "", 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, user.DefaultPreferences(), preferences)
+}
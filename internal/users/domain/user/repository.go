@@ -0,0 +1,49 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	commonerrors "github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/errors"
+)
+
+type NotFoundError struct {
+	UserUUID string
+}
+
+func (e NotFoundError) Error() string {
+	return fmt.Sprintf("user '%s' not found", e.UserUUID)
+}
+
+func (e NotFoundError) Slug() string {
+	return "user-not-found"
+}
+
+func (e NotFoundError) ErrorType() commonerrors.ErrorType {
+	return commonerrors.ErrorTypeNotFound
+}
+
+type Repository interface {
+	// GetUser returns the user. Users which are not persisted yet are returned with zero balance.
+	GetUser(ctx context.Context, userUUID string) (*User, error)
+
+	UpdateUser(
+		ctx context.Context,
+		userUUID string,
+		updateFn func(ctx context.Context, u *User) (*User, error),
+	) error
+
+	// ApplyOperation updates the user like UpdateUser, but only once for the operationID.
+	// When the operation was already applied, OperationAlreadyAppliedError is returned without calling updateFn,
+	// so retries are not failing on domain rules (like a balance which can't be negative) checked again.
+	ApplyOperation(
+		ctx context.Context,
+		userUUID string,
+		operationID string,
+		updateFn func(ctx context.Context, u *User) (*User, error),
+	) error
+
+	// UsersWithCreditsExpiringBefore returns UUIDs of users, who have credits expiring before t, which are not expired yet.
+	UsersWithCreditsExpiringBefore(ctx context.Context, t time.Time) ([]string, error)
+}
@@ -0,0 +1,44 @@
+package user
+
+import (
+	"fmt"
+
+	commonerrors "github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/errors"
+)
+
+// Role is enum-like type, it's the same value as the role claim of the auth provider.
+type Role struct {
+	s string
+}
+
+var (
+	Attendee = Role{"attendee"}
+	Trainer  = Role{"trainer"}
+	Admin    = Role{"admin"}
+)
+
+var roleValues = []Role{
+	Attendee,
+	Trainer,
+	Admin,
+}
+
+func NewRoleFromString(roleStr string) (Role, error) {
+	for _, role := range roleValues {
+		if role.String() == roleStr {
+			return role, nil
+		}
+	}
+	return Role{}, commonerrors.NewIncorrectInputError(
+		fmt.Sprintf("unknown '%s' role", roleStr),
+		"invalid-role",
+	)
+}
+
+func (r Role) IsZero() bool {
+	return r == Role{}
+}
+
+func (r Role) String() string {
+	return r.s
+}
@@ -0,0 +1,348 @@
+package user
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	commonerrors "github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/errors"
+	"github.com/pkg/errors"
+)
+
+// creditsExpirationActor is stored as the actor of ledger entries of expired credits.
+const creditsExpirationActor = "credits-expiration"
+
+type User struct {
+	uuid string
+
+	// displayName, email and role are copied from the auth provider, which is the source of truth
+	displayName string
+	email       string
+	role        Role
+
+	// customDisplayName is set, when the user changed the display name, it's not overwritten by the auth provider then
+	customDisplayName bool
+
+	preferences Preferences
+
+	// batches are sorted from the oldest
+	batches []CreditBatch
+
+	// newLedgerEntries are entries added since the user was loaded, they are appended to the ledger by the repository
+	newLedgerEntries []LedgerEntry
+}
+
+func NewUser(uuid string) (*User, error) {
+	if uuid == "" {
+		return nil, errors.New("empty user uuid")
+	}
+
+	return &User{uuid: uuid, preferences: DefaultPreferences()}, nil
+}
+
+// UnmarshalUserFromDatabase unmarshals User from the database.
+//
+// It should be used only for unmarshalling from the database!
+// You can't use UnmarshalUserFromDatabase as constructor - It may put domain into the invalid state!
+func UnmarshalUserFromDatabase(
+	uuid string,
+	displayName string,
+	customDisplayName bool,
+	email string,
+	role Role,
+	preferences Preferences,
+	batches []CreditBatch,
+) (*User, error) {
+	u, err := NewUser(uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	u.displayName = displayName
+	u.customDisplayName = customDisplayName
+	u.email = email
+	u.role = role
+	u.preferences = preferences
+	u.batches = append([]CreditBatch(nil), batches...)
+	sortCreditBatches(u.batches)
+
+	return u, nil
+}
+
+func sortCreditBatches(batches []CreditBatch) {
+	sort.SliceStable(batches, func(i, j int) bool {
+		return batches[i].grantedAt.Before(batches[j].grantedAt)
+	})
+}
+
+func (u User) UUID() string {
+	return u.uuid
+}
+
+// Balance returns all credits, which were not marked as expired yet.
+func (u User) Balance() int {
+	balance := 0
+	for _, b := range u.batches {
+		if !b.expired {
+			balance += b.remaining
+		}
+	}
+
+	return balance
+}
+
+// BalanceAt returns credits, which can be used at t.
+//
+// It may be lower than Balance, when some credits expired, but ExpireCredits was not called yet.
+func (u User) BalanceAt(t time.Time) int {
+	balance := 0
+	for _, b := range u.batches {
+		if !b.isExpiredAt(t) {
+			balance += b.remaining
+		}
+	}
+
+	return balance
+}
+
+func (u User) CreditBatches() []CreditBatch {
+	return u.batches
+}
+
+// NextCreditsExpiration returns the time, when the oldest not expired credits expire.
+// False is returned, when no credits will expire.
+func (u User) NextCreditsExpiration() (time.Time, bool) {
+	var next time.Time
+	for _, b := range u.batches {
+		if b.expired || b.neverExpires() || b.remaining == 0 {
+			continue
+		}
+
+		if next.IsZero() || b.expiresAt.Before(next) {
+			next = b.expiresAt
+		}
+	}
+
+	return next, !next.IsZero()
+}
+
+func (u User) DisplayName() string {
+	return u.displayName
+}
+
+func (u User) Email() string {
+	return u.email
+}
+
+// CustomDisplayName returns true, when the display name was changed by the user.
+func (u User) CustomDisplayName() bool {
+	return u.customDisplayName
+}
+
+// Role returns zero Role, when the role was not copied from the auth provider yet.
+func (u User) Role() Role {
+	return u.role
+}
+
+type NegativeBalanceError struct {
+	CurrentBalance int
+	AmountChange   int
+}
+
+func (e NegativeBalanceError) Error() string {
+	return fmt.Sprintf(
+		"balance cannot be smaller than 0, current balance: %d, amount change: %d",
+		e.CurrentBalance,
+		e.AmountChange,
+	)
+}
+
+func (e NegativeBalanceError) Slug() string {
+	return "balance-cannot-be-negative"
+}
+
+func (e NegativeBalanceError) ErrorType() commonerrors.ErrorType {
+	return commonerrors.ErrorTypePreconditionFailed
+}
+
+// ChangeBalance applies the ledger entry to the credit batches.
+//
+// Credits are used from the oldest not expired batch first.
+// Cancel refunds are returned to the batch used by the booking, or to a new batch when that batch already expired.
+// All other credits are granted as a new batch.
+func (u *User) ChangeBalance(entry LedgerEntry) error {
+	currentBalance := u.BalanceAt(entry.CreatedAt())
+	if currentBalance+entry.AmountChange() < 0 {
+		return NegativeBalanceError{
+			CurrentBalance: currentBalance,
+			AmountChange:   entry.AmountChange(),
+		}
+	}
+
+	if err := u.ExpireCredits(entry.CreatedAt()); err != nil {
+		return err
+	}
+
+	if entry.AmountChange() > 0 {
+		u.grantCredits(entry)
+	} else {
+		u.useCredits(entry)
+	}
+
+	u.newLedgerEntries = append(u.newLedgerEntries, entry)
+
+	return nil
+}
+
+func (u *User) grantCredits(entry LedgerEntry) {
+	if entry.Reason() == CancelRefund {
+		for i := range u.batches {
+			b := &u.batches[i]
+			if !b.hasBookedTraining(entry.TrainingUUID()) {
+				continue
+			}
+
+			b.removeBookedTraining(entry.TrainingUUID())
+			if !b.isExpiredAt(entry.CreatedAt()) {
+				b.remaining += entry.AmountChange()
+				return
+			}
+
+			break
+		}
+	}
+
+	u.batches = append(u.batches, newCreditBatch(entry.OperationID(), entry.CreatedAt(), entry.AmountChange()))
+	sortCreditBatches(u.batches)
+}
+
+func (u *User) useCredits(entry LedgerEntry) {
+	toUse := -entry.AmountChange()
+
+	for i := range u.batches {
+		if toUse == 0 {
+			return
+		}
+
+		b := &u.batches[i]
+		if b.isExpiredAt(entry.CreatedAt()) || b.remaining == 0 {
+			continue
+		}
+
+		used := b.remaining
+		if used > toUse {
+			used = toUse
+		}
+
+		b.remaining -= used
+		toUse -= used
+
+		if entry.TrainingUUID() != "" && !b.hasBookedTraining(entry.TrainingUUID()) {
+			b.bookedTrainings = append(b.bookedTrainings, entry.TrainingUUID())
+		}
+	}
+}
+
+// ExpireCredits marks batches, which expired before now, as expired.
+// The remaining credits of these batches are removed from the balance with ledger entries.
+func (u *User) ExpireCredits(now time.Time) error {
+	for i := range u.batches {
+		b := &u.batches[i]
+		if b.expired || !b.isExpiredAt(now) {
+			continue
+		}
+
+		if b.remaining > 0 {
+			entry, err := NewLedgerEntry(
+				"expiry-"+b.id,
+				Expiry,
+				-b.remaining,
+				"",
+				creditsExpirationActor,
+				b.expiresAt,
+			)
+			if err != nil {
+				return err
+			}
+
+			u.newLedgerEntries = append(u.newLedgerEntries, entry)
+		}
+
+		b.remaining = 0
+		b.expired = true
+	}
+
+	return nil
+}
+
+// NewLedgerEntries returns balance changes which were not persisted yet.
+func (u User) NewLedgerEntries() []LedgerEntry {
+	return u.newLedgerEntries
+}
+
+// SyncAuthProfile updates the profile with the data provided by the auth provider, for example from the user's token.
+//
+// The role is set only if it's not known yet, because token may be issued before the role was changed.
+// The display name is kept, when it was changed by the user.
+func (u *User) SyncAuthProfile(displayName string, email string, role Role) {
+	if !u.customDisplayName {
+		u.displayName = displayName
+	}
+	u.email = email
+
+	if u.role.IsZero() {
+		u.role = role
+	}
+}
+
+// MaxDisplayNameLength is counted in characters, not bytes.
+const MaxDisplayNameLength = 64
+
+func (u *User) ChangeDisplayName(displayName string) error {
+	displayName = strings.TrimSpace(displayName)
+
+	if displayName == "" {
+		return commonerrors.NewIncorrectInputError("empty display name", "invalid-display-name")
+	}
+	if utf8.RuneCountInString(displayName) > MaxDisplayNameLength {
+		return commonerrors.NewIncorrectInputError(
+			fmt.Sprintf("display name can't be longer than %d characters", MaxDisplayNameLength),
+			"invalid-display-name",
+		)
+	}
+
+	u.displayName = displayName
+	u.customDisplayName = true
+
+	return nil
+}
+
+func (u User) Preferences() Preferences {
+	return u.preferences
+}
+
+func (u *User) ChangePreferences(preferences Preferences) {
+	u.preferences = preferences
+}
+
+func (u *User) ChangeRole(role Role) error {
+	if role.IsZero() {
+		return errors.New("empty role")
+	}
+
+	u.role = role
+
+	return nil
+}
+
+// Anonymize removes personal data of the user.
+//
+// The balance and credit batches are kept, because they are needed for accounting of purchased credits.
+func (u *User) Anonymize() {
+	u.displayName = ""
+	u.customDisplayName = false
+	u.email = ""
+	u.preferences = DefaultPreferences()
+}
@@ -0,0 +1,237 @@
+package user_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/domain/user"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewUser(t *testing.T) {
+	t.Parallel()
+	userUUID := uuid.New().String()
+
+	u, err := user.NewUser(userUUID)
+	require.NoError(t, err)
+
+	assert.Equal(t, userUUID, u.UUID())
+	assert.Equal(t, 0, u.Balance())
+}
+
+func TestNewUser_empty_uuid(t *testing.T) {
+	t.Parallel()
+
+	_, err := user.NewUser("")
+	assert.Error(t, err)
+}
+
+func TestUser_ChangeBalance(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		Name            string
+		Balance         int
+		AmountChange    int
+		ExpectedBalance int
+		ExpectedErr     error
+	}{
+		{
+			Name:            "add",
+			Balance:         0,
+			AmountChange:    20,
+			ExpectedBalance: 20,
+		},
+		{
+			Name:            "subtract",
+			Balance:         5,
+			AmountChange:    -1,
+			ExpectedBalance: 4,
+		},
+		{
+			Name:            "subtract_to_zero",
+			Balance:         1,
+			AmountChange:    -1,
+			ExpectedBalance: 0,
+		},
+		{
+			Name:            "negative_balance",
+			Balance:         1,
+			AmountChange:    -2,
+			ExpectedBalance: 1,
+			ExpectedErr: user.NegativeBalanceError{
+				CurrentBalance: 1,
+				AmountChange:   -2,
+			},
+		},
+	}
+
+	for _, c := range testCases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			t.Parallel()
+
+			u := newUserWithLegacyBalance(t, c.Balance)
+
+			entry := newExampleLedgerEntry(t, c.AmountChange)
+
+			err := u.ChangeBalance(entry)
+			assert.Equal(t, c.ExpectedErr, err)
+			assert.Equal(t, c.ExpectedBalance, u.Balance())
+
+			if c.ExpectedErr == nil {
+				assert.Equal(t, []user.LedgerEntry{entry}, u.NewLedgerEntries())
+			} else {
+				assert.Empty(t, u.NewLedgerEntries())
+			}
+		})
+	}
+}
+
+func newUserWithLegacyBalance(t *testing.T, balance int) *user.User {
+	t.Helper()
+
+	var batches []user.CreditBatch
+	if balance > 0 {
+		batch, err := user.NewLegacyCreditBatch(balance)
+		require.NoError(t, err)
+		batches = append(batches, batch)
+	}
+
+	u, err := user.UnmarshalUserFromDatabase(
+		uuid.New().String(),
+		"",
+		false,
+		"",
+		user.Attendee,
+		user.DefaultPreferences(),
+		batches,
+	)
+	require.NoError(t, err)
+
+	return u
+}
+
+func newExampleLedgerEntry(t *testing.T, amountChange int) user.LedgerEntry {
+	t.Helper()
+
+	entry, err := user.NewLedgerEntry(
+		uuid.New().String(),
+		user.ManualAdjustment,
+		amountChange,
+		"",
+		"admin",
+		time.Now(),
+	)
+	require.NoError(t, err)
+
+	return entry
+}
+
+func TestUser_SyncAuthProfile(t *testing.T) {
+	t.Parallel()
+
+	u, err := user.NewUser(uuid.New().String())
+	require.NoError(t, err)
+
+	u.SyncAuthProfile("Mariusz Pudzianowski", "attendee@threedots.tech", user.Attendee)
+	assert.Equal(t, "Mariusz Pudzianowski", u.DisplayName())
+	assert.Equal(t, "attendee@threedots.tech", u.Email())
+	assert.Equal(t, user.Attendee, u.Role())
+
+	require.NoError(t, u.ChangeRole(user.Trainer))
+
+	// token issued before the role change still contains the old role
+	u.SyncAuthProfile("Mariusz", "attendee@threedots.tech", user.Attendee)
+	assert.Equal(t, "Mariusz", u.DisplayName())
+	assert.Equal(t, user.Trainer, u.Role())
+}
+
+func TestUser_ChangeDisplayName(t *testing.T) {
+	t.Parallel()
+
+	u, err := user.NewUser(uuid.New().String())
+	require.NoError(t, err)
+
+	u.SyncAuthProfile("Mariusz Pudzianowski", "attendee@threedots.tech", user.Attendee)
+
+	require.NoError(t, u.ChangeDisplayName("  Pudzian "))
+	assert.Equal(t, "Pudzian", u.DisplayName())
+	assert.True(t, u.CustomDisplayName())
+
+	// the name chosen by the user is more important than the name from the auth provider
+	u.SyncAuthProfile("Mariusz Pudzianowski", "new-email@threedots.tech", user.Attendee)
+	assert.Equal(t, "Pudzian", u.DisplayName())
+	assert.Equal(t, "new-email@threedots.tech", u.Email())
+}
+
+func TestUser_ChangeDisplayName_invalid(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		Name        string
+		DisplayName string
+	}{
+		{
+			Name:        "empty",
+			DisplayName: "",
+		},
+		{
+			Name:        "only_whitespace",
+			DisplayName: "   ",
+		},
+		{
+			Name:        "too_long",
+			DisplayName: strings.Repeat("ą", user.MaxDisplayNameLength+1),
+		},
+	}
+
+	for _, c := range testCases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			t.Parallel()
+
+			u, err := user.NewUser(uuid.New().String())
+			require.NoError(t, err)
+			u.SyncAuthProfile("Mariusz Pudzianowski", "attendee@threedots.tech", user.Attendee)
+
+			err = u.ChangeDisplayName(c.DisplayName)
+			assert.Error(t, err)
+
+			assert.Equal(t, "Mariusz Pudzianowski", u.DisplayName())
+			assert.False(t, u.CustomDisplayName())
+		})
+	}
+}
+
+func TestUser_ChangeRole_empty(t *testing.T) {
+	t.Parallel()
+
+	u, err := user.NewUser(uuid.New().String())
+	require.NoError(t, err)
+
+	assert.Error(t, u.ChangeRole(user.Role{}))
+}
+
+func TestUser_Anonymize(t *testing.T) {
+	t.Parallel()
+
+	u := newUserWithLegacyBalance(t, 3)
+	u.SyncAuthProfile("Mariusz Pudzianowski", "attendee@threedots.tech", user.Attendee)
+	require.NoError(t, u.ChangeDisplayName("Pudzian"))
+
+	preferences, err := user.NewPreferences("Europe/Warsaw", "pl-PL", user.PushNotifications, time.Hour)
+	require.NoError(t, err)
+	u.ChangePreferences(preferences)
+
+	u.Anonymize()
+
+	assert.Empty(t, u.DisplayName())
+	assert.False(t, u.CustomDisplayName())
+	assert.Empty(t, u.Email())
+	assert.Equal(t, user.DefaultPreferences(), u.Preferences())
+	assert.Equal(t, 3, u.Balance())
+}
@@ -164,6 +164,9 @@ func setAttendeeTrainingsAmount(attendeeUUIDs []string) error {
 		_, err = usersClient.UpdateTrainingBalance(context.Background(), &users.UpdateTrainingBalanceRequest{
 			UserId:       attendeeUUID,
 			AmountChange: 20,
+			OperationId:  "fixtures-initial-credits-" + attendeeUUID,
+			Reason:       "manual_adjustment",
+			Actor:        "fixtures",
 		})
 		if err != nil {
 			return err
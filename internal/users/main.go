@@ -7,10 +7,12 @@ import (
 	"os"
 	"strings"
 
-	"cloud.google.com/go/firestore"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/genproto/users"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/logs"
 	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/server"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/tracing"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/ports"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/service"
 	"github.com/go-chi/chi/v5"
 	"google.golang.org/grpc"
 )
@@ -18,26 +20,50 @@ import (
 func main() {
 	logs.Init()
 
+	flushTraces := tracing.Init("users")
+	defer flushTraces()
+
 	ctx := context.Background()
-	firestoreClient, err := firestore.NewClient(ctx, os.Getenv("GCP_PROJECT"))
-	if err != nil {
-		panic(err)
-	}
-	firebaseDB := db{firestoreClient}
+
+	application, healthChecks, cleanup := service.NewApplication(ctx)
 
 	serverType := strings.ToLower(os.Getenv("SERVER_TO_RUN"))
 	switch serverType {
 	case "http":
 		go loadFixtures()
 
-		server.RunHTTPServer(func(router chi.Router) http.Handler {
-			return HandlerFromMux(HttpServer{firebaseDB}, router)
-		})
+		httpServer := ports.NewHttpServer(application)
+
+		opts := []server.HTTPServerOption{
+			server.WithAuthenticatedMiddlewares(httpServer.SessionTrackingMiddleware),
+			server.WithHTTPHealthChecks(healthChecks...),
+			server.WithRateLimits(ports.HttpRateLimits),
+			server.WithHTTPShutdownHooks(cleanup),
+		}
+		if application.Commands.HandlePaymentWebhook != nil {
+			// webhooks are not mounted when purchases are disabled, so nobody can send them
+			opts = append(opts, server.WithPublicRoutes("/webhooks", func(router chi.Router) http.Handler {
+				router.Post("/payments", httpServer.HandlePaymentWebhook)
+				return router
+			}))
+		}
+
+		server.RunHTTPServer(
+			func(router chi.Router) http.Handler {
+				return ports.HandlerFromMux(httpServer, router)
+			},
+			opts...,
+		)
 	case "grpc":
-		server.RunGRPCServer(func(server *grpc.Server) {
-			svc := GrpcServer{firebaseDB}
-			users.RegisterUsersServiceServer(server, svc)
-		})
+		server.RunGRPCServer(
+			func(server *grpc.Server) {
+				svc := ports.NewGrpcServer(application)
+				users.RegisterUsersServiceServer(server, svc)
+			},
+			server.WithAllowedCallers(ports.GrpcAllowedCallers),
+			server.WithGRPCHealthChecks(healthChecks...),
+			server.WithGRPCShutdownHooks(cleanup),
+		)
 	default:
 		panic(fmt.Sprintf("server type '%s' is not supported", serverType))
 	}
@@ -0,0 +1,166 @@
+package ports
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/errors"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/genproto/users"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/grpcerr"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/app"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/app/command"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/app/query"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/domain/user"
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GrpcAllowedCallers lists services, which are allowed to call methods of GrpcServer.
+//
+// The users service calls itself, when it loads fixtures.
+var GrpcAllowedCallers = map[string][]string{
+	"/users.UsersService/GetTrainingBalance":    {"users"},
+	"/users.UsersService/UpdateTrainingBalance": {"trainings", "users"},
+	"/users.UsersService/GetUser":               {"trainer", "trainings"},
+	"/users.UsersService/BatchGetUsers":         {"trainer", "trainings"},
+	"/users.UsersService/ListUsers":             {"trainer", "trainings"},
+	"/users.UsersService/ExportUserData":        {"trainer", "trainings"},
+	"/users.UsersService/EraseUserData":         {"trainer", "trainings"},
+	"/users.UsersService/IsSessionRevoked":      {"trainer", "trainings"},
+}
+
+type GrpcServer struct {
+	app app.Application
+}
+
+func NewGrpcServer(application app.Application) GrpcServer {
+	return GrpcServer{app: application}
+}
+
+func (g GrpcServer) GetTrainingBalance(ctx context.Context, request *users.GetTrainingBalanceRequest) (*users.GetTrainingBalanceResponse, error) {
+	balance, err := g.app.Queries.TrainingBalance.Handle(ctx, query.TrainingBalance{UserUUID: request.UserId})
+	if err != nil {
+		return nil, grpcerr.Status(err)
+	}
+
+	return &users.GetTrainingBalanceResponse{Amount: int64(balance)}, nil
+}
+
+func (g GrpcServer) UpdateTrainingBalance(
+	ctx context.Context,
+	request *users.UpdateTrainingBalanceRequest,
+) (*empty.Empty, error) {
+	reason, err := user.NewLedgerReasonFromString(request.Reason)
+	if err != nil {
+		return nil, grpcerr.Status(errors.NewIncorrectInputError(err.Error(), "invalid-ledger-reason"))
+	}
+
+	err = g.app.Commands.UpdateTrainingBalance.Handle(ctx, command.UpdateTrainingBalance{
+		UserUUID:     request.UserId,
+		OperationID:  request.OperationId,
+		Reason:       reason,
+		AmountChange: int(request.AmountChange),
+		TrainingUUID: request.TrainingUuid,
+		Actor:        request.Actor,
+	})
+	if err != nil {
+		return nil, grpcerr.Status(err)
+	}
+
+	return &empty.Empty{}, nil
+}
+
+func (g GrpcServer) GetUser(ctx context.Context, request *users.GetUserRequest) (*users.User, error) {
+	u, err := g.app.Queries.UserDetails.Handle(ctx, query.UserDetails{UserUUID: request.UserId})
+	if err != nil {
+		return nil, grpcerr.Status(err)
+	}
+
+	return userToResponse(u), nil
+}
+
+func (g GrpcServer) BatchGetUsers(ctx context.Context, request *users.BatchGetUsersRequest) (*users.BatchGetUsersResponse, error) {
+	found, err := g.app.Queries.UsersByUUIDs.Handle(ctx, query.UsersByUUIDs{UserUUIDs: request.UserIds})
+	if err != nil {
+		return nil, grpcerr.Status(err)
+	}
+
+	return &users.BatchGetUsersResponse{Users: usersToResponse(found)}, nil
+}
+
+func (g GrpcServer) ListUsers(ctx context.Context, request *users.ListUsersRequest) (*users.ListUsersResponse, error) {
+	found, err := g.app.Queries.Users.Handle(ctx, query.Users{
+		Search: request.Search,
+		Limit:  int(request.Limit),
+	})
+	if err != nil {
+		return nil, grpcerr.Status(err)
+	}
+
+	return &users.ListUsersResponse{Users: usersToResponse(found)}, nil
+}
+
+func (g GrpcServer) ExportUserData(ctx context.Context, request *users.ExportUserDataRequest) (*users.ExportUserDataResponse, error) {
+	if request.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "empty user_id")
+	}
+
+	data, err := g.app.Queries.UserData.Handle(ctx, query.UserData{UserUUID: request.UserId})
+	if err != nil {
+		return nil, grpcerr.Status(err)
+	}
+
+	export, err := json.Marshal(personalDataToExport(data))
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &users.ExportUserDataResponse{Data: export}, nil
+}
+
+func (g GrpcServer) EraseUserData(ctx context.Context, request *users.EraseUserDataRequest) (*empty.Empty, error) {
+	err := g.app.Commands.EraseUserData.Handle(ctx, command.EraseUserData{UserUUID: request.UserId})
+	if err != nil {
+		return nil, grpcerr.Status(err)
+	}
+
+	return &empty.Empty{}, nil
+}
+
+func (g GrpcServer) IsSessionRevoked(ctx context.Context, request *users.IsSessionRevokedRequest) (*users.IsSessionRevokedResponse, error) {
+	revoked, err := g.app.Queries.SessionRevoked.Handle(ctx, query.SessionRevoked{
+		UserUUID:  request.UserId,
+		SessionID: request.SessionId,
+	})
+	if err != nil {
+		return nil, grpcerr.Status(err)
+	}
+
+	return &users.IsSessionRevokedResponse{Revoked: revoked}, nil
+}
+
+func userToResponse(u query.User) *users.User {
+	return &users.User{
+		Uuid:        u.UUID,
+		DisplayName: u.DisplayName,
+		Email:       u.Email,
+		Role:        u.Role,
+		Preferences: &users.UserPreferences{
+			TimeZone:                u.Preferences.TimeZone,
+			Locale:                  u.Preferences.Locale,
+			NotificationChannel:     u.Preferences.NotificationChannel,
+			ReminderLeadTimeMinutes: int64(u.Preferences.ReminderLeadTime / time.Minute),
+		},
+	}
+}
+
+func usersToResponse(found []query.User) []*users.User {
+	resp := make([]*users.User, 0, len(found))
+	for _, u := range found {
+		resp = append(resp, userToResponse(u))
+	}
+
+	return resp
+}
@@ -0,0 +1,436 @@
+package ports
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/auth"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/errors"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/ratelimit"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/server/httperr"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/app"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/app/command"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/app/query"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+// HttpRateLimits limits requests starting payments.
+var HttpRateLimits = map[string]ratelimit.Limit{
+	"POST /purchases": {Requests: 10, Period: time.Minute},
+}
+
+type HttpServer struct {
+	app app.Application
+}
+
+func NewHttpServer(application app.Application) HttpServer {
+	return HttpServer{app: application}
+}
+
+func (h HttpServer) GetCurrentUser(w http.ResponseWriter, r *http.Request) {
+	authUser, err := auth.UserFromCtx(r.Context())
+	if err != nil {
+		httperr.RespondWithSlugError(err, w, r)
+		return
+	}
+
+	err = h.app.Commands.SyncAuthProfile.Handle(r.Context(), command.SyncAuthProfile{
+		UserUUID:    authUser.UUID,
+		DisplayName: authUser.DisplayName,
+		Email:       authUser.Email,
+		Role:        authUser.Role,
+	})
+	if err != nil {
+		httperr.RespondWithSlugError(err, w, r)
+		return
+	}
+
+	h.respondWithCurrentUser(w, r, authUser)
+}
+
+func (h HttpServer) UpdateCurrentUser(w http.ResponseWriter, r *http.Request) {
+	profileUpdate := UserProfileUpdate{}
+	if err := render.Decode(r, &profileUpdate); err != nil {
+		httperr.BadRequest("invalid-request", err, w, r)
+		return
+	}
+
+	authUser, err := auth.UserFromCtx(r.Context())
+	if err != nil {
+		httperr.RespondWithSlugError(err, w, r)
+		return
+	}
+
+	cmd := command.UpdateUserProfile{
+		UserUUID:    authUser.UUID,
+		DisplayName: profileUpdate.DisplayName,
+		TimeZone:    profileUpdate.TimeZone,
+		Locale:      profileUpdate.Locale,
+	}
+	if profileUpdate.NotificationChannel != nil {
+		notificationChannel := string(*profileUpdate.NotificationChannel)
+		cmd.NotificationChannel = &notificationChannel
+	}
+	if profileUpdate.ReminderLeadTimeMinutes != nil {
+		reminderLeadTime := time.Duration(*profileUpdate.ReminderLeadTimeMinutes) * time.Minute
+		cmd.ReminderLeadTime = &reminderLeadTime
+	}
+
+	if err := h.app.Commands.UpdateUserProfile.Handle(r.Context(), cmd); err != nil {
+		httperr.RespondWithSlugError(withProfileFieldError(err), w, r)
+		return
+	}
+
+	h.respondWithCurrentUser(w, r, authUser)
+}
+
+func (h HttpServer) respondWithCurrentUser(w http.ResponseWriter, r *http.Request, authUser auth.User) {
+	u, err := h.app.Queries.UserDetails.Handle(r.Context(), query.UserDetails{UserUUID: authUser.UUID})
+	if err != nil {
+		httperr.InternalError("cannot-get-user", err, w, r)
+		return
+	}
+
+	expiringCredits, err := h.app.Queries.ExpiringCredits.Handle(r.Context(), query.UserExpiringCredits{UserUUID: authUser.UUID})
+	if err != nil {
+		httperr.InternalError("cannot-get-user", err, w, r)
+		return
+	}
+
+	userResponse := User{
+		DisplayName:     u.DisplayName,
+		Balance:         u.Balance,
+		Role:            authUser.Role,
+		ExpiringCredits: []ExpiringCredits{},
+		Preferences: UserPreferences{
+			TimeZone:                u.Preferences.TimeZone,
+			Locale:                  u.Preferences.Locale,
+			NotificationChannel:     UserPreferencesNotificationChannel(u.Preferences.NotificationChannel),
+			ReminderLeadTimeMinutes: int(u.Preferences.ReminderLeadTime / time.Minute),
+		},
+	}
+	for _, c := range expiringCredits {
+		userResponse.ExpiringCredits = append(userResponse.ExpiringCredits, ExpiringCredits{
+			Amount:    c.Amount,
+			ExpiresAt: c.ExpiresAt,
+		})
+	}
+
+	render.Respond(w, r, userResponse)
+}
+
+func (h HttpServer) GetCurrentUserLedger(w http.ResponseWriter, r *http.Request) {
+	authUser, err := auth.UserFromCtx(r.Context())
+	if err != nil {
+		httperr.RespondWithSlugError(err, w, r)
+		return
+	}
+
+	entries, err := h.app.Queries.Ledger.Handle(r.Context(), query.Ledger{UserUUID: authUser.UUID})
+	if err != nil {
+		httperr.InternalError("cannot-get-ledger", err, w, r)
+		return
+	}
+
+	resp := []LedgerEntry{}
+	for _, e := range entries {
+		entry := LedgerEntry{
+			OperationId:  e.OperationID,
+			Reason:       LedgerEntryReason(e.Reason),
+			AmountChange: e.AmountChange,
+			Actor:        e.Actor,
+			CreatedAt:    e.CreatedAt,
+		}
+		if e.TrainingUUID != "" {
+			trainingUUID := e.TrainingUUID
+			entry.TrainingUuid = &trainingUUID
+		}
+		if e.Note != "" {
+			note := e.Note
+			entry.Note = &note
+		}
+
+		resp = append(resp, entry)
+	}
+
+	render.Respond(w, r, resp)
+}
+
+func (h HttpServer) GetCreditPackages(w http.ResponseWriter, r *http.Request) {
+	packages, err := h.app.Queries.CreditPackages.Handle(r.Context(), query.CreditPackages{})
+	if err != nil {
+		httperr.RespondWithSlugError(err, w, r)
+		return
+	}
+
+	resp := []CreditPackage{}
+	for _, p := range packages {
+		resp = append(resp, CreditPackage{
+			Id:       p.ID,
+			Name:     p.Name,
+			Credits:  p.Credits,
+			Price:    p.Price,
+			Currency: p.Currency,
+		})
+	}
+
+	render.Respond(w, r, resp)
+}
+
+func (h HttpServer) CreatePurchase(w http.ResponseWriter, r *http.Request) {
+	postPurchase := PostPurchase{}
+	if err := render.Decode(r, &postPurchase); err != nil {
+		httperr.BadRequest("invalid-request", err, w, r)
+		return
+	}
+
+	authUser, ok := auth.HttpUserWithPermission(w, r, auth.PermissionCreditsPurchase)
+	if !ok {
+		return
+	}
+
+	cmd := command.CreatePurchaseCheckout{
+		PurchaseUUID: uuid.New().String(),
+		UserUUID:     authUser.UUID,
+		PackageID:    postPurchase.PackageId,
+	}
+	if err := h.app.Commands.CreatePurchaseCheckout.Handle(r.Context(), cmd); err != nil {
+		httperr.RespondWithSlugError(err, w, r)
+		return
+	}
+
+	p, err := h.app.Queries.UserPurchase.Handle(r.Context(), query.UserPurchase{
+		PurchaseUUID: cmd.PurchaseUUID,
+		UserUUID:     authUser.UUID,
+	})
+	if err != nil {
+		httperr.RespondWithSlugError(err, w, r)
+		return
+	}
+
+	w.Header().Set("content-location", "/purchases/"+cmd.PurchaseUUID)
+	render.Status(r, http.StatusCreated)
+	render.Respond(w, r, purchaseToResponse(p))
+}
+
+func (h HttpServer) GetPurchase(w http.ResponseWriter, r *http.Request, purchaseUUID string) {
+	authUser, err := auth.UserFromCtx(r.Context())
+	if err != nil {
+		httperr.RespondWithSlugError(err, w, r)
+		return
+	}
+
+	p, err := h.app.Queries.UserPurchase.Handle(r.Context(), query.UserPurchase{
+		PurchaseUUID: purchaseUUID,
+		UserUUID:     authUser.UUID,
+	})
+	if err != nil {
+		httperr.RespondWithSlugError(err, w, r)
+		return
+	}
+
+	render.Respond(w, r, purchaseToResponse(p))
+}
+
+// HandlePaymentWebhook is called by the payment provider, so it's not a part of the OpenAPI spec.
+// It should be served without the user's authentication, the provider's signature is verified instead.
+func (h HttpServer) HandlePaymentWebhook(w http.ResponseWriter, r *http.Request) {
+	payload, err := io.ReadAll(r.Body)
+	if err != nil {
+		httperr.BadRequest("invalid-request", err, w, r)
+		return
+	}
+
+	err = h.app.Commands.HandlePaymentWebhook.Handle(r.Context(), command.HandlePaymentWebhook{
+		Payload: payload,
+		Headers: r.Header,
+	})
+	if err != nil {
+		httperr.RespondWithSlugError(err, w, r)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h HttpServer) GetUsers(w http.ResponseWriter, r *http.Request, params GetUsersParams) {
+	if _, ok := auth.HttpUserWithPermission(w, r, auth.PermissionUsersManage); !ok {
+		return
+	}
+
+	q := query.Users{}
+	if params.Search != nil {
+		q.Search = *params.Search
+	}
+	if params.Limit != nil {
+		q.Limit = *params.Limit
+	}
+
+	users, err := h.app.Queries.Users.Handle(r.Context(), q)
+	if err != nil {
+		httperr.RespondWithSlugError(err, w, r)
+		return
+	}
+
+	resp := []UserDetails{}
+	for _, u := range users {
+		resp = append(resp, userDetailsToResponse(u))
+	}
+
+	render.Respond(w, r, resp)
+}
+
+func (h HttpServer) GetUser(w http.ResponseWriter, r *http.Request, userUUID string) {
+	if _, ok := auth.HttpUserWithPermission(w, r, auth.PermissionUsersManage); !ok {
+		return
+	}
+
+	u, err := h.app.Queries.UserDetails.Handle(r.Context(), query.UserDetails{UserUUID: userUUID})
+	if err != nil {
+		httperr.RespondWithSlugError(err, w, r)
+		return
+	}
+
+	render.Respond(w, r, userDetailsToResponse(u))
+}
+
+func (h HttpServer) AdjustUserCredits(w http.ResponseWriter, r *http.Request, userUUID string) {
+	adjustment := CreditsAdjustment{}
+	if err := render.Decode(r, &adjustment); err != nil {
+		httperr.BadRequest("invalid-request", err, w, r)
+		return
+	}
+
+	admin, ok := auth.HttpUserWithPermission(w, r, auth.PermissionUsersManage)
+	if !ok {
+		return
+	}
+
+	err := h.app.Commands.AdjustCredits.Handle(r.Context(), command.AdjustCredits{
+		UserUUID:     userUUID,
+		OperationID:  "manual-adjustment-" + uuid.New().String(),
+		AmountChange: adjustment.AmountChange,
+		Note:         adjustment.Reason,
+		ActorUUID:    admin.UUID,
+	})
+	if err != nil {
+		httperr.RespondWithSlugError(err, w, r)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h HttpServer) ChangeUserRole(w http.ResponseWriter, r *http.Request, userUUID string) {
+	roleChange := RoleChange{}
+	if err := render.Decode(r, &roleChange); err != nil {
+		httperr.BadRequest("invalid-request", err, w, r)
+		return
+	}
+
+	admin, ok := auth.HttpUserWithPermission(w, r, auth.PermissionUsersManage)
+	if !ok {
+		return
+	}
+
+	err := h.app.Commands.ChangeUserRole.Handle(r.Context(), command.ChangeUserRole{
+		UserUUID:  userUUID,
+		Role:      string(roleChange.Role),
+		ActorUUID: admin.UUID,
+	})
+	if err != nil {
+		httperr.RespondWithSlugError(err, w, r)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h HttpServer) ExportUserData(w http.ResponseWriter, r *http.Request, userUUID string) {
+	if _, ok := auth.HttpUserWithPermission(w, r, auth.PermissionUsersManage); !ok {
+		return
+	}
+
+	archive, err := h.app.Queries.UserDataArchive.Handle(r.Context(), query.UserDataArchive{UserUUID: userUUID})
+	if err != nil {
+		httperr.RespondWithSlugError(err, w, r)
+		return
+	}
+
+	// data exported by other services is passed through as it is
+	render.Respond(w, r, struct {
+		Users     userDataExport  `json:"users"`
+		Trainings json.RawMessage `json:"trainings"`
+		Trainer   json.RawMessage `json:"trainer"`
+	}{
+		Users:     personalDataToExport(archive.Users),
+		Trainings: archive.Trainings,
+		Trainer:   archive.Trainer,
+	})
+}
+
+func (h HttpServer) EraseUserData(w http.ResponseWriter, r *http.Request, userUUID string) {
+	if _, ok := auth.HttpUserWithPermission(w, r, auth.PermissionUsersManage); !ok {
+		return
+	}
+
+	err := h.app.Commands.ForgetUser.Handle(r.Context(), command.ForgetUser{UserUUID: userUUID})
+	if err != nil {
+		httperr.RespondWithSlugError(err, w, r)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func userDetailsToResponse(u query.User) UserDetails {
+	return UserDetails{
+		Uuid:        u.UUID,
+		DisplayName: u.DisplayName,
+		Email:       u.Email,
+		Role:        u.Role,
+		Balance:     u.Balance,
+	}
+}
+
+func purchaseToResponse(p query.Purchase) Purchase {
+	return Purchase{
+		Uuid:        p.UUID,
+		PackageId:   p.PackageID,
+		Credits:     p.Credits,
+		Price:       p.Price,
+		Currency:    p.Currency,
+		Status:      PurchaseStatus(p.Status),
+		CheckoutUrl: p.CheckoutURL,
+	}
+}
+
+// profileUpdateFields maps slugs of the errors returned when updating the profile to the fields of UserProfileUpdate.
+var profileUpdateFields = map[string]string{
+	"invalid-display-name":         "displayName",
+	"invalid-time-zone":            "timeZone",
+	"invalid-locale":               "locale",
+	"invalid-notification-channel": "notificationChannel",
+	"invalid-reminder-lead-time":   "reminderLeadTimeMinutes",
+}
+
+func withProfileFieldError(err error) error {
+	slugErr, ok := errors.AsSlugError(err)
+	if !ok {
+		return err
+	}
+
+	field, ok := profileUpdateFields[slugErr.Slug()]
+	if !ok {
+		return err
+	}
+
+	return slugErr.WithFieldErrors(errors.FieldError{
+		Field:   field,
+		Slug:    slugErr.Slug(),
+		Message: slugErr.Error(),
+	})
+}
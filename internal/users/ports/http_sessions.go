@@ -0,0 +1,124 @@
+package ports
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/auth"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/server/httperr"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/app/command"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/app/query"
+	"github.com/go-chi/render"
+)
+
+// SessionTrackingMiddleware records authenticated requests in the user's session history
+// and rejects requests done with revoked sessions.
+//
+// It must be run after the auth middleware.
+func (h HttpServer) SessionTrackingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authUser, err := auth.UserFromCtx(r.Context())
+		if err != nil {
+			httperr.RespondWithSlugError(err, w, r)
+			return
+		}
+
+		if authUser.SessionID == "" {
+			// tokens without the session can't be revoked, so there is nothing to check
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		err = h.app.Commands.RecordSessionActivity.Handle(r.Context(), command.RecordSessionActivity{
+			UserUUID:  authUser.UUID,
+			SessionID: authUser.SessionID,
+			IP:        clientIP(r),
+			UserAgent: r.UserAgent(),
+		})
+		if err != nil {
+			httperr.RespondWithSlugError(err, w, r)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// clientIP returns the IP of the client, RemoteAddr contains only the IP, when it was set by the RealIP middleware.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+func (h HttpServer) GetCurrentUserSessions(w http.ResponseWriter, r *http.Request) {
+	authUser, err := auth.UserFromCtx(r.Context())
+	if err != nil {
+		httperr.RespondWithSlugError(err, w, r)
+		return
+	}
+
+	sessions, err := h.app.Queries.UserSessions.Handle(r.Context(), query.UserSessions{
+		UserUUID:         authUser.UUID,
+		CurrentSessionID: authUser.SessionID,
+	})
+	if err != nil {
+		httperr.InternalError("cannot-get-sessions", err, w, r)
+		return
+	}
+
+	render.Respond(w, r, sessionsToResponse(sessions))
+}
+
+func (h HttpServer) RevokeCurrentUserSession(w http.ResponseWriter, r *http.Request, sessionId string) {
+	authUser, err := auth.UserFromCtx(r.Context())
+	if err != nil {
+		httperr.RespondWithSlugError(err, w, r)
+		return
+	}
+
+	err = h.app.Commands.RevokeSession.Handle(r.Context(), command.RevokeSession{
+		UserUUID:  authUser.UUID,
+		SessionID: sessionId,
+	})
+	if err != nil {
+		httperr.RespondWithSlugError(err, w, r)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h HttpServer) GetUserSessions(w http.ResponseWriter, r *http.Request, userUUID string) {
+	if _, ok := auth.HttpUserWithPermission(w, r, auth.PermissionUsersManage); !ok {
+		return
+	}
+
+	sessions, err := h.app.Queries.UserSessions.Handle(r.Context(), query.UserSessions{UserUUID: userUUID})
+	if err != nil {
+		httperr.InternalError("cannot-get-sessions", err, w, r)
+		return
+	}
+
+	render.Respond(w, r, sessionsToResponse(sessions))
+}
+
+func sessionsToResponse(sessions []query.Session) []Session {
+	resp := []Session{}
+	for _, s := range sessions {
+		resp = append(resp, Session{
+			Id:         s.ID,
+			Ip:         s.IP,
+			UserAgent:  s.UserAgent,
+			StartedAt:  s.StartedAt,
+			LastSeenAt: s.LastSeenAt,
+			Current:    s.Current,
+			Revoked:    s.Revoked,
+		})
+	}
+
+	return resp
+}
@@ -0,0 +1,526 @@
+// Package ports provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/deepmap/oapi-codegen version v1.8.2 DO NOT EDIT.
+package ports
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/deepmap/oapi-codegen/pkg/runtime"
+	"github.com/go-chi/chi/v5"
+)
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+
+	// (GET /admin/users)
+	GetUsers(w http.ResponseWriter, r *http.Request, params GetUsersParams)
+
+	// (GET /admin/users/{userUUID})
+	GetUser(w http.ResponseWriter, r *http.Request, userUUID string)
+
+	// (POST /admin/users/{userUUID}/credits)
+	AdjustUserCredits(w http.ResponseWriter, r *http.Request, userUUID string)
+
+	// (POST /admin/users/{userUUID}/data-erasure)
+	EraseUserData(w http.ResponseWriter, r *http.Request, userUUID string)
+
+	// (GET /admin/users/{userUUID}/data-export)
+	ExportUserData(w http.ResponseWriter, r *http.Request, userUUID string)
+
+	// (PUT /admin/users/{userUUID}/role)
+	ChangeUserRole(w http.ResponseWriter, r *http.Request, userUUID string)
+
+	// (GET /admin/users/{userUUID}/sessions)
+	GetUserSessions(w http.ResponseWriter, r *http.Request, userUUID string)
+
+	// (GET /credit-packages)
+	GetCreditPackages(w http.ResponseWriter, r *http.Request)
+
+	// (POST /purchases)
+	CreatePurchase(w http.ResponseWriter, r *http.Request)
+
+	// (GET /purchases/{purchaseUUID})
+	GetPurchase(w http.ResponseWriter, r *http.Request, purchaseUUID string)
+
+	// (GET /users/current)
+	GetCurrentUser(w http.ResponseWriter, r *http.Request)
+
+	// (PATCH /users/current)
+	UpdateCurrentUser(w http.ResponseWriter, r *http.Request)
+
+	// (GET /users/current/ledger)
+	GetCurrentUserLedger(w http.ResponseWriter, r *http.Request)
+
+	// (GET /users/current/sessions)
+	GetCurrentUserSessions(w http.ResponseWriter, r *http.Request)
+
+	// (DELETE /users/current/sessions/{sessionId})
+	RevokeCurrentUserSession(w http.ResponseWriter, r *http.Request, sessionId string)
+}
+
+// ServerInterfaceWrapper converts contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler            ServerInterface
+	HandlerMiddlewares []MiddlewareFunc
+}
+
+type MiddlewareFunc func(http.HandlerFunc) http.HandlerFunc
+
+// GetUsers operation middleware
+func (siw *ServerInterfaceWrapper) GetUsers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{""})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetUsersParams
+
+	// ------------- Optional query parameter "search" -------------
+	if paramValue := r.URL.Query().Get("search"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "search", r.URL.Query(), &params.Search)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter search: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+	if paramValue := r.URL.Query().Get("limit"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter limit: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetUsers(w, r, params)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetUser operation middleware
+func (siw *ServerInterfaceWrapper) GetUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "userUUID" -------------
+	var userUUID string
+
+	err = runtime.BindStyledParameter("simple", false, "userUUID", chi.URLParam(r, "userUUID"), &userUUID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter userUUID: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{""})
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetUser(w, r, userUUID)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// AdjustUserCredits operation middleware
+func (siw *ServerInterfaceWrapper) AdjustUserCredits(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "userUUID" -------------
+	var userUUID string
+
+	err = runtime.BindStyledParameter("simple", false, "userUUID", chi.URLParam(r, "userUUID"), &userUUID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter userUUID: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{""})
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.AdjustUserCredits(w, r, userUUID)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// EraseUserData operation middleware
+func (siw *ServerInterfaceWrapper) EraseUserData(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "userUUID" -------------
+	var userUUID string
+
+	err = runtime.BindStyledParameter("simple", false, "userUUID", chi.URLParam(r, "userUUID"), &userUUID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter userUUID: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{""})
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.EraseUserData(w, r, userUUID)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// ExportUserData operation middleware
+func (siw *ServerInterfaceWrapper) ExportUserData(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "userUUID" -------------
+	var userUUID string
+
+	err = runtime.BindStyledParameter("simple", false, "userUUID", chi.URLParam(r, "userUUID"), &userUUID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter userUUID: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{""})
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ExportUserData(w, r, userUUID)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// ChangeUserRole operation middleware
+func (siw *ServerInterfaceWrapper) ChangeUserRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "userUUID" -------------
+	var userUUID string
+
+	err = runtime.BindStyledParameter("simple", false, "userUUID", chi.URLParam(r, "userUUID"), &userUUID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter userUUID: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{""})
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ChangeUserRole(w, r, userUUID)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetUserSessions operation middleware
+func (siw *ServerInterfaceWrapper) GetUserSessions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "userUUID" -------------
+	var userUUID string
+
+	err = runtime.BindStyledParameter("simple", false, "userUUID", chi.URLParam(r, "userUUID"), &userUUID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter userUUID: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{""})
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetUserSessions(w, r, userUUID)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetCreditPackages operation middleware
+func (siw *ServerInterfaceWrapper) GetCreditPackages(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{""})
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetCreditPackages(w, r)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// CreatePurchase operation middleware
+func (siw *ServerInterfaceWrapper) CreatePurchase(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{""})
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreatePurchase(w, r)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetPurchase operation middleware
+func (siw *ServerInterfaceWrapper) GetPurchase(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "purchaseUUID" -------------
+	var purchaseUUID string
+
+	err = runtime.BindStyledParameter("simple", false, "purchaseUUID", chi.URLParam(r, "purchaseUUID"), &purchaseUUID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter purchaseUUID: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{""})
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetPurchase(w, r, purchaseUUID)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetCurrentUser operation middleware
+func (siw *ServerInterfaceWrapper) GetCurrentUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{""})
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetCurrentUser(w, r)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// UpdateCurrentUser operation middleware
+func (siw *ServerInterfaceWrapper) UpdateCurrentUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{""})
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UpdateCurrentUser(w, r)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetCurrentUserLedger operation middleware
+func (siw *ServerInterfaceWrapper) GetCurrentUserLedger(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{""})
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetCurrentUserLedger(w, r)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetCurrentUserSessions operation middleware
+func (siw *ServerInterfaceWrapper) GetCurrentUserSessions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{""})
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetCurrentUserSessions(w, r)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// RevokeCurrentUserSession operation middleware
+func (siw *ServerInterfaceWrapper) RevokeCurrentUserSession(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "sessionId" -------------
+	var sessionId string
+
+	err = runtime.BindStyledParameter("simple", false, "sessionId", chi.URLParam(r, "sessionId"), &sessionId)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter sessionId: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{""})
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RevokeCurrentUserSession(w, r, sessionId)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// Handler creates http.Handler with routing matching OpenAPI spec.
+func Handler(si ServerInterface) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{})
+}
+
+type ChiServerOptions struct {
+	BaseURL     string
+	BaseRouter  chi.Router
+	Middlewares []MiddlewareFunc
+}
+
+// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux.
+func HandlerFromMux(si ServerInterface, r chi.Router) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{
+		BaseRouter: r,
+	})
+}
+
+func HandlerFromMuxWithBaseURL(si ServerInterface, r chi.Router, baseURL string) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{
+		BaseURL:    baseURL,
+		BaseRouter: r,
+	})
+}
+
+// HandlerWithOptions creates http.Handler with additional options
+func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handler {
+	r := options.BaseRouter
+
+	if r == nil {
+		r = chi.NewRouter()
+	}
+	wrapper := ServerInterfaceWrapper{
+		Handler:            si,
+		HandlerMiddlewares: options.Middlewares,
+	}
+
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/admin/users", wrapper.GetUsers)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/admin/users/{userUUID}", wrapper.GetUser)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/admin/users/{userUUID}/credits", wrapper.AdjustUserCredits)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/admin/users/{userUUID}/data-erasure", wrapper.EraseUserData)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/admin/users/{userUUID}/data-export", wrapper.ExportUserData)
+	})
+	r.Group(func(r chi.Router) {
+		r.Put(options.BaseURL+"/admin/users/{userUUID}/role", wrapper.ChangeUserRole)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/admin/users/{userUUID}/sessions", wrapper.GetUserSessions)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/credit-packages", wrapper.GetCreditPackages)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/purchases", wrapper.CreatePurchase)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/purchases/{purchaseUUID}", wrapper.GetPurchase)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/users/current", wrapper.GetCurrentUser)
+	})
+	r.Group(func(r chi.Router) {
+		r.Patch(options.BaseURL+"/users/current", wrapper.UpdateCurrentUser)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/users/current/ledger", wrapper.GetCurrentUserLedger)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/users/current/sessions", wrapper.GetCurrentUserSessions)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/users/current/sessions/{sessionId}", wrapper.RevokeCurrentUserSession)
+	})
+
+	return r
+}
@@ -0,0 +1,119 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/app/query"
+)
+
+// userDataExport is the format of the user's personal data exported by the users service.
+// It's a part of the data archive, so it should be changed in the backward compatible way.
+type userDataExport struct {
+	Profile   *exportedProfile      `json:"profile"`
+	Ledger    []exportedLedgerEntry `json:"ledger"`
+	Purchases []exportedPurchase    `json:"purchases"`
+	Sessions  []exportedSession     `json:"sessions"`
+}
+
+type exportedProfile struct {
+	UUID        string `json:"uuid"`
+	DisplayName string `json:"displayName"`
+	Email       string `json:"email"`
+	Role        string `json:"role"`
+	Balance     int    `json:"balance"`
+
+	Preferences exportedPreferences `json:"preferences"`
+}
+
+type exportedPreferences struct {
+	TimeZone                string `json:"timeZone"`
+	Locale                  string `json:"locale"`
+	NotificationChannel     string `json:"notificationChannel"`
+	ReminderLeadTimeMinutes int    `json:"reminderLeadTimeMinutes"`
+}
+
+type exportedLedgerEntry struct {
+	OperationID  string    `json:"operationId"`
+	Reason       string    `json:"reason"`
+	AmountChange int       `json:"amountChange"`
+	TrainingUUID string    `json:"trainingUuid,omitempty"`
+	Note         string    `json:"note,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+type exportedPurchase struct {
+	UUID      string `json:"uuid"`
+	PackageID string `json:"packageId"`
+	Credits   int    `json:"credits"`
+	Price     int    `json:"price"`
+	Currency  string `json:"currency"`
+	Status    string `json:"status"`
+}
+
+type exportedSession struct {
+	ID         string    `json:"id"`
+	IP         string    `json:"ip"`
+	UserAgent  string    `json:"userAgent"`
+	StartedAt  time.Time `json:"startedAt"`
+	LastSeenAt time.Time `json:"lastSeenAt"`
+	Revoked    bool      `json:"revoked"`
+}
+
+func personalDataToExport(data query.PersonalData) userDataExport {
+	export := userDataExport{
+		Ledger:    []exportedLedgerEntry{},
+		Purchases: []exportedPurchase{},
+		Sessions:  []exportedSession{},
+	}
+
+	if data.User != nil {
+		export.Profile = &exportedProfile{
+			UUID:        data.User.UUID,
+			DisplayName: data.User.DisplayName,
+			Email:       data.User.Email,
+			Role:        data.User.Role,
+			Balance:     data.User.Balance,
+			Preferences: exportedPreferences{
+				TimeZone:                data.User.Preferences.TimeZone,
+				Locale:                  data.User.Preferences.Locale,
+				NotificationChannel:     data.User.Preferences.NotificationChannel,
+				ReminderLeadTimeMinutes: int(data.User.Preferences.ReminderLeadTime / time.Minute),
+			},
+		}
+	}
+
+	for _, e := range data.Ledger {
+		export.Ledger = append(export.Ledger, exportedLedgerEntry{
+			OperationID:  e.OperationID,
+			Reason:       e.Reason,
+			AmountChange: e.AmountChange,
+			TrainingUUID: e.TrainingUUID,
+			Note:         e.Note,
+			CreatedAt:    e.CreatedAt,
+		})
+	}
+
+	for _, p := range data.Purchases {
+		export.Purchases = append(export.Purchases, exportedPurchase{
+			UUID:      p.UUID,
+			PackageID: p.PackageID,
+			Credits:   p.Credits,
+			Price:     p.Price,
+			Currency:  p.Currency,
+			Status:    p.Status,
+		})
+	}
+
+	for _, s := range data.Sessions {
+		export.Sessions = append(export.Sessions, exportedSession{
+			ID:         s.ID,
+			IP:         s.IP,
+			UserAgent:  s.UserAgent,
+			StartedAt:  s.StartedAt,
+			LastSeenAt: s.LastSeenAt,
+			Revoked:    s.Revoked,
+		})
+	}
+
+	return export
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	firebase "firebase.google.com/go/v4"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/adapters"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/app/command"
+	"google.golang.org/api/option"
+)
+
+// newAuthProvider returns the same auth provider, which is used by the HTTP auth middleware.
+func newAuthProvider(ctx context.Context) command.AuthProvider {
+	if mockAuth, _ := strconv.ParseBool(os.Getenv("MOCK_AUTH")); mockAuth {
+		return adapters.MockAuthProvider{}
+	}
+
+	switch provider := strings.ToLower(os.Getenv("AUTH_PROVIDER")); provider {
+	case "", "firebase":
+		return newFirebaseAuthProvider(ctx)
+	case "oidc":
+		return adapters.OIDCAuthProvider{}
+	default:
+		panic(fmt.Sprintf("auth provider '%s' is not supported", provider))
+	}
+}
+
+func newFirebaseAuthProvider(ctx context.Context) command.AuthProvider {
+	var opts []option.ClientOption
+	if file := os.Getenv("SERVICE_ACCOUNT_FILE"); file != "" {
+		opts = append(opts, option.WithCredentialsFile(file))
+	}
+
+	config := &firebase.Config{ProjectID: os.Getenv("GCP_PROJECT")}
+	firebaseApp, err := firebase.NewApp(ctx, config, opts...)
+	if err != nil {
+		panic(err)
+	}
+
+	authClient, err := firebaseApp.Auth(ctx)
+	if err != nil {
+		panic(err)
+	}
+
+	return adapters.NewFirebaseAuthProvider(authClient)
+}
@@ -0,0 +1,522 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/client"
+	usersHTTP "github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/client/users"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/genproto/users"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/server"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/tests"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/adapters"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/ports"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetCurrentUser(t *testing.T) {
+	t.Parallel()
+
+	userUUID := uuid.New().String()
+	token := tests.FakeAttendeeJWT(t, userUUID)
+	httpClient := tests.NewUsersHTTPClient(t, token)
+
+	user := httpClient.GetCurrentUser(t)
+	require.Equal(t, 0, user.Balance)
+	require.Equal(t, "attendee", user.Role)
+
+	grpcClient := newUsersGrpcClient(t)
+
+	_, err := grpcClient.UpdateTrainingBalance(context.Background(), &users.UpdateTrainingBalanceRequest{
+		UserId:       userUUID,
+		AmountChange: 5,
+		OperationId:  uuid.New().String(),
+		Reason:       "manual_adjustment",
+		Actor:        "admin",
+	})
+	require.NoError(t, err)
+
+	user = httpClient.GetCurrentUser(t)
+	require.Equal(t, 5, user.Balance)
+}
+
+func TestUpdateCurrentUser(t *testing.T) {
+	t.Parallel()
+
+	userUUID := uuid.New().String()
+	httpClient := tests.NewUsersHTTPClient(t, tests.FakeAttendeeJWT(t, userUUID))
+
+	user := httpClient.GetCurrentUser(t)
+	require.Equal(t, "UTC", user.Preferences.TimeZone)
+	require.Equal(t, usersHTTP.UserPreferencesNotificationChannelEmail, user.Preferences.NotificationChannel)
+
+	displayName := "Pudzian"
+	timeZone := "Europe/Warsaw"
+	locale := "pl-PL"
+	notificationChannel := usersHTTP.UserProfileUpdateNotificationChannelPush
+	reminderLeadTimeMinutes := 90
+
+	user = httpClient.UpdateCurrentUser(t, usersHTTP.UserProfileUpdate{
+		DisplayName:             &displayName,
+		TimeZone:                &timeZone,
+		Locale:                  &locale,
+		NotificationChannel:     &notificationChannel,
+		ReminderLeadTimeMinutes: &reminderLeadTimeMinutes,
+	}, http.StatusOK)
+	require.Equal(t, "Pudzian", user.DisplayName)
+	require.Equal(t, "Europe/Warsaw", user.Preferences.TimeZone)
+	require.Equal(t, "pl-PL", user.Preferences.Locale)
+	require.Equal(t, usersHTTP.UserPreferencesNotificationChannelPush, user.Preferences.NotificationChannel)
+	require.Equal(t, 90, user.Preferences.ReminderLeadTimeMinutes)
+
+	// the display name from the token doesn't overwrite the name chosen by the user
+	user = httpClient.GetCurrentUser(t)
+	require.Equal(t, "Pudzian", user.DisplayName)
+
+	invalidTimeZone := "Europe/Atlantis"
+	httpClient.UpdateCurrentUser(t, usersHTTP.UserProfileUpdate{TimeZone: &invalidTimeZone}, http.StatusBadRequest)
+
+	u, err := newUsersGrpcClient(t).GetUser(context.Background(), &users.GetUserRequest{UserId: userUUID})
+	require.NoError(t, err)
+	require.Equal(t, "Pudzian", u.DisplayName)
+	require.Equal(t, "Europe/Warsaw", u.Preferences.TimeZone)
+	require.Equal(t, "push", u.Preferences.NotificationChannel)
+	require.EqualValues(t, 90, u.Preferences.ReminderLeadTimeMinutes)
+}
+
+func TestGetUsers_grpc(t *testing.T) {
+	t.Parallel()
+
+	userUUID := uuid.New().String()
+	tests.NewUsersHTTPClient(t, tests.FakeAttendeeJWT(t, userUUID)).GetCurrentUser(t)
+
+	grpcClient := newUsersGrpcClient(t)
+	ctx := context.Background()
+
+	u, err := grpcClient.GetUser(ctx, &users.GetUserRequest{UserId: userUUID})
+	require.NoError(t, err)
+	require.Equal(t, "attendee@threedots.tech", u.Email)
+	require.Equal(t, "attendee", u.Role)
+	require.NotEmpty(t, u.DisplayName)
+
+	_, err = grpcClient.GetUser(ctx, &users.GetUserRequest{UserId: uuid.New().String()})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	batch, err := grpcClient.BatchGetUsers(ctx, &users.BatchGetUsersRequest{
+		UserIds: []string{userUUID, userUUID, uuid.New().String()},
+	})
+	require.NoError(t, err)
+	require.Len(t, batch.Users, 1)
+	require.Equal(t, userUUID, batch.Users[0].Uuid)
+
+	list, err := grpcClient.ListUsers(ctx, &users.ListUsersRequest{Search: "attendee@threedots", Limit: 10})
+	require.NoError(t, err)
+	require.NotEmpty(t, list.Users)
+	require.LessOrEqual(t, len(list.Users), 10)
+}
+
+func TestUpdateTrainingBalance(t *testing.T) {
+	t.Parallel()
+
+	userUUID := uuid.New().String()
+	grpcClient := newUsersGrpcClient(t)
+	ctx := context.Background()
+
+	_, err := grpcClient.UpdateTrainingBalance(ctx, &users.UpdateTrainingBalanceRequest{
+		UserId:       userUUID,
+		AmountChange: 2,
+		OperationId:  uuid.New().String(),
+		Reason:       "manual_adjustment",
+		Actor:        "admin",
+	})
+	require.NoError(t, err)
+
+	_, err = grpcClient.UpdateTrainingBalance(ctx, &users.UpdateTrainingBalanceRequest{
+		UserId:       userUUID,
+		AmountChange: -3,
+		OperationId:  uuid.New().String(),
+		Reason:       "booking",
+		TrainingUuid: uuid.New().String(),
+		Actor:        userUUID,
+	})
+	require.Error(t, err, "balance can't be negative")
+
+	_, err = grpcClient.UpdateTrainingBalance(ctx, &users.UpdateTrainingBalanceRequest{
+		UserId:       userUUID,
+		AmountChange: -2,
+		OperationId:  uuid.New().String(),
+		Reason:       "manual_adjustment",
+		Actor:        "admin",
+	})
+	require.NoError(t, err)
+
+	resp, err := grpcClient.GetTrainingBalance(ctx, &users.GetTrainingBalanceRequest{UserId: userUUID})
+	require.NoError(t, err)
+	require.EqualValues(t, 0, resp.Amount)
+}
+
+func TestUpdateTrainingBalance_retried(t *testing.T) {
+	t.Parallel()
+
+	userUUID := uuid.New().String()
+	trainingUUID := uuid.New().String()
+	grpcClient := newUsersGrpcClient(t)
+	ctx := context.Background()
+
+	_, err := grpcClient.UpdateTrainingBalance(ctx, &users.UpdateTrainingBalanceRequest{
+		UserId:       userUUID,
+		AmountChange: 3,
+		OperationId:  uuid.New().String(),
+		Reason:       "manual_adjustment",
+		Actor:        "admin",
+	})
+	require.NoError(t, err)
+
+	refund := &users.UpdateTrainingBalanceRequest{
+		UserId:       userUUID,
+		AmountChange: 1,
+		OperationId:  "cancel-refund-" + trainingUUID,
+		Reason:       "cancel_refund",
+		TrainingUuid: trainingUUID,
+		Actor:        userUUID,
+	}
+	for i := 0; i < 2; i++ {
+		_, err = grpcClient.UpdateTrainingBalance(ctx, refund)
+		require.NoError(t, err)
+	}
+
+	resp, err := grpcClient.GetTrainingBalance(ctx, &users.GetTrainingBalanceRequest{UserId: userUUID})
+	require.NoError(t, err)
+	require.EqualValues(t, 4, resp.Amount)
+
+	httpClient := tests.NewUsersHTTPClient(t, tests.FakeAttendeeJWT(t, userUUID))
+	ledger := httpClient.GetCurrentUserLedger(t)
+	require.Len(t, ledger, 2)
+
+	require.Equal(t, refund.OperationId, ledger[0].OperationId)
+	require.EqualValues(t, "cancel_refund", ledger[0].Reason)
+	require.Equal(t, 1, ledger[0].AmountChange)
+	require.NotNil(t, ledger[0].TrainingUuid)
+	require.Equal(t, trainingUUID, *ledger[0].TrainingUuid)
+	require.Equal(t, userUUID, ledger[0].Actor)
+
+	require.EqualValues(t, "manual_adjustment", ledger[1].Reason)
+	require.Nil(t, ledger[1].TrainingUuid)
+}
+
+func TestUpdateTrainingBalance_invalid_reason(t *testing.T) {
+	t.Parallel()
+
+	grpcClient := newUsersGrpcClient(t)
+
+	_, err := grpcClient.UpdateTrainingBalance(context.Background(), &users.UpdateTrainingBalanceRequest{
+		UserId:       uuid.New().String(),
+		AmountChange: 1,
+		OperationId:  uuid.New().String(),
+		Reason:       "gift",
+		Actor:        "admin",
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestPurchaseCreditPackage(t *testing.T) {
+	t.Parallel()
+
+	userUUID := uuid.New().String()
+	httpClient := tests.NewUsersHTTPClient(t, tests.FakeAttendeeJWT(t, userUUID))
+
+	packages := httpClient.GetCreditPackages(t)
+	require.NotEmpty(t, packages)
+	creditPackage := packages[0]
+
+	purchase := httpClient.CreatePurchase(t, creditPackage.Id)
+	require.EqualValues(t, "pending", purchase.Status)
+	require.Equal(t, creditPackage.Credits, purchase.Credits)
+	require.NotEmpty(t, purchase.CheckoutUrl)
+
+	// the provider may deliver the webhook more than once
+	for i := 0; i < 2; i++ {
+		require.Equal(t, http.StatusNoContent, sendPaymentWebhook(t, purchase.Uuid, true))
+	}
+
+	require.EqualValues(t, "paid", httpClient.GetPurchase(t, purchase.Uuid).Status)
+	currentUser := httpClient.GetCurrentUser(t)
+	require.Equal(t, creditPackage.Credits, currentUser.Balance)
+	require.Len(t, currentUser.ExpiringCredits, 1)
+	require.Equal(t, creditPackage.Credits, currentUser.ExpiringCredits[0].Amount)
+	require.True(t, currentUser.ExpiringCredits[0].ExpiresAt.After(time.Now().AddDate(0, 2, 0)))
+
+	ledger := httpClient.GetCurrentUserLedger(t)
+	require.Len(t, ledger, 1)
+	require.EqualValues(t, "purchase", ledger[0].Reason)
+	require.Equal(t, creditPackage.Credits, ledger[0].AmountChange)
+}
+
+func TestPurchaseCreditPackage_payment_failed(t *testing.T) {
+	t.Parallel()
+
+	userUUID := uuid.New().String()
+	httpClient := tests.NewUsersHTTPClient(t, tests.FakeAttendeeJWT(t, userUUID))
+
+	purchase := httpClient.CreatePurchase(t, httpClient.GetCreditPackages(t)[0].Id)
+
+	require.Equal(t, http.StatusNoContent, sendPaymentWebhook(t, purchase.Uuid, false))
+
+	require.EqualValues(t, "failed", httpClient.GetPurchase(t, purchase.Uuid).Status)
+	require.Equal(t, 0, httpClient.GetCurrentUser(t).Balance)
+}
+
+func TestPaymentWebhook_invalid_signature(t *testing.T) {
+	t.Parallel()
+
+	payload, headers, err := adapters.NewFakePaymentProvider("invalid-secret").SignedWebhook(uuid.New().String(), true)
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusBadRequest, postPaymentWebhook(t, payload, headers))
+}
+
+func TestAdminUsersManagement(t *testing.T) {
+	t.Parallel()
+
+	userUUID := uuid.New().String()
+	attendeeClient := tests.NewUsersHTTPClient(t, tests.FakeAttendeeJWT(t, userUUID))
+
+	// the profile is copied from the token, when the user uses the service
+	attendeeClient.GetCurrentUser(t)
+
+	adminUUID := uuid.New().String()
+	adminClient := tests.NewUsersHTTPClient(t, tests.FakeAdminJWT(t, adminUUID))
+
+	foundUsers := adminClient.GetUsers(t, "ATTENDEE@threedots")
+	require.NotEmpty(t, foundUsers)
+	for _, u := range foundUsers {
+		require.Contains(t, u.Email, "attendee@threedots")
+	}
+
+	details := adminClient.GetUser(t, userUUID)
+	require.Equal(t, "attendee", details.Role)
+	require.Equal(t, "attendee@threedots.tech", details.Email)
+	require.Equal(t, 0, details.Balance)
+
+	adminClient.AdjustUserCredits(t, userUUID, 3, "compensation for cancelled class", http.StatusNoContent)
+	adminClient.AdjustUserCredits(t, userUUID, -4, "too much taken", http.StatusUnprocessableEntity)
+	adminClient.AdjustUserCredits(t, userUUID, 1, "", http.StatusBadRequest)
+	require.Equal(t, 3, adminClient.GetUser(t, userUUID).Balance)
+
+	ledger := attendeeClient.GetCurrentUserLedger(t)
+	require.Len(t, ledger, 1)
+	require.Equal(t, adminUUID, ledger[0].Actor)
+	require.NotNil(t, ledger[0].Note)
+	require.Equal(t, "compensation for cancelled class", *ledger[0].Note)
+
+	adminClient.ChangeUserRole(t, userUUID, "trainer", http.StatusNoContent)
+	require.Equal(t, "trainer", adminClient.GetUser(t, userUUID).Role)
+
+	adminClient.ChangeUserRole(t, adminUUID, "attendee", http.StatusBadRequest)
+}
+
+func TestSessions(t *testing.T) {
+	t.Parallel()
+
+	userUUID := uuid.New().String()
+	firstLogin := time.Now().Add(-time.Hour)
+	secondLogin := time.Now()
+
+	firstClient := tests.NewUsersHTTPClient(t, tests.FakeAttendeeJWTWithAuthTime(t, userUUID, firstLogin))
+	secondClient := tests.NewUsersHTTPClient(t, tests.FakeAttendeeJWTWithAuthTime(t, userUUID, secondLogin))
+
+	firstClient.GetCurrentUser(t)
+	secondClient.GetCurrentUser(t)
+
+	sessions := secondClient.GetCurrentUserSessions(t)
+	require.Len(t, sessions, 2)
+
+	firstSessionID := ""
+	for _, s := range sessions {
+		require.NotEmpty(t, s.Ip)
+		require.False(t, s.Revoked)
+
+		if s.Current {
+			require.Equal(t, fmt.Sprintf("%d", secondLogin.Unix()), s.Id)
+		} else {
+			firstSessionID = s.Id
+		}
+	}
+	require.Equal(t, fmt.Sprintf("%d", firstLogin.Unix()), firstSessionID)
+
+	secondClient.RevokeCurrentUserSession(t, firstSessionID, http.StatusNoContent)
+	secondClient.RevokeCurrentUserSession(t, "not-existing", http.StatusNotFound)
+
+	// the revoked session can't be used anymore
+	firstClient.RevokeCurrentUserSession(t, firstSessionID, http.StatusUnauthorized)
+
+	// other services check revoked sessions through gRPC
+	grpcClient := newUsersGrpcClient(t)
+	for sessionID, expectedRevoked := range map[string]bool{
+		firstSessionID:                        true,
+		fmt.Sprintf("%d", secondLogin.Unix()): false,
+		"not-recorded":                        false,
+	} {
+		resp, err := grpcClient.IsSessionRevoked(context.Background(), &users.IsSessionRevokedRequest{
+			UserId:    userUUID,
+			SessionId: sessionID,
+		})
+		require.NoError(t, err)
+		require.Equal(t, expectedRevoked, resp.Revoked, "session %s", sessionID)
+	}
+
+	adminClient := tests.NewUsersHTTPClient(t, tests.FakeAdminJWT(t, uuid.New().String()))
+	sessions = adminClient.GetUserSessions(t, userUUID)
+	require.Len(t, sessions, 2)
+	for _, s := range sessions {
+		require.Equal(t, s.Id == firstSessionID, s.Revoked)
+		require.False(t, s.Current)
+	}
+}
+
+func TestAdminUsersManagement_not_admin(t *testing.T) {
+	t.Parallel()
+
+	userUUID := uuid.New().String()
+	attendeeClient := tests.NewUsersHTTPClient(t, tests.FakeAttendeeJWT(t, userUUID))
+
+	attendeeClient.AdjustUserCredits(t, userUUID, 10, "free credits", http.StatusForbidden)
+	attendeeClient.ChangeUserRole(t, userUUID, "admin", http.StatusForbidden)
+}
+
+func TestExportAndEraseUserData(t *testing.T) {
+	t.Parallel()
+
+	userUUID := uuid.New().String()
+	attendeeClient := tests.NewUsersHTTPClient(t, tests.FakeAttendeeJWT(t, userUUID))
+	attendeeClient.GetCurrentUser(t)
+
+	adminClient := tests.NewUsersHTTPClient(t, tests.FakeAdminJWT(t, uuid.New().String()))
+	adminClient.AdjustUserCredits(t, userUUID, 3, "welcome bonus", http.StatusNoContent)
+
+	archive := adminClient.ExportUserData(t, userUUID)
+	profile, ok := archive.Users.AdditionalProperties["profile"].(map[string]interface{})
+	require.True(t, ok)
+	require.Equal(t, "attendee@threedots.tech", profile["email"])
+	require.Len(t, archive.Users.AdditionalProperties["ledger"], 1)
+
+	grpcClient := newUsersGrpcClient(t)
+	exported, err := grpcClient.ExportUserData(context.Background(), &users.ExportUserDataRequest{UserId: userUUID})
+	require.NoError(t, err)
+	require.Contains(t, string(exported.Data), "attendee@threedots.tech")
+
+	adminClient.EraseUserData(t, userUUID, http.StatusNoContent)
+
+	details := adminClient.GetUser(t, userUUID)
+	require.Empty(t, details.Email)
+	require.Empty(t, details.DisplayName)
+	// credits are kept for the accounting
+	require.Equal(t, 3, details.Balance)
+
+	// erasing is idempotent
+	adminClient.EraseUserData(t, userUUID, http.StatusNoContent)
+}
+
+func TestExportAndEraseUserData_not_admin(t *testing.T) {
+	t.Parallel()
+
+	userUUID := uuid.New().String()
+	attendeeClient := tests.NewUsersHTTPClient(t, tests.FakeAttendeeJWT(t, userUUID))
+
+	attendeeClient.EraseUserData(t, userUUID, http.StatusForbidden)
+}
+
+func sendPaymentWebhook(t *testing.T, purchaseUUID string, paid bool) int {
+	t.Helper()
+
+	provider := adapters.NewFakePaymentProvider(os.Getenv("FAKE_PAYMENT_WEBHOOK_SECRET"))
+
+	payload, headers, err := provider.SignedWebhook(purchaseUUID, paid)
+	require.NoError(t, err)
+
+	return postPaymentWebhook(t, payload, headers)
+}
+
+func postPaymentWebhook(t *testing.T, payload []byte, headers http.Header) int {
+	t.Helper()
+
+	url := fmt.Sprintf("http://%s/webhooks/payments", os.Getenv("USERS_HTTP_ADDR"))
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+	require.NoError(t, err)
+	req.Header = headers
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	return resp.StatusCode
+}
+
+func newUsersGrpcClient(t *testing.T) users.UsersServiceClient {
+	t.Helper()
+
+	grpcClient, closeClient, err := client.NewUsersClient()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = closeClient()
+	})
+
+	return grpcClient
+}
+
+func startService() bool {
+	app := NewComponentTestApplication(context.Background())
+
+	usersHTTPAddr := os.Getenv("USERS_HTTP_ADDR")
+	httpServer := ports.NewHttpServer(app)
+	go server.RunHTTPServerOnAddr(
+		usersHTTPAddr,
+		func(router chi.Router) http.Handler {
+			return ports.HandlerFromMux(httpServer, router)
+		},
+		server.WithAuthenticatedMiddlewares(httpServer.SessionTrackingMiddleware),
+		server.WithPublicRoutes("/webhooks", func(router chi.Router) http.Handler {
+			router.Post("/payments", httpServer.HandlePaymentWebhook)
+			return router
+		}),
+	)
+
+	usersGrpcAddr := os.Getenv("USERS_GRPC_ADDR")
+	go server.RunGRPCServerOnAddr(usersGrpcAddr, func(server *grpc.Server) {
+		svc := ports.NewGrpcServer(app)
+		users.RegisterUsersServiceServer(server, svc)
+	})
+
+	ok := tests.WaitForPort(usersHTTPAddr)
+	if !ok {
+		log.Println("Timed out waiting for users HTTP to come up")
+		return false
+	}
+
+	ok = tests.WaitForPort(usersGrpcAddr)
+	if !ok {
+		log.Println("Timed out waiting for users gRPC to come up")
+	}
+
+	return ok
+}
+
+func TestMain(m *testing.M) {
+	if !startService() {
+		os.Exit(1)
+	}
+
+	os.Exit(m.Run())
+}
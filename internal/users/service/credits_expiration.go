@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/app/command"
+	"github.com/sirupsen/logrus"
+)
+
+const creditsExpirationInterval = time.Hour
+
+// expireCreditsPeriodically runs the credits expiration in the background.
+//
+// It's running in every instance of the service, expiring credits of the same user by two instances is safe,
+// because it's done in a transaction and expiration ledger entries are deduplicated.
+func expireCreditsPeriodically(ctx context.Context, expireCredits command.ExpireCreditsHandler, logger *logrus.Entry) {
+	ticker := time.NewTicker(creditsExpirationInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := expireCredits.Handle(ctx, command.ExpireCredits{Now: time.Now().UTC()}); err != nil {
+			logger.WithError(err).Error("Unable to expire credits")
+		}
+	}
+}
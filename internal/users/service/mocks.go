@@ -0,0 +1,16 @@
+package service
+
+import (
+	"context"
+)
+
+type UserDataServiceMock struct {
+}
+
+func (s UserDataServiceMock) ExportUserData(ctx context.Context, userUUID string) ([]byte, error) {
+	return []byte("{}"), nil
+}
+
+func (s UserDataServiceMock) EraseUserData(ctx context.Context, userUUID string) error {
+	return nil
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/adapters"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/app/command"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/domain/purchase"
+)
+
+// creditPackages are packages offered to attendees, prices are in cents.
+var creditPackages = []purchase.CreditPackage{
+	purchase.MustNewCreditPackage("single", "Single training", 1, 1500, "EUR"),
+	purchase.MustNewCreditPackage("5-trainings", "5 trainings", 5, 6500, "EUR"),
+	purchase.MustNewCreditPackage("10-trainings", "10 trainings", 10, 12000, "EUR"),
+}
+
+func newCatalogue() purchase.Catalogue {
+	catalogue, err := purchase.NewCatalogue(creditPackages)
+	if err != nil {
+		panic(err)
+	}
+
+	return catalogue
+}
+
+// newPaymentProvider creates the payment provider selected with PAYMENT_PROVIDER env.
+// When it's not set, purchases are disabled and nil is returned.
+func newPaymentProvider() command.PaymentProvider {
+	provider := strings.ToLower(os.Getenv("PAYMENT_PROVIDER"))
+
+	switch provider {
+	case "":
+		return nil
+	case "fake":
+		return adapters.NewFakePaymentProvider(os.Getenv("FAKE_PAYMENT_WEBHOOK_SECRET"))
+	default:
+		panic(fmt.Sprintf("payment provider '%s' is not supported", provider))
+	}
+}
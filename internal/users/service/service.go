@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"cloud.google.com/go/firestore"
+	grpcClient "github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/client"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/metrics"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/mysqldb"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/common/server"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/adapters"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/app"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/app/command"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/app/query"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/domain/purchase"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/domain/session"
+	"github.com/ThreeDotsLabs/wild-workouts-go-ddd-example/internal/users/domain/user"
+	"github.com/sirupsen/logrus"
+)
+
+// userDataService is another service storing personal data of users.
+type userDataService interface {
+	command.UserDataService
+	query.UserDataExporter
+}
+
+type usersRepository interface {
+	user.Repository
+	query.UserDetailsReadModel
+	query.UsersReadModel
+	query.UsersByUUIDsReadModel
+	query.TrainingBalanceReadModel
+	query.ExpiringCreditsReadModel
+	query.LedgerReadModel
+}
+
+type purchasesRepository interface {
+	purchase.Repository
+	query.PurchaseReadModel
+	query.UserPurchasesReadModel
+}
+
+type sessionsRepository interface {
+	session.Repository
+	query.UserSessionsReadModel
+}
+
+// storage contains repositories of all data stored by the service.
+type storage struct {
+	users     usersRepository
+	purchases purchasesRepository
+	sessions  sessionsRepository
+
+	healthChecks []server.HealthCheck
+}
+
+// NewApplication returns the application, checks of storage and services required to serve requests
+// and the function closing connections to other services.
+func NewApplication(ctx context.Context) (app.Application, []server.HealthCheck, func()) {
+	trainingsClient, closeTrainingsClient, err := grpcClient.NewTrainingsClient()
+	if err != nil {
+		panic(err)
+	}
+
+	trainerClient, closeTrainerClient, err := grpcClient.NewTrainerClient()
+	if err != nil {
+		panic(err)
+	}
+
+	trainingsHealthClient, closeTrainingsHealthClient, err := grpcClient.NewTrainingsHealthClient()
+	if err != nil {
+		panic(err)
+	}
+
+	trainerHealthClient, closeTrainerHealthClient, err := grpcClient.NewTrainerHealthClient()
+	if err != nil {
+		panic(err)
+	}
+
+	trainingsGrpc := adapters.NewTrainingsGrpc(trainingsClient)
+	trainerGrpc := adapters.NewTrainerGrpc(trainerClient)
+
+	application, healthChecks := newApplication(ctx, trainingsGrpc, trainerGrpc)
+	healthChecks = append(
+		healthChecks,
+		server.GRPCServiceHealthCheck("trainings", trainingsHealthClient),
+		server.GRPCServiceHealthCheck("trainer", trainerHealthClient),
+	)
+
+	return application,
+		healthChecks,
+		func() {
+			_ = closeTrainingsClient()
+			_ = closeTrainerClient()
+			_ = closeTrainingsHealthClient()
+			_ = closeTrainerHealthClient()
+		}
+}
+
+func NewComponentTestApplication(ctx context.Context) app.Application {
+	application, _ := newApplication(ctx, UserDataServiceMock{}, UserDataServiceMock{})
+	return application
+}
+
+func newApplication(
+	ctx context.Context,
+	trainingsService userDataService,
+	trainerService userDataService,
+) (app.Application, []server.HealthCheck) {
+	usersStorage := newStorage(ctx)
+	usersRepository := usersStorage.users
+	purchasesRepository := usersStorage.purchases
+	sessionsRepository := usersStorage.sessions
+
+	catalogue := newCatalogue()
+	paymentProvider := newPaymentProvider()
+	authProvider := newAuthProvider(ctx)
+
+	logger := logrus.NewEntry(logrus.StandardLogger())
+	metricsClient := metrics.Prometheus{}
+
+	expireCredits := command.NewExpireCreditsHandler(usersRepository, logger, metricsClient)
+	go expireCreditsPeriodically(ctx, expireCredits, logger)
+
+	application := app.Application{
+		Commands: app.Commands{
+			UpdateTrainingBalance: command.NewUpdateTrainingBalanceHandler(usersRepository, logger, metricsClient),
+			SyncAuthProfile:       command.NewSyncAuthProfileHandler(usersRepository, logger, metricsClient),
+			UpdateUserProfile:     command.NewUpdateUserProfileHandler(usersRepository, logger, metricsClient),
+			ExpireCredits:         expireCredits,
+
+			RecordSessionActivity: command.NewRecordSessionActivityHandler(sessionsRepository, logger, metricsClient),
+			RevokeSession:         command.NewRevokeSessionHandler(sessionsRepository, logger, metricsClient),
+
+			AdjustCredits:  command.NewAdjustCreditsHandler(usersRepository, logger, metricsClient),
+			ChangeUserRole: command.NewChangeUserRoleHandler(usersRepository, authProvider, logger, metricsClient),
+
+			EraseUserData: command.NewEraseUserDataHandler(
+				usersRepository,
+				sessionsRepository,
+				authProvider,
+				logger,
+				metricsClient,
+			),
+			ForgetUser: command.NewForgetUserHandler(
+				usersRepository,
+				sessionsRepository,
+				authProvider,
+				trainingsService,
+				trainerService,
+				logger,
+				metricsClient,
+			),
+
+			CreatePurchaseCheckout: command.NewCreatePurchaseCheckoutHandler(
+				catalogue,
+				purchasesRepository,
+				paymentProvider,
+				logger,
+				metricsClient,
+			),
+		},
+		Queries: app.Queries{
+			TrainingBalance: query.NewTrainingBalanceHandler(usersRepository, logger, metricsClient),
+			Ledger:          query.NewLedgerHandler(usersRepository, logger, metricsClient),
+			ExpiringCredits: query.NewUserExpiringCreditsHandler(usersRepository, logger, metricsClient),
+
+			CreditPackages: query.NewCreditPackagesHandler(catalogue, logger, metricsClient),
+			UserPurchase:   query.NewUserPurchaseHandler(purchasesRepository, logger, metricsClient),
+
+			Users:        query.NewUsersHandler(usersRepository, logger, metricsClient),
+			UserDetails:  query.NewUserDetailsHandler(usersRepository, logger, metricsClient),
+			UsersByUUIDs: query.NewUsersByUUIDsHandler(usersRepository, logger, metricsClient),
+
+			UserSessions:   query.NewUserSessionsHandler(sessionsRepository, logger, metricsClient),
+			SessionRevoked: query.NewSessionRevokedHandler(sessionsRepository, logger, metricsClient),
+
+			UserData: query.NewUserDataHandler(
+				usersRepository,
+				purchasesRepository,
+				sessionsRepository,
+				logger,
+				metricsClient,
+			),
+			UserDataArchive: query.NewUserDataArchiveHandler(
+				usersRepository,
+				purchasesRepository,
+				sessionsRepository,
+				trainingsService,
+				trainerService,
+				logger,
+				metricsClient,
+			),
+		},
+	}
+
+	if paymentProvider != nil {
+		application.Commands.HandlePaymentWebhook = command.NewHandlePaymentWebhookHandler(
+			purchasesRepository,
+			usersRepository,
+			paymentProvider,
+			logger,
+			metricsClient,
+		)
+	}
+
+	return application, usersStorage.healthChecks
+}
+
+// newStorage creates repositories for the storage selected with USERS_STORAGE env (firestore by default).
+// All data of the service is kept in the selected storage, so the other one is not required.
+func newStorage(ctx context.Context) storage {
+	storageType := strings.ToLower(os.Getenv("USERS_STORAGE"))
+
+	switch storageType {
+	case "", "firestore":
+		firestoreClient, err := firestore.NewClient(ctx, os.Getenv("GCP_PROJECT"))
+		if err != nil {
+			panic(err)
+		}
+
+		return storage{
+			users:        adapters.NewUsersFirestoreRepository(firestoreClient),
+			purchases:    adapters.NewPurchasesFirestoreRepository(firestoreClient),
+			sessions:     adapters.NewSessionsFirestoreRepository(firestoreClient),
+			healthChecks: []server.HealthCheck{server.FirestoreHealthCheck(firestoreClient)},
+		}
+	case "mysql":
+		db, err := mysqldb.NewConnection()
+		if err != nil {
+			panic(err)
+		}
+
+		if err := adapters.MigrateMySQL(ctx, db); err != nil {
+			panic(err)
+		}
+
+		return storage{
+			users:        adapters.NewUsersMySQLRepository(db),
+			purchases:    adapters.NewPurchasesMySQLRepository(db),
+			sessions:     adapters.NewSessionsMySQLRepository(db),
+			healthChecks: []server.HealthCheck{server.MySQLHealthCheck(db)},
+		}
+	default:
+		panic(fmt.Sprintf("storage '%s' is not supported", storageType))
+	}
+}
@@ -1,3 +1,20 @@
+locals {
+  # Cloud Run services have deterministic URLs, which are known before the services are created.
+  # They are used instead of module outputs, because gRPC servers need their own URL as the audience of identity tokens.
+  trainer_grpc_host   = "trainer-grpc-${google_project.project.number}.${var.region}.run.app"
+  trainings_grpc_host = "trainings-grpc-${google_project.project.number}.${var.region}.run.app"
+  users_grpc_host     = "users-grpc-${google_project.project.number}.${var.region}.run.app"
+
+  service_accounts = {
+    for name, account in google_service_account.service : name => "serviceAccount:${account.email}"
+  }
+
+  # names of services used in allowlists of gRPC servers, mapped to emails of their service accounts
+  grpc_service_identities = join(";", [
+    for name, account in google_service_account.service : "${name}=${account.email}"
+  ])
+}
+
 module cloud_run_trainer_grpc {
   source = "./service"
 
@@ -5,8 +22,25 @@ module cloud_run_trainer_grpc {
   location   = var.region
   dependency = null_resource.init_docker_images
 
-  name     = "trainer"
-  protocol = "grpc"
+  name            = "trainer"
+  protocol        = "grpc"
+  service_account = google_service_account.service["trainer"].email
+  invokers = [
+    local.service_accounts["trainer"],
+    local.service_accounts["trainings"],
+    local.service_accounts["users"],
+  ]
+
+  envs = [
+    {
+      name  = "GRPC_AUTH_AUDIENCE"
+      value = "https://${local.trainer_grpc_host}"
+    },
+    {
+      name  = "GRPC_SERVICE_IDENTITIES"
+      value = local.grpc_service_identities
+    }
+  ]
 }
 
 module cloud_run_trainer_http {
@@ -16,14 +50,56 @@ module cloud_run_trainer_http {
   location   = var.region
   dependency = null_resource.init_docker_images
 
-  name     = "trainer"
-  protocol = "http"
-  auth     = false
+  name            = "trainer"
+  protocol        = "http"
+  auth            = false
+  service_account = google_service_account.service["trainer"].email
 
   envs = [
     {
       name  = "TRAINER_GRPC_ADDR"
-      value = module.cloud_run_trainer_grpc.endpoint
+      value = "${local.trainer_grpc_host}:443"
+    },
+    {
+      name  = "USERS_GRPC_ADDR"
+      value = "${local.users_grpc_host}:443"
+    }
+  ]
+}
+
+module cloud_run_trainings_grpc {
+  source = "./service"
+
+  project    = var.project
+  location   = var.region
+  dependency = [
+    null_resource.init_docker_images,
+    google_secret_manager_secret_version.anonymization_key,
+    google_secret_manager_secret_iam_member.trainings_anonymization_key,
+  ]
+
+  name            = "trainings"
+  protocol        = "grpc"
+  service_account = google_service_account.service["trainings"].email
+  invokers = [
+    local.service_accounts["users"],
+  ]
+
+  secret_envs = [
+    {
+      name   = "ANONYMIZATION_KEY"
+      secret = google_secret_manager_secret.anonymization_key.secret_id
+    }
+  ]
+
+  envs = [
+    {
+      name  = "GRPC_AUTH_AUDIENCE"
+      value = "https://${local.trainings_grpc_host}"
+    },
+    {
+      name  = "GRPC_SERVICE_IDENTITIES"
+      value = local.grpc_service_identities
     }
   ]
 }
@@ -33,20 +109,32 @@ module cloud_run_trainings_http {
 
   project    = var.project
   location   = var.region
-  dependency = null_resource.init_docker_images
+  dependency = [
+    null_resource.init_docker_images,
+    google_secret_manager_secret_version.anonymization_key,
+    google_secret_manager_secret_iam_member.trainings_anonymization_key,
+  ]
+
+  name            = "trainings"
+  protocol        = "http"
+  auth            = false
+  service_account = google_service_account.service["trainings"].email
 
-  name     = "trainings"
-  protocol = "http"
-  auth     = false
+  secret_envs = [
+    {
+      name   = "ANONYMIZATION_KEY"
+      secret = google_secret_manager_secret.anonymization_key.secret_id
+    }
+  ]
 
   envs = [
     {
       name  = "TRAINER_GRPC_ADDR"
-      value = module.cloud_run_trainer_grpc.endpoint
+      value = "${local.trainer_grpc_host}:443"
     },
     {
       name  = "USERS_GRPC_ADDR"
-      value = module.cloud_run_users_grpc.endpoint
+      value = "${local.users_grpc_host}:443"
     }
   ]
 }
@@ -58,8 +146,25 @@ module cloud_run_users_grpc {
   location   = var.region
   dependency = null_resource.init_docker_images
 
-  name     = "users"
-  protocol = "grpc"
+  name            = "users"
+  protocol        = "grpc"
+  service_account = google_service_account.service["users"].email
+  invokers = [
+    local.service_accounts["trainer"],
+    local.service_accounts["trainings"],
+    local.service_accounts["users"],
+  ]
+
+  envs = [
+    {
+      name  = "GRPC_AUTH_AUDIENCE"
+      value = "https://${local.users_grpc_host}"
+    },
+    {
+      name  = "GRPC_SERVICE_IDENTITIES"
+      value = local.grpc_service_identities
+    }
+  ]
 }
 
 module cloud_run_users_http {
@@ -69,14 +174,23 @@ module cloud_run_users_http {
   location   = var.region
   dependency = null_resource.init_docker_images
 
-  name     = "users"
-  protocol = "http"
-  auth     = false
+  name            = "users"
+  protocol        = "http"
+  auth            = false
+  service_account = google_service_account.service["users"].email
 
   envs = [
     {
       name  = "USERS_GRPC_ADDR"
-      value = module.cloud_run_users_grpc.endpoint
+      value = "${local.users_grpc_host}:443"
+    },
+    {
+      name  = "TRAINER_GRPC_ADDR"
+      value = "${local.trainer_grpc_host}:443"
+    },
+    {
+      name  = "TRAININGS_GRPC_ADDR"
+      value = "${local.trainings_grpc_host}:443"
     }
   ]
 }
@@ -32,3 +32,37 @@ resource "google_service_account_iam_member" "default-compute-account" {
   depends_on = [google_project_service.cloud_build]
 }
 
+
+# each service runs as its own service account, so gRPC servers can tell which service is calling them
+resource "google_service_account" "service" {
+  for_each = toset(["trainer", "trainings", "users"])
+
+  account_id   = "${each.value}-service"
+  display_name = "${title(each.value)} Service Account"
+
+  depends_on = [google_project_iam_member.owner]
+}
+
+resource "google_project_iam_member" "service_datastore_user" {
+  for_each = google_service_account.service
+
+  role   = "roles/datastore.user"
+  member = "serviceAccount:${each.value.email}"
+}
+
+# the users service changes roles of users and deletes their accounts
+resource "google_project_iam_member" "users_firebase_auth_admin" {
+  role   = "roles/firebaseauth.admin"
+  member = "serviceAccount:${google_service_account.service["users"].email}"
+}
+
+# cloud build deploys new revisions, which run as the services' accounts
+resource "google_service_account_iam_member" "cloud_build_service_account_user" {
+  for_each = google_service_account.service
+
+  service_account_id = each.value.name
+  role               = "roles/iam.serviceAccountUser"
+  member             = local.cloud_build_member
+
+  depends_on = [google_project_service.cloud_build]
+}
@@ -58,3 +58,8 @@ resource "google_project_service" "source_repo" {
   service    = "sourcerepo.googleapis.com"
   depends_on = [google_project.project]
 }
+
+resource "google_project_service" "secret_manager" {
+  service    = "secretmanager.googleapis.com"
+  depends_on = [google_project.project]
+}
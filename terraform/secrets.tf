@@ -0,0 +1,26 @@
+# key used by the trainings service to derive UUIDs of anonymous users, when personal data of the user is erased
+resource "random_password" "anonymization_key" {
+  length  = 32
+  special = false
+}
+
+resource "google_secret_manager_secret" "anonymization_key" {
+  secret_id = "anonymization-key"
+
+  replication {
+    automatic = true
+  }
+
+  depends_on = [google_project_service.secret_manager]
+}
+
+resource "google_secret_manager_secret_version" "anonymization_key" {
+  secret      = google_secret_manager_secret.anonymization_key.id
+  secret_data = random_password.anonymization_key.result
+}
+
+resource "google_secret_manager_secret_iam_member" "trainings_anonymization_key" {
+  secret_id = google_secret_manager_secret.anonymization_key.id
+  role      = "roles/secretmanager.secretAccessor"
+  member    = "serviceAccount:${google_service_account.service["trainings"].email}"
+}
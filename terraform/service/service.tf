@@ -21,6 +21,8 @@ resource "google_cloud_run_service" "service" {
 
   template {
     spec {
+      service_account_name = var.service_account
+
       containers {
         image = data.google_container_registry_image.image.image_url
 
@@ -31,6 +33,19 @@ resource "google_cloud_run_service" "service" {
             value = env.value.value
           }
         }
+
+        dynamic "env" {
+          for_each = var.secret_envs
+          content {
+            name = env.value.name
+            value_from {
+              secret_key_ref {
+                name = env.value.secret
+                key  = "latest"
+              }
+            }
+          }
+        }
       }
     }
 
@@ -63,3 +78,12 @@ resource "google_cloud_run_service_iam_policy" "noauth_policy" {
 
   policy_data = data.google_iam_policy.noauth.policy_data
 }
+
+resource "google_cloud_run_service_iam_member" "invoker" {
+  for_each = var.auth ? toset(var.invokers) : toset([])
+
+  location = google_cloud_run_service.service.location
+  service  = google_cloud_run_service.service.name
+  role     = "roles/run.invoker"
+  member   = each.value
+}
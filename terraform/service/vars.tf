@@ -11,6 +11,22 @@ variable envs {
   }))
   default = []
 }
+variable secret_envs {
+  description = "envs with values read from the latest version of Secret Manager secrets"
+  type = list(object({
+    name   = string
+    secret = string
+  }))
+  default = []
+}
+variable service_account {
+  description = "email of the service account, which the service runs as"
+}
+variable invokers {
+  description = "members allowed to call the service, when it requires auth"
+  type        = list(string)
+  default     = []
+}
 variable auth {
   type    = bool
   default = true
@@ -63,7 +63,9 @@ class MockAuth {
                 let found = getTestUsers().filter(u => u.login === login && u.password === password);
 
                 if (found) {
-                    localStorage.setItem('_mock_user', JSON.stringify(found[0]));
+                    // the same as auth_time of Firebase tokens, it identifies the session
+                    let user = {...found[0], authTime: Math.floor(Date.now() / 1000)}
+                    localStorage.setItem('_mock_user', JSON.stringify(user));
                     resolve()
                 } else {
                     reject('invalid login or password')
@@ -87,6 +89,7 @@ class MockAuth {
                 'email': user.login,
                 'role': user.role,
                 'name': user.name,
+                'auth_time': user.authTime,
             }
             let token = sign(claims, 'mock_secret')
             resolve(token)